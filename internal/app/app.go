@@ -2,33 +2,66 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/PaulSonOfLars/gotgbot/v2"
 	"github.com/PaulSonOfLars/gotgbot/v2/ext"
 
+	"twitterx-bot/internal/articles"
 	"twitterx-bot/internal/config"
+	"twitterx-bot/internal/export"
+	"twitterx-bot/internal/follow"
 	"twitterx-bot/internal/handlers"
 	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/ratelimit"
+	"twitterx-bot/internal/storage"
+	"twitterx-bot/internal/telegram/outbox"
 	"twitterx-bot/internal/telegraph"
+	"twitterx-bot/internal/translation"
 	"twitterx-bot/internal/twitterxapi"
 )
 
-func NewBot() (*gotgbot.Bot, *ext.Updater, *logger.Logger, error) {
+// App is the configured bot with its update loop and background services
+// (such as the self-hosted article server) that run alongside polling.
+type App struct {
+	Bot     *gotgbot.Bot
+	Updater *ext.Updater
+	Log     *logger.Logger
+
+	storage  storage.Storage
+	services []service
+}
+
+// service is a background task that runs until ctx is cancelled.
+type service struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+func NewBot() (*App, error) {
 	cfg, err := config.Load()
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, err
 	}
+	var services []service
 
 	l := logger.New(cfg.Debug)
 	log := l.With("component", "app")
 	log.Info("config loaded", "debug", cfg.Debug, "twitterx_api_url", cfg.TwitterXAPIURL, "telegram_api_url", cfg.TelegramAPIURL)
 
+	store, err := openStorage(cfg)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("storage opened", "path", cfg.StoragePath)
+
 	// Initialize Telegraph service if enabled
 	var telegraphService *telegraph.Service
 	telegraphClient := telegraph.NewClient(&http.Client{
@@ -37,8 +70,33 @@ func NewBot() (*gotgbot.Bot, *ext.Updater, *logger.Logger, error) {
 	opts := []telegraph.Option{}
 	opts = append(opts, telegraph.WithAuthorName(cfg.TelegraphAuthorName))
 	opts = append(opts, telegraph.WithAuthorURL(cfg.TelegraphAuthorURL))
+	opts = append(opts, telegraph.WithAccountPool(cfg.TelegraphAccounts))
+	opts = append(opts, telegraph.WithRetry(cfg.TelegraphMaxAttempts, cfg.TelegraphMaxFloodWait))
+	opts = append(opts, telegraph.WithQueue(cfg.TelegraphWorkers, cfg.TelegraphMaxPending))
+	if cfg.TelegraphStoreFile != "" {
+		opts = append(opts, telegraph.WithStore(telegraph.NewFileStore(cfg.TelegraphStoreFile)))
+	}
 	telegraphService = telegraph.NewService(telegraphClient, opts...)
-	log.Info("telegraph integration enabled", "author_name", cfg.TelegraphAuthorName, "author_url", cfg.TelegraphAuthorURL)
+	log.Info("telegraph integration enabled", "author_name", cfg.TelegraphAuthorName, "author_url", cfg.TelegraphAuthorURL, "chain_article_threshold", cfg.ChainArticleThreshold, "store_file", cfg.TelegraphStoreFile, "accounts", cfg.TelegraphAccounts, "workers", cfg.TelegraphWorkers)
+
+	// Long tweets are published to Telegraph, the self-hosted article server, or both
+	var articlePublisher articles.Publisher = telegraphService
+	if cfg.ArticleServerAddr != "" {
+		articleService := articles.NewService(articles.NewDiskStore(cfg.ArticleStoreDir), cfg.ArticlePublicURL, cfg.ArticleRetention)
+		services = append(services, newArticleServices(cfg, articleService, l)...)
+		if cfg.ArticleBackend == config.ArticleBackendSelfHosted {
+			articlePublisher = articleService
+		} else {
+			articlePublisher = articles.NewFallback(telegraphService, articleService)
+		}
+		log.Info("self-hosted articles enabled", "backend", cfg.ArticleBackend, "addr", cfg.ArticleServerAddr, "public_url", cfg.ArticlePublicURL, "retention", cfg.ArticleRetention)
+	}
+
+	detector := translation.NewDetector()
+	translator := newTranslator(cfg, detector)
+	log.Info("translation enabled", "providers", cfg.TranslationProviders, "cache_size", cfg.TranslationCacheSize, "cache_ttl", cfg.TranslationCacheTTL)
+
+	log.Info("rate limits", "user", cfg.RateLimitUser, "chat", cfg.RateLimitChat, "global", cfg.RateLimitGlobal)
 
 	botOpts := &gotgbot.BotOpts{}
 	if cfg.TelegramAPIURL != "" {
@@ -51,7 +109,8 @@ func NewBot() (*gotgbot.Bot, *ext.Updater, *logger.Logger, error) {
 
 	bot, err := gotgbot.NewBot(cfg.BotToken, botOpts)
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("init bot: %w", err)
+		store.Close()
+		return nil, fmt.Errorf("init bot: %w", err)
 	}
 
 	dispatcher := ext.NewDispatcher(&ext.DispatcherOpts{
@@ -72,19 +131,125 @@ func NewBot() (*gotgbot.Bot, *ext.Updater, *logger.Logger, error) {
 	updater := ext.NewUpdater(dispatcher, &ext.UpdaterOpts{})
 
 	apiClient := twitterxapi.NewClient(cfg.TwitterXAPIURL)
-	handlers.Register(dispatcher, l, apiClient, telegraphService)
+	handlerOpts := []handlers.Option{
+		handlers.WithStorage(store),
+		handlers.WithAdmins(cfg.AdminIDs...),
+		handlers.WithAllowlist(cfg.AllowlistMode),
+		handlers.WithSendQueue(outbox.New(l)),
+		handlers.WithRateLimits(handlers.RateLimits{
+			User:   rateLimit(cfg.RateLimitUser),
+			Chat:   rateLimit(cfg.RateLimitChat),
+			Global: rateLimit(cfg.RateLimitGlobal),
+		}),
+		handlers.WithRepostDetection(cfg.RepostWindow),
+		handlers.WithTranslator(translator),
+		handlers.WithLanguageLabels(detector),
+		handlers.WithChainArticles(articlePublisher, cfg.ChainArticleThreshold),
+		handlers.WithDigestArticles(articlePublisher),
+		handlers.WithExportImages(export.NewHTTPImages(&http.Client{Timeout: 20 * time.Second})),
+	}
+	if cfg.FollowInterval > 0 {
+		handlerOpts = append(handlerOpts, handlers.WithFollowing(apiClient, follow.WithInterval(cfg.FollowInterval, cfg.FollowInterval/5)))
+		log.Info("following enabled", "interval", cfg.FollowInterval)
+	}
+	for _, job := range handlers.Register(dispatcher, l, apiClient, articlePublisher, handlerOpts...) {
+		services = append(services, service{
+			name: job.Name,
+			run: func(ctx context.Context) error {
+				return job.Run(ctx, bot)
+			},
+		})
+	}
+
+	return &App{Bot: bot, Updater: updater, Log: l, storage: store, services: services}, nil
+}
+
+func rateLimit(limit config.RateLimit) ratelimit.Limit {
+	return ratelimit.Limit{Burst: limit.Count, Per: limit.Per}
+}
 
-	return bot, updater, l, nil
+// openStorage opens the SQLite database at cfg.StoragePath, or in-memory storage when it is empty.
+func openStorage(cfg config.Config) (storage.Storage, error) {
+	if cfg.StoragePath == "" {
+		return storage.NewMemory(), nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	store, err := storage.OpenSQLite(ctx, cfg.StoragePath)
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
 }
 
-func Start(bot *gotgbot.Bot, updater *ext.Updater, l *logger.Logger) error {
-	if bot == nil {
+// newArticleServices returns the HTTP server for self-hosted articles and its retention cleanup.
+func newArticleServices(cfg config.Config, articleService *articles.Service, l *logger.Logger) []service {
+	server := &http.Server{
+		Addr:              cfg.ArticleServerAddr,
+		Handler:           articleService.Handler(l),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	return []service{
+		{
+			name: "article_server",
+			run: func(ctx context.Context) error {
+				errCh := make(chan error, 1)
+				go func() {
+					errCh <- server.ListenAndServe()
+				}()
+				select {
+				case err := <-errCh:
+					return err
+				case <-ctx.Done():
+					shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+					defer cancel()
+					return server.Shutdown(shutdownCtx)
+				}
+			},
+		},
+		{
+			name: "article_cleanup",
+			run: func(ctx context.Context) error {
+				return articleService.RunCleanup(ctx, time.Hour, l)
+			},
+		},
+	}
+}
+
+// newTranslator builds the translation fallback chain from config, wrapped in a cache.
+// Offline language detection skips texts already in the target language before any network call.
+func newTranslator(cfg config.Config, detector *translation.Detector) translation.Translator {
+	httpClient := &http.Client{
+		Timeout: translation.DefaultTimeout * time.Second,
+	}
+
+	providers := make([]translation.Translator, 0, len(cfg.TranslationProviders))
+	for _, name := range cfg.TranslationProviders {
+		switch name {
+		case config.TranslationProviderGoogle:
+			providers = append(providers, translation.NewService(httpClient, cfg.TranslationAPIURL))
+		case config.TranslationProviderLibreTranslate:
+			providers = append(providers, translation.NewLibreTranslate(httpClient, cfg.LibreTranslateURL, cfg.LibreTranslateAPIKey))
+		case config.TranslationProviderDeepL:
+			providers = append(providers, translation.NewDeepL(httpClient, cfg.DeepLAPIURL, cfg.DeepLAuthKey))
+		}
+	}
+
+	cached := translation.NewCache(translation.NewFallback(providers...), cfg.TranslationCacheSize, cfg.TranslationCacheTTL)
+	return translation.NewSkipDetected(cached, detector)
+}
+
+// Start runs background services and polls for updates until SIGINT or SIGTERM.
+func (a *App) Start() error {
+	if a == nil || a.Bot == nil {
 		return fmt.Errorf("start bot: bot is nil")
 	}
-	if updater == nil {
+	if a.Updater == nil {
 		return fmt.Errorf("start bot: updater is nil")
 	}
-	log := l.With("component", "app")
+	bot := a.Bot
+	log := a.Log.With("component", "app")
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
@@ -98,23 +263,49 @@ func Start(bot *gotgbot.Bot, updater *ext.Updater, l *logger.Logger) error {
 		log.Error("set bot description failed", "err", err)
 	}
 
-	if err := updater.StartPolling(bot, &ext.PollingOpts{DropPendingUpdates: true}); err != nil {
+	if a.storage != nil {
+		defer func() {
+			if err := a.storage.Close(); err != nil {
+				log.Error("close storage failed", "err", err)
+			}
+		}()
+	}
+
+	var wg sync.WaitGroup
+	for _, svc := range a.services {
+		wg.Add(1)
+		go func(svc service) {
+			defer wg.Done()
+			svcLog := log.With("service_name", svc.name)
+			svcLog.Info("service started")
+			if err := svc.run(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				svcLog.Error("service stopped with error", "err", err)
+				return
+			}
+			svcLog.Info("service stopped")
+		}(svc)
+	}
+
+	if err := a.Updater.StartPolling(bot, &ext.PollingOpts{DropPendingUpdates: true}); err != nil {
+		stop()
+		wg.Wait()
 		return fmt.Errorf("start polling: %w", err)
 	}
 	log.Info("bot started", "username", bot.User.Username)
 
 	<-ctx.Done()
 	log.Info("shutdown signal received")
-	updater.Stop()
+	a.Updater.Stop()
 	log.Info("updater stopped")
+	wg.Wait()
 	return nil
 }
 
 func Run() error {
-	bot, updater, l, err := NewBot()
+	a, err := NewBot()
 	if err != nil {
 		return err
 	}
 
-	return Start(bot, updater, l)
+	return a.Start()
 }
@@ -1,9 +1,17 @@
 package app
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
 	"testing"
+
+	"twitterx-bot/internal/config"
+	"twitterx-bot/internal/storage"
+	"twitterx-bot/internal/translation"
 )
 
 // TestNewBot_UsesConfiguredTelegramAPIURL verifies that when TELEGRAM_API_URL is set,
@@ -27,20 +35,21 @@ func TestNewBot_UsesConfiguredTelegramAPIURL(t *testing.T) {
 	t.Setenv("TELEGRAM_API_URL", mockServer.URL)
 	t.Setenv("TWITTERX_API_URL", "http://127.0.0.1:8080")
 
-	bot, updater, logger, err := NewBot()
+	a, err := NewBot()
 	if err != nil {
 		t.Fatalf("NewBot() error = %v", err)
 	}
 
-	if bot == nil {
+	if a.Bot == nil {
 		t.Fatal("bot is nil")
 	}
-	if updater == nil {
+	if a.Updater == nil {
 		t.Fatal("updater is nil")
 	}
-	if logger == nil {
+	if a.Log == nil {
 		t.Fatal("logger is nil")
 	}
+	bot := a.Bot
 
 	if !called {
 		t.Fatal("mock server was not called - bot did not use configured TELEGRAM_API_URL")
@@ -51,13 +60,125 @@ func TestNewBot_UsesConfiguredTelegramAPIURL(t *testing.T) {
 	}
 }
 
+// TestNewBot_SelfHostedArticles verifies that enabling the article server registers its background services.
+func TestNewBot_SelfHostedArticles(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"id":123,"is_bot":true,"first_name":"TestBot","username":"testbot"}}`))
+	}))
+	defer mockServer.Close()
+
+	t.Setenv("BOT_TOKEN", "123:ABC")
+	t.Setenv("TELEGRAM_API_URL", mockServer.URL)
+	t.Setenv("TWITTERX_API_URL", "http://127.0.0.1:8080")
+	t.Setenv("ARTICLE_BACKEND", "selfhosted")
+	t.Setenv("ARTICLE_SERVER_ADDR", "127.0.0.1:0")
+	t.Setenv("ARTICLE_PUBLIC_URL", "https://articles.example.com")
+	t.Setenv("ARTICLE_STORE_DIR", t.TempDir())
+
+	a, err := NewBot()
+	if err != nil {
+		t.Fatalf("NewBot() error = %v", err)
+	}
+
+	var names []string
+	for _, svc := range a.services {
+		names = append(names, svc.name)
+	}
+	want := []string{"article_server", "article_cleanup", "watch_notifier", "follow_poller", "digest_scheduler", "storage_pruner"}
+	if !slices.Equal(names, want) {
+		t.Fatalf("services = %v, want %v", names, want)
+	}
+}
+
+// TestNewBot_HandlerJobs verifies that the background jobs of the handlers run as services.
+func TestNewBot_HandlerJobs(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"id":123,"is_bot":true,"first_name":"TestBot","username":"testbot"}}`))
+	}))
+	defer mockServer.Close()
+
+	t.Setenv("BOT_TOKEN", "123:ABC")
+	t.Setenv("TELEGRAM_API_URL", mockServer.URL)
+	t.Setenv("TWITTERX_API_URL", "http://127.0.0.1:8080")
+	t.Setenv("FOLLOW_INTERVAL", "10m")
+
+	a, err := NewBot()
+	if err != nil {
+		t.Fatalf("NewBot() error = %v", err)
+	}
+
+	var names []string
+	for _, svc := range a.services {
+		names = append(names, svc.name)
+	}
+	want := []string{"watch_notifier", "follow_poller", "digest_scheduler", "storage_pruner"}
+	if !slices.Equal(names, want) {
+		t.Fatalf("services = %v, want %v", names, want)
+	}
+}
+
+// TestNewBot_OpensSQLiteStorage verifies that STORAGE_PATH selects the SQLite backend.
+func TestNewBot_OpensSQLiteStorage(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"id":123,"is_bot":true,"first_name":"TestBot","username":"testbot"}}`))
+	}))
+	defer mockServer.Close()
+
+	path := filepath.Join(t.TempDir(), "bot.db")
+	t.Setenv("BOT_TOKEN", "123:ABC")
+	t.Setenv("TELEGRAM_API_URL", mockServer.URL)
+	t.Setenv("TWITTERX_API_URL", "http://127.0.0.1:8080")
+	t.Setenv("STORAGE_PATH", path)
+
+	a, err := NewBot()
+	if err != nil {
+		t.Fatalf("NewBot() error = %v", err)
+	}
+	defer a.storage.Close()
+
+	if _, ok := a.storage.(*storage.SQLite); !ok {
+		t.Fatalf("storage = %T, want *storage.SQLite", a.storage)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("database file not created: %v", err)
+	}
+}
+
 // TestNewBot_RequiresBotToken verifies that NewBot returns an error when BOT_TOKEN is empty.
 func TestNewBot_RequiresBotToken(t *testing.T) {
 	t.Setenv("BOT_TOKEN", "")
 	t.Setenv("DEBUG", "false")
 
-	_, _, _, err := NewBot()
+	_, err := NewBot()
 	if err == nil {
 		t.Fatal("expected error when BOT_TOKEN is empty")
 	}
 }
+
+// TestNewTranslator_UsesConfiguredProviders verifies that the fallback chain
+// tries providers in the configured order.
+func TestNewTranslator_UsesConfiguredProviders(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer failing.Close()
+
+	libre := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"translatedText":"Привіт","detectedLanguage":{"language":"en"}}`))
+	}))
+	defer libre.Close()
+
+	translator := newTranslator(config.Config{
+		TranslationProviders: []string{config.TranslationProviderGoogle, config.TranslationProviderLibreTranslate},
+		TranslationAPIURL:    failing.URL,
+		LibreTranslateURL:    libre.URL,
+	}, translation.NewDetector())
+
+	tr, err := translator.Translate(context.Background(), "Hello", translation.LangUkrainian)
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if tr.Text != "Привіт" {
+		t.Fatalf("Text = %q, want %q", tr.Text, "Привіт")
+	}
+}
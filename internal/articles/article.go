@@ -0,0 +1,216 @@
+// Package articles renders tweets and reply chains as self-hosted HTML pages,
+// an alternative to Telegraph when telegra.ph is blocked or rate-limited.
+package articles
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"twitterx-bot/internal/chain"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+// ErrInvalidID is returned for article IDs that are not safe to use as file names or URL paths.
+var ErrInvalidID = errors.New("invalid article id")
+
+var idRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,127}$`)
+
+// Entry is a single tweet of an article with its relationship in the chain.
+// Label replaces the heading of the relationship, e.g. the rank of a digest tweet.
+type Entry struct {
+	Tweet *twitterxapi.Tweet `json:"tweet"`
+	Type  chain.ChainType    `json:"type"`
+	Label string             `json:"label,omitempty"`
+}
+
+// Article is a stored tweet or reply chain.
+type Article struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Entries   []Entry   `json:"entries"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Thread reports whether the article holds more than one tweet.
+func (a Article) Thread() bool {
+	return len(a.Entries) > 1
+}
+
+// Store persists articles.
+type Store interface {
+	Save(ctx context.Context, article Article) error
+	// Get returns the article or false when it does not exist.
+	Get(ctx context.Context, id string) (Article, bool, error)
+	// DeleteBefore removes articles last updated before t and returns how many were removed.
+	DeleteBefore(ctx context.Context, t time.Time) (int, error)
+}
+
+// ValidateID reports whether id is safe to use as a file name and URL path segment.
+func ValidateID(id string) error {
+	if !idRegex.MatchString(id) {
+		return fmt.Errorf("%w: %q", ErrInvalidID, id)
+	}
+	return nil
+}
+
+// MemoryStore keeps articles in memory.
+type MemoryStore struct {
+	mu       sync.RWMutex
+	articles map[string]Article
+}
+
+// NewMemoryStore creates an empty in-memory article store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{articles: make(map[string]Article)}
+}
+
+// Save stores the article, replacing any article with the same ID.
+func (s *MemoryStore) Save(_ context.Context, article Article) error {
+	if err := ValidateID(article.ID); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.articles[article.ID] = article
+	return nil
+}
+
+// Get returns the article with the given ID.
+func (s *MemoryStore) Get(_ context.Context, id string) (Article, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	article, ok := s.articles[id]
+	return article, ok, nil
+}
+
+// DeleteBefore removes articles last updated before t.
+func (s *MemoryStore) DeleteBefore(_ context.Context, t time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removed := 0
+	for id, article := range s.articles {
+		if article.UpdatedAt.Before(t) {
+			delete(s.articles, id)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// DiskStore keeps each article as a JSON file in a directory.
+type DiskStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewDiskStore creates a store in dir. The directory is created on first save.
+func NewDiskStore(dir string) *DiskStore {
+	return &DiskStore{dir: dir}
+}
+
+// Save writes the article atomically, replacing any article with the same ID.
+func (s *DiskStore) Save(_ context.Context, article Article) error {
+	if err := ValidateID(article.ID); err != nil {
+		return err
+	}
+	data, err := json.Marshal(article)
+	if err != nil {
+		return fmt.Errorf("encode article: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("create article dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(s.dir, ".article-*.tmp")
+	if err != nil {
+		return fmt.Errorf("create article temp file: %w", err)
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write article: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close article: %w", err)
+	}
+	if err := os.Rename(tmpName, s.path(article.ID)); err != nil {
+		return fmt.Errorf("replace article: %w", err)
+	}
+	return nil
+}
+
+// Get reads the article with the given ID.
+func (s *DiskStore) Get(_ context.Context, id string) (Article, bool, error) {
+	if ValidateID(id) != nil {
+		return Article{}, false, nil
+	}
+	article, err := readArticle(s.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return Article{}, false, nil
+	}
+	if err != nil {
+		return Article{}, false, err
+	}
+	return article, true, nil
+}
+
+// DeleteBefore removes articles last updated before t.
+func (s *DiskStore) DeleteBefore(_ context.Context, t time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read article dir: %w", err)
+	}
+
+	removed := 0
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(s.dir, file.Name())
+		article, err := readArticle(path)
+		if err != nil || !article.UpdatedAt.Before(t) {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return removed, fmt.Errorf("remove article: %w", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+func (s *DiskStore) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+func readArticle(path string) (Article, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Article{}, err
+	}
+	var article Article
+	if err := json.Unmarshal(data, &article); err != nil {
+		return Article{}, fmt.Errorf("decode article %s: %w", filepath.Base(path), err)
+	}
+	return article, nil
+}
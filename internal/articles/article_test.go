@@ -0,0 +1,62 @@
+package articles
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"twitterx-bot/internal/chain"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+func TestDiskStore_SaveGetDelete(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "articles")
+	store := NewDiskStore(dir)
+	ctx := context.Background()
+
+	old := Article{ID: "tweet-1", Title: "Old", UpdatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	fresh := Article{
+		ID:        "thread-2",
+		Title:     "Fresh",
+		Entries:   []Entry{{Tweet: &twitterxapi.Tweet{ID: "2", Text: "hi"}, Type: chain.ChainTypeRoot}},
+		UpdatedAt: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for _, a := range []Article{old, fresh} {
+		if err := store.Save(ctx, a); err != nil {
+			t.Fatalf("Save(%s) error = %v", a.ID, err)
+		}
+	}
+
+	got, ok, err := NewDiskStore(dir).Get(ctx, "thread-2")
+	if err != nil || !ok {
+		t.Fatalf("Get() = %v, %v", ok, err)
+	}
+	if got.Title != "Fresh" || len(got.Entries) != 1 || got.Entries[0].Tweet.Text != "hi" {
+		t.Fatalf("Get() = %+v", got)
+	}
+
+	removed, err := store.DeleteBefore(ctx, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))
+	if err != nil || removed != 1 {
+		t.Fatalf("DeleteBefore() = %d, %v, want 1", removed, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tweet-1.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected old article file to be removed, stat err = %v", err)
+	}
+	if _, ok, _ := store.Get(ctx, "thread-2"); !ok {
+		t.Fatal("expected fresh article to be kept")
+	}
+}
+
+func TestDiskStore_RejectsUnsafeIDs(t *testing.T) {
+	store := NewDiskStore(t.TempDir())
+
+	if err := store.Save(context.Background(), Article{ID: "../escape"}); !errors.Is(err, ErrInvalidID) {
+		t.Fatalf("Save() error = %v, want ErrInvalidID", err)
+	}
+	if _, ok, err := store.Get(context.Background(), "../escape"); ok || err != nil {
+		t.Fatalf("Get() = %v, %v, want not found", ok, err)
+	}
+}
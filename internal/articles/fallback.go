@@ -0,0 +1,71 @@
+package articles
+
+import (
+	"context"
+	"errors"
+
+	"twitterx-bot/internal/chain"
+	"twitterx-bot/internal/telegram/tweet"
+	"twitterx-bot/internal/telegraph"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+// ErrNoPublishers is returned by a Fallback without publishers.
+var ErrNoPublishers = errors.New("no article publishers configured")
+
+// Publisher creates articles for single tweets, reply chains and chat digests.
+type Publisher interface {
+	tweet.ArticleCreator
+	tweet.ChainArticleCreator
+	CreateDigestArticle(ctx context.Context, title string, entries []telegraph.DigestEntry) (string, error)
+}
+
+// Fallback tries publishers in order and returns the first successful URL,
+// e.g. Telegraph first and the self-hosted server when telegra.ph is unavailable.
+type Fallback struct {
+	publishers []Publisher
+}
+
+// NewFallback creates a publisher that falls back through publishers in order.
+func NewFallback(publishers ...Publisher) *Fallback {
+	return &Fallback{publishers: publishers}
+}
+
+// CreateArticle publishes the tweet with the first publisher that succeeds.
+func (f *Fallback) CreateArticle(ctx context.Context, tw *twitterxapi.Tweet) (string, error) {
+	return f.try(ctx, func(p Publisher) (string, error) {
+		return p.CreateArticle(ctx, tw)
+	})
+}
+
+// CreateChainArticle publishes the chain with the first publisher that succeeds.
+func (f *Fallback) CreateChainArticle(ctx context.Context, items []chain.ChainItem) (string, error) {
+	return f.try(ctx, func(p Publisher) (string, error) {
+		return p.CreateChainArticle(ctx, items)
+	})
+}
+
+// CreateDigestArticle publishes the digest with the first publisher that succeeds.
+func (f *Fallback) CreateDigestArticle(ctx context.Context, title string, entries []telegraph.DigestEntry) (string, error) {
+	return f.try(ctx, func(p Publisher) (string, error) {
+		return p.CreateDigestArticle(ctx, title, entries)
+	})
+}
+
+func (f *Fallback) try(ctx context.Context, publish func(Publisher) (string, error)) (string, error) {
+	var errs []error
+	for _, p := range f.publishers {
+		url, err := publish(p)
+		if err == nil {
+			return url, nil
+		}
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if len(errs) == 0 {
+		return "", ErrNoPublishers
+	}
+	return "", errors.Join(errs...)
+}
@@ -0,0 +1,77 @@
+package articles
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"twitterx-bot/internal/chain"
+	"twitterx-bot/internal/telegraph"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+type stubPublisher struct {
+	url   string
+	err   error
+	calls int
+}
+
+func (s *stubPublisher) CreateArticle(_ context.Context, _ *twitterxapi.Tweet) (string, error) {
+	s.calls++
+	return s.url, s.err
+}
+
+func (s *stubPublisher) CreateChainArticle(_ context.Context, _ []chain.ChainItem) (string, error) {
+	s.calls++
+	return s.url, s.err
+}
+
+func (s *stubPublisher) CreateDigestArticle(_ context.Context, _ string, _ []telegraph.DigestEntry) (string, error) {
+	s.calls++
+	return s.url, s.err
+}
+
+func TestFallback_UsesSecondaryOnFailure(t *testing.T) {
+	primary := &stubPublisher{err: errors.New("telegraph blocked")}
+	secondary := &stubPublisher{url: "https://articles.example.com/a/tweet-1"}
+	fallback := NewFallback(primary, secondary)
+
+	url, err := fallback.CreateArticle(context.Background(), &twitterxapi.Tweet{ID: "1"})
+	if err != nil {
+		t.Fatalf("CreateArticle() error = %v", err)
+	}
+	if url != secondary.url || primary.calls != 1 {
+		t.Fatalf("url = %q, primary calls = %d", url, primary.calls)
+	}
+}
+
+func TestFallback_PrimarySucceeds(t *testing.T) {
+	primary := &stubPublisher{url: "https://telegra.ph/thread"}
+	secondary := &stubPublisher{url: "https://articles.example.com/a/thread-1"}
+
+	url, err := NewFallback(primary, secondary).CreateChainArticle(context.Background(), nil)
+	if err != nil || url != primary.url {
+		t.Fatalf("CreateChainArticle() = %q, %v", url, err)
+	}
+	if secondary.calls != 0 {
+		t.Fatalf("secondary calls = %d, want 0", secondary.calls)
+	}
+}
+
+func TestFallback_DigestUsesSecondaryOnFailure(t *testing.T) {
+	primary := &stubPublisher{err: errors.New("telegraph blocked")}
+	secondary := &stubPublisher{url: "https://articles.example.com/a/digest-1"}
+
+	url, err := NewFallback(primary, secondary).CreateDigestArticle(context.Background(), "Digest", nil)
+	if err != nil || url != secondary.url {
+		t.Fatalf("CreateDigestArticle() = %q, %v", url, err)
+	}
+}
+
+func TestFallback_AllFail(t *testing.T) {
+	errA, errB := errors.New("a"), errors.New("b")
+	_, err := NewFallback(&stubPublisher{err: errA}, &stubPublisher{err: errB}).CreateArticle(context.Background(), nil)
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Fatalf("error = %v, want both errors joined", err)
+	}
+}
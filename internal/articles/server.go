@@ -0,0 +1,245 @@
+package articles
+
+import (
+	"html"
+	"html/template"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"twitterx-bot/internal/chain"
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+var linkRegex = regexp.MustCompile(`https?://[^\s<]+`)
+
+// page is the template view of an article.
+type page struct {
+	Title       string
+	Description string
+	Image       string
+	Author      string
+	URL         string
+	Thread      bool
+	Sections    []section
+}
+
+// section is the template view of a single tweet.
+type section struct {
+	Marker     string
+	Tweet      *twitterxapi.Tweet
+	ProfileURL string
+	Paragraphs []template.HTML
+	Quote      *section
+}
+
+var pageTemplate = template.Must(template.New("article").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<meta property="og:type" content="article">
+<meta property="og:title" content="{{.Title}}">
+<meta property="og:url" content="{{.URL}}">
+{{- if .Description}}
+<meta property="og:description" content="{{.Description}}">
+<meta name="description" content="{{.Description}}">
+{{- end}}
+{{- if .Image}}
+<meta property="og:image" content="{{.Image}}">
+{{- end}}
+{{- if .Author}}
+<meta property="article:author" content="{{.Author}}">
+<meta name="author" content="{{.Author}}">
+{{- end}}
+<style>
+body{margin:0;background:#fff;color:#111;font:18px/1.6 Georgia,serif}
+article{max-width:680px;margin:0 auto;padding:24px 16px}
+h1{font:700 30px/1.25 -apple-system,Helvetica,Arial,sans-serif;margin:0 0 24px}
+h3{font:600 15px/1.4 -apple-system,Helvetica,Arial,sans-serif;color:#666;margin:32px 0 8px}
+aside{display:flex;align-items:center;gap:10px;font-family:-apple-system,Helvetica,Arial,sans-serif;font-size:16px}
+aside img{width:40px;height:40px;border-radius:50%}
+aside a{color:inherit;text-decoration:none}
+figure{margin:16px 0}
+figure img,figure video{max-width:100%;height:auto;display:block}
+blockquote{margin:16px 0;padding:4px 16px;border-left:3px solid #ccc;color:#444}
+footer{font:14px/1.4 -apple-system,Helvetica,Arial,sans-serif;color:#666}
+hr{border:0;border-top:1px solid #eee;margin:32px 0}
+a{color:#1d6fa5}
+</style>
+</head>
+<body>
+<article>
+<h1>{{.Title}}</h1>
+{{- range $i, $s := .Sections}}
+{{- if $i}}
+<hr>
+{{- end}}
+<section>
+{{- if $.Thread}}
+<h3>{{$s.Marker}}</h3>
+{{- end}}
+{{template "tweet" $s}}
+</section>
+{{- end}}
+</article>
+</body>
+</html>
+{{define "author"}}<aside>
+{{- if .Tweet.Author.AvatarURL}}<img src="{{.Tweet.Author.AvatarURL}}" alt="">{{end}}
+{{- if .ProfileURL}}<a href="{{.ProfileURL}}"><b>{{or .Tweet.Author.Name .Tweet.Author.ScreenName}}</b></a>{{else}}<b>{{.Tweet.Author.Name}}</b>{{end}}
+{{- if .Tweet.Author.ScreenName}} @{{.Tweet.Author.ScreenName}}{{end}}</aside>{{end}}
+{{define "media"}}
+{{- with .Tweet.Media}}
+{{- range .Photos}}
+<figure><img src="{{.URL}}" alt=""></figure>
+{{- end}}
+{{- range .Videos}}
+<figure><video src="{{.URL}}"{{if .ThumbnailURL}} poster="{{.ThumbnailURL}}"{{end}} controls playsinline></video></figure>
+{{- end}}
+{{- end}}
+{{- end}}
+{{define "tweet"}}
+{{- template "author" .}}
+{{- range .Paragraphs}}
+<p>{{.}}</p>
+{{- end}}
+{{- template "media" .}}
+{{- with .Quote}}
+<blockquote>
+{{template "author" .}}
+{{- range .Paragraphs}}
+<p>{{.}}</p>
+{{- end}}
+{{- template "media" .}}
+{{- if .Tweet.URL}}
+<p><a href="{{.Tweet.URL}}">Quoted tweet</a></p>
+{{- end}}
+</blockquote>
+{{- end}}
+<footer>
+{{- if .Tweet.ReplyingTo}}Replying to @{{.Tweet.ReplyingTo}}{{if .Tweet.URL}} · {{end}}{{end}}
+{{- if .Tweet.URL}}<a href="{{.Tweet.URL}}">View on X</a>{{end}}
+</footer>
+{{- end}}
+`))
+
+// Handler serves stored articles at /a/{id}.
+func (s *Service) Handler(log *logger.Logger) http.Handler {
+	log = log.With("component", "articles_http")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /a/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		article, ok, err := s.Get(r.Context(), id)
+		if err != nil {
+			log.Error("load article failed", "id", id, "err", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		if err := pageTemplate.Execute(w, s.view(article)); err != nil {
+			log.Warn("render article failed", "id", id, "err", err)
+		}
+	})
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return mux
+}
+
+// view converts an article into its template view.
+func (s *Service) view(article Article) page {
+	p := page{
+		Title:  article.Title,
+		URL:    s.URL(article.ID),
+		Thread: article.Thread(),
+	}
+
+	var prev *twitterxapi.Tweet
+	for _, entry := range article.Entries {
+		if entry.Tweet == nil {
+			continue
+		}
+		sec := newSection(entry.Tweet, entry.Type)
+		if entry.Label != "" {
+			sec.Marker = entry.Label
+		}
+		// The quoted tweet already has its own section right before the tweet quoting it.
+		if entry.Tweet.Quote != nil && entry.Tweet.Quote != prev && !sameTweet(entry.Tweet.Quote, prev) {
+			quote := newSection(entry.Tweet.Quote, chain.ChainTypeQuote)
+			sec.Quote = &quote
+		}
+		p.Sections = append(p.Sections, sec)
+		prev = entry.Tweet
+	}
+
+	if n := len(p.Sections); n > 0 {
+		root := p.Sections[n-1].Tweet
+		p.Description = truncate(strings.Join(strings.Fields(root.Text), " "), 200)
+		p.Author = root.Author.Name
+		if root.Media != nil && len(root.Media.Photos) > 0 {
+			p.Image = root.Media.Photos[0].URL
+		}
+	}
+	return p
+}
+
+func newSection(tweet *twitterxapi.Tweet, t chain.ChainType) section {
+	sec := section{Marker: marker(t), Tweet: tweet}
+	if screenName := strings.TrimPrefix(strings.TrimSpace(tweet.Author.ScreenName), "@"); screenName != "" {
+		sec.ProfileURL = "https://x.com/" + screenName
+	}
+	for _, para := range strings.Split(tweet.Text, "\n\n") {
+		if para = strings.TrimSpace(para); para != "" {
+			sec.Paragraphs = append(sec.Paragraphs, linkify(para))
+		}
+	}
+	return sec
+}
+
+// linkify escapes text, turns URLs into links and line breaks into <br>.
+func linkify(text string) template.HTML {
+	var sb strings.Builder
+	last := 0
+	for _, loc := range linkRegex.FindAllStringIndex(text, -1) {
+		sb.WriteString(html.EscapeString(text[last:loc[0]]))
+		link := html.EscapeString(text[loc[0]:loc[1]])
+		sb.WriteString(`<a href="` + link + `">` + link + `</a>`)
+		last = loc[1]
+	}
+	sb.WriteString(html.EscapeString(text[last:]))
+	return template.HTML(strings.ReplaceAll(sb.String(), "\n", "<br>"))
+}
+
+func marker(t chain.ChainType) string {
+	switch t {
+	case chain.ChainTypeReply:
+		return "↩️ Reply"
+	case chain.ChainTypeQuote:
+		return "💬 Quoted tweet"
+	default:
+		return "🐦 Tweet"
+	}
+}
+
+// sameTweet compares tweets by ID, since pointers differ after loading from disk.
+func sameTweet(a, b *twitterxapi.Tweet) bool {
+	return a != nil && b != nil && a.ID != "" && a.ID == b.ID
+}
+
+func truncate(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max-1]) + "…"
+}
@@ -0,0 +1,177 @@
+package articles
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"twitterx-bot/internal/chain"
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/telegraph"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+// DefaultRetention is how long articles are kept when no retention is configured.
+const DefaultRetention = 30 * 24 * time.Hour
+
+// ErrEmptyArticle is returned when there is no tweet to publish.
+var ErrEmptyArticle = errors.New("article has no tweets")
+
+// Service stores tweets, chains and digests as articles and returns their public URLs.
+// It implements Publisher.
+type Service struct {
+	store     Store
+	baseURL   string
+	retention time.Duration
+	now       func() time.Time
+}
+
+// NewService creates an article service. baseURL is the public URL the HTTP handler is served at.
+// Articles older than retention are removed by Cleanup; zero keeps them forever.
+func NewService(store Store, baseURL string, retention time.Duration) *Service {
+	return &Service{
+		store:     store,
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+// CreateArticle stores a single tweet and returns its page URL.
+// Publishing the same tweet again refreshes the existing page.
+func (s *Service) CreateArticle(ctx context.Context, tweet *twitterxapi.Tweet) (string, error) {
+	if tweet == nil {
+		return "", ErrEmptyArticle
+	}
+	entries := []Entry{{Tweet: tweet, Type: chain.ChainTypeRoot}}
+	return s.publish(ctx, articleID("tweet", tweet.ID), titleFor("Tweet", tweet), entries)
+}
+
+// CreateChainArticle stores a reply chain and returns its page URL.
+func (s *Service) CreateChainArticle(ctx context.Context, items []chain.ChainItem) (string, error) {
+	entries := make([]Entry, 0, len(items))
+	var root *twitterxapi.Tweet
+	for _, item := range items {
+		if item.Tweet == nil {
+			continue
+		}
+		entries = append(entries, Entry{Tweet: item.Tweet, Type: item.Type})
+		root = item.Tweet
+	}
+	if root == nil {
+		return "", ErrEmptyArticle
+	}
+	return s.publish(ctx, articleID("thread", root.ID), titleFor("Thread", root), entries)
+}
+
+// CreateDigestArticle stores the tweets of a chat digest and returns its page URL.
+// Every digest gets a new page.
+func (s *Service) CreateDigestArticle(ctx context.Context, title string, digest []telegraph.DigestEntry) (string, error) {
+	entries := make([]Entry, 0, len(digest))
+	for _, entry := range digest {
+		if entry.Tweet == nil {
+			continue
+		}
+		entries = append(entries, Entry{Tweet: entry.Tweet, Type: chain.ChainTypeRoot, Label: entry.Label})
+	}
+	if len(entries) == 0 {
+		return "", ErrEmptyArticle
+	}
+	return s.publish(ctx, articleID("digest", ""), title, entries)
+}
+
+// URL returns the public URL of the article with the given ID.
+func (s *Service) URL(id string) string {
+	return s.baseURL + "/a/" + id
+}
+
+// Get returns a stored article unless it has expired.
+func (s *Service) Get(ctx context.Context, id string) (Article, bool, error) {
+	article, ok, err := s.store.Get(ctx, id)
+	if err != nil || !ok {
+		return Article{}, false, err
+	}
+	if s.expired(article) {
+		return Article{}, false, nil
+	}
+	return article, true, nil
+}
+
+// Cleanup removes articles older than the retention period.
+func (s *Service) Cleanup(ctx context.Context) (int, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+	return s.store.DeleteBefore(ctx, s.now().Add(-s.retention))
+}
+
+// RunCleanup calls Cleanup every interval until ctx is done.
+func (s *Service) RunCleanup(ctx context.Context, interval time.Duration, log *logger.Logger) error {
+	if s.retention <= 0 {
+		return nil
+	}
+	log = log.With("component", "articles")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if removed, err := s.Cleanup(ctx); err != nil {
+			log.Warn("article cleanup failed", "err", err)
+		} else if removed > 0 {
+			log.Info("expired articles removed", "count", removed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) publish(ctx context.Context, id, title string, entries []Entry) (string, error) {
+	now := s.now()
+	article := Article{
+		ID:        id,
+		Title:     title,
+		Entries:   entries,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if existing, ok, err := s.store.Get(ctx, id); err == nil && ok {
+		article.CreatedAt = existing.CreatedAt
+	}
+
+	if err := s.store.Save(ctx, article); err != nil {
+		return "", err
+	}
+	return s.URL(id), nil
+}
+
+func (s *Service) expired(article Article) bool {
+	return s.retention > 0 && article.UpdatedAt.Before(s.now().Add(-s.retention))
+}
+
+// articleID builds a stable ID from the tweet ID, or a random one when the tweet has no usable ID.
+func articleID(prefix, tweetID string) string {
+	id := prefix + "-" + strings.ToLower(strings.TrimSpace(tweetID))
+	if ValidateID(id) == nil && tweetID != "" {
+		return id
+	}
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return prefix + "-" + hex.EncodeToString(buf)
+}
+
+func titleFor(kind string, tweet *twitterxapi.Tweet) string {
+	if screenName := strings.TrimPrefix(strings.TrimSpace(tweet.Author.ScreenName), "@"); screenName != "" {
+		return kind + " by @" + screenName
+	}
+	if name := strings.TrimSpace(tweet.Author.Name); name != "" {
+		return kind + " by " + name
+	}
+	return kind
+}
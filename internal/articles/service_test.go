@@ -0,0 +1,186 @@
+package articles
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"twitterx-bot/internal/chain"
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/telegraph"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+func testTweet(id, text string) *twitterxapi.Tweet {
+	return &twitterxapi.Tweet{
+		ID:     id,
+		URL:    "https://x.com/user/status/" + id,
+		Text:   text,
+		Author: twitterxapi.Author{Name: "User", ScreenName: "user", AvatarURL: "https://img/avatar.jpg"},
+	}
+}
+
+func TestService_CreateArticle_StableURL(t *testing.T) {
+	store := NewMemoryStore()
+	service := NewService(store, "https://articles.example.com/", 0)
+
+	first := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return first }
+	url, err := service.CreateArticle(context.Background(), testTweet("123", "Hello"))
+	if err != nil {
+		t.Fatalf("CreateArticle() error = %v", err)
+	}
+	if url != "https://articles.example.com/a/tweet-123" {
+		t.Fatalf("url = %q", url)
+	}
+
+	service.now = func() time.Time { return first.Add(time.Hour) }
+	again, err := service.CreateArticle(context.Background(), testTweet("123", "Hello, edited"))
+	if err != nil || again != url {
+		t.Fatalf("second CreateArticle() = %q, %v, want same URL", again, err)
+	}
+
+	article, _, _ := store.Get(context.Background(), "tweet-123")
+	if !article.CreatedAt.Equal(first) || article.Entries[0].Tweet.Text != "Hello, edited" {
+		t.Fatalf("expected refreshed article keeping CreatedAt, got %+v", article)
+	}
+}
+
+func TestService_CreateChainArticle(t *testing.T) {
+	service := NewService(NewMemoryStore(), "https://articles.example.com", 0)
+
+	url, err := service.CreateChainArticle(context.Background(), []chain.ChainItem{
+		{Tweet: testTweet("1", "Parent"), Type: chain.ChainTypeReply},
+		{Tweet: nil},
+		{Tweet: testTweet("2", "Root"), Type: chain.ChainTypeRoot},
+	})
+	if err != nil {
+		t.Fatalf("CreateChainArticle() error = %v", err)
+	}
+	if url != "https://articles.example.com/a/thread-2" {
+		t.Fatalf("url = %q", url)
+	}
+
+	if _, err := service.CreateChainArticle(context.Background(), nil); !errors.Is(err, ErrEmptyArticle) {
+		t.Fatalf("empty chain error = %v, want ErrEmptyArticle", err)
+	}
+}
+
+func TestService_CreateDigestArticle(t *testing.T) {
+	service := NewService(NewMemoryStore(), "https://articles.example.com", 0)
+
+	url, err := service.CreateDigestArticle(context.Background(), "Weekly digest", []telegraph.DigestEntry{
+		{Tweet: testTweet("1", "Most shared"), Label: "1. Shared 3 times"},
+		{Tweet: nil},
+		{Tweet: testTweet("2", "Runner-up"), Label: "2. Shared twice"},
+	})
+	if err != nil {
+		t.Fatalf("CreateDigestArticle() error = %v", err)
+	}
+	id := strings.TrimPrefix(url, "https://articles.example.com/a/")
+	if !strings.HasPrefix(id, "digest-") {
+		t.Fatalf("url = %q, want a digest page", url)
+	}
+
+	article, ok, err := service.Get(context.Background(), id)
+	if err != nil || !ok {
+		t.Fatalf("Get() = %v, %v", ok, err)
+	}
+	view := service.view(article)
+	if view.Title != "Weekly digest" || len(view.Sections) != 2 || view.Sections[0].Marker != "1. Shared 3 times" {
+		t.Errorf("view = %+v, want the labelled digest tweets", view)
+	}
+
+	if _, err := service.CreateDigestArticle(context.Background(), "Empty", nil); !errors.Is(err, ErrEmptyArticle) {
+		t.Fatalf("empty digest error = %v, want ErrEmptyArticle", err)
+	}
+}
+
+func TestService_Retention(t *testing.T) {
+	store := NewMemoryStore()
+	service := NewService(store, "https://articles.example.com", 24*time.Hour)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	if _, err := service.CreateArticle(context.Background(), testTweet("1", "Hello")); err != nil {
+		t.Fatalf("CreateArticle() error = %v", err)
+	}
+
+	now = now.Add(48 * time.Hour)
+	if _, ok, _ := service.Get(context.Background(), "tweet-1"); ok {
+		t.Fatal("expected expired article to be hidden")
+	}
+	removed, err := service.Cleanup(context.Background())
+	if err != nil || removed != 1 {
+		t.Fatalf("Cleanup() = %d, %v, want 1", removed, err)
+	}
+}
+
+func TestHandler_RendersArticle(t *testing.T) {
+	service := NewService(NewMemoryStore(), "https://articles.example.com", 0)
+	quoted := testTweet("9", "Quoted <text>")
+	quoted.Author = twitterxapi.Author{Name: "Quoted", ScreenName: "quoted"}
+	root := testTweet("2", "Root with link https://example.com/page\n\nSecond paragraph")
+	root.Quote = quoted
+	root.Media = &twitterxapi.Media{
+		Photos: []twitterxapi.Photo{{URL: "https://img/1.jpg"}},
+		Videos: []twitterxapi.Video{{URL: "https://video/1.mp4", ThumbnailURL: "https://img/thumb.jpg"}},
+	}
+	if _, err := service.CreateChainArticle(context.Background(), []chain.ChainItem{
+		{Tweet: testTweet("1", "Parent"), Type: chain.ChainTypeReply},
+		{Tweet: root, Type: chain.ChainTypeRoot},
+	}); err != nil {
+		t.Fatalf("CreateChainArticle() error = %v", err)
+	}
+
+	server := httptest.NewServer(service.Handler(logger.Default()))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/a/thread-2")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	page := string(body)
+
+	for _, want := range []string{
+		"<title>Thread by @user</title>",
+		`<meta property="og:image" content="https://img/1.jpg">`,
+		"↩️ Reply",
+		`<a href="https://example.com/page">https://example.com/page</a>`,
+		"<p>Second paragraph</p>",
+		`<figure><img src="https://img/1.jpg" alt=""></figure>`,
+		`<video src="https://video/1.mp4" poster="https://img/thumb.jpg" controls playsinline>`,
+		"<blockquote>",
+		"Quoted &lt;text&gt;",
+		`<a href="https://x.com/user/status/2">View on X</a>`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page does not contain %q", want)
+		}
+	}
+}
+
+func TestHandler_NotFound(t *testing.T) {
+	service := NewService(NewMemoryStore(), "https://articles.example.com", 0)
+	server := httptest.NewServer(service.Handler(logger.Default()))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/a/tweet-404")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", resp.StatusCode)
+	}
+}
@@ -0,0 +1,127 @@
+package chatsettings
+
+import (
+	"context"
+	"sync"
+
+	"twitterx-bot/internal/logger"
+)
+
+// MediaMode controls how tweet media is delivered.
+type MediaMode string
+
+const (
+	// MediaFull sends photos and videos as media messages.
+	MediaFull MediaMode = ""
+	// MediaPreview sends text with a link preview of the first media.
+	MediaPreview MediaMode = "preview"
+	// MediaText sends text only, with link previews disabled.
+	MediaText MediaMode = "text"
+)
+
+// SensitivePolicy controls media of tweets marked as possibly sensitive.
+type SensitivePolicy string
+
+const (
+	// SensitiveShow sends sensitive media like any other media.
+	SensitiveShow SensitivePolicy = ""
+	// SensitiveSpoiler hides sensitive media behind a spoiler.
+	SensitiveSpoiler SensitivePolicy = "spoiler"
+	// SensitiveHide sends sensitive tweets as text without media.
+	SensitiveHide SensitivePolicy = "hide"
+)
+
+// ChainMode controls how reply chains are offered.
+type ChainMode string
+
+const (
+	// ChainButton adds a "Send full chain" button to replies.
+	ChainButton ChainMode = ""
+	// ChainAuto sends the full chain right away.
+	ChainAuto ChainMode = "auto"
+	// ChainOff sends only the requested tweet.
+	ChainOff ChainMode = "off"
+)
+
+// SignatureMode controls the line appended to tweets posted in channels.
+type SignatureMode string
+
+const (
+	// SignatureOff appends nothing.
+	SignatureOff SignatureMode = ""
+	// SignatureTitle appends the channel title.
+	SignatureTitle SignatureMode = "title"
+	// SignatureLink appends the channel title linking to the channel.
+	SignatureLink SignatureMode = "link"
+)
+
+// Settings holds per-chat behavior options. The zero value is the default behavior.
+type Settings struct {
+	// TranslateTo is the ISO code tweets are translated into. Empty disables translation.
+	TranslateTo string `json:"translate_to,omitempty"`
+
+	MediaMode      MediaMode       `json:"media_mode,omitempty"`
+	SensitiveMedia SensitivePolicy `json:"sensitive_media,omitempty"`
+	ChainMode      ChainMode       `json:"chain_mode,omitempty"`
+	// HideAttribution drops the "by @requester" line from sent tweets.
+	HideAttribution bool `json:"hide_attribution,omitempty"`
+	// DisableArticles truncates long tweets and chains instead of publishing articles.
+	DisableArticles bool `json:"disable_articles,omitempty"`
+	// DeleteOriginal deletes the message with the link once the tweet is sent.
+	DeleteOriginal bool `json:"delete_original,omitempty"`
+
+	// DisableChannelMode leaves tweet links posted in a channel as they are.
+	DisableChannelMode bool `json:"disable_channel_mode,omitempty"`
+	// Signature is appended to tweets the bot posts in a channel.
+	Signature SignatureMode `json:"signature,omitempty"`
+}
+
+// Provider returns per-chat settings.
+type Provider interface {
+	Get(ctx context.Context, chatID int64) (Settings, error)
+}
+
+// Store loads and saves per-chat settings.
+type Store interface {
+	Provider
+	Save(ctx context.Context, chatID int64, settings Settings) error
+}
+
+// Load returns the settings of chatID, or the defaults when p is nil or fails; failures are logged.
+func Load(ctx context.Context, p Provider, chatID int64, log *logger.Logger) Settings {
+	if p == nil {
+		return Settings{}
+	}
+	settings, err := p.Get(ctx, chatID)
+	if err != nil {
+		log.Warn("load chat settings failed", "chat_id", chatID, "err", err)
+		return Settings{}
+	}
+	return settings
+}
+
+// MemoryStore keeps chat settings in process memory.
+type MemoryStore struct {
+	mu       sync.RWMutex
+	settings map[int64]Settings
+}
+
+// NewMemoryStore creates an empty in-memory settings store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{settings: make(map[int64]Settings)}
+}
+
+// Get returns the settings for chatID, or defaults if none were saved.
+func (m *MemoryStore) Get(_ context.Context, chatID int64) (Settings, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.settings[chatID], nil
+}
+
+// Save stores the settings for chatID.
+func (m *MemoryStore) Save(_ context.Context, chatID int64, settings Settings) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.settings[chatID] = settings
+	return nil
+}
@@ -0,0 +1,66 @@
+package chatsettings
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMemoryStore_GetDefaults(t *testing.T) {
+	store := NewMemoryStore()
+
+	got, err := store.Get(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got != (Settings{}) {
+		t.Fatalf("Get() = %+v, want zero settings", got)
+	}
+}
+
+func TestMemoryStore_SaveAndGet(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	if err := store.Save(ctx, 42, Settings{TranslateTo: "uk"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, err := store.Get(ctx, 42)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.TranslateTo != "uk" {
+		t.Fatalf("TranslateTo = %q, want %q", got.TranslateTo, "uk")
+	}
+
+	other, err := store.Get(ctx, 43)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if other.TranslateTo != "" {
+		t.Fatalf("other chat TranslateTo = %q, want empty", other.TranslateTo)
+	}
+}
+
+type failingProvider struct{}
+
+func (failingProvider) Get(context.Context, int64) (Settings, error) {
+	return Settings{TranslateTo: "uk"}, errors.New("db down")
+}
+
+func TestLoad(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	_ = store.Save(ctx, 42, Settings{TranslateTo: "uk"})
+
+	if got := Load(ctx, store, 42, nil); got.TranslateTo != "uk" {
+		t.Errorf("Load() = %+v, want the saved settings", got)
+	}
+	if got := Load(ctx, nil, 42, nil); got != (Settings{}) {
+		t.Errorf("Load() without provider = %+v, want defaults", got)
+	}
+	if got := Load(ctx, failingProvider{}, 42, nil); got != (Settings{}) {
+		t.Errorf("Load() after error = %+v, want defaults", got)
+	}
+}
@@ -2,18 +2,91 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// Article backends accepted in ARTICLE_BACKEND.
+const (
+	ArticleBackendTelegraph  = "telegraph"
+	ArticleBackendSelfHosted = "selfhosted"
+)
+
+// Translation provider names accepted in TRANSLATION_PROVIDERS.
+const (
+	TranslationProviderGoogle         = "google"
+	TranslationProviderLibreTranslate = "libretranslate"
+	TranslationProviderDeepL          = "deepl"
+)
+
+// RateLimit allows Count events every Per; a zero Count disables it.
+type RateLimit struct {
+	Count int
+	Per   time.Duration
+}
+
 type Config struct {
 	BotToken       string
 	Debug          bool
 	TwitterXAPIURL string
 	TelegramAPIURL string
 
+	// AdminIDs are the Telegram user IDs of the bot owners, allowed to run global commands.
+	AdminIDs []int64
+	// AllowlistMode serves only chats allowed by an admin with /allow.
+	AllowlistMode bool
+
+	// Rate limits for tweet links, commands, callbacks and inline queries,
+	// per user, per chat and across the whole bot.
+	RateLimitUser   RateLimit
+	RateLimitChat   RateLimit
+	RateLimitGlobal RateLimit
+
+	// StoragePath is the SQLite database with chats, settings and history; empty keeps them in memory.
+	StoragePath string
+	// RepostWindow is how long a tweet shared in a chat is answered with "already shared"; 0 disables it.
+	RepostWindow time.Duration
+	// FollowInterval is how often followed accounts are polled for new tweets; 0 disables /follow.
+	FollowInterval time.Duration
+
 	TelegraphAuthorName string
 	TelegraphAuthorURL  string
+	// TelegraphStoreFile persists the Telegraph accounts; empty keeps them in memory. Created pages are always kept in memory.
+	TelegraphStoreFile string
+	// TelegraphAccounts is the number of Telegraph accounts requests are spread across.
+	TelegraphAccounts     int
+	TelegraphMaxAttempts  int
+	TelegraphMaxFloodWait time.Duration
+	TelegraphWorkers      int
+	TelegraphMaxPending   int
+	// ArticleBackend selects where long tweets are published. With the telegraph backend
+	// the self-hosted server, when enabled, is used as a fallback.
+	ArticleBackend string
+	// ArticleServerAddr enables the self-hosted article server on this address.
+	ArticleServerAddr string
+	// ArticlePublicURL is the public base URL of the self-hosted article server.
+	ArticlePublicURL string
+	ArticleStoreDir  string
+	// ArticleRetention is how long self-hosted articles are kept; 0 keeps them forever.
+	ArticleRetention time.Duration
+
+	// ChainArticleThreshold is the chain length from which a chain is published
+	// as a single Telegraph article instead of separate messages; 0 disables it.
+	ChainArticleThreshold int
+
+	// TranslationProviders lists providers in fallback order.
+	TranslationProviders []string
+	// TranslationAPIURL overrides the Google Translate endpoint.
+	TranslationAPIURL    string
+	LibreTranslateURL    string
+	LibreTranslateAPIKey string
+	DeepLAPIURL          string
+	DeepLAuthKey         string
+	TranslationCacheSize int
+	TranslationCacheTTL  time.Duration
 }
 
 func Load() (Config, error) {
@@ -22,13 +95,77 @@ func Load() (Config, error) {
 
 	cfg := Config{
 		BotToken:       os.Getenv("BOT_TOKEN"),
-		Debug:          os.Getenv("DEBUG") == "true" || os.Getenv("DEBUG") == "True" || os.Getenv("DEBUG") == "1",
+		Debug:          parseBool(os.Getenv("DEBUG")),
 		TwitterXAPIURL: os.Getenv("TWITTERX_API_URL"),
 		TelegramAPIURL: telegramAPIURL,
+		AllowlistMode:  parseBool(os.Getenv("ALLOWLIST_MODE")),
+
+		StoragePath: strings.TrimSpace(os.Getenv("STORAGE_PATH")),
 
 		TelegraphAuthorName: "TwitterX",
 		TelegraphAuthorURL:  "https://t.me/twitter_x_bot",
+		TelegraphStoreFile:  strings.TrimSpace(os.Getenv("TELEGRAPH_STORE_FILE")),
+
+		ArticleBackend:    strings.ToLower(strings.TrimSpace(os.Getenv("ARTICLE_BACKEND"))),
+		ArticleServerAddr: strings.TrimSpace(os.Getenv("ARTICLE_SERVER_ADDR")),
+		ArticlePublicURL:  strings.TrimRight(strings.TrimSpace(os.Getenv("ARTICLE_PUBLIC_URL")), "/"),
+		ArticleStoreDir:   strings.TrimSpace(os.Getenv("ARTICLE_STORE_DIR")),
+
+		TranslationProviders: parseList(os.Getenv("TRANSLATION_PROVIDERS")),
+		TranslationAPIURL:    strings.TrimSpace(os.Getenv("TRANSLATION_API_URL")),
+		LibreTranslateURL:    strings.TrimSpace(os.Getenv("LIBRETRANSLATE_URL")),
+		LibreTranslateAPIKey: strings.TrimSpace(os.Getenv("LIBRETRANSLATE_API_KEY")),
+		DeepLAPIURL:          strings.TrimSpace(os.Getenv("DEEPL_API_URL")),
+		DeepLAuthKey:         strings.TrimSpace(os.Getenv("DEEPL_AUTH_KEY")),
+	}
+	if len(cfg.TranslationProviders) == 0 {
+		cfg.TranslationProviders = []string{TranslationProviderGoogle}
+	}
+
+	var err error
+	if cfg.AdminIDs, err = parseIDList("ADMIN_IDS"); err != nil {
+		return Config{}, err
+	}
+	if cfg.AllowlistMode && len(cfg.AdminIDs) == 0 {
+		return Config{}, errors.New("ALLOWLIST_MODE requires ADMIN_IDS to manage the allowlist")
+	}
+	if err := cfg.loadRateLimits(); err != nil {
+		return Config{}, err
+	}
+	if err := cfg.loadTelegraph(); err != nil {
+		return Config{}, err
+	}
+	if err := cfg.loadArticles(); err != nil {
+		return Config{}, err
 	}
+	if cfg.ChainArticleThreshold, err = parseInt("CHAIN_ARTICLE_THRESHOLD", 5); err != nil {
+		return Config{}, err
+	}
+	if cfg.ChainArticleThreshold < 0 {
+		return Config{}, errors.New("CHAIN_ARTICLE_THRESHOLD must not be negative")
+	}
+	if cfg.RepostWindow, err = parseDuration("REPOST_WINDOW", 24*time.Hour); err != nil {
+		return Config{}, err
+	}
+	if cfg.RepostWindow < 0 {
+		return Config{}, errors.New("REPOST_WINDOW must not be negative")
+	}
+	if cfg.FollowInterval, err = parseDuration("FOLLOW_INTERVAL", 5*time.Minute); err != nil {
+		return Config{}, err
+	}
+	if cfg.FollowInterval < 0 || (cfg.FollowInterval > 0 && cfg.FollowInterval < time.Minute) {
+		return Config{}, errors.New("FOLLOW_INTERVAL must be 0 or at least 1m")
+	}
+	if cfg.TranslationCacheSize, err = parseInt("TRANSLATION_CACHE_SIZE", 1000); err != nil {
+		return Config{}, err
+	}
+	if cfg.TranslationCacheTTL, err = parseDuration("TRANSLATION_CACHE_TTL", 24*time.Hour); err != nil {
+		return Config{}, err
+	}
+	if err := cfg.validateTranslation(); err != nil {
+		return Config{}, err
+	}
+
 	if cfg.TwitterXAPIURL == "" {
 		return Config{}, errors.New("TwitterXAPIURL is required")
 	}
@@ -37,3 +174,177 @@ func Load() (Config, error) {
 	}
 	return cfg, nil
 }
+
+func (c *Config) loadRateLimits() error {
+	var err error
+	if c.RateLimitUser, err = parseRateLimit("RATE_LIMIT_USER", RateLimit{Count: 20, Per: time.Minute}); err != nil {
+		return err
+	}
+	if c.RateLimitChat, err = parseRateLimit("RATE_LIMIT_CHAT", RateLimit{Count: 30, Per: time.Minute}); err != nil {
+		return err
+	}
+	if c.RateLimitGlobal, err = parseRateLimit("RATE_LIMIT_GLOBAL", RateLimit{Count: 25, Per: time.Second}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Config) loadTelegraph() error {
+	var err error
+	if c.TelegraphAccounts, err = parseInt("TELEGRAPH_ACCOUNTS", 1); err != nil {
+		return err
+	}
+	if c.TelegraphMaxAttempts, err = parseInt("TELEGRAPH_MAX_ATTEMPTS", 3); err != nil {
+		return err
+	}
+	if c.TelegraphMaxFloodWait, err = parseDuration("TELEGRAPH_MAX_FLOOD_WAIT", 10*time.Second); err != nil {
+		return err
+	}
+	if c.TelegraphWorkers, err = parseInt("TELEGRAPH_WORKERS", 4); err != nil {
+		return err
+	}
+	if c.TelegraphMaxPending, err = parseInt("TELEGRAPH_MAX_PENDING", 32); err != nil {
+		return err
+	}
+	if c.TelegraphAccounts < 1 {
+		return errors.New("TELEGRAPH_ACCOUNTS must be at least 1")
+	}
+	if c.TelegraphMaxAttempts < 1 {
+		return errors.New("TELEGRAPH_MAX_ATTEMPTS must be at least 1")
+	}
+	if c.TelegraphWorkers < 0 || c.TelegraphMaxPending < 0 {
+		return errors.New("TELEGRAPH_WORKERS and TELEGRAPH_MAX_PENDING must not be negative")
+	}
+	return nil
+}
+
+func (c *Config) loadArticles() error {
+	if c.ArticleBackend == "" {
+		c.ArticleBackend = ArticleBackendTelegraph
+	}
+	if c.ArticleStoreDir == "" {
+		c.ArticleStoreDir = "data/articles"
+	}
+
+	var err error
+	if c.ArticleRetention, err = parseDuration("ARTICLE_RETENTION", 30*24*time.Hour); err != nil {
+		return err
+	}
+
+	switch c.ArticleBackend {
+	case ArticleBackendTelegraph:
+	case ArticleBackendSelfHosted:
+		if c.ArticleServerAddr == "" {
+			return errors.New("ARTICLE_SERVER_ADDR is required for the selfhosted article backend")
+		}
+	default:
+		return fmt.Errorf("unknown article backend %q", c.ArticleBackend)
+	}
+	if c.ArticleServerAddr != "" && c.ArticlePublicURL == "" {
+		return errors.New("ARTICLE_PUBLIC_URL is required when ARTICLE_SERVER_ADDR is set")
+	}
+	return nil
+}
+
+func (c Config) validateTranslation() error {
+	for _, provider := range c.TranslationProviders {
+		switch provider {
+		case TranslationProviderGoogle:
+		case TranslationProviderLibreTranslate:
+			if c.LibreTranslateURL == "" {
+				return errors.New("LIBRETRANSLATE_URL is required for the libretranslate provider")
+			}
+		case TranslationProviderDeepL:
+			if c.DeepLAuthKey == "" {
+				return errors.New("DEEPL_AUTH_KEY is required for the deepl provider")
+			}
+		default:
+			return fmt.Errorf("unknown translation provider %q", provider)
+		}
+	}
+	return nil
+}
+
+// parseList splits a comma-separated value into lower-cased, trimmed, non-empty items.
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseBool reports whether value is "true", "True" or "1", the form used for DEBUG.
+func parseBool(value string) bool {
+	value = strings.TrimSpace(value)
+	return value == "true" || value == "True" || value == "1"
+}
+
+// parseIDList parses a comma-separated list of Telegram IDs.
+func parseIDList(key string) ([]int64, error) {
+	var ids []int64
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(item, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid ID %q", key, item)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// parseRateLimit parses "<count>/<duration>" such as "20/1m"; "off" or "0" disable the limit.
+func parseRateLimit(key string, fallback RateLimit) (RateLimit, error) {
+	value := strings.ToLower(strings.TrimSpace(os.Getenv(key)))
+	switch value {
+	case "":
+		return fallback, nil
+	case "off", "0":
+		return RateLimit{}, nil
+	}
+
+	countPart, perPart, ok := strings.Cut(value, "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("%s: invalid rate limit %q, want <count>/<duration>", key, value)
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(countPart))
+	if err != nil || count < 0 {
+		return RateLimit{}, fmt.Errorf("%s: invalid rate limit count %q", key, countPart)
+	}
+	per, err := time.ParseDuration(strings.TrimSpace(perPart))
+	if err != nil || per <= 0 {
+		return RateLimit{}, fmt.Errorf("%s: invalid rate limit period %q", key, perPart)
+	}
+	return RateLimit{Count: count, Per: per}, nil
+}
+
+func parseInt(key string, fallback int) (int, error) {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid integer %q", key, value)
+	}
+	return n, nil
+}
+
+func parseDuration(key string, fallback time.Duration) (time.Duration, error) {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid duration %q", key, value)
+	}
+	return d, nil
+}
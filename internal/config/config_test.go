@@ -1,6 +1,9 @@
 package config
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestLoad_TelegramAPIURL_DefaultEmpty(t *testing.T) {
 	t.Setenv("BOT_TOKEN", "123:ABC")
@@ -44,3 +47,324 @@ func TestLoad_BotTokenRequired(t *testing.T) {
 		t.Fatalf("expected error")
 	}
 }
+
+func TestLoad_TranslationAPIURL(t *testing.T) {
+	t.Setenv("BOT_TOKEN", "123:ABC")
+	t.Setenv("TWITTERX_API_URL", "http://localhost:8080")
+	t.Setenv("TRANSLATION_API_URL", "  http://translate.local/api  ")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.TranslationAPIURL != "http://translate.local/api" {
+		t.Fatalf("TranslationAPIURL = %q, want %q", cfg.TranslationAPIURL, "http://translate.local/api")
+	}
+}
+
+func TestLoad_TranslationProviders(t *testing.T) {
+	t.Setenv("BOT_TOKEN", "123:ABC")
+	t.Setenv("TWITTERX_API_URL", "http://localhost:8080")
+	t.Setenv("TRANSLATION_PROVIDERS", " LibreTranslate, deepl ,google")
+	t.Setenv("LIBRETRANSLATE_URL", "http://libretranslate:5000")
+	t.Setenv("DEEPL_AUTH_KEY", "key")
+	t.Setenv("TRANSLATION_CACHE_SIZE", "50")
+	t.Setenv("TRANSLATION_CACHE_TTL", "1h")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := []string{TranslationProviderLibreTranslate, TranslationProviderDeepL, TranslationProviderGoogle}
+	if len(cfg.TranslationProviders) != len(want) {
+		t.Fatalf("TranslationProviders = %v, want %v", cfg.TranslationProviders, want)
+	}
+	for i := range want {
+		if cfg.TranslationProviders[i] != want[i] {
+			t.Fatalf("TranslationProviders = %v, want %v", cfg.TranslationProviders, want)
+		}
+	}
+	if cfg.TranslationCacheSize != 50 {
+		t.Fatalf("TranslationCacheSize = %d, want 50", cfg.TranslationCacheSize)
+	}
+	if cfg.TranslationCacheTTL != time.Hour {
+		t.Fatalf("TranslationCacheTTL = %v, want 1h", cfg.TranslationCacheTTL)
+	}
+}
+
+func TestLoad_TranslationProvidersDefault(t *testing.T) {
+	t.Setenv("BOT_TOKEN", "123:ABC")
+	t.Setenv("TWITTERX_API_URL", "http://localhost:8080")
+	t.Setenv("TRANSLATION_PROVIDERS", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(cfg.TranslationProviders) != 1 || cfg.TranslationProviders[0] != TranslationProviderGoogle {
+		t.Fatalf("TranslationProviders = %v, want [google]", cfg.TranslationProviders)
+	}
+}
+
+func TestLoad_TranslationProvidersInvalid(t *testing.T) {
+	tests := map[string]map[string]string{
+		"unknown provider":     {"TRANSLATION_PROVIDERS": "bing"},
+		"libre without url":    {"TRANSLATION_PROVIDERS": "libretranslate", "LIBRETRANSLATE_URL": ""},
+		"deepl without key":    {"TRANSLATION_PROVIDERS": "deepl", "DEEPL_AUTH_KEY": ""},
+		"invalid cache size":   {"TRANSLATION_CACHE_SIZE": "many"},
+		"invalid cache period": {"TRANSLATION_CACHE_TTL": "forever"},
+	}
+
+	for name, env := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("BOT_TOKEN", "123:ABC")
+			t.Setenv("TWITTERX_API_URL", "http://localhost:8080")
+			for k, v := range env {
+				t.Setenv(k, v)
+			}
+
+			if _, err := Load(); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
+
+func TestLoad_ChainArticleThreshold(t *testing.T) {
+	t.Setenv("BOT_TOKEN", "123:ABC")
+	t.Setenv("TWITTERX_API_URL", "http://localhost:8080")
+
+	t.Setenv("CHAIN_ARTICLE_THRESHOLD", "")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.ChainArticleThreshold != 5 {
+		t.Fatalf("ChainArticleThreshold = %d, want default 5", cfg.ChainArticleThreshold)
+	}
+
+	t.Setenv("CHAIN_ARTICLE_THRESHOLD", "0")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.ChainArticleThreshold != 0 {
+		t.Fatalf("ChainArticleThreshold = %d, want 0", cfg.ChainArticleThreshold)
+	}
+
+	t.Setenv("CHAIN_ARTICLE_THRESHOLD", "-1")
+	if _, err := Load(); err == nil {
+		t.Fatalf("Load() error = nil, want error for negative threshold")
+	}
+}
+
+func TestLoad_TelegraphReliability(t *testing.T) {
+	t.Setenv("BOT_TOKEN", "123:ABC")
+	t.Setenv("TWITTERX_API_URL", "http://localhost:8080")
+	t.Setenv("TELEGRAPH_ACCOUNTS", "3")
+	t.Setenv("TELEGRAPH_MAX_FLOOD_WAIT", "30s")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.TelegraphAccounts != 3 {
+		t.Errorf("TelegraphAccounts = %d, want 3", cfg.TelegraphAccounts)
+	}
+	if cfg.TelegraphMaxFloodWait != 30*time.Second {
+		t.Errorf("TelegraphMaxFloodWait = %v, want 30s", cfg.TelegraphMaxFloodWait)
+	}
+	if cfg.TelegraphMaxAttempts != 3 || cfg.TelegraphWorkers != 4 || cfg.TelegraphMaxPending != 32 {
+		t.Errorf("unexpected defaults: attempts=%d workers=%d pending=%d", cfg.TelegraphMaxAttempts, cfg.TelegraphWorkers, cfg.TelegraphMaxPending)
+	}
+
+	t.Setenv("TELEGRAPH_ACCOUNTS", "0")
+	if _, err := Load(); err == nil {
+		t.Fatalf("Load() error = nil, want error for zero accounts")
+	}
+}
+
+func TestLoad_Articles(t *testing.T) {
+	t.Setenv("BOT_TOKEN", "123:ABC")
+	t.Setenv("TWITTERX_API_URL", "http://localhost:8080")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.ArticleBackend != ArticleBackendTelegraph || cfg.ArticleServerAddr != "" {
+		t.Fatalf("unexpected defaults: backend=%q addr=%q", cfg.ArticleBackend, cfg.ArticleServerAddr)
+	}
+
+	t.Setenv("ARTICLE_BACKEND", "selfhosted")
+	t.Setenv("ARTICLE_SERVER_ADDR", ":8081")
+	t.Setenv("ARTICLE_PUBLIC_URL", "https://articles.example.com/")
+	t.Setenv("ARTICLE_RETENTION", "48h")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.ArticlePublicURL != "https://articles.example.com" {
+		t.Errorf("ArticlePublicURL = %q, want trailing slash trimmed", cfg.ArticlePublicURL)
+	}
+	if cfg.ArticleRetention != 48*time.Hour {
+		t.Errorf("ArticleRetention = %v, want 48h", cfg.ArticleRetention)
+	}
+}
+
+func TestLoad_ArticlesInvalid(t *testing.T) {
+	tests := map[string]map[string]string{
+		"unknown backend":         {"ARTICLE_BACKEND": "medium"},
+		"selfhosted without addr": {"ARTICLE_BACKEND": "selfhosted", "ARTICLE_SERVER_ADDR": ""},
+		"addr without public url": {"ARTICLE_SERVER_ADDR": ":8081", "ARTICLE_PUBLIC_URL": ""},
+		"invalid retention":       {"ARTICLE_RETENTION": "a month"},
+	}
+
+	for name, env := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("BOT_TOKEN", "123:ABC")
+			t.Setenv("TWITTERX_API_URL", "http://localhost:8080")
+			for k, v := range env {
+				t.Setenv(k, v)
+			}
+			if _, err := Load(); err == nil {
+				t.Fatal("Load() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestLoad_StoragePath(t *testing.T) {
+	t.Setenv("BOT_TOKEN", "123:ABC")
+	t.Setenv("TWITTERX_API_URL", "http://localhost:8080")
+	t.Setenv("STORAGE_PATH", "  /data/bot.db ")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.StoragePath != "/data/bot.db" {
+		t.Errorf("StoragePath = %q, want %q", cfg.StoragePath, "/data/bot.db")
+	}
+}
+
+func TestLoad_RepostWindow(t *testing.T) {
+	t.Setenv("BOT_TOKEN", "123:ABC")
+	t.Setenv("TWITTERX_API_URL", "http://localhost:8080")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.RepostWindow != 24*time.Hour {
+		t.Errorf("RepostWindow = %v, want 24h default", cfg.RepostWindow)
+	}
+
+	t.Setenv("REPOST_WINDOW", "0")
+	if cfg, err = Load(); err != nil || cfg.RepostWindow != 0 {
+		t.Errorf("Load() = %v, %v; want disabled window", cfg.RepostWindow, err)
+	}
+
+	t.Setenv("REPOST_WINDOW", "-1h")
+	if _, err := Load(); err == nil {
+		t.Error("Load() error = nil for negative window")
+	}
+}
+
+func TestLoad_AdminIDs(t *testing.T) {
+	t.Setenv("BOT_TOKEN", "123:ABC")
+	t.Setenv("TWITTERX_API_URL", "http://localhost:8080")
+	t.Setenv("ADMIN_IDS", " 42, 1001 ,")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.AdminIDs) != 2 || cfg.AdminIDs[0] != 42 || cfg.AdminIDs[1] != 1001 {
+		t.Errorf("AdminIDs = %v, want [42 1001]", cfg.AdminIDs)
+	}
+
+	t.Setenv("ADMIN_IDS", "42,abc")
+	if _, err := Load(); err == nil {
+		t.Error("Load() error = nil for invalid admin ID")
+	}
+}
+
+func TestLoad_AllowlistMode(t *testing.T) {
+	t.Setenv("BOT_TOKEN", "123:ABC")
+	t.Setenv("TWITTERX_API_URL", "http://localhost:8080")
+	t.Setenv("ALLOWLIST_MODE", "true")
+
+	if _, err := Load(); err == nil {
+		t.Error("Load() error = nil for allowlist mode without admins")
+	}
+
+	t.Setenv("ADMIN_IDS", "42")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.AllowlistMode {
+		t.Error("AllowlistMode = false, want true")
+	}
+}
+
+func TestLoad_RateLimits(t *testing.T) {
+	t.Setenv("BOT_TOKEN", "123:ABC")
+	t.Setenv("TWITTERX_API_URL", "http://localhost:8080")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.RateLimitUser != (RateLimit{Count: 20, Per: time.Minute}) || cfg.RateLimitGlobal != (RateLimit{Count: 25, Per: time.Second}) {
+		t.Errorf("defaults = %+v, %+v", cfg.RateLimitUser, cfg.RateLimitGlobal)
+	}
+
+	t.Setenv("RATE_LIMIT_USER", "5/30s")
+	t.Setenv("RATE_LIMIT_CHAT", "off")
+	if cfg, err = Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.RateLimitUser != (RateLimit{Count: 5, Per: 30 * time.Second}) {
+		t.Errorf("RateLimitUser = %+v, want 5/30s", cfg.RateLimitUser)
+	}
+	if cfg.RateLimitChat != (RateLimit{}) {
+		t.Errorf("RateLimitChat = %+v, want disabled", cfg.RateLimitChat)
+	}
+
+	for _, value := range []string{"5", "x/1m", "5/soon", "5/0s", "-1/1m"} {
+		t.Setenv("RATE_LIMIT_GLOBAL", value)
+		if _, err := Load(); err == nil {
+			t.Errorf("Load() error = nil for RATE_LIMIT_GLOBAL=%q", value)
+		}
+	}
+}
+
+func TestLoad_FollowInterval(t *testing.T) {
+	t.Setenv("BOT_TOKEN", "123:ABC")
+	t.Setenv("TWITTERX_API_URL", "http://localhost:8080")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.FollowInterval != 5*time.Minute {
+		t.Errorf("FollowInterval = %v, want 5m", cfg.FollowInterval)
+	}
+
+	t.Setenv("FOLLOW_INTERVAL", "0")
+	if cfg, err = Load(); err != nil || cfg.FollowInterval != 0 {
+		t.Errorf("Load() = %v, %v; want disabled", cfg.FollowInterval, err)
+	}
+
+	for _, value := range []string{"10s", "-1m", "soon"} {
+		t.Setenv("FOLLOW_INTERVAL", value)
+		if _, err := Load(); err == nil {
+			t.Errorf("Load() error = nil for FOLLOW_INTERVAL=%q", value)
+		}
+	}
+}
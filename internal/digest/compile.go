@@ -0,0 +1,122 @@
+package digest
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"twitterx-bot/internal/storage"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+const fetchTimeout = 10 * time.Second
+
+// TweetFetcher fetches the current version of a tweet for its text and engagement.
+type TweetFetcher interface {
+	GetTweet(ctx context.Context, username, tweetID string) (*twitterxapi.Tweet, error)
+}
+
+// Item is a tweet shared in the chat during the digest period.
+type Item struct {
+	TweetID string
+	Author  string
+	// Shares counts how many times the bot sent the tweet to the chat.
+	Shares int
+	// MessageID is the first bot message with the tweet in the period.
+	MessageID     int64
+	FirstSharedAt time.Time
+	// Tweet is the refetched tweet, nil when it could not be loaded.
+	Tweet *twitterxapi.Tweet
+}
+
+// Engagement weighs the tweet's likes, retweets and replies; 0 when the tweet is unknown.
+func (i Item) Engagement() int {
+	if i.Tweet == nil {
+		return 0
+	}
+	return i.Tweet.Likes + 2*i.Tweet.Retweets + i.Tweet.Replies
+}
+
+// Result is a compiled digest.
+type Result struct {
+	Digest       storage.Digest
+	Since, Until time.Time
+	// Shared is the number of tweets sent in the period, counting repeats.
+	Shared int
+	// Items are the top tweets, most shared first, then by engagement.
+	Items []Item
+	// More is the number of other distinct tweets shared in the period.
+	More int
+}
+
+// Compile collects the tweets sent to the chat in [since, until) and ranks them by how often
+// they were shared and by engagement. Twice top tweets are refetched to rank the top ones.
+func Compile(ctx context.Context, history storage.HistoryRepository, fetcher TweetFetcher, d storage.Digest, since, until time.Time, top int) (Result, error) {
+	entries, err := history.List(ctx, storage.HistoryFilter{ChatID: d.ChatID, Since: since})
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{Digest: d, Since: since, Until: until}
+	byTweet := make(map[string]*Item)
+	var items []*Item
+	for _, entry := range entries {
+		if !entry.SentAt.Before(until) || entry.TweetID == "" {
+			continue
+		}
+		result.Shared++
+		item, ok := byTweet[entry.TweetID]
+		if !ok {
+			item = &Item{TweetID: entry.TweetID, Author: entry.Author}
+			byTweet[entry.TweetID] = item
+			items = append(items, item)
+		}
+		item.Shares++
+		// History is listed newest first, so the last entry seen is the first share
+		item.MessageID = entry.MessageID
+		item.FirstSharedAt = entry.SentAt
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Shares != items[j].Shares {
+			return items[i].Shares > items[j].Shares
+		}
+		return items[i].FirstSharedAt.Before(items[j].FirstSharedAt)
+	})
+
+	candidates := items
+	if len(candidates) > 2*top {
+		candidates = candidates[:2*top]
+	}
+	for _, item := range candidates {
+		item.Tweet = fetch(ctx, fetcher, item)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Shares != candidates[j].Shares {
+			return candidates[i].Shares > candidates[j].Shares
+		}
+		return candidates[i].Engagement() > candidates[j].Engagement()
+	})
+
+	if len(candidates) > top {
+		candidates = candidates[:top]
+	}
+	for _, item := range candidates {
+		result.Items = append(result.Items, *item)
+	}
+	result.More = len(items) - len(result.Items)
+	return result, nil
+}
+
+func fetch(ctx context.Context, fetcher TweetFetcher, item *Item) *twitterxapi.Tweet {
+	if fetcher == nil || item.Author == "" {
+		return nil
+	}
+	fetchCtx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+	tw, err := fetcher.GetTweet(fetchCtx, item.Author, item.TweetID)
+	if err != nil {
+		return nil
+	}
+	return tw
+}
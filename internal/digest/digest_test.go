@@ -0,0 +1,189 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/storage"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+func at(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestNext(t *testing.T) {
+	daily := storage.Digest{Frequency: storage.DigestDaily, Minute: 9 * 60}
+	// 2026-10-14 is a Wednesday
+	weekly := storage.Digest{Frequency: storage.DigestWeekly, Weekday: time.Monday, Minute: 9 * 60}
+
+	tests := []struct {
+		name  string
+		d     storage.Digest
+		after string
+		want  string
+	}{
+		{"daily later today", daily, "2026-10-14T08:00:00Z", "2026-10-14T09:00:00Z"},
+		{"daily exactly at time", daily, "2026-10-14T09:00:00Z", "2026-10-15T09:00:00Z"},
+		{"daily tomorrow", daily, "2026-10-14T10:00:00Z", "2026-10-15T09:00:00Z"},
+		{"weekly next monday", weekly, "2026-10-14T10:00:00Z", "2026-10-19T09:00:00Z"},
+		{"weekly same day later", weekly, "2026-10-19T08:59:00Z", "2026-10-19T09:00:00Z"},
+		{"weekly same day passed", weekly, "2026-10-19T09:01:00Z", "2026-10-26T09:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Next(tt.d, at(tt.after)); !got.Equal(at(tt.want)) {
+				t.Fatalf("Next() = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDue(t *testing.T) {
+	d := storage.Digest{Frequency: storage.DigestDaily, Minute: 9 * 60, UpdatedAt: at("2026-10-14T08:00:00Z")}
+
+	if Due(d, at("2026-10-14T08:59:00Z")) {
+		t.Fatal("Due() before the first schedule = true")
+	}
+	if !Due(d, at("2026-10-14T09:00:00Z")) {
+		t.Fatal("Due() at the schedule = false")
+	}
+	d.LastSentAt = at("2026-10-14T09:00:30Z")
+	if Due(d, at("2026-10-14T12:00:00Z")) {
+		t.Fatal("Due() after sending = true")
+	}
+	if !Due(d, at("2026-10-16T12:00:00Z")) {
+		t.Fatal("Due() after a missed day = false")
+	}
+}
+
+func TestSince(t *testing.T) {
+	now := at("2026-10-14T09:00:00Z")
+	d := storage.Digest{Frequency: storage.DigestWeekly}
+	if got := Since(d, now); !got.Equal(now.Add(-7 * 24 * time.Hour)) {
+		t.Fatalf("Since() without a previous digest = %v, want a week back", got)
+	}
+	d.LastSentAt = at("2026-10-12T09:00:00Z")
+	if got := Since(d, now); !got.Equal(d.LastSentAt) {
+		t.Fatalf("Since() = %v, want the last digest", got)
+	}
+}
+
+type fakeFetcher struct {
+	tweets map[string]*twitterxapi.Tweet
+}
+
+func (f fakeFetcher) GetTweet(_ context.Context, _, tweetID string) (*twitterxapi.Tweet, error) {
+	if tw, ok := f.tweets[tweetID]; ok {
+		return tw, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func share(t *testing.T, store storage.Storage, chatID, messageID int64, tweetID string, sentAt time.Time) {
+	t.Helper()
+	err := store.History().Add(context.Background(), storage.HistoryEntry{
+		ChatID: chatID, MessageID: messageID, TweetID: tweetID, Author: "nasa", SentAt: sentAt,
+	})
+	if err != nil {
+		t.Fatalf("History().Add() error = %v", err)
+	}
+}
+
+func TestCompile_RanksBySharesThenEngagement(t *testing.T) {
+	store := storage.NewMemory()
+	base := at("2026-10-14T00:00:00Z")
+	const chatID = -100
+
+	share(t, store, chatID, 1, "old", base.Add(-time.Hour))
+	share(t, store, chatID, 10, "a", base.Add(time.Hour))
+	share(t, store, chatID, 11, "b", base.Add(2*time.Hour))
+	share(t, store, chatID, 12, "c", base.Add(3*time.Hour))
+	share(t, store, chatID, 13, "c", base.Add(4*time.Hour))
+	share(t, store, -200, 20, "a", base.Add(time.Hour))
+	share(t, store, chatID, 14, "late", base.Add(30*time.Hour))
+
+	fetcher := fakeFetcher{tweets: map[string]*twitterxapi.Tweet{
+		"a": {ID: "a", Likes: 10},
+		"b": {ID: "b", Likes: 5, Retweets: 10},
+		"c": {ID: "c", Likes: 1},
+	}}
+
+	d := storage.Digest{ChatID: chatID, Frequency: storage.DigestDaily}
+	result, err := Compile(context.Background(), store.History(), fetcher, d, base, base.Add(24*time.Hour), 2)
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	if result.Shared != 4 {
+		t.Fatalf("Shared = %d, want 4", result.Shared)
+	}
+	if len(result.Items) != 2 || result.More != 1 {
+		t.Fatalf("items = %d, more = %d, want 2 and 1", len(result.Items), result.More)
+	}
+	first, second := result.Items[0], result.Items[1]
+	if first.TweetID != "c" || first.Shares != 2 || first.MessageID != 12 {
+		t.Fatalf("first = %+v, want tweet c shared twice, first in message 12", first)
+	}
+	if second.TweetID != "b" || second.Tweet == nil {
+		t.Fatalf("second = %+v, want tweet b ranked by engagement", second)
+	}
+}
+
+type fakePublisher struct {
+	results []Result
+}
+
+func (f *fakePublisher) Publish(_ context.Context, result Result) error {
+	f.results = append(f.results, result)
+	return nil
+}
+
+func TestScheduler_RunDue(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemory()
+	now := at("2026-10-14T09:00:00Z")
+
+	digests := []storage.Digest{
+		{ChatID: -1, Frequency: storage.DigestDaily, Minute: 9 * 60, UpdatedAt: now.Add(-time.Hour)},
+		{ChatID: -2, Frequency: storage.DigestDaily, Minute: 9 * 60, UpdatedAt: now.Add(-time.Hour)},
+		{ChatID: -3, Frequency: storage.DigestDaily, Minute: 10 * 60, UpdatedAt: now.Add(-time.Hour)},
+	}
+	for _, d := range digests {
+		if err := store.Digests().Set(ctx, d); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+	share(t, store, -1, 5, "a", now.Add(-2*time.Hour))
+	share(t, store, -3, 6, "b", now.Add(-2*time.Hour))
+
+	publisher := &fakePublisher{}
+	s := NewScheduler(logger.New(true), store.Digests(), store.History(), fakeFetcher{}, publisher)
+	s.now = func() time.Time { return now }
+	s.RunDue(ctx)
+
+	if len(publisher.results) != 1 || publisher.results[0].Digest.ChatID != -1 {
+		t.Fatalf("published = %+v, want only chat -1", publisher.results)
+	}
+	for _, chatID := range []int64{-1, -2} {
+		d, _, _ := store.Digests().Get(ctx, chatID)
+		if !d.LastSentAt.Equal(now) {
+			t.Fatalf("chat %d LastSentAt = %v, want %v", chatID, d.LastSentAt, now)
+		}
+	}
+	if d, _, _ := store.Digests().Get(ctx, -3); !d.LastSentAt.IsZero() {
+		t.Fatalf("chat -3 LastSentAt = %v, want not sent yet", d.LastSentAt)
+	}
+
+	s.RunDue(ctx)
+	if len(publisher.results) != 1 {
+		t.Fatalf("published = %d after a second run, want 1", len(publisher.results))
+	}
+}
@@ -0,0 +1,50 @@
+// Package digest compiles scheduled summaries of the tweets shared in a chat.
+package digest
+
+import (
+	"time"
+
+	"twitterx-bot/internal/storage"
+)
+
+// Period returns how far back a digest of the given frequency looks.
+func Period(frequency storage.DigestFrequency) time.Duration {
+	if frequency == storage.DigestWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// Next returns the first scheduled time of the digest strictly after t, in UTC.
+func Next(d storage.Digest, t time.Time) time.Time {
+	t = t.UTC()
+	next := time.Date(t.Year(), t.Month(), t.Day(), 0, d.Minute, 0, 0, time.UTC)
+	step := 1
+	if d.Frequency == storage.DigestWeekly {
+		next = next.AddDate(0, 0, (int(d.Weekday)-int(next.Weekday())+7)%7)
+		step = 7
+	}
+	if !next.After(t) {
+		next = next.AddDate(0, 0, step)
+	}
+	return next
+}
+
+// Due reports whether a scheduled time has passed since the digest was last sent or changed.
+func Due(d storage.Digest, now time.Time) bool {
+	anchor := d.UpdatedAt
+	if d.LastSentAt.After(anchor) {
+		anchor = d.LastSentAt
+	}
+	return !Next(d, anchor).After(now)
+}
+
+// Since returns the start of the period the next digest covers: the last digest,
+// but no more than one period back.
+func Since(d storage.Digest, now time.Time) time.Time {
+	since := now.Add(-Period(d.Frequency))
+	if d.LastSentAt.After(since) {
+		return d.LastSentAt
+	}
+	return since
+}
@@ -0,0 +1,110 @@
+package digest
+
+import (
+	"context"
+	"time"
+
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/storage"
+)
+
+const (
+	// DefaultTop is the number of tweets listed in a digest.
+	DefaultTop = 10
+
+	checkInterval = time.Minute
+	sendTimeout   = 2 * time.Minute
+)
+
+// Publisher posts a compiled digest to its chat.
+type Publisher interface {
+	Publish(ctx context.Context, result Result) error
+}
+
+// Scheduler posts the digests of all chats when they are due.
+// The zero value is not usable; create it with NewScheduler.
+type Scheduler struct {
+	log       *logger.Logger
+	digests   storage.DigestRepository
+	history   storage.HistoryRepository
+	fetcher   TweetFetcher
+	publisher Publisher
+	top       int
+	now       func() time.Time
+}
+
+// NewScheduler creates a scheduler listing DefaultTop tweets per digest.
+func NewScheduler(log *logger.Logger, digests storage.DigestRepository, history storage.HistoryRepository, fetcher TweetFetcher, publisher Publisher) *Scheduler {
+	return &Scheduler{
+		log:       log,
+		digests:   digests,
+		history:   history,
+		fetcher:   fetcher,
+		publisher: publisher,
+		top:       DefaultTop,
+		now:       time.Now,
+	}
+}
+
+// Run checks for due digests every minute until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		s.RunDue(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue compiles and posts every digest that is due. A period without shared tweets
+// posts nothing. Missed schedules (for example while the bot was down) produce one digest.
+func (s *Scheduler) RunDue(ctx context.Context) {
+	log := s.log.With("component", "digest")
+
+	digests, err := s.digests.List(ctx)
+	if err != nil {
+		log.Error("list digests failed", "err", err)
+		return
+	}
+
+	for _, d := range digests {
+		now := s.now()
+		if ctx.Err() != nil || !Due(d, now) {
+			continue
+		}
+		sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+		s.send(sendCtx, d, now)
+		cancel()
+	}
+}
+
+func (s *Scheduler) send(ctx context.Context, d storage.Digest, now time.Time) {
+	log := s.log.With("component", "digest", "chat_id", d.ChatID, "frequency", d.Frequency)
+
+	result, err := Compile(ctx, s.history, s.fetcher, d, Since(d, now), now, s.top)
+	if err != nil {
+		// Not marked as sent, so the digest is retried on the next check
+		log.Error("compile digest failed", "err", err)
+		return
+	}
+
+	if result.Shared > 0 {
+		// A failed post is not retried: the chat may be gone and would be retried forever
+		if err := s.publisher.Publish(ctx, result); err != nil {
+			log.Warn("publish digest failed", "err", err)
+		} else {
+			log.Info("digest posted", "shared", result.Shared, "items", len(result.Items))
+		}
+	} else {
+		log.Debug("digest skipped: nothing shared")
+	}
+
+	if err := s.digests.MarkSent(ctx, d.ChatID, now); err != nil {
+		log.Error("mark digest sent failed", "err", err)
+	}
+}
@@ -0,0 +1,226 @@
+// Package export renders tweets and their threads as self-contained Markdown, HTML and JSON
+// documents for archiving.
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"twitterx-bot/internal/chain"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+var (
+	ErrFetchTweet    = errors.New("fetch tweet")
+	ErrTweetNotFound = errors.New("tweet not found")
+	ErrBuildChain    = errors.New("build chain")
+)
+
+// twitterEpoch is the Unix time in milliseconds tweet IDs count from.
+const twitterEpoch = 1288834974657
+
+// TweetFetcher fetches tweets by username and tweet ID.
+type TweetFetcher interface {
+	GetTweet(ctx context.Context, username, tweetID string) (*twitterxapi.Tweet, error)
+}
+
+// Document is an exported tweet or thread.
+type Document struct {
+	FileName string
+	Format   Format
+	Data     []byte
+	// Tweets is the number of tweets in the thread.
+	Tweets int
+	// Author is the screen name of the requested tweet's author.
+	Author string
+}
+
+// Exporter fetches a tweet with its thread and renders it as a document.
+type Exporter struct {
+	fetcher TweetFetcher
+	images  ImageFetcher
+	now     func() time.Time
+}
+
+// Option configures optional Exporter behavior.
+type Option func(*Exporter)
+
+// WithImages embeds the photos of HTML exports as base64 data, so the file works offline.
+// Without it, photos are linked.
+func WithImages(images ImageFetcher) Option {
+	return func(e *Exporter) {
+		e.images = images
+	}
+}
+
+// New creates an Exporter fetching tweets with fetcher.
+func New(fetcher TweetFetcher, opts ...Option) *Exporter {
+	e := &Exporter{fetcher: fetcher, now: time.Now}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Export fetches the tweet, builds its thread with chain.BuildChain and renders it in the format.
+func (e *Exporter) Export(ctx context.Context, username, tweetID string, format Format) (Document, error) {
+	tw, err := e.fetcher.GetTweet(ctx, username, tweetID)
+	if err != nil {
+		return Document{}, fmt.Errorf("%w: %v", ErrFetchTweet, err)
+	}
+	if tw == nil {
+		return Document{}, ErrTweetNotFound
+	}
+
+	items, err := chain.BuildChain(ctx, e.fetcher, tw)
+	if err != nil {
+		return Document{}, fmt.Errorf("%w: %v", ErrBuildChain, err)
+	}
+
+	doc := newDocument(items, e.now())
+	var data []byte
+	switch format {
+	case Markdown:
+		data = renderMarkdown(doc)
+	case HTML:
+		data = renderHTML(ctx, doc, e.images)
+	case JSON:
+		if data, err = renderJSON(doc); err != nil {
+			return Document{}, fmt.Errorf("render json: %w", err)
+		}
+	default:
+		return Document{}, fmt.Errorf("unsupported export format %q", format)
+	}
+
+	author := tw.Author.ScreenName
+	if author == "" {
+		author = username
+	}
+	name := fmt.Sprintf("%s-%s", author, tweetID)
+	if len(doc.Tweets) > 1 {
+		name += "-thread"
+	}
+	return Document{
+		FileName: name + "." + string(format),
+		Format:   format,
+		Data:     data,
+		Tweets:   len(doc.Tweets),
+		Author:   author,
+	}, nil
+}
+
+// document is the renderer-independent view of an export.
+type document struct {
+	Source     string
+	ExportedAt time.Time
+	Tweets     []exportedTweet
+}
+
+// exportedTweet is a tweet of the thread with its role in it.
+type exportedTweet struct {
+	*twitterxapi.Tweet
+	Type chain.ChainType
+	// Quoted is the tweet to nest as a quote; nil when the thread lists it right before.
+	Quoted *twitterxapi.Tweet
+}
+
+func newDocument(items []chain.ChainItem, now time.Time) document {
+	doc := document{ExportedAt: now.UTC()}
+	var prev *twitterxapi.Tweet
+	for _, item := range items {
+		if item.Tweet == nil {
+			continue
+		}
+		quoted := item.Tweet.Quote
+		if quoted == prev {
+			quoted = nil
+		}
+		doc.Tweets = append(doc.Tweets, exportedTweet{Tweet: item.Tweet, Type: item.Type, Quoted: quoted})
+		prev = item.Tweet
+	}
+	if n := len(doc.Tweets); n > 0 {
+		doc.Source = tweetURL(doc.Tweets[n-1].Tweet)
+	}
+	return doc
+}
+
+// root is the requested tweet, the last one of the thread.
+func (d document) root() *twitterxapi.Tweet {
+	if len(d.Tweets) == 0 {
+		return nil
+	}
+	return d.Tweets[len(d.Tweets)-1].Tweet
+}
+
+// title is "Thread by Name (@user)" or "Tweet by Name (@user)".
+func (d document) title() string {
+	kind := "Tweet"
+	if len(d.Tweets) > 1 {
+		kind = "Thread"
+	}
+	root := d.root()
+	if root == nil {
+		return kind
+	}
+	return kind + " by " + authorLabel(root.Author)
+}
+
+// tweetTime decodes the time a tweet was posted from its ID. IDs from before 2010
+// do not carry a timestamp.
+func tweetTime(id string) (time.Time, bool) {
+	n, err := strconv.ParseUint(id, 10, 64)
+	if err != nil || n < 1<<22 {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(int64(n>>22) + twitterEpoch).UTC(), true
+}
+
+func tweetURL(tw *twitterxapi.Tweet) string {
+	if tw.URL != "" {
+		return tw.URL
+	}
+	return fmt.Sprintf("https://x.com/%s/status/%s", tw.Author.ScreenName, tw.ID)
+}
+
+func authorLabel(author twitterxapi.Author) string {
+	switch {
+	case author.Name != "" && author.ScreenName != "":
+		return fmt.Sprintf("%s (@%s)", author.Name, author.ScreenName)
+	case author.ScreenName != "":
+		return "@" + author.ScreenName
+	default:
+		return author.Name
+	}
+}
+
+// mediaItem is a photo or video of a tweet.
+type mediaItem struct {
+	Kind      string
+	URL       string
+	Thumbnail string
+	Width     int
+	Height    int
+}
+
+func tweetMedia(tw *twitterxapi.Tweet) []mediaItem {
+	if tw.Media == nil {
+		return nil
+	}
+	var items []mediaItem
+	for _, photo := range tw.Media.Photos {
+		if photo.URL != "" {
+			items = append(items, mediaItem{Kind: "photo", URL: photo.URL, Width: photo.Width, Height: photo.Height})
+		}
+	}
+	for _, video := range tw.Media.Videos {
+		if video.URL != "" {
+			items = append(items, mediaItem{Kind: "video", URL: video.URL, Thumbnail: video.ThumbnailURL, Width: video.Width, Height: video.Height})
+		}
+	}
+	return items
+}
+
+const timeLayout = "2006-01-02 15:04 UTC"
@@ -0,0 +1,201 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"twitterx-bot/internal/twitterxapi"
+)
+
+type fakeFetcher map[string]*twitterxapi.Tweet
+
+func (f fakeFetcher) GetTweet(_ context.Context, username, tweetID string) (*twitterxapi.Tweet, error) {
+	if tw, ok := f[username+"/"+tweetID]; ok {
+		return tw, nil
+	}
+	return nil, errors.New("not found")
+}
+
+type fakeImages struct {
+	calls int
+}
+
+func (f *fakeImages) FetchImage(_ context.Context, url string) ([]byte, string, error) {
+	f.calls++
+	if strings.Contains(url, "broken") {
+		return nil, "", errors.New("boom")
+	}
+	return []byte("png-bytes"), "image/png", nil
+}
+
+func strPtr(s string) *string { return &s }
+
+// thread is a reply by @bob to @alice, which quotes @carol.
+func thread() fakeFetcher {
+	parent := &twitterxapi.Tweet{
+		ID:     "1212092628029698048",
+		URL:    "https://x.com/alice/status/1212092628029698048",
+		Text:   "Happy new year *everyone*\n#2020 is here",
+		Author: twitterxapi.Author{Name: "Alice", ScreenName: "alice"},
+		Media: &twitterxapi.Media{
+			Photos: []twitterxapi.Photo{{URL: "https://img/fireworks.jpg"}, {URL: "https://img/broken.jpg"}},
+		},
+		Quote: &twitterxapi.Tweet{ID: "20", Text: "just setting up my twttr", Author: twitterxapi.Author{Name: "Carol", ScreenName: "carol"}},
+	}
+	reply := &twitterxapi.Tweet{
+		ID:               "1212092628029698049",
+		URL:              "https://x.com/bob/status/1212092628029698049",
+		Text:             "Same to you <3",
+		Author:           twitterxapi.Author{Name: "Bob", ScreenName: "bob"},
+		Likes:            5,
+		ReplyingTo:       strPtr("alice"),
+		ReplyingToStatus: strPtr("1212092628029698048"),
+		Media: &twitterxapi.Media{
+			Videos: []twitterxapi.Video{{URL: "https://video/clip.mp4", ThumbnailURL: "https://img/clip.jpg"}},
+		},
+	}
+	return fakeFetcher{"alice/1212092628029698048": parent, "bob/1212092628029698049": reply}
+}
+
+func newTestExporter(opts ...Option) *Exporter {
+	e := New(thread(), opts...)
+	e.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+	return e
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := map[string]Format{"md": Markdown, "Markdown": Markdown, ".html": HTML, "JSON": JSON}
+	for input, want := range tests {
+		if got, ok := ParseFormat(input); !ok || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v, want %q", input, got, ok, want)
+		}
+	}
+	if _, ok := ParseFormat("pdf"); ok {
+		t.Error("ParseFormat(pdf) ok = true, want false")
+	}
+}
+
+func TestTweetTime(t *testing.T) {
+	got, ok := tweetTime("1212092628029698048")
+	want := time.Date(2019, 12, 31, 19, 26, 16, 771_000_000, time.UTC)
+	if !ok || !got.Equal(want) {
+		t.Errorf("tweetTime() = %v, %v, want %v", got, ok, want)
+	}
+	if _, ok := tweetTime("20"); ok {
+		t.Error("tweetTime() of a pre-snowflake ID ok = true, want false")
+	}
+}
+
+func TestExport_Markdown(t *testing.T) {
+	doc, err := newTestExporter().Export(context.Background(), "bob", "1212092628029698049", Markdown)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if doc.FileName != "bob-1212092628029698049-thread.md" || doc.Tweets != 3 || doc.Author != "bob" {
+		t.Errorf("document = %q with %d tweets by %q", doc.FileName, doc.Tweets, doc.Author)
+	}
+
+	text := string(doc.Data)
+	for _, want := range []string{
+		"# Thread by Bob (@bob)",
+		"on 2024-05-01 12:00 UTC · 3 tweets",
+		"## 1. Carol (@carol) · quoted",
+		"## 2. Alice (@alice)",
+		"*2019-12-31 19:26 UTC · [Open on X](https://x.com/alice/status/1212092628029698048)*",
+		`Happy new year \*everyone\*`,
+		`\#2020 is here`,
+		"![Photo 1](https://img/fireworks.jpg)",
+		`Same to you \<3`,
+		"[▶ Video 1](https://video/clip.mp4)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("markdown missing %q:\n%s", want, text)
+		}
+	}
+	if n := strings.Count(text, "just setting up my twttr"); n != 1 {
+		t.Errorf("quoted tweet listed in the thread rendered %d times, want 1", n)
+	}
+
+	doc, err = newTestExporter().Export(context.Background(), "alice", "1212092628029698048", Markdown)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if text := string(doc.Data); !strings.Contains(text, "**Quoting Carol (@carol)**\n\n> *[Open on X](https://x.com/carol/status/20)*\n>\n> just setting up my twttr") {
+		t.Errorf("markdown missing the nested quote:\n%s", text)
+	}
+}
+
+func TestExport_HTMLEmbedsImages(t *testing.T) {
+	images := &fakeImages{}
+	doc, err := newTestExporter(WithImages(images)).Export(context.Background(), "bob", "1212092628029698049", HTML)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	text := string(doc.Data)
+	for _, want := range []string{
+		"<title>Thread by Bob (@bob)</title>",
+		`<img src="data:image/png;base64,cG5nLWJ5dGVz" alt="Photo 1">`,
+		`<img src="https://img/broken.jpg" alt="Photo 2">`,
+		`<a href="https://video/clip.mp4">▶ Video 1</a>`,
+		"Same to you &lt;3",
+		`<time datetime="2019-12-31T19:26:16Z">`,
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("html missing %q:\n%s", want, text)
+		}
+	}
+	if images.calls != 3 {
+		t.Errorf("image fetches = %d, want 3", images.calls)
+	}
+}
+
+func TestExport_JSON(t *testing.T) {
+	doc, err := newTestExporter().Export(context.Background(), "alice", "1212092628029698048", JSON)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if doc.FileName != "alice-1212092628029698048.json" {
+		t.Errorf("file name = %q", doc.FileName)
+	}
+
+	var got struct {
+		Source string `json:"source"`
+		Tweets []struct {
+			ID        string     `json:"id"`
+			Type      string     `json:"type"`
+			CreatedAt *time.Time `json:"created_at"`
+			Media     []struct {
+				Type string `json:"type"`
+			} `json:"media"`
+			Quote *struct {
+				ID        string     `json:"id"`
+				CreatedAt *time.Time `json:"created_at"`
+			} `json:"quote"`
+		} `json:"tweets"`
+	}
+	if err := json.Unmarshal(doc.Data, &got); err != nil {
+		t.Fatalf("decode export: %v", err)
+	}
+	if got.Source != "https://x.com/alice/status/1212092628029698048" || len(got.Tweets) != 1 {
+		t.Fatalf("export = %+v", got)
+	}
+	tw := got.Tweets[0]
+	if tw.Type != "root" || tw.CreatedAt == nil || len(tw.Media) != 2 || tw.Quote == nil || tw.Quote.ID != "20" || tw.Quote.CreatedAt != nil {
+		t.Errorf("tweet = %+v", tw)
+	}
+}
+
+func TestExport_Errors(t *testing.T) {
+	e := newTestExporter()
+	if _, err := e.Export(context.Background(), "nobody", "1", JSON); !errors.Is(err, ErrFetchTweet) {
+		t.Errorf("Export() of a missing tweet error = %v, want ErrFetchTweet", err)
+	}
+	if _, err := e.Export(context.Background(), "alice", "1212092628029698048", Format("pdf")); err == nil {
+		t.Error("Export() with an unknown format succeeded")
+	}
+}
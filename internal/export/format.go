@@ -0,0 +1,55 @@
+package export
+
+import "strings"
+
+// Format is the file format of an export.
+type Format string
+
+const (
+	Markdown Format = "md"
+	HTML     Format = "html"
+	JSON     Format = "json"
+)
+
+// Formats lists the supported formats in the order they are offered to users.
+var Formats = []Format{Markdown, HTML, JSON}
+
+// ParseFormat accepts a format name or extension such as "md", "markdown", "html" or "json".
+func ParseFormat(input string) (Format, bool) {
+	switch strings.ToLower(strings.TrimPrefix(strings.TrimSpace(input), ".")) {
+	case "md", "markdown":
+		return Markdown, true
+	case "html", "htm":
+		return HTML, true
+	case "json":
+		return JSON, true
+	default:
+		return "", false
+	}
+}
+
+// Label is the human-readable name of the format.
+func (f Format) Label() string {
+	switch f {
+	case Markdown:
+		return "Markdown"
+	case HTML:
+		return "HTML"
+	case JSON:
+		return "JSON"
+	default:
+		return string(f)
+	}
+}
+
+// MimeType is the content type of documents in the format.
+func (f Format) MimeType() string {
+	switch f {
+	case Markdown:
+		return "text/markdown; charset=utf-8"
+	case HTML:
+		return "text/html; charset=utf-8"
+	default:
+		return "application/json"
+	}
+}
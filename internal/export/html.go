@@ -0,0 +1,111 @@
+package export
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"strings"
+
+	"twitterx-bot/internal/chain"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+// maxEmbeddedBytes caps the images embedded in one HTML export, keeping the document
+// well below the Telegram upload limit; further images are linked.
+const maxEmbeddedBytes = 20 << 20
+
+const htmlStyle = `body{font-family:-apple-system,"Segoe UI",Roboto,sans-serif;max-width:640px;margin:2em auto;padding:0 1em;color:#0f1419;line-height:1.5}
+header p,.meta{color:#536471;font-size:.9em}
+article{border-top:1px solid #eff3f4;padding:1em 0}
+blockquote{border:1px solid #cfd9de;border-radius:12px;margin:.5em 0;padding:.5em 1em}
+img{max-width:100%;border-radius:12px;display:block;margin:.5em 0}
+.text{white-space:pre-wrap;word-wrap:break-word}
+a{color:#1d9bf0}`
+
+// embedder turns image URLs into data URIs within the size budget of the document.
+type embedder struct {
+	ctx    context.Context
+	images ImageFetcher
+	budget int
+	cache  map[string]string
+}
+
+// src returns a data URI for the image, or the URL itself when it cannot be embedded.
+func (e *embedder) src(url string) string {
+	if e.images == nil || url == "" {
+		return url
+	}
+	if uri, ok := e.cache[url]; ok {
+		return uri
+	}
+	data, contentType, err := e.images.FetchImage(e.ctx, url)
+	if err != nil || len(data) > e.budget {
+		e.cache[url] = url
+		return url
+	}
+	e.budget -= len(data)
+	uri := "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(data)
+	e.cache[url] = uri
+	return uri
+}
+
+func renderHTML(ctx context.Context, doc document, images ImageFetcher) []byte {
+	e := &embedder{ctx: ctx, images: images, budget: maxEmbeddedBytes, cache: make(map[string]string)}
+	title := html.EscapeString(doc.title())
+
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	sb.WriteString("<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n")
+	sb.WriteString("<title>" + title + "</title>\n<style>\n" + htmlStyle + "\n</style>\n</head>\n<body>\n")
+	sb.WriteString("<header>\n<h1>" + title + "</h1>\n")
+	sb.WriteString(fmt.Sprintf("<p>Exported from <a href=\"%s\">%s</a> on %s · %s</p>\n</header>\n",
+		html.EscapeString(doc.Source), html.EscapeString(doc.Source), doc.ExportedAt.Format(timeLayout), plural(len(doc.Tweets), "tweet")))
+
+	for i, tw := range doc.Tweets {
+		sb.WriteString(fmt.Sprintf("<article id=\"tweet-%d\">\n<h2>%d. %s", i+1, i+1, html.EscapeString(authorLabel(tw.Author))))
+		if tw.Type == chain.ChainTypeQuote {
+			sb.WriteString(" · quoted")
+		}
+		sb.WriteString("</h2>\n")
+		writeHTMLTweet(&sb, e, tw.Tweet, tw.Quoted)
+		sb.WriteString("</article>\n")
+	}
+
+	sb.WriteString("</body>\n</html>\n")
+	return []byte(sb.String())
+}
+
+// writeHTMLTweet writes the body of a tweet; the quoted tweet is nested as a block quote.
+func writeHTMLTweet(sb *strings.Builder, e *embedder, tw, quoted *twitterxapi.Tweet) {
+	url := html.EscapeString(tweetURL(tw))
+	meta := fmt.Sprintf("<a href=\"%s\">Open on X</a>", url)
+	if posted, ok := tweetTime(tw.ID); ok {
+		meta = fmt.Sprintf("<time datetime=\"%s\">%s</time> · %s", posted.Format("2006-01-02T15:04:05Z"), posted.Format(timeLayout), meta)
+	}
+	sb.WriteString("<p class=\"meta\">" + meta + "</p>\n")
+
+	if text := strings.TrimSpace(tw.Text); text != "" {
+		sb.WriteString("<p class=\"text\">" + html.EscapeString(text) + "</p>\n")
+	}
+
+	for i, item := range tweetMedia(tw) {
+		label := fmt.Sprintf("%s %d", titleCase(item.Kind), i+1)
+		switch {
+		case item.Kind == "photo":
+			sb.WriteString(fmt.Sprintf("<a href=\"%s\"><img src=\"%s\" alt=\"%s\"></a>\n",
+				html.EscapeString(item.URL), html.EscapeString(e.src(item.URL)), label))
+		case item.Thumbnail != "":
+			sb.WriteString(fmt.Sprintf("<a href=\"%s\"><img src=\"%s\" alt=\"%s\"></a>\n<p><a href=\"%s\">▶ %s</a></p>\n",
+				html.EscapeString(item.URL), html.EscapeString(e.src(item.Thumbnail)), label, html.EscapeString(item.URL), label))
+		default:
+			sb.WriteString(fmt.Sprintf("<p><a href=\"%s\">▶ %s</a></p>\n", html.EscapeString(item.URL), label))
+		}
+	}
+
+	if quoted != nil {
+		sb.WriteString("<blockquote>\n<p><b>Quoting " + html.EscapeString(authorLabel(quoted.Author)) + "</b></p>\n")
+		writeHTMLTweet(sb, e, quoted, quoted.Quote)
+		sb.WriteString("</blockquote>\n")
+	}
+}
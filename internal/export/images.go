@@ -0,0 +1,68 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxImageBytes caps the size of a single embedded image.
+const DefaultMaxImageBytes = 5 << 20
+
+// ErrImageTooLarge is returned for images over the size limit; they are linked instead.
+var ErrImageTooLarge = errors.New("image too large")
+
+// ImageFetcher downloads images embedded in HTML exports.
+type ImageFetcher interface {
+	// FetchImage returns the image data and its content type, such as "image/jpeg".
+	FetchImage(ctx context.Context, url string) ([]byte, string, error)
+}
+
+// HTTPImages downloads images over HTTP.
+type HTTPImages struct {
+	client   *http.Client
+	maxBytes int64
+}
+
+// NewHTTPImages creates an ImageFetcher using client, or http.DefaultClient when nil.
+// Images larger than DefaultMaxImageBytes are rejected.
+func NewHTTPImages(client *http.Client) *HTTPImages {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPImages{client: client, maxBytes: DefaultMaxImageBytes}
+}
+
+// FetchImage downloads the image at url.
+func (h *HTTPImages) FetchImage(ctx context.Context, url string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("fetch image: %w", err)
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("fetch image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("fetch image: status %d", resp.StatusCode)
+	}
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !strings.HasPrefix(contentType, "image/") {
+		return nil, "", fmt.Errorf("fetch image: unexpected content type %q", contentType)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, h.maxBytes+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("fetch image: %w", err)
+	}
+	if int64(len(data)) > h.maxBytes {
+		return nil, "", ErrImageTooLarge
+	}
+	return data, contentType, nil
+}
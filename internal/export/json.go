@@ -0,0 +1,101 @@
+package export
+
+import (
+	"encoding/json"
+	"time"
+
+	"twitterx-bot/internal/twitterxapi"
+)
+
+type jsonDocument struct {
+	Source     string      `json:"source"`
+	ExportedAt time.Time   `json:"exported_at"`
+	Tweets     []jsonTweet `json:"tweets"`
+}
+
+type jsonTweet struct {
+	ID string `json:"id"`
+	// Type is the role of the tweet in the thread: reply, quote or root; empty for nested quotes.
+	Type      string      `json:"type,omitempty"`
+	URL       string      `json:"url"`
+	CreatedAt *time.Time  `json:"created_at,omitempty"`
+	Author    jsonAuthor  `json:"author"`
+	Text      string      `json:"text"`
+	Media     []jsonMedia `json:"media,omitempty"`
+	Likes     int         `json:"likes"`
+	Retweets  int         `json:"retweets"`
+	Replies   int         `json:"replies"`
+	Sensitive bool        `json:"possibly_sensitive,omitempty"`
+
+	ReplyingTo       string     `json:"replying_to,omitempty"`
+	ReplyingToStatus string     `json:"replying_to_status,omitempty"`
+	Quote            *jsonTweet `json:"quote,omitempty"`
+}
+
+type jsonAuthor struct {
+	Name       string `json:"name"`
+	ScreenName string `json:"screen_name"`
+	AvatarURL  string `json:"avatar_url,omitempty"`
+}
+
+type jsonMedia struct {
+	Type         string `json:"type"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+	Width        int    `json:"width,omitempty"`
+	Height       int    `json:"height,omitempty"`
+}
+
+func renderJSON(doc document) ([]byte, error) {
+	out := jsonDocument{Source: doc.Source, ExportedAt: doc.ExportedAt, Tweets: []jsonTweet{}}
+	for _, tw := range doc.Tweets {
+		entry := newJSONTweet(tw.Tweet)
+		entry.Type = string(tw.Type)
+		out.Tweets = append(out.Tweets, entry)
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+func newJSONTweet(tw *twitterxapi.Tweet) jsonTweet {
+	entry := jsonTweet{
+		ID:  tw.ID,
+		URL: tweetURL(tw),
+		Author: jsonAuthor{
+			Name:       tw.Author.Name,
+			ScreenName: tw.Author.ScreenName,
+			AvatarURL:  tw.Author.AvatarURL,
+		},
+		Text:      tw.Text,
+		Likes:     tw.Likes,
+		Retweets:  tw.Retweets,
+		Replies:   tw.Replies,
+		Sensitive: tw.PossiblySensitive,
+	}
+	if posted, ok := tweetTime(tw.ID); ok {
+		entry.CreatedAt = &posted
+	}
+	for _, item := range tweetMedia(tw) {
+		entry.Media = append(entry.Media, jsonMedia{
+			Type:         item.Kind,
+			URL:          item.URL,
+			ThumbnailURL: item.Thumbnail,
+			Width:        item.Width,
+			Height:       item.Height,
+		})
+	}
+	if tw.ReplyingTo != nil {
+		entry.ReplyingTo = *tw.ReplyingTo
+	}
+	if tw.ReplyingToStatus != nil {
+		entry.ReplyingToStatus = *tw.ReplyingToStatus
+	}
+	if tw.Quote != nil {
+		quote := newJSONTweet(tw.Quote)
+		entry.Quote = &quote
+	}
+	return entry
+}
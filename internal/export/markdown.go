@@ -0,0 +1,96 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"twitterx-bot/internal/chain"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+func renderMarkdown(doc document) []byte {
+	var sb strings.Builder
+	sb.WriteString("# " + markdownEscape(doc.title()) + "\n\n")
+	sb.WriteString(fmt.Sprintf("Exported from <%s> on %s · %s\n", doc.Source, doc.ExportedAt.Format(timeLayout), plural(len(doc.Tweets), "tweet")))
+
+	for i, tw := range doc.Tweets {
+		sb.WriteString("\n---\n\n")
+		sb.WriteString(fmt.Sprintf("## %d. %s", i+1, markdownEscape(authorLabel(tw.Author))))
+		if tw.Type == chain.ChainTypeQuote {
+			sb.WriteString(" · quoted")
+		}
+		sb.WriteString("\n\n")
+		writeMarkdownTweet(&sb, tw.Tweet, tw.Quoted, "")
+	}
+	return []byte(sb.String())
+}
+
+// writeMarkdownTweet writes the body of a tweet; the quoted tweet is nested as a block quote.
+func writeMarkdownTweet(sb *strings.Builder, tw, quoted *twitterxapi.Tweet, prefix string) {
+	line := func(text string) {
+		if text == "" {
+			sb.WriteString(strings.TrimRight(prefix, " ") + "\n")
+			return
+		}
+		sb.WriteString(prefix + text + "\n")
+	}
+
+	meta := fmt.Sprintf("[Open on X](%s)", tweetURL(tw))
+	if posted, ok := tweetTime(tw.ID); ok {
+		meta = posted.Format(timeLayout) + " · " + meta
+	}
+	line("*" + meta + "*")
+	line("")
+
+	if text := strings.TrimSpace(tw.Text); text != "" {
+		for _, l := range strings.Split(text, "\n") {
+			line(markdownEscape(l))
+		}
+		line("")
+	}
+
+	media := tweetMedia(tw)
+	for i, item := range media {
+		label := fmt.Sprintf("%s %d", titleCase(item.Kind), i+1)
+		if item.Kind == "photo" {
+			line(fmt.Sprintf("![%s](%s)", label, item.URL))
+		} else {
+			line(fmt.Sprintf("[▶ %s](%s)", label, item.URL))
+		}
+	}
+	if len(media) > 0 {
+		line("")
+	}
+
+	if quoted != nil {
+		line("**Quoting " + markdownEscape(authorLabel(quoted.Author)) + "**")
+		line("")
+		writeMarkdownTweet(sb, quoted, quoted.Quote, prefix+"> ")
+	}
+}
+
+var markdownReplacer = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", `\<`)
+
+// markdownEscape escapes the characters that would otherwise turn tweet text into markup.
+// Hashtags stay readable: "#" and ">" only start a heading or a quote at the beginning of a line.
+func markdownEscape(text string) string {
+	text = markdownReplacer.Replace(text)
+	if strings.HasPrefix(text, "#") || strings.HasPrefix(text, ">") {
+		text = `\` + text
+	}
+	return text
+}
+
+func titleCase(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,331 @@
+// Package follow polls the timelines of followed accounts and posts their new tweets
+// to the chats following them.
+package follow
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/storage"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+const (
+	// DefaultInterval is how often each followed account is polled.
+	DefaultInterval = 5 * time.Minute
+
+	defaultMaxPerPoll  = 5
+	defaultPollTimeout = 30 * time.Second
+	maxTick            = 30 * time.Second
+)
+
+// TimelineFetcher returns the latest tweets of an account, newest first.
+type TimelineFetcher interface {
+	GetTimeline(ctx context.Context, username string, opts twitterxapi.TimelineOpts) ([]*twitterxapi.Tweet, error)
+}
+
+// ErrChatGone is returned by a Publisher when the bot was removed from the chat or blocked in it.
+var ErrChatGone = errors.New("chat gone")
+
+// Publisher posts a tweet to a chat.
+type Publisher interface {
+	Publish(ctx context.Context, chatID int64, tweet *twitterxapi.Tweet) error
+}
+
+// ChatPolicy decides whether the bot may post to a chat on its own.
+type ChatPolicy interface {
+	Serves(ctx context.Context, chatID int64) (bool, error)
+}
+
+// Poller checks every followed account once per interval. Each account keeps its own
+// cursor and schedule; the interval is jittered so accounts do not hit the API in bursts.
+// The zero value is not usable; create it with NewPoller.
+type Poller struct {
+	log        *logger.Logger
+	follows    storage.FollowRepository
+	timeline   TimelineFetcher
+	publisher  Publisher
+	policy     ChatPolicy
+	interval   time.Duration
+	jitter     time.Duration
+	maxPerPoll int
+	now        func() time.Time
+	randN      func(n int64) int64
+
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+// Option configures a Poller.
+type Option func(*Poller)
+
+// WithInterval sets how often each account is polled; every poll is moved by a random
+// offset of up to jitter in either direction.
+func WithInterval(interval, jitter time.Duration) Option {
+	return func(p *Poller) {
+		p.interval = interval
+		p.jitter = jitter
+	}
+}
+
+// WithChatPolicy skips followers the policy does not serve, such as denied chats or chats
+// the bot has left.
+func WithChatPolicy(policy ChatPolicy) Option {
+	return func(p *Poller) {
+		p.policy = policy
+	}
+}
+
+// WithMaxPerPoll caps how many new tweets of one account are posted per poll;
+// older ones are skipped so a long downtime does not flood the chats.
+func WithMaxPerPoll(n int) Option {
+	return func(p *Poller) {
+		p.maxPerPoll = n
+	}
+}
+
+// NewPoller creates a poller with DefaultInterval and a jitter of a fifth of it.
+func NewPoller(log *logger.Logger, follows storage.FollowRepository, timeline TimelineFetcher, publisher Publisher, opts ...Option) *Poller {
+	p := &Poller{
+		log:        log,
+		follows:    follows,
+		timeline:   timeline,
+		publisher:  publisher,
+		interval:   DefaultInterval,
+		jitter:     DefaultInterval / 5,
+		maxPerPoll: defaultMaxPerPoll,
+		now:        time.Now,
+		randN:      rand.Int64N,
+		next:       make(map[string]time.Time),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.interval <= 0 {
+		p.interval = DefaultInterval
+	}
+	if p.jitter < 0 || p.jitter >= p.interval {
+		p.jitter = p.interval / 5
+	}
+	if p.maxPerPoll <= 0 {
+		p.maxPerPoll = defaultMaxPerPoll
+	}
+	return p
+}
+
+// Run polls the accounts that are due until ctx is cancelled.
+func (p *Poller) Run(ctx context.Context) error {
+	tick := p.interval / 10
+	if tick > maxTick {
+		tick = maxTick
+	}
+	if tick < time.Second {
+		tick = time.Second
+	}
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	for {
+		p.PollDue(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// PollDue polls every followed account whose next poll time has passed.
+// Accounts seen for the first time are scheduled at a random point within one interval.
+func (p *Poller) PollDue(ctx context.Context) {
+	log := p.log.With("component", "follow_poller")
+
+	accounts, err := p.follows.Accounts(ctx)
+	if err != nil {
+		log.Error("list followed accounts failed", "err", err)
+		return
+	}
+
+	for _, username := range p.due(accounts) {
+		if ctx.Err() != nil {
+			return
+		}
+		pollCtx, cancel := context.WithTimeout(ctx, defaultPollTimeout)
+		if err := p.Poll(pollCtx, username); err != nil {
+			log.Warn("poll account failed", "username", username, "err", err)
+		}
+		cancel()
+		p.schedule(username, p.now().Add(p.jittered()))
+	}
+}
+
+// due returns the accounts to poll now and forgets the schedule of unfollowed ones.
+func (p *Poller) due(accounts []string) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	followed := make(map[string]struct{}, len(accounts))
+	var due []string
+	for _, username := range accounts {
+		followed[username] = struct{}{}
+		next, ok := p.next[username]
+		if !ok {
+			next = now.Add(time.Duration(p.randN(int64(p.interval))))
+			p.next[username] = next
+		}
+		if !next.After(now) {
+			due = append(due, username)
+		}
+	}
+	for username := range p.next {
+		if _, ok := followed[username]; !ok {
+			delete(p.next, username)
+		}
+	}
+	return due
+}
+
+func (p *Poller) schedule(username string, at time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.next[username] = at
+}
+
+// jittered returns the interval moved by a random offset within [-jitter, jitter].
+func (p *Poller) jittered() time.Duration {
+	if p.jitter <= 0 {
+		return p.interval
+	}
+	return p.interval - p.jitter + time.Duration(p.randN(int64(2*p.jitter)+1))
+}
+
+// Poll fetches the tweets of username newer than its cursor and posts them to every
+// chat following the account, oldest first. Without a cursor it only records the newest
+// tweet so that following an account does not repost its history. Chats the policy does not
+// serve are skipped; chats the bot was removed from lose all their follows.
+func (p *Poller) Poll(ctx context.Context, username string) error {
+	log := p.log.With("component", "follow_poller", "username", username)
+
+	cursor, ok, err := p.follows.Cursor(ctx, username)
+	if err != nil {
+		return err
+	}
+	tweets, err := p.timeline.GetTimeline(ctx, username, twitterxapi.TimelineOpts{
+		SinceID: cursor.LastTweetID,
+		Limit:   p.maxPerPoll,
+	})
+	if err != nil {
+		return err
+	}
+
+	newest := cursor.LastTweetID
+	var fresh []*twitterxapi.Tweet
+	for _, tw := range tweets {
+		if tw == nil || !NewerID(tw.ID, cursor.LastTweetID) {
+			continue
+		}
+		if NewerID(tw.ID, newest) {
+			newest = tw.ID
+		}
+		if !isReplyToOther(tw, username) {
+			fresh = append(fresh, tw)
+		}
+	}
+
+	next := storage.FollowCursor{Username: username, LastTweetID: newest, CheckedAt: p.now()}
+	if !ok {
+		log.Info("follow cursor initialized", "last_tweet_id", newest)
+		return p.follows.SetCursor(ctx, next)
+	}
+
+	sort.Slice(fresh, func(i, j int) bool { return NewerID(fresh[j].ID, fresh[i].ID) })
+	if len(fresh) > p.maxPerPoll {
+		log.Info("skipping older tweets", "skipped", len(fresh)-p.maxPerPoll)
+		fresh = fresh[len(fresh)-p.maxPerPoll:]
+	}
+
+	if len(fresh) > 0 {
+		followers, err := p.follows.Followers(ctx, username)
+		if err != nil {
+			return err
+		}
+		chats := p.servedChats(ctx, log, followers)
+		for _, tw := range fresh {
+			for i := 0; i < len(chats); i++ {
+				chatID := chats[i]
+				err := p.publisher.Publish(ctx, chatID, tw)
+				switch {
+				case errors.Is(err, ErrChatGone):
+					p.dropChat(ctx, log, chatID)
+					chats = append(chats[:i], chats[i+1:]...)
+					i--
+				case err != nil:
+					log.Warn("publish followed tweet failed", "chat_id", chatID, "tweet_id", tw.ID, "err", err)
+				}
+			}
+		}
+		log.Info("followed tweets posted", "tweets", len(fresh), "chats", len(chats))
+	}
+
+	// The cursor moves past failed posts too: a chat the bot cannot post to must not
+	// make every other follower receive the same tweets again.
+	return p.follows.SetCursor(ctx, next)
+}
+
+// servedChats returns the chats of followers the policy serves. Policy errors let the chat through.
+func (p *Poller) servedChats(ctx context.Context, log *logger.Logger, followers []storage.Follow) []int64 {
+	chats := make([]int64, 0, len(followers))
+	for _, follow := range followers {
+		if p.policy != nil {
+			serves, err := p.policy.Serves(ctx, follow.ChatID)
+			if err != nil {
+				log.Warn("check chat access failed", "chat_id", follow.ChatID, "err", err)
+			} else if !serves {
+				log.Debug("follower skipped: chat not served", "chat_id", follow.ChatID)
+				continue
+			}
+		}
+		chats = append(chats, follow.ChatID)
+	}
+	return chats
+}
+
+// dropChat removes every follow of a chat the bot can no longer post to.
+func (p *Poller) dropChat(ctx context.Context, log *logger.Logger, chatID int64) {
+	follows, err := p.follows.ListByChat(ctx, chatID)
+	if err != nil {
+		log.Warn("list follows of a gone chat failed", "chat_id", chatID, "err", err)
+		return
+	}
+	for _, follow := range follows {
+		if _, err := p.follows.Remove(ctx, chatID, follow.Username); err != nil {
+			log.Warn("remove follow of a gone chat failed", "chat_id", chatID, "followed", follow.Username, "err", err)
+		}
+	}
+	log.Info("follows of a gone chat removed", "chat_id", chatID, "follows", len(follows))
+}
+
+// isReplyToOther reports whether tw replies to someone else; replies within the
+// account's own threads are kept.
+func isReplyToOther(tw *twitterxapi.Tweet, username string) bool {
+	if tw.ReplyingTo == nil || *tw.ReplyingTo == "" {
+		return false
+	}
+	return !strings.EqualFold(strings.TrimPrefix(*tw.ReplyingTo, "@"), username)
+}
+
+// NewerID reports whether tweet ID a is newer than b. IDs are decimal snowflakes, so a longer
+// ID is newer and IDs of equal length compare as strings. Every ID is newer than an empty one.
+func NewerID(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) > len(b)
+	}
+	return a > b
+}
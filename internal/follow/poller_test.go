@@ -0,0 +1,288 @@
+package follow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/storage"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+type fakeTimeline struct {
+	mu     sync.Mutex
+	tweets map[string][]*twitterxapi.Tweet
+	calls  []string
+	err    error
+}
+
+func (f *fakeTimeline) GetTimeline(_ context.Context, username string, opts twitterxapi.TimelineOpts) ([]*twitterxapi.Tweet, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, username+"@"+opts.SinceID)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.tweets[username], nil
+}
+
+type published struct {
+	chatID  int64
+	tweetID string
+}
+
+type fakePublisher struct {
+	posts  []published
+	failOn int64
+	goneOn int64
+}
+
+func (f *fakePublisher) Publish(_ context.Context, chatID int64, tw *twitterxapi.Tweet) error {
+	if chatID == f.failOn {
+		return errors.New("forbidden")
+	}
+	if chatID == f.goneOn {
+		return fmt.Errorf("%w: bot was kicked", ErrChatGone)
+	}
+	f.posts = append(f.posts, published{chatID: chatID, tweetID: tw.ID})
+	return nil
+}
+
+func tweets(ids ...string) []*twitterxapi.Tweet {
+	list := make([]*twitterxapi.Tweet, 0, len(ids))
+	for _, id := range ids {
+		list = append(list, &twitterxapi.Tweet{ID: id, Author: twitterxapi.Author{ScreenName: "nasa"}})
+	}
+	return list
+}
+
+func follow(t *testing.T, store storage.Storage, chatID int64, username string) {
+	t.Helper()
+	if _, err := store.Follows().Add(context.Background(), storage.Follow{ChatID: chatID, Username: username, CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+}
+
+func TestPoller_Poll_InitializesCursorWithoutPosting(t *testing.T) {
+	store := storage.NewMemory()
+	follow(t, store, -100, "nasa")
+	timeline := &fakeTimeline{tweets: map[string][]*twitterxapi.Tweet{"nasa": tweets("30", "20", "10")}}
+	publisher := &fakePublisher{}
+	p := NewPoller(logger.New(true), store.Follows(), timeline, publisher)
+
+	if err := p.Poll(context.Background(), "nasa"); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+
+	if len(publisher.posts) != 0 {
+		t.Errorf("posts = %+v, want none on the first poll", publisher.posts)
+	}
+	cursor, ok, _ := store.Follows().Cursor(context.Background(), "nasa")
+	if !ok || cursor.LastTweetID != "30" {
+		t.Errorf("Cursor() = %+v, %v; want 30", cursor, ok)
+	}
+}
+
+func TestPoller_Poll_PostsNewTweetsOldestFirstToEveryFollower(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemory()
+	follow(t, store, -100, "nasa")
+	follow(t, store, -200, "nasa")
+	follow(t, store, -300, "nasa")
+	if err := store.Follows().SetCursor(ctx, storage.FollowCursor{Username: "nasa", LastTweetID: "98"}); err != nil {
+		t.Fatalf("SetCursor() error = %v", err)
+	}
+
+	other := "someone"
+	reply := &twitterxapi.Tweet{ID: "101", ReplyingTo: &other}
+	list := append(tweets("102"), reply)
+	list = append(list, tweets("100", "99", "98", "97")...)
+	timeline := &fakeTimeline{tweets: map[string][]*twitterxapi.Tweet{"nasa": list}}
+	publisher := &fakePublisher{failOn: -200}
+	p := NewPoller(logger.New(true), store.Follows(), timeline, publisher)
+
+	if err := p.Poll(ctx, "nasa"); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+
+	want := []published{{-300, "99"}, {-100, "99"}, {-300, "100"}, {-100, "100"}, {-300, "102"}, {-100, "102"}}
+	if len(publisher.posts) != len(want) {
+		t.Fatalf("posts = %+v, want %+v", publisher.posts, want)
+	}
+	for i := range want {
+		if publisher.posts[i] != want[i] {
+			t.Errorf("posts[%d] = %+v, want %+v", i, publisher.posts[i], want[i])
+		}
+	}
+	if timeline.calls[0] != "nasa@98" {
+		t.Errorf("timeline called with %q, want since_id 98", timeline.calls[0])
+	}
+	cursor, _, _ := store.Follows().Cursor(ctx, "nasa")
+	if cursor.LastTweetID != "102" {
+		t.Errorf("cursor = %q, want 102 (skipped replies move it too)", cursor.LastTweetID)
+	}
+}
+
+// denyChats serves every chat but the denied ones.
+type denyChats map[int64]bool
+
+func (d denyChats) Serves(_ context.Context, chatID int64) (bool, error) {
+	return !d[chatID], nil
+}
+
+func TestPoller_Poll_SkipsUnservedChatsAndDropsGoneOnes(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemory()
+	for _, chatID := range []int64{-100, -200, -300} {
+		follow(t, store, chatID, "nasa")
+	}
+	follow(t, store, -300, "esa")
+	if err := store.Follows().SetCursor(ctx, storage.FollowCursor{Username: "nasa", LastTweetID: "10"}); err != nil {
+		t.Fatalf("SetCursor() error = %v", err)
+	}
+
+	timeline := &fakeTimeline{tweets: map[string][]*twitterxapi.Tweet{"nasa": tweets("12", "11")}}
+	publisher := &fakePublisher{goneOn: -300}
+	p := NewPoller(logger.New(true), store.Follows(), timeline, publisher, WithChatPolicy(denyChats{-200: true}))
+
+	if err := p.Poll(ctx, "nasa"); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+
+	want := []published{{-100, "11"}, {-100, "12"}}
+	if len(publisher.posts) != len(want) || publisher.posts[0] != want[0] || publisher.posts[1] != want[1] {
+		t.Fatalf("posts = %+v, want %+v", publisher.posts, want)
+	}
+	if follows, _ := store.Follows().ListByChat(ctx, -300); len(follows) != 0 {
+		t.Errorf("follows of the gone chat = %+v, want none", follows)
+	}
+	if follows, _ := store.Follows().ListByChat(ctx, -200); len(follows) != 1 {
+		t.Errorf("follows of the denied chat = %+v, want kept", follows)
+	}
+}
+
+func TestPoller_Poll_CapsTweetsPerPoll(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemory()
+	follow(t, store, -100, "nasa")
+	_ = store.Follows().SetCursor(ctx, storage.FollowCursor{Username: "nasa", LastTweetID: "1"})
+	timeline := &fakeTimeline{tweets: map[string][]*twitterxapi.Tweet{"nasa": tweets("5", "4", "3", "2")}}
+	publisher := &fakePublisher{}
+	p := NewPoller(logger.New(true), store.Follows(), timeline, publisher, WithMaxPerPoll(2))
+
+	if err := p.Poll(ctx, "nasa"); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+
+	if len(publisher.posts) != 2 || publisher.posts[0].tweetID != "4" || publisher.posts[1].tweetID != "5" {
+		t.Errorf("posts = %+v, want the 2 newest tweets", publisher.posts)
+	}
+}
+
+func TestPoller_Poll_KeepsCursorOnFetchError(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemory()
+	follow(t, store, -100, "nasa")
+	_ = store.Follows().SetCursor(ctx, storage.FollowCursor{Username: "nasa", LastTweetID: "7"})
+	timeline := &fakeTimeline{err: errors.New("api down")}
+	p := NewPoller(logger.New(true), store.Follows(), timeline, &fakePublisher{})
+
+	if err := p.Poll(ctx, "nasa"); err == nil {
+		t.Fatal("Poll() error = nil, want fetch error")
+	}
+	if cursor, _, _ := store.Follows().Cursor(ctx, "nasa"); cursor.LastTweetID != "7" {
+		t.Errorf("cursor = %q, want 7", cursor.LastTweetID)
+	}
+}
+
+func TestPoller_PollDue_SchedulesAccountsWithJitter(t *testing.T) {
+	store := storage.NewMemory()
+	follow(t, store, -100, "nasa")
+	follow(t, store, -100, "esa")
+	timeline := &fakeTimeline{}
+
+	now := time.Unix(1_700_000_000, 0)
+	var randCalls []int64
+	p := NewPoller(logger.New(true), store.Follows(), timeline, &fakePublisher{}, WithInterval(10*time.Minute, time.Minute))
+	p.now = func() time.Time { return now }
+	p.randN = func(n int64) int64 {
+		randCalls = append(randCalls, n)
+		return 0
+	}
+
+	// New accounts start at a random offset within the interval; 0 makes them due at once
+	p.PollDue(context.Background())
+	if len(timeline.calls) != 2 {
+		t.Fatalf("timeline calls = %v, want both accounts", timeline.calls)
+	}
+	if randCalls[0] != int64(10*time.Minute) || randCalls[len(randCalls)-1] != int64(2*time.Minute)+1 {
+		t.Errorf("rand bounds = %v, want interval then 2*jitter+1", randCalls)
+	}
+
+	// Next polls are at interval - jitter + rand, i.e. 9 minutes later here
+	now = now.Add(8 * time.Minute)
+	p.PollDue(context.Background())
+	if len(timeline.calls) != 2 {
+		t.Errorf("timeline calls = %v, want no poll before the next slot", timeline.calls)
+	}
+	now = now.Add(time.Minute)
+	p.PollDue(context.Background())
+	if len(timeline.calls) != 4 {
+		t.Errorf("timeline calls = %v, want both accounts polled again", timeline.calls)
+	}
+
+	if _, err := store.Follows().Remove(context.Background(), -100, "esa"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	now = now.Add(10 * time.Minute)
+	p.PollDue(context.Background())
+	if len(timeline.calls) != 5 || timeline.calls[4] != "nasa@" {
+		t.Errorf("timeline calls = %v, want only nasa after unfollowing esa", timeline.calls)
+	}
+}
+
+func TestNewerID(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"10", "9", true},
+		{"9", "10", false},
+		{"1800000000000000001", "1800000000000000000", true},
+		{"5", "5", false},
+		{"1", "", true},
+		{"", "1", false},
+	}
+	for _, tt := range tests {
+		if got := NewerID(tt.a, tt.b); got != tt.want {
+			t.Errorf("NewerID(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestParseUsername(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{"@NASA", "nasa", true},
+		{"nasa", "nasa", true},
+		{"https://x.com/NASA", "nasa", true},
+		{"twitter.com/nasa/status/1", "nasa", true},
+		{"https://example.com/nasa", "", false},
+		{"@", "", false},
+		{"@this_name_is_too_long", "", false},
+		{"na-sa", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseUsername(tt.input)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseUsername(%q) = %q, %v; want %q, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
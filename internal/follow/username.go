@@ -0,0 +1,37 @@
+package follow
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var usernameRegex = regexp.MustCompile(`^[A-Za-z0-9_]{1,15}$`)
+
+// ParseUsername extracts the account from "@user", "user" or a profile URL such as
+// https://x.com/user and returns it lower-cased without "@".
+func ParseUsername(input string) (string, bool) {
+	input = strings.TrimSpace(input)
+	if strings.Contains(input, "/") {
+		raw := input
+		if !strings.Contains(raw, "://") {
+			raw = "https://" + raw
+		}
+		u, err := url.Parse(raw)
+		if err != nil {
+			return "", false
+		}
+		switch strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.") {
+		case "x.com", "twitter.com", "mobile.twitter.com", "mobile.x.com":
+		default:
+			return "", false
+		}
+		input = strings.Split(strings.Trim(u.Path, "/"), "/")[0]
+	}
+
+	username := strings.TrimPrefix(input, "@")
+	if !usernameRegex.MatchString(username) {
+		return "", false
+	}
+	return strings.ToLower(username), true
+}
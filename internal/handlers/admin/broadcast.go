@@ -0,0 +1,141 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+
+	"twitterx-bot/internal/handlers/shared"
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/storage"
+	"twitterx-bot/internal/telegram/outbox"
+)
+
+const (
+	// defaultBroadcastInterval sends at most 20 messages per second, below the 30/s Bot API limit.
+	defaultBroadcastInterval = 50 * time.Millisecond
+	// progressEvery is how many chats are processed between progress message updates.
+	progressEvery = 20
+	// maxRetryAfter caps how long a single 429 response may pause the broadcast.
+	maxRetryAfter = time.Minute
+)
+
+// broadcastResult counts the outcome of a broadcast.
+type broadcastResult struct {
+	total, sent, failed, left int
+}
+
+func (r broadcastResult) String() string {
+	return fmt.Sprintf("%d/%d delivered, %d failed, %d left", r.sent, r.total, r.failed, r.left)
+}
+
+// Broadcast handles "/broadcast <text>", or "/broadcast" in reply to a message that is copied.
+// Messages go to every chat the bot is a member of and that is allowed, one by one with
+// rate limiting; a progress message is updated while it runs. Chats that blocked or
+// removed the bot are marked as left.
+func (h *Handler) Broadcast(b *gotgbot.Bot, ctx *ext.Context) error {
+	log := h.commandLog(ctx)
+	msg := ctx.EffectiveMessage
+
+	text := strings.TrimSpace(strings.TrimPrefix(msg.Text, strings.Fields(msg.Text)[0]))
+	if text == "" && msg.ReplyToMessage == nil {
+		return h.reply(b, msg, "Usage: /broadcast <text>, or reply to a message with /broadcast to copy it")
+	}
+
+	targets, err := h.broadcastTargets(context.Background())
+	if err != nil {
+		log.Error("load broadcast targets failed", "err", err)
+		return h.reply(b, msg, "Cannot load chats, try again later.")
+	}
+	if len(targets) == 0 {
+		return h.reply(b, msg, "There are no chats to broadcast to.")
+	}
+
+	progress, err := msg.Reply(b, fmt.Sprintf("📣 Broadcasting to %d chats…", len(targets)), nil)
+	if err != nil {
+		return err
+	}
+	log.Info("broadcast started", "chats", len(targets))
+
+	send := func(chatID int64) error {
+		if msg.ReplyToMessage != nil {
+			_, err := b.CopyMessage(chatID, msg.Chat.Id, msg.ReplyToMessage.MessageId, nil)
+			return err
+		}
+		_, err := b.SendMessage(chatID, text, nil)
+		return err
+	}
+
+	result := broadcastResult{total: len(targets)}
+	for i, chat := range targets {
+		if i > 0 {
+			time.Sleep(h.broadcastInterval)
+		}
+
+		err := sendWithRetry(func() error { return send(chat.ID) })
+		switch {
+		case err == nil:
+			result.sent++
+		case shared.IsBotRemoved(err):
+			result.left++
+			chat.Left = true
+			if err := h.chats.Upsert(context.Background(), chat); err != nil {
+				log.Warn("mark chat left failed", "target_chat_id", chat.ID, "err", err)
+			}
+		default:
+			result.failed++
+			log.Warn("broadcast message failed", "target_chat_id", chat.ID, "err", err)
+		}
+
+		if done := i + 1; done%progressEvery == 0 && done < len(targets) {
+			h.updateProgress(b, progress, fmt.Sprintf("📣 Broadcasting… %s", result), log)
+		}
+	}
+
+	log.Info("broadcast finished", "sent", result.sent, "failed", result.failed, "left", result.left)
+	h.updateProgress(b, progress, fmt.Sprintf("📣 Broadcast finished: %s", result), log)
+	return nil
+}
+
+// broadcastTargets returns the chats the bot is still a member of and allowed to serve.
+func (h *Handler) broadcastTargets(ctx context.Context) ([]storage.Chat, error) {
+	chats, err := h.chats.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var targets []storage.Chat
+	for _, chat := range chats {
+		if chat.Left {
+			continue
+		}
+		allowed, err := h.policy.chatAllowed(ctx, chat.ID)
+		if err != nil {
+			return nil, err
+		}
+		if allowed {
+			targets = append(targets, chat)
+		}
+	}
+	return targets, nil
+}
+
+func (h *Handler) updateProgress(b *gotgbot.Bot, progress *gotgbot.Message, text string, log *logger.Logger) {
+	if _, _, err := progress.EditText(b, text, nil); err != nil {
+		log.Debug("update broadcast progress failed", "err", err)
+	}
+}
+
+// sendWithRetry calls send again once after the delay requested by a 429 response.
+func sendWithRetry(send func() error) error {
+	err := send()
+	wait, ok := outbox.RetryAfter(err)
+	if !ok || wait > maxRetryAfter {
+		return err
+	}
+	time.Sleep(wait)
+	return send()
+}
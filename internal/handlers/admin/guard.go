@@ -0,0 +1,168 @@
+package admin
+
+import (
+	"context"
+	"strings"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+
+	"twitterx-bot/internal/handlers/shared"
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/storage"
+)
+
+// Commands are the owner-only commands; the guard drops them for everyone but bot admins.
+var Commands = []string{"allow", "deny", "ban", "unban", "chats", "broadcast"}
+
+// policy decides whether a chat may use the bot.
+type policy struct {
+	access    storage.AccessRepository
+	allowlist bool
+}
+
+// chatAllowed reports whether the bot serves chatID: denied chats never are and,
+// in allowlist mode, only explicitly allowed ones are.
+func (p policy) chatAllowed(ctx context.Context, chatID int64) (bool, error) {
+	access, ok, err := p.access.Chat(ctx, chatID)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		return access.Allowed, nil
+	}
+	return !p.allowlist, nil
+}
+
+// ChatPolicy tells whether the bot may post to a chat on its own, such as followed tweets.
+type ChatPolicy struct {
+	chats  storage.ChatRepository
+	policy policy
+}
+
+// NewChatPolicy creates the policy for posts the bot makes on its own. allowlist must match the Guard setting.
+func NewChatPolicy(chats storage.ChatRepository, access storage.AccessRepository, allowlist bool) ChatPolicy {
+	return ChatPolicy{chats: chats, policy: policy{access: access, allowlist: allowlist}}
+}
+
+// Serves reports whether the bot is still in chatID and the chat is allowed.
+func (p ChatPolicy) Serves(ctx context.Context, chatID int64) (bool, error) {
+	chat, ok, err := p.chats.Get(ctx, chatID)
+	if err != nil {
+		return false, err
+	}
+	if ok && chat.Left {
+		return false, nil
+	}
+	return p.policy.chatAllowed(ctx, chatID)
+}
+
+// Guard is a dispatcher middleware that ends processing of updates from banned users,
+// denied (or, in allowlist mode, not allowed) chats and owner commands sent by non-admins.
+// Bot admins always pass. Storage errors let the update through.
+type Guard struct {
+	log    *logger.Logger
+	policy policy
+	admins shared.BotAdmins
+}
+
+// NewGuard creates the access middleware. With allowlist set, only chats allowed with /allow are served.
+func NewGuard(log *logger.Logger, access storage.AccessRepository, admins shared.BotAdmins, allowlist bool) Guard {
+	return Guard{log: log, policy: policy{access: access, allowlist: allowlist}, admins: admins}
+}
+
+func (g Guard) CheckUpdate(_ *gotgbot.Bot, ctx *ext.Context) bool {
+	return ctx.EffectiveUser != nil || ctx.EffectiveChat != nil
+}
+
+func (g Guard) HandleUpdate(b *gotgbot.Bot, ctx *ext.Context) error {
+	reason := g.denyReason(ctx)
+	if reason == "" {
+		return nil
+	}
+
+	log := g.log.With("component", "access_guard", "reason", reason)
+	if ctx.EffectiveChat != nil {
+		log = log.With("chat_id", ctx.EffectiveChat.Id)
+	}
+	if ctx.EffectiveUser != nil {
+		log = log.With("user_id", ctx.EffectiveUser.Id, "username", ctx.EffectiveUser.Username)
+	}
+	log.Info("update blocked")
+
+	// Callbacks and inline queries keep spinning on the client until answered
+	switch {
+	case ctx.CallbackQuery != nil:
+		if _, err := ctx.CallbackQuery.Answer(b, &gotgbot.AnswerCallbackQueryOpts{
+			Text: "This bot is not available here.",
+		}); err != nil {
+			log.Debug("answer blocked callback failed", "err", err)
+		}
+	case ctx.InlineQuery != nil:
+		if _, err := ctx.InlineQuery.Answer(b, nil, &gotgbot.AnswerInlineQueryOpts{IsPersonal: true}); err != nil {
+			log.Debug("answer blocked inline query failed", "err", err)
+		}
+	}
+	return ext.EndGroups
+}
+
+func (g Guard) Name() string {
+	return "access_guard"
+}
+
+// denyReason returns why the update must not be handled, or "" to let it through.
+func (g Guard) denyReason(ctx *ext.Context) string {
+	user := ctx.EffectiveUser
+	if user != nil && g.admins.Contains(user.Id) {
+		return ""
+	}
+	if isOwnerCommand(ctx.EffectiveMessage) {
+		return "owner command"
+	}
+
+	reqCtx := context.Background()
+	if user != nil {
+		_, banned, err := g.policy.access.Banned(reqCtx, user.Id)
+		if err != nil {
+			g.log.With("component", "access_guard", "user_id", user.Id).Warn("check ban failed", "err", err)
+		}
+		if banned {
+			return "user banned"
+		}
+	}
+
+	// Inline queries have no chat; they are checked against the user's private chat
+	var chatID int64
+	switch {
+	case ctx.EffectiveChat != nil:
+		chatID = ctx.EffectiveChat.Id
+	case user != nil:
+		chatID = user.Id
+	default:
+		return ""
+	}
+	allowed, err := g.policy.chatAllowed(reqCtx, chatID)
+	if err != nil {
+		g.log.With("component", "access_guard", "chat_id", chatID).Warn("check chat access failed", "err", err)
+		return ""
+	}
+	if !allowed {
+		return "chat not allowed"
+	}
+	return ""
+}
+
+// isOwnerCommand reports whether msg starts with one of Commands, with or without the bot username.
+func isOwnerCommand(msg *gotgbot.Message) bool {
+	if msg == nil || !strings.HasPrefix(msg.Text, "/") {
+		return false
+	}
+	command, _, _ := strings.Cut(strings.Fields(msg.Text)[0][1:], "@")
+	command = strings.ToLower(command)
+	for _, owner := range Commands {
+		if command == owner {
+			return true
+		}
+	}
+	return false
+}
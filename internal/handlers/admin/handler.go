@@ -0,0 +1,267 @@
+// Package admin implements bot owner commands (allowlist, bans, chat list, broadcast)
+// and the access middleware that enforces them.
+package admin
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/storage"
+)
+
+const (
+	commandTimeout = 10 * time.Second
+	// maxListLength keeps /chats pages below the 4096 character message limit.
+	maxListLength = 3500
+)
+
+// Handler implements the owner commands. It relies on Guard to restrict them to bot admins.
+type Handler struct {
+	log    *logger.Logger
+	chats  storage.ChatRepository
+	policy policy
+	now    func() time.Time
+
+	// broadcastInterval spaces out broadcast messages to stay below the Bot API limits.
+	broadcastInterval time.Duration
+}
+
+// New creates the owner command handler. allowlist must match the Guard setting.
+func New(log *logger.Logger, chats storage.ChatRepository, access storage.AccessRepository, allowlist bool) *Handler {
+	return &Handler{
+		log:               log,
+		chats:             chats,
+		policy:            policy{access: access, allowlist: allowlist},
+		now:               time.Now,
+		broadcastInterval: defaultBroadcastInterval,
+	}
+}
+
+// Allow handles "/allow [chat_id]": the current chat is allowed when no ID is given.
+func (h *Handler) Allow(b *gotgbot.Bot, ctx *ext.Context) error {
+	return h.setChatAccess(b, ctx, true)
+}
+
+// Deny handles "/deny [chat_id]": the bot ignores denied chats even outside allowlist mode.
+func (h *Handler) Deny(b *gotgbot.Bot, ctx *ext.Context) error {
+	return h.setChatAccess(b, ctx, false)
+}
+
+func (h *Handler) setChatAccess(b *gotgbot.Bot, ctx *ext.Context, allowed bool) error {
+	log := h.commandLog(ctx)
+	msg := ctx.EffectiveMessage
+
+	chatID := ctx.EffectiveChat.Id
+	if args := ctx.Args()[1:]; len(args) > 0 {
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return h.reply(b, msg, "Usage: /allow [chat_id] or /deny [chat_id]")
+		}
+		chatID = id
+	}
+
+	reqCtx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	if err := h.policy.access.SetChat(reqCtx, storage.ChatAccess{
+		ChatID:    chatID,
+		Allowed:   allowed,
+		UpdatedBy: ctx.EffectiveUser.Id,
+		UpdatedAt: h.now(),
+	}); err != nil {
+		log.Error("set chat access failed", "target_chat_id", chatID, "err", err)
+		return h.reply(b, msg, "Cannot update the allowlist, try again later.")
+	}
+	log.Info("chat access changed", "target_chat_id", chatID, "allowed", allowed)
+
+	name := h.chatName(reqCtx, chatID)
+	if allowed {
+		return h.reply(b, msg, fmt.Sprintf("✅ %s is allowed.", name))
+	}
+	return h.reply(b, msg, fmt.Sprintf("⛔ %s is denied.", name))
+}
+
+// Ban handles "/ban <user_id> [reason]", or "/ban [reason]" in reply to the user's message.
+func (h *Handler) Ban(b *gotgbot.Bot, ctx *ext.Context) error {
+	log := h.commandLog(ctx)
+	msg := ctx.EffectiveMessage
+
+	userID, reason, ok := banTarget(msg, ctx.Args()[1:])
+	if !ok {
+		return h.reply(b, msg, "Usage: /ban <user_id> [reason], or reply to a message with /ban [reason]")
+	}
+
+	reqCtx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	if err := h.policy.access.Ban(reqCtx, storage.Ban{
+		UserID:   userID,
+		Reason:   reason,
+		BannedBy: ctx.EffectiveUser.Id,
+		BannedAt: h.now(),
+	}); err != nil {
+		log.Error("ban user failed", "target_user_id", userID, "err", err)
+		return h.reply(b, msg, "Cannot ban the user, try again later.")
+	}
+	log.Info("user banned", "target_user_id", userID, "reason", reason)
+	return h.reply(b, msg, fmt.Sprintf("🚫 User %d is banned.", userID))
+}
+
+// Unban handles "/unban <user_id>".
+func (h *Handler) Unban(b *gotgbot.Bot, ctx *ext.Context) error {
+	log := h.commandLog(ctx)
+	msg := ctx.EffectiveMessage
+
+	args := ctx.Args()[1:]
+	if len(args) != 1 {
+		return h.reply(b, msg, "Usage: /unban <user_id>")
+	}
+	userID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return h.reply(b, msg, "Usage: /unban <user_id>")
+	}
+
+	reqCtx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	removed, err := h.policy.access.Unban(reqCtx, userID)
+	if err != nil {
+		log.Error("unban user failed", "target_user_id", userID, "err", err)
+		return h.reply(b, msg, "Cannot unban the user, try again later.")
+	}
+	if !removed {
+		return h.reply(b, msg, fmt.Sprintf("User %d is not banned.", userID))
+	}
+	log.Info("user unbanned", "target_user_id", userID)
+	return h.reply(b, msg, fmt.Sprintf("User %d is unbanned.", userID))
+}
+
+// Chats handles "/chats": lists the chats the bot is a member of with their access state.
+func (h *Handler) Chats(b *gotgbot.Bot, ctx *ext.Context) error {
+	log := h.commandLog(ctx)
+	msg := ctx.EffectiveMessage
+
+	reqCtx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	chats, err := h.chats.List(reqCtx)
+	if err != nil {
+		log.Error("list chats failed", "err", err)
+		return h.reply(b, msg, "Cannot load chats, try again later.")
+	}
+	accessList, err := h.policy.access.ListChats(reqCtx)
+	if err != nil {
+		log.Error("list chat access failed", "err", err)
+		return h.reply(b, msg, "Cannot load chats, try again later.")
+	}
+	access := make(map[int64]bool, len(accessList))
+	for _, entry := range accessList {
+		access[entry.ChatID] = entry.Allowed
+	}
+
+	var lines []string
+	for _, chat := range chats {
+		if chat.Left {
+			continue
+		}
+		lines = append(lines, formatChatLine(chat, access, h.policy.allowlist))
+	}
+	if len(lines) == 0 {
+		return h.reply(b, msg, "The bot is not a member of any chat yet.")
+	}
+
+	header := fmt.Sprintf("<b>Chats</b> (%d)\n", len(lines))
+	for _, page := range paginate(lines, maxListLength) {
+		if _, err := msg.Reply(b, header+page, &gotgbot.SendMessageOpts{ParseMode: "HTML"}); err != nil {
+			return err
+		}
+		header = ""
+	}
+	return nil
+}
+
+// formatChatLine renders a chat as: ✅ Title (@username) · supergroup · <code>id</code>.
+func formatChatLine(chat storage.Chat, access map[int64]bool, allowlist bool) string {
+	mark := "▫️"
+	if allowed, ok := access[chat.ID]; ok {
+		mark = "⛔"
+		if allowed {
+			mark = "✅"
+		}
+	} else if allowlist {
+		mark = "⛔"
+	}
+
+	name := chat.Title
+	if chat.Username != "" {
+		name = strings.TrimSpace(name + " @" + chat.Username)
+	}
+	if name == "" {
+		name = "untitled"
+	}
+	return fmt.Sprintf("%s %s · %s · <code>%d</code>", mark, html.EscapeString(name), html.EscapeString(chat.Type), chat.ID)
+}
+
+// paginate joins lines into pages no longer than max bytes.
+func paginate(lines []string, max int) []string {
+	var pages []string
+	var sb strings.Builder
+	for _, line := range lines {
+		if sb.Len() > 0 && sb.Len()+len(line)+1 > max {
+			pages = append(pages, sb.String())
+			sb.Reset()
+		}
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+	}
+	if sb.Len() > 0 {
+		pages = append(pages, sb.String())
+	}
+	return pages
+}
+
+// banTarget extracts the user to ban from the arguments or the replied-to message.
+func banTarget(msg *gotgbot.Message, args []string) (int64, string, bool) {
+	if len(args) > 0 {
+		if id, err := strconv.ParseInt(args[0], 10, 64); err == nil {
+			return id, strings.Join(args[1:], " "), true
+		}
+	}
+	if reply := msg.ReplyToMessage; reply != nil && reply.From != nil {
+		return reply.From.Id, strings.Join(args, " "), true
+	}
+	return 0, "", false
+}
+
+// chatName describes a chat by its known title, falling back to the ID.
+func (h *Handler) chatName(ctx context.Context, chatID int64) string {
+	chat, ok, err := h.chats.Get(ctx, chatID)
+	if err != nil || !ok || chat.Title == "" {
+		return fmt.Sprintf("Chat %d", chatID)
+	}
+	return fmt.Sprintf("%s (%d)", chat.Title, chatID)
+}
+
+func (h *Handler) commandLog(ctx *ext.Context) *logger.Logger {
+	log := h.log.With("component", "admin")
+	if ctx.EffectiveChat != nil {
+		log = log.With("chat_id", ctx.EffectiveChat.Id)
+	}
+	if ctx.EffectiveUser != nil {
+		log = log.With("user_id", ctx.EffectiveUser.Id, "username", ctx.EffectiveUser.Username)
+	}
+	return log
+}
+
+func (h *Handler) reply(b *gotgbot.Bot, msg *gotgbot.Message, text string) error {
+	_, err := msg.Reply(b, text, nil)
+	return err
+}
@@ -0,0 +1,288 @@
+package admin_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+
+	"twitterx-bot/internal/handlers"
+	"twitterx-bot/internal/handlers/admin"
+	"twitterx-bot/internal/handlers/testutil"
+	"twitterx-bot/internal/storage"
+	"twitterx-bot/internal/twitterxapi"
+	testtelegram "twitterx-bot/pkg/testutil/telegram"
+)
+
+const (
+	adminID = int64(42)
+	userID  = int64(1001)
+	groupID = int64(-1001234567890)
+	link    = "https://x.com/testuser/status/123"
+)
+
+var group = gotgbot.Chat{Id: groupID, Type: "supergroup", Title: "Friends"}
+
+func setup(t *testing.T, opts ...handlers.Option) (*gotgbot.Bot, *testtelegram.MockServer, *ext.Dispatcher) {
+	fakeAPI := &testutil.FakeTweetAPI{
+		Tweets: map[string]*twitterxapi.Tweet{
+			"testuser/123": {
+				ID:     "123",
+				URL:    link,
+				Text:   "Hello",
+				Author: twitterxapi.Author{Name: "Test User", ScreenName: "testuser"},
+			},
+		},
+	}
+	opts = append([]handlers.Option{handlers.WithStorage(storage.NewMemory()), handlers.WithAdmins(adminID)}, opts...)
+	return testutil.SetupBotAndDispatcherWithOptions(t, fakeAPI, nil, opts...)
+}
+
+func send(t *testing.T, bot *gotgbot.Bot, dispatcher *ext.Dispatcher, chat gotgbot.Chat, fromID int64, text string) {
+	t.Helper()
+	msg := &gotgbot.Message{
+		MessageId: 10,
+		Text:      text,
+		Chat:      chat,
+		From:      &gotgbot.User{Id: fromID, FirstName: "User"},
+	}
+	if strings.HasPrefix(text, "/") {
+		command, _, _ := strings.Cut(text, " ")
+		msg.Entities = []gotgbot.MessageEntity{{Type: "bot_command", Offset: 0, Length: int64(len(command))}}
+	}
+	if err := dispatcher.ProcessUpdate(bot, &gotgbot.Update{UpdateId: 1, Message: msg}, nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+}
+
+func private(id int64) gotgbot.Chat {
+	return gotgbot.Chat{Id: id, Type: "private", FirstName: "User"}
+}
+
+func messagesTo(mock *testtelegram.MockServer, chatID int64) []string {
+	var texts []string
+	for _, call := range mock.GetCalls("sendMessage") {
+		if id, _ := call.JSONInt64("chat_id"); id == chatID {
+			text, _ := call.JSONString("text")
+			texts = append(texts, text)
+		}
+	}
+	return texts
+}
+
+func TestIntegration_Admin_OwnerCommandsIgnoredForUsers(t *testing.T) {
+	bot, mock, dispatcher := setup(t)
+
+	send(t, bot, dispatcher, private(userID), userID, "/ban 7")
+	send(t, bot, dispatcher, private(userID), userID, "/chats")
+
+	if calls := mock.GetCalls("sendMessage"); len(calls) != 0 {
+		t.Errorf("sendMessage calls = %d, want owner commands ignored", len(calls))
+	}
+}
+
+func TestIntegration_Admin_BanBlocksUser(t *testing.T) {
+	bot, mock, dispatcher := setup(t)
+
+	send(t, bot, dispatcher, private(adminID), adminID, "/ban 1001 spam")
+	if texts := messagesTo(mock, adminID); len(texts) != 1 || !strings.Contains(texts[0], "User 1001 is banned") {
+		t.Fatalf("admin replies = %q, want ban confirmation", texts)
+	}
+
+	send(t, bot, dispatcher, group, userID, link)
+	if texts := messagesTo(mock, groupID); len(texts) != 0 {
+		t.Errorf("banned user got replies %q", texts)
+	}
+
+	send(t, bot, dispatcher, private(adminID), adminID, "/unban 1001")
+	send(t, bot, dispatcher, group, userID, link)
+	if texts := messagesTo(mock, groupID); len(texts) != 1 {
+		t.Errorf("group replies after unban = %d, want the tweet", len(texts))
+	}
+}
+
+func TestIntegration_Admin_DenyAndAllowChat(t *testing.T) {
+	bot, mock, dispatcher := setup(t)
+
+	send(t, bot, dispatcher, group, adminID, "/deny")
+	send(t, bot, dispatcher, group, userID, link)
+	texts := messagesTo(mock, groupID)
+	if len(texts) != 1 || !strings.Contains(texts[0], "Friends (-1001234567890) is denied") {
+		t.Fatalf("group replies = %q, want only the deny confirmation", texts)
+	}
+
+	send(t, bot, dispatcher, private(adminID), adminID, "/allow -1001234567890")
+	send(t, bot, dispatcher, group, userID, link)
+	if texts := messagesTo(mock, groupID); len(texts) != 2 {
+		t.Errorf("group replies after allow = %d, want the tweet", len(texts))
+	}
+}
+
+func TestIntegration_Admin_AllowlistMode(t *testing.T) {
+	bot, mock, dispatcher := setup(t, handlers.WithAllowlist(true))
+
+	send(t, bot, dispatcher, group, userID, link)
+	if texts := messagesTo(mock, groupID); len(texts) != 0 {
+		t.Fatalf("unlisted group got replies %q", texts)
+	}
+
+	update := &gotgbot.Update{
+		UpdateId: 2,
+		CallbackQuery: &gotgbot.CallbackQuery{
+			Id:      "cb-blocked",
+			Data:    "chain:testuser:123:10",
+			From:    gotgbot.User{Id: userID, FirstName: "User"},
+			Message: &gotgbot.Message{MessageId: 11, Date: 1000000, Chat: group},
+		},
+	}
+	if err := dispatcher.ProcessUpdate(bot, update, nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+	answers := mock.GetCalls("answerCallbackQuery")
+	if len(answers) != 1 {
+		t.Fatalf("answerCallbackQuery calls = %d, want 1", len(answers))
+	}
+	if text, _ := answers[0].JSONString("text"); !strings.Contains(text, "not available") {
+		t.Errorf("callback answer = %q, want not available", text)
+	}
+
+	send(t, bot, dispatcher, private(adminID), adminID, link)
+	if texts := messagesTo(mock, adminID); len(texts) != 1 {
+		t.Errorf("admin replies = %d, want admins always served", len(texts))
+	}
+
+	send(t, bot, dispatcher, private(adminID), adminID, "/allow -1001234567890")
+	send(t, bot, dispatcher, group, userID, link)
+	if texts := messagesTo(mock, groupID); len(texts) != 1 {
+		t.Errorf("allowed group replies = %d, want the tweet", len(texts))
+	}
+}
+
+func TestIntegration_Admin_ChatsSkipsLeftChats(t *testing.T) {
+	bot, mock, dispatcher := setup(t)
+
+	send(t, bot, dispatcher, group, userID, "hello")
+	gone := gotgbot.Chat{Id: -100777, Type: "group", Title: "Gone"}
+	send(t, bot, dispatcher, gone, userID, "hello")
+	kicked := &gotgbot.Update{
+		UpdateId: 3,
+		MyChatMember: &gotgbot.ChatMemberUpdated{
+			Chat:          gone,
+			From:          gotgbot.User{Id: userID, FirstName: "User"},
+			NewChatMember: gotgbot.ChatMemberBanned{User: gotgbot.User{Id: 123456, IsBot: true, FirstName: "MockBot"}},
+			OldChatMember: gotgbot.ChatMemberMember{User: gotgbot.User{Id: 123456, IsBot: true, FirstName: "MockBot"}},
+		},
+	}
+	if err := dispatcher.ProcessUpdate(bot, kicked, nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	send(t, bot, dispatcher, private(adminID), adminID, "/chats")
+	texts := messagesTo(mock, adminID)
+	if len(texts) != 1 {
+		t.Fatalf("admin replies = %d, want 1", len(texts))
+	}
+	if !strings.Contains(texts[0], "Friends · supergroup · <code>-1001234567890</code>") {
+		t.Errorf("chat list = %q, want the group", texts[0])
+	}
+	if strings.Contains(texts[0], "Gone") {
+		t.Errorf("chat list = %q, want left chat skipped", texts[0])
+	}
+}
+
+func TestIntegration_Admin_BroadcastReportsProgress(t *testing.T) {
+	bot, mock, dispatcher := setup(t)
+
+	send(t, bot, dispatcher, group, userID, "hello")
+	send(t, bot, dispatcher, private(userID), userID, "hello")
+	send(t, bot, dispatcher, private(adminID), adminID, "/broadcast Maintenance tonight")
+
+	for _, chatID := range []int64{groupID, userID, adminID} {
+		found := false
+		for _, text := range messagesTo(mock, chatID) {
+			found = found || text == "Maintenance tonight"
+		}
+		if !found {
+			t.Errorf("chat %d did not get the broadcast", chatID)
+		}
+	}
+
+	edits := mock.GetCalls("editMessageText")
+	if len(edits) == 0 {
+		t.Fatal("progress message was not updated")
+	}
+	if text, _ := edits[len(edits)-1].JSONString("text"); !strings.Contains(text, "finished: 3/3 delivered, 0 failed, 0 left") {
+		t.Errorf("final progress = %q, want all delivered", text)
+	}
+}
+
+func TestIntegration_Admin_BroadcastMarksBlockedChatsLeft(t *testing.T) {
+	bot, mock, dispatcher := setup(t)
+	mock.SetTelegramError("copyMessage", 403, "Forbidden: bot was blocked by the user")
+
+	send(t, bot, dispatcher, private(userID), userID, "hello")
+	update := &gotgbot.Update{
+		UpdateId: 4,
+		Message: &gotgbot.Message{
+			MessageId:      20,
+			Text:           "/broadcast",
+			Chat:           private(adminID),
+			From:           &gotgbot.User{Id: adminID, FirstName: "Admin"},
+			Entities:       []gotgbot.MessageEntity{{Type: "bot_command", Offset: 0, Length: 10}},
+			ReplyToMessage: &gotgbot.Message{MessageId: 19, Chat: private(adminID), Text: "News"},
+		},
+	}
+	if err := dispatcher.ProcessUpdate(bot, update, nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	if calls := mock.GetCalls("copyMessage"); len(calls) != 2 {
+		t.Errorf("copyMessage calls = %d, want 2", len(calls))
+	}
+	edits := mock.GetCalls("editMessageText")
+	if len(edits) == 0 {
+		t.Fatal("progress message was not updated")
+	}
+	if text, _ := edits[len(edits)-1].JSONString("text"); !strings.Contains(text, "0/2 delivered, 0 failed, 2 left") {
+		t.Errorf("final progress = %q, want both chats left", text)
+	}
+
+	// The admin chat is seen again with the next command; the blocked user stays skipped
+	send(t, bot, dispatcher, private(adminID), adminID, "/broadcast again")
+	if texts := messagesTo(mock, userID); len(texts) != 0 {
+		t.Errorf("left chat got %q", texts)
+	}
+	edits = mock.GetCalls("editMessageText")
+	if text, _ := edits[len(edits)-1].JSONString("text"); !strings.Contains(text, "1/1 delivered") {
+		t.Errorf("final progress = %q, want only the admin chat", text)
+	}
+}
+
+func TestChatPolicy_Serves(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemory()
+	_ = store.Chats().Upsert(ctx, storage.Chat{ID: -1, Type: "group", Left: true})
+	_ = store.Chats().Upsert(ctx, storage.Chat{ID: -2, Type: "group"})
+	_ = store.Access().SetChat(ctx, storage.ChatAccess{ChatID: -3, Allowed: false})
+	_ = store.Access().SetChat(ctx, storage.ChatAccess{ChatID: -4, Allowed: true})
+
+	tests := []struct {
+		allowlist bool
+		chatID    int64
+		want      bool
+	}{
+		{false, -1, false},
+		{false, -2, true},
+		{false, -3, false},
+		{true, -2, false},
+		{true, -4, true},
+	}
+	for _, tt := range tests {
+		got, err := admin.NewChatPolicy(store.Chats(), store.Access(), tt.allowlist).Serves(ctx, tt.chatID)
+		if err != nil || got != tt.want {
+			t.Errorf("Serves(%d) with allowlist %v = %v, %v; want %v", tt.chatID, tt.allowlist, got, err, tt.want)
+		}
+	}
+}
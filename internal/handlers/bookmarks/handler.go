@@ -0,0 +1,183 @@
+// Package bookmarks implements the "⭐ Save" button under tweets and /saved, the personal
+// library of saved tweets.
+package bookmarks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/storage"
+	"twitterx-bot/internal/telegram/tweet"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+// TweetFetcher fetches tweets by username and tweet ID.
+type TweetFetcher interface {
+	GetTweet(ctx context.Context, username, tweetID string) (*twitterxapi.Tweet, error)
+}
+
+// Handler saves tweets for users and browses their bookmarks.
+type Handler struct {
+	log       *logger.Logger
+	bookmarks storage.BookmarkRepository
+	fetcher   TweetFetcher
+	timeout   time.Duration
+	sender    tweet.Sender
+	now       func() time.Time
+}
+
+// New creates the bookmark handlers. The sender resends saved tweets; Bot and Log are set per update.
+func New(log *logger.Logger, bookmarks storage.BookmarkRepository, fetcher TweetFetcher, timeout time.Duration, sender tweet.Sender) *Handler {
+	return &Handler{log: log, bookmarks: bookmarks, fetcher: fetcher, timeout: timeout, sender: sender, now: time.Now}
+}
+
+// Save handles the "⭐ Save" button: the tweet is fetched and stored for the user who pressed it.
+func (h *Handler) Save(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.CallbackQuery
+	log := h.log.With("component", "bookmarks", "callback", "save", "callback_id", cb.Id, "user_id", cb.From.Id, "username", cb.From.Username)
+
+	username, tweetID, ok := tweet.DecodeSaveCallback(cb.Data)
+	if !ok {
+		log.Error("decode save callback failed", "data", cb.Data)
+		return answer(b, cb, "Invalid callback data", false)
+	}
+	log = log.With("tweet_username", username, "tweet_id", tweetID)
+
+	reqCtx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+
+	tw, err := h.fetcher.GetTweet(reqCtx, username, tweetID)
+	if err != nil || tw == nil {
+		log.Warn("fetch tweet to save failed", "err", err)
+		return answer(b, cb, "Cannot load the tweet, try again later", false)
+	}
+
+	_, added, err := h.bookmarks.Add(reqCtx, storage.Bookmark{
+		UserID:    cb.From.Id,
+		TweetID:   tweetID,
+		Author:    username,
+		Tweet:     tw,
+		CreatedAt: h.now(),
+	})
+	if err != nil {
+		log.Error("add bookmark failed", "err", err)
+		return answer(b, cb, "Cannot save the tweet, try again later", false)
+	}
+	if !added {
+		return answer(b, cb, "Already in your saved tweets", false)
+	}
+
+	log.Info("tweet saved")
+	return answer(b, cb, "⭐ Saved. Send /saved to me in a private chat to see your saved tweets.", false)
+}
+
+// Saved handles "/saved" and "/saved <search>" in a private chat.
+func (h *Handler) Saved(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+	if ctx.EffectiveChat == nil || ctx.EffectiveChat.Type != gotgbot.ChatTypePrivate || ctx.EffectiveUser == nil {
+		_, err := msg.Reply(b, "Saved tweets are personal. Send /saved to me in a private chat.", nil)
+		return err
+	}
+	log := h.log.With("component", "bookmarks", "user_id", ctx.EffectiveUser.Id, "username", ctx.EffectiveUser.Username)
+
+	view := pageView{Search: searchQuery(commandPayload(msg.Text))}
+	text, markup, err := h.render(context.Background(), ctx.EffectiveUser.Id, &view)
+	if err != nil {
+		log.Error("list bookmarks failed", "err", err)
+		_, err := msg.Reply(b, "Cannot load your saved tweets, try again later.", nil)
+		return err
+	}
+
+	_, err = msg.Reply(b, text, &gotgbot.SendMessageOpts{
+		ParseMode:          "HTML",
+		ReplyMarkup:        markup,
+		LinkPreviewOptions: &gotgbot.LinkPreviewOptions{IsDisabled: true},
+	})
+	return err
+}
+
+// Callback handles the buttons of the /saved list: turning pages, removing and resending tweets.
+func (h *Handler) Callback(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.CallbackQuery
+	log := h.log.With("component", "bookmarks", "callback_id", cb.Id, "user_id", cb.From.Id, "username", cb.From.Username)
+
+	action, ok := decodeCallback(cb.Data)
+	if !ok || cb.Message == nil || ctx.EffectiveChat == nil {
+		log.Error("decode bookmarks callback failed", "data", cb.Data)
+		return answer(b, cb, "Invalid callback data", false)
+	}
+	reqCtx := context.Background()
+	userID := cb.From.Id
+
+	if action.Kind == actionSend {
+		return h.resend(b, cb, ctx.EffectiveChat.Id, log, action.ID)
+	}
+
+	notice := ""
+	if action.Kind == actionRemove {
+		removed, err := h.bookmarks.Remove(reqCtx, userID, action.ID)
+		if err != nil {
+			log.Error("remove bookmark failed", "bookmark_id", action.ID, "err", err)
+			return answer(b, cb, "Cannot remove the tweet, try again later", false)
+		}
+		if removed {
+			log.Info("bookmark removed", "bookmark_id", action.ID)
+			notice = "Removed"
+		}
+	}
+
+	text, markup, err := h.render(reqCtx, userID, &action.View)
+	if err != nil {
+		log.Error("list bookmarks failed", "err", err)
+		return answer(b, cb, "Cannot load your saved tweets, try again later", false)
+	}
+	if _, _, err := b.EditMessageText(text, &gotgbot.EditMessageTextOpts{
+		ChatId:             ctx.EffectiveChat.Id,
+		MessageId:          cb.Message.GetMessageId(),
+		ParseMode:          "HTML",
+		ReplyMarkup:        *markup,
+		LinkPreviewOptions: &gotgbot.LinkPreviewOptions{IsDisabled: true},
+	}); err != nil {
+		log.Debug("edit saved tweets failed", "err", err)
+	}
+	return answer(b, cb, notice, false)
+}
+
+// resend sends the saved snapshot of the tweet to the chat of the list.
+func (h *Handler) resend(b *gotgbot.Bot, cb *gotgbot.CallbackQuery, chatID int64, log *logger.Logger, id int64) error {
+	reqCtx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+
+	bookmark, ok, err := h.bookmarks.Get(reqCtx, cb.From.Id, id)
+	if err != nil {
+		log.Error("get bookmark failed", "bookmark_id", id, "err", err)
+		return answer(b, cb, "Cannot load the tweet, try again later", false)
+	}
+	if !ok || bookmark.Tweet == nil {
+		return answer(b, cb, "This tweet is no longer saved", false)
+	}
+	if err := answer(b, cb, "Sending...", false); err != nil {
+		log.Debug("answer callback failed", "err", err)
+	}
+
+	sender := h.sender
+	sender.Bot = b
+	sender.Log = log
+	if err := sender.SendTweet(reqCtx, chatID, 0, bookmark.Tweet, nil); err != nil {
+		log.Error("resend saved tweet failed", "bookmark_id", id, "err", err)
+		_, err := b.SendMessage(chatID, fmt.Sprintf("Cannot send the saved tweet by @%s, try again later.", bookmark.Author), nil)
+		return err
+	}
+	log.Info("saved tweet resent", "bookmark_id", id, "tweet_id", bookmark.TweetID)
+	return nil
+}
+
+func answer(b *gotgbot.Bot, cb *gotgbot.CallbackQuery, text string, alert bool) error {
+	_, err := cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: text, ShowAlert: alert})
+	return err
+}
@@ -0,0 +1,226 @@
+package bookmarks_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+
+	"twitterx-bot/internal/handlers"
+	"twitterx-bot/internal/handlers/testutil"
+	"twitterx-bot/internal/storage"
+	"twitterx-bot/internal/telegram/tweet"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+const userID = int64(8008)
+
+func privateMessage(updateID, msgID int64, text string) *gotgbot.Update {
+	return &gotgbot.Update{
+		UpdateId: updateID,
+		Message: &gotgbot.Message{
+			MessageId: msgID,
+			Text:      text,
+			Chat:      gotgbot.Chat{Id: userID, Type: "private"},
+			From:      &gotgbot.User{Id: userID, FirstName: "Sam"},
+		},
+	}
+}
+
+func callback(updateID int64, data string, chat gotgbot.Chat, msgID int64) *gotgbot.Update {
+	return &gotgbot.Update{
+		UpdateId: updateID,
+		CallbackQuery: &gotgbot.CallbackQuery{
+			Id:      fmt.Sprintf("cb-%d", updateID),
+			Data:    data,
+			From:    gotgbot.User{Id: userID, FirstName: "Sam"},
+			Message: &gotgbot.Message{MessageId: msgID, Chat: chat},
+		},
+	}
+}
+
+func savedTweet(id, text string) *twitterxapi.Tweet {
+	return &twitterxapi.Tweet{
+		ID:     id,
+		URL:    "https://x.com/nasa/status/" + id,
+		Text:   text,
+		Author: twitterxapi.Author{Name: "NASA", ScreenName: "nasa"},
+	}
+}
+
+func TestIntegration_SaveButton_StoresTweetForPressingUser(t *testing.T) {
+	store := storage.NewMemory()
+	fakeAPI := &testutil.FakeTweetAPI{Tweets: map[string]*twitterxapi.Tweet{"nasa/1": savedTweet("1", "Launch day")}}
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, fakeAPI, nil, handlers.WithStorage(store))
+
+	group := gotgbot.Chat{Id: -100, Type: "supergroup"}
+	link := &gotgbot.Update{
+		UpdateId: 1,
+		Message: &gotgbot.Message{
+			MessageId: 10,
+			Text:      "https://x.com/nasa/status/1",
+			Chat:      group,
+			From:      &gotgbot.User{Id: 1, FirstName: "Poster"},
+		},
+	}
+	if err := dispatcher.ProcessUpdate(bot, link, nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+	sent := mock.GetCalls("sendMessage")
+	if len(sent) != 1 {
+		t.Fatalf("sendMessage calls = %d, want 1", len(sent))
+	}
+	markup, _ := sent[0].JSONString("reply_markup")
+	if !strings.Contains(markup, tweet.EncodeSaveCallback("nasa", "1")) {
+		t.Fatalf("tweet reply_markup = %s, want a save button", markup)
+	}
+
+	for i := int64(2); i <= 3; i++ {
+		if err := dispatcher.ProcessUpdate(bot, callback(i, tweet.EncodeSaveCallback("nasa", "1"), group, 11), nil); err != nil {
+			t.Fatalf("ProcessUpdate() error = %v", err)
+		}
+	}
+
+	answers := mock.GetCalls("answerCallbackQuery")
+	if len(answers) != 2 {
+		t.Fatalf("answerCallbackQuery calls = %d, want 2", len(answers))
+	}
+	if text, _ := answers[0].JSONString("text"); !strings.Contains(text, "Saved") {
+		t.Errorf("first answer = %q, want saved", text)
+	}
+	if text, _ := answers[1].JSONString("text"); !strings.Contains(text, "Already") {
+		t.Errorf("second answer = %q, want already saved", text)
+	}
+
+	list, total, _ := store.Bookmarks().List(context.Background(), storage.BookmarkFilter{UserID: userID})
+	if total != 1 || list[0].TweetID != "1" || list[0].Tweet.Text != "Launch day" {
+		t.Errorf("bookmarks = %+v, want the tweet saved once", list)
+	}
+}
+
+func TestIntegration_Saved_PagesSearchRemoveResend(t *testing.T) {
+	store := storage.NewMemory()
+	ctx := context.Background()
+	base := time.Now().Add(-time.Hour)
+	for i := 1; i <= 7; i++ {
+		text := fmt.Sprintf("Launch %d", i)
+		if i == 3 {
+			text = "Moon photos"
+		}
+		id := fmt.Sprint(i)
+		if _, _, err := store.Bookmarks().Add(ctx, storage.Bookmark{UserID: userID, TweetID: id, Author: "nasa", Tweet: savedTweet(id, text), CreatedAt: base.Add(time.Duration(i) * time.Minute)}); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, nil, nil, handlers.WithStorage(store))
+	pm := gotgbot.Chat{Id: userID, Type: "private"}
+
+	if err := dispatcher.ProcessUpdate(bot, privateMessage(1, 10, "/saved"), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+	sent := mock.GetCalls("sendMessage")
+	if len(sent) != 1 {
+		t.Fatalf("sendMessage calls = %d, want 1", len(sent))
+	}
+	text, _ := sent[0].JSONString("text")
+	if !strings.Contains(text, "Saved tweets</b>: 7") || !strings.Contains(text, "Launch 7") || strings.Contains(text, "Launch 2") || !strings.Contains(text, "Page 1 of 2") {
+		t.Errorf("first page = %q, want the 5 newest of 7", text)
+	}
+	markup, _ := sent[0].JSONString("reply_markup")
+	if !strings.Contains(markup, `"bm:page:1:"`) || !strings.Contains(markup, `"bm:send:7"`) {
+		t.Errorf("first page keyboard = %s, want next and send buttons", markup)
+	}
+
+	if err := dispatcher.ProcessUpdate(bot, callback(2, "bm:page:1:", pm, 11), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+	edits := mock.GetCalls("editMessageText")
+	if len(edits) != 1 {
+		t.Fatalf("editMessageText calls = %d, want 1", len(edits))
+	}
+	if text, _ := edits[0].JSONString("text"); !strings.Contains(text, "Launch 1") || !strings.Contains(text, "Page 2 of 2") {
+		t.Errorf("second page = %q", text)
+	}
+
+	if err := dispatcher.ProcessUpdate(bot, privateMessage(3, 12, "/saved MOON"), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+	sent = mock.GetCalls("sendMessage")
+	if text, _ := sent[1].JSONString("text"); !strings.Contains(text, "matching <code>MOON</code>: 1") || !strings.Contains(text, "Moon photos") {
+		t.Errorf("search = %q, want the moon tweet only", text)
+	}
+
+	if err := dispatcher.ProcessUpdate(bot, callback(4, "bm:rm:3:0:MOON", pm, 13), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+	if _, ok, _ := store.Bookmarks().Get(ctx, userID, 3); ok {
+		t.Error("bookmark 3 still saved after removal")
+	}
+	edits = mock.GetCalls("editMessageText")
+	if text, _ := edits[1].JSONString("text"); !strings.Contains(text, "No saved tweets match") {
+		t.Errorf("list after removal = %q", text)
+	}
+
+	if err := dispatcher.ProcessUpdate(bot, callback(5, "bm:send:5", pm, 11), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+	sent = mock.GetCalls("sendMessage")
+	if len(sent) != 3 {
+		t.Fatalf("sendMessage calls = %d, want the resent tweet", len(sent))
+	}
+	if text, _ := sent[2].JSONString("text"); !strings.Contains(text, "Launch 5") {
+		t.Errorf("resent tweet = %q", text)
+	}
+}
+
+func TestIntegration_Saved_InGroup_AsksForPrivateChat(t *testing.T) {
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, nil, nil, handlers.WithStorage(storage.NewMemory()))
+
+	update := privateMessage(1, 10, "/saved")
+	update.Message.Chat = gotgbot.Chat{Id: -100, Type: "group"}
+	if err := dispatcher.ProcessUpdate(bot, update, nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	calls := mock.GetCalls("sendMessage")
+	if len(calls) != 1 {
+		t.Fatalf("sendMessage calls = %d, want 1", len(calls))
+	}
+	if text, _ := calls[0].JSONString("text"); !strings.Contains(text, "private chat") {
+		t.Errorf("reply = %q", text)
+	}
+}
+
+func TestIntegration_InlineEmptyQuery_OffersSavedTweets(t *testing.T) {
+	store := storage.NewMemory()
+	for i := 1; i <= 2; i++ {
+		id := fmt.Sprint(i)
+		if _, _, err := store.Bookmarks().Add(context.Background(), storage.Bookmark{UserID: userID, TweetID: id, Author: "nasa", Tweet: savedTweet(id, "Saved "+id), CreatedAt: time.Now()}); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, nil, nil, handlers.WithStorage(store))
+
+	update := &gotgbot.Update{
+		UpdateId:    1,
+		InlineQuery: &gotgbot.InlineQuery{Id: "inline-saved", From: gotgbot.User{Id: userID, FirstName: "Sam"}},
+	}
+	if err := dispatcher.ProcessUpdate(bot, update, nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	calls := mock.GetCalls("answerInlineQuery")
+	if len(calls) != 1 {
+		t.Fatalf("answerInlineQuery calls = %d, want 1", len(calls))
+	}
+	results := testutil.DecodeInlineResults(t, calls[0])
+	if len(results) != 2 {
+		t.Fatalf("results = %d, want the 2 saved tweets", len(results))
+	}
+	if offset, _ := calls[0].JSONString("next_offset"); offset != "" {
+		t.Errorf("next_offset = %q, want none", offset)
+	}
+}
@@ -0,0 +1,201 @@
+package bookmarks
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+
+	"twitterx-bot/internal/storage"
+	"twitterx-bot/internal/telegram/tweet"
+)
+
+// CallbackPrefix marks callback data of the /saved list.
+// Format: bm:page:<page>:<search>, bm:rm:<id>:<page>:<search>, bm:send:<id>
+const CallbackPrefix = "bm:"
+
+const (
+	pageSize      = 5
+	snippetLength = 100
+	// maxSearchLength keeps the search, which every button carries, within the 64 bytes of callback data.
+	maxSearchLength = 32
+)
+
+type actionKind string
+
+const (
+	actionPage   actionKind = "page"
+	actionRemove actionKind = "rm"
+	actionSend   actionKind = "send"
+)
+
+// pageView is the page of the list being shown.
+type pageView struct {
+	Page   int
+	Search string
+}
+
+// callbackAction is a decoded button press of the list.
+type callbackAction struct {
+	Kind actionKind
+	// ID is the bookmark to remove or resend.
+	ID   int64
+	View pageView
+}
+
+func encodePage(view pageView) string {
+	return fmt.Sprintf("%s%s:%d:%s", CallbackPrefix, actionPage, view.Page, view.Search)
+}
+
+func encodeRemove(id int64, view pageView) string {
+	return fmt.Sprintf("%s%s:%d:%d:%s", CallbackPrefix, actionRemove, id, view.Page, view.Search)
+}
+
+func encodeSend(id int64) string {
+	return fmt.Sprintf("%s%s:%d", CallbackPrefix, actionSend, id)
+}
+
+func decodeCallback(data string) (callbackAction, bool) {
+	kind, rest, ok := strings.Cut(strings.TrimPrefix(data, CallbackPrefix), ":")
+	if !ok || !strings.HasPrefix(data, CallbackPrefix) {
+		return callbackAction{}, false
+	}
+
+	action := callbackAction{Kind: actionKind(kind)}
+	switch action.Kind {
+	case actionPage:
+		view, ok := decodeView(rest)
+		action.View = view
+		return action, ok
+	case actionRemove:
+		id, view, ok := strings.Cut(rest, ":")
+		if !ok {
+			return callbackAction{}, false
+		}
+		action.ID, ok = parseID(id)
+		if !ok {
+			return callbackAction{}, false
+		}
+		action.View, ok = decodeView(view)
+		return action, ok
+	case actionSend:
+		action.ID, ok = parseID(rest)
+		return action, ok
+	default:
+		return callbackAction{}, false
+	}
+}
+
+// decodeView parses <page>:<search>; the search may contain colons.
+func decodeView(data string) (pageView, bool) {
+	page, search, ok := strings.Cut(data, ":")
+	if !ok {
+		return pageView{}, false
+	}
+	n, err := strconv.Atoi(page)
+	if err != nil || n < 0 {
+		return pageView{}, false
+	}
+	return pageView{Page: n, Search: search}, true
+}
+
+func parseID(input string) (int64, bool) {
+	id, err := strconv.ParseInt(input, 10, 64)
+	return id, err == nil && id > 0
+}
+
+// render builds the page of the list. A page past the end, such as after removing the last
+// bookmark of the last page, falls back to the last page; view is updated to the page shown.
+func (h *Handler) render(ctx context.Context, userID int64, view *pageView) (string, *gotgbot.InlineKeyboardMarkup, error) {
+	filter := storage.BookmarkFilter{UserID: userID, Search: view.Search, Offset: view.Page * pageSize, Limit: pageSize}
+	list, total, err := h.bookmarks.List(ctx, filter)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(list) == 0 && total > 0 {
+		view.Page = (total - 1) / pageSize
+		filter.Offset = view.Page * pageSize
+		if list, total, err = h.bookmarks.List(ctx, filter); err != nil {
+			return "", nil, err
+		}
+	}
+
+	var sb strings.Builder
+	switch {
+	case total == 0 && view.Search != "":
+		sb.WriteString(fmt.Sprintf("No saved tweets match <code>%s</code>.", html.EscapeString(view.Search)))
+	case total == 0:
+		sb.WriteString("You have no saved tweets yet. Press ⭐ Save under a tweet to keep it here.")
+	case view.Search != "":
+		sb.WriteString(fmt.Sprintf("⭐ <b>Saved tweets</b> matching <code>%s</code>: %d\n", html.EscapeString(view.Search), total))
+	default:
+		sb.WriteString(fmt.Sprintf("⭐ <b>Saved tweets</b>: %d\n", total))
+	}
+
+	var rows [][]gotgbot.InlineKeyboardButton
+	for i, bookmark := range list {
+		n := view.Page*pageSize + i + 1
+		sb.WriteString("\n" + entryText(n, bookmark))
+		rows = append(rows, []gotgbot.InlineKeyboardButton{
+			{Text: fmt.Sprintf("📤 %d", n), CallbackData: encodeSend(bookmark.ID)},
+			{Text: fmt.Sprintf("🗑 %d", n), CallbackData: encodeRemove(bookmark.ID, *view)},
+		})
+	}
+
+	var nav []gotgbot.InlineKeyboardButton
+	if view.Page > 0 {
+		nav = append(nav, gotgbot.InlineKeyboardButton{Text: "« Prev", CallbackData: encodePage(pageView{Page: view.Page - 1, Search: view.Search})})
+	}
+	if (view.Page+1)*pageSize < total {
+		nav = append(nav, gotgbot.InlineKeyboardButton{Text: "Next »", CallbackData: encodePage(pageView{Page: view.Page + 1, Search: view.Search})})
+	}
+	if len(nav) > 0 {
+		rows = append(rows, nav)
+	}
+
+	if total > 0 {
+		pages := (total + pageSize - 1) / pageSize
+		sb.WriteString(fmt.Sprintf("\nPage %d of %d · 📤 send again · 🗑 remove", view.Page+1, pages))
+	}
+	sb.WriteString("\nSearch with <code>/saved &lt;words&gt;</code>.")
+
+	return sb.String(), &gotgbot.InlineKeyboardMarkup{InlineKeyboard: rows}, nil
+}
+
+// entryText describes a bookmark in the list: author, a snippet and a link to the tweet.
+func entryText(n int, bookmark storage.Bookmark) string {
+	url := fmt.Sprintf("https://x.com/%s/status/%s", bookmark.Author, bookmark.TweetID)
+	var text string
+	if tw := bookmark.Tweet; tw != nil {
+		if tw.URL != "" {
+			url = tw.URL
+		}
+		text = tweet.TruncateText(strings.Join(strings.Fields(tw.Text), " "), snippetLength)
+	}
+
+	line := fmt.Sprintf(`%d. <a href="%s">@%s</a> · %s`, n, html.EscapeString(url), html.EscapeString(bookmark.Author), bookmark.CreatedAt.UTC().Format("2 Jan 2006"))
+	if text != "" {
+		line += "\n" + html.EscapeString(text)
+	}
+	return line + "\n"
+}
+
+// searchQuery normalizes the search typed after /saved and shortens it to fit in callback data.
+func searchQuery(input string) string {
+	search := strings.Join(strings.Fields(input), " ")
+	for len(search) > maxSearchLength {
+		_, size := utf8.DecodeLastRuneInString(search)
+		search = search[:len(search)-size]
+	}
+	return strings.TrimSpace(search)
+}
+
+// commandPayload returns the text after the command.
+func commandPayload(text string) string {
+	_, payload, _ := strings.Cut(strings.TrimSpace(text), " ")
+	return strings.TrimSpace(payload)
+}
@@ -9,8 +9,11 @@ import (
 
 	"twitterx-bot/internal/handlers/shared"
 	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/stats"
+	"twitterx-bot/internal/storage"
 	"twitterx-bot/internal/telegram/tweet"
 	"twitterx-bot/internal/usecase/tweetsvc/sendchain"
+	"twitterx-bot/internal/usecase/tweetsvc/sendtweet"
 )
 
 // TweetFetcher fetches tweets by username and tweet ID.
@@ -23,12 +26,28 @@ type Handlers struct {
 	log          *logger.Logger
 	fetcher      TweetFetcher
 	chainTimeout time.Duration
-	telegraph    tweet.ArticleCreator
+	sender       tweet.Sender
+	stats        *stats.Recorder
+}
+
+// Option configures optional callback handler behavior.
+type Option func(*Handlers)
+
+// WithStats records expanded chains, resent tweets, deletions and failures as usage events.
+func WithStats(recorder *stats.Recorder) Option {
+	return func(h *Handlers) {
+		h.stats = recorder
+	}
 }
 
 // New creates callback handlers with the configured logger, tweet fetcher, and chain timeout.
-func New(log *logger.Logger, fetcher TweetFetcher, chainTimeout time.Duration, telegraph tweet.ArticleCreator) *Handlers {
-	return &Handlers{log: log, fetcher: fetcher, chainTimeout: chainTimeout, telegraph: telegraph}
+// The sender carries optional dependencies (Telegraph, translation); Bot and Log are set per update.
+func New(log *logger.Logger, fetcher TweetFetcher, chainTimeout time.Duration, sender tweet.Sender, opts ...Option) *Handlers {
+	h := &Handlers{log: log, fetcher: fetcher, chainTimeout: chainTimeout, sender: sender}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // Chain processes callback queries that request a tweet chain.
@@ -68,11 +87,17 @@ func (h *Handlers) Chain(b *gotgbot.Bot, ctx *ext.Context) error {
 	defer cancel()
 
 	chatID := ctx.EffectiveChat.Id
-	uc := sendchain.New(h.fetcher, tweet.Sender{Bot: b, Telegraph: h.telegraph, Log: log})
+	sender := h.sender
+	sender.Bot = b
+	sender.Log = log
+	uc := sendchain.New(h.fetcher, sender)
+	uc.Settings = h.sender.Settings
 	if sendErr := uc.SendChain(reqCtx, chatID, replyToMsgID, username, tweetID, shared.UserDisplayName(&cb.From)); sendErr != nil {
 		log.Error("send chain failed", "err", sendErr)
+		h.stats.Record(reqCtx, shared.UsageEvent(storage.EventFailed, chatID, &cb.From, username))
 		return nil
 	}
+	h.stats.Record(reqCtx, shared.UsageEvent(storage.EventChainSent, chatID, &cb.From, username))
 
 	if _, delErr := cb.Message.Delete(b, nil); delErr != nil {
 		log.Debug("delete original message failed", "err", delErr)
@@ -82,6 +107,55 @@ func (h *Handlers) Chain(b *gotgbot.Bot, ctx *ext.Context) error {
 	return nil
 }
 
+// Repost sends a tweet again when "Send again" is pressed on an "already shared" notice.
+func (h *Handlers) Repost(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.CallbackQuery
+	log := h.log.With("component", "callback", "callback", "repost", "callback_id", cb.Id, "user_id", cb.From.Id, "username", cb.From.Username)
+	if ctx.EffectiveChat != nil {
+		log = log.With("chat_id", ctx.EffectiveChat.Id)
+	}
+
+	username, tweetID, replyToMsgID, ok := tweet.DecodeRepostCallback(cb.Data)
+	if !ok || ctx.EffectiveChat == nil {
+		log.Error("decode repost callback failed", "data", cb.Data)
+		_, err := cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{
+			Text: "Invalid callback data",
+		})
+		return err
+	}
+
+	log = log.With("tweet_username", username, "tweet_id", tweetID, "reply_to_msg_id", replyToMsgID)
+	log.Info("repost callback received")
+
+	if _, err := cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{
+		Text: "Sending again...",
+	}); err != nil {
+		log.Debug("answer callback failed", "err", err)
+	}
+
+	reqCtx, cancel := context.WithTimeout(context.Background(), h.chainTimeout)
+	defer cancel()
+
+	sender := h.sender
+	sender.Bot = b
+	sender.Log = log
+	uc := sendtweet.New(h.fetcher, sender)
+	uc.Settings = h.sender.Settings
+	if sendErr := uc.SendTweet(reqCtx, ctx.EffectiveChat.Id, replyToMsgID, username, tweetID, shared.UserDisplayName(&cb.From)); sendErr != nil {
+		log.Error("send tweet failed", "err", sendErr)
+		h.stats.Record(reqCtx, shared.UsageEvent(storage.EventFailed, ctx.EffectiveChat.Id, &cb.From, username))
+		return nil
+	}
+	h.stats.Record(reqCtx, shared.UsageEvent(storage.EventTweetSent, ctx.EffectiveChat.Id, &cb.From, username))
+
+	if _, delErr := cb.Message.Delete(b, nil); delErr != nil {
+		log.Debug("delete already shared notice failed", "err", delErr)
+	}
+
+	log.Info("tweet sent again")
+	return nil
+}
+
 // Delete handles callbacks that remove a previously sent tweet message.
 func (h *Handlers) Delete(b *gotgbot.Bot, ctx *ext.Context) error {
 	cb := ctx.CallbackQuery
@@ -114,8 +188,10 @@ func (h *Handlers) Delete(b *gotgbot.Bot, ctx *ext.Context) error {
 		})
 		return answerErr
 	}
+	h.stats.Record(context.Background(), shared.UsageEvent(storage.EventDeleted, chatID, &cb.From, deleteData.ChainUsername))
 
 	botMsgID := cb.Message.GetMessageId()
+	saveCallbackData := tweet.FindSaveButton(messageMarkup(cb.Message))
 
 	if deleteData.HasChain {
 		chainCallbackData := tweet.EncodeChainCallback(
@@ -123,18 +199,27 @@ func (h *Handlers) Delete(b *gotgbot.Bot, ctx *ext.Context) error {
 			deleteData.ChainTweetID,
 			deleteData.MsgID,
 		)
+		markup := tweet.BuildChainOnlyKeyboard(chainCallbackData)
+		if saveCallbackData != "" {
+			markup = tweet.AddSaveButton(markup, saveCallbackData)
+		}
 		if _, _, editErr := b.EditMessageReplyMarkup(&gotgbot.EditMessageReplyMarkupOpts{
 			ChatId:      chatID,
 			MessageId:   botMsgID,
-			ReplyMarkup: *tweet.BuildChainOnlyKeyboard(chainCallbackData),
+			ReplyMarkup: *markup,
 		}); editErr != nil {
 			log.Debug("edit reply markup failed", "err", editErr)
 		}
 	} else {
+		markup := gotgbot.InlineKeyboardMarkup{}
+		if saveCallbackData != "" {
+			// The tweet stays in the chat, so it can still be saved
+			markup = *tweet.AddSaveButton(nil, saveCallbackData)
+		}
 		if _, _, editErr := b.EditMessageReplyMarkup(&gotgbot.EditMessageReplyMarkupOpts{
 			ChatId:      chatID,
 			MessageId:   botMsgID,
-			ReplyMarkup: gotgbot.InlineKeyboardMarkup{},
+			ReplyMarkup: markup,
 		}); editErr != nil {
 			log.Debug("remove reply markup failed", "err", editErr)
 		}
@@ -148,3 +233,14 @@ func (h *Handlers) Delete(b *gotgbot.Bot, ctx *ext.Context) error {
 	}
 	return err
 }
+
+// messageMarkup returns the inline keyboard of the callback message, nil when it is inaccessible.
+func messageMarkup(msg gotgbot.MaybeInaccessibleMessage) *gotgbot.InlineKeyboardMarkup {
+	switch m := msg.(type) {
+	case gotgbot.Message:
+		return m.ReplyMarkup
+	case *gotgbot.Message:
+		return m.ReplyMarkup
+	}
+	return nil
+}
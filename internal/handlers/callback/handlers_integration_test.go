@@ -5,6 +5,7 @@ import (
 
 	"github.com/PaulSonOfLars/gotgbot/v2"
 
+	"twitterx-bot/internal/handlers"
 	"twitterx-bot/internal/handlers/testutil"
 	"twitterx-bot/internal/telegram/tweet"
 	"twitterx-bot/internal/twitterxapi"
@@ -92,6 +93,75 @@ func TestIntegration_ChainCallback_SendsMultipleMessages(t *testing.T) {
 	}
 }
 
+func TestIntegration_ChainCallback_LongChainSentAsArticle(t *testing.T) {
+	parentID := "111111"
+	fakeAPI := &testutil.FakeTweetAPI{
+		Tweets: map[string]*twitterxapi.Tweet{
+			"user/222222": {
+				ID:               "222222",
+				URL:              "https://x.com/user/status/222222",
+				Text:             "Reply to parent",
+				ReplyingToStatus: &parentID,
+				ReplyingTo:       testutil.StrPtr("user"),
+				Author:           twitterxapi.Author{Name: "User", ScreenName: "user"},
+			},
+			"user/111111": {
+				ID:     "111111",
+				URL:    "https://x.com/user/status/111111",
+				Text:   "Parent tweet",
+				Author: twitterxapi.Author{Name: "User", ScreenName: "user"},
+			},
+		},
+	}
+
+	telegraphMock := &testutil.FakeTelegraph{URL: "https://telegra.ph/thread-123"}
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, fakeAPI, telegraphMock,
+		handlers.WithChainArticles(telegraphMock, 2),
+	)
+
+	const (
+		chatID = int64(878787)
+		msgID  = int64(460)
+	)
+
+	update := gotgbot.Update{
+		UpdateId: 9,
+		CallbackQuery: &gotgbot.CallbackQuery{
+			Id:   "cb-chain-article",
+			Data: tweet.EncodeChainCallback("user", "222222", msgID),
+			From: gotgbot.User{Id: 1009, FirstName: "Iris", Username: "iris"},
+			Message: &gotgbot.Message{
+				MessageId: 461,
+				Date:      1000009,
+				Chat:      gotgbot.Chat{Id: chatID, Type: "group"},
+			},
+		},
+	}
+
+	if err := dispatcher.ProcessUpdate(bot, &update, nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	if !telegraphMock.ChainCalled {
+		t.Fatalf("expected CreateChainArticle to be called")
+	}
+	if len(telegraphMock.GotChain) != 2 {
+		t.Fatalf("chain items = %d, want 2", len(telegraphMock.GotChain))
+	}
+
+	msgCalls := mock.GetCalls("sendMessage")
+	if len(msgCalls) != 1 {
+		t.Fatalf("sendMessage calls = %d, want 1 (single article link)", len(msgCalls))
+	}
+	text, _ := msgCalls[0].JSONString("text")
+	if !testutil.ContainsString(text, telegraphMock.URL) || !testutil.ContainsString(text, "2 tweets") {
+		t.Fatalf("article message text = %q, want link and tweet count", text)
+	}
+	if replyMsgID, ok := msgCalls[0].JSONInt64("reply_parameters.message_id"); !ok || replyMsgID != msgID {
+		t.Errorf("article message should reply to original message %d, got %d", msgID, replyMsgID)
+	}
+}
+
 func TestIntegration_ChainCallback_UsesTelegraphForLongCaption(t *testing.T) {
 	parentID := "111111"
 	tweetURL := "https://x.com/chainuser/status/222222"
@@ -145,12 +215,8 @@ func TestIntegration_ChainCallback_UsesTelegraphForLongCaption(t *testing.T) {
 	if !telegraphMock.Called {
 		t.Fatalf("expected Telegraph CreateArticle to be called")
 	}
-	wantText := longTweet.Text + "\n\n" + tweetURL
-	if telegraphMock.GotText != wantText {
-		t.Fatalf("CreateArticle text = %q, want %q", telegraphMock.GotText, wantText)
-	}
-	if telegraphMock.GotTitle != "Tweet by @chainuser" {
-		t.Fatalf("CreateArticle title = %q, want %q", telegraphMock.GotTitle, "Tweet by @chainuser")
+	if telegraphMock.GotTweet != longTweet {
+		t.Fatalf("CreateArticle tweet = %v, want %v", telegraphMock.GotTweet, longTweet)
 	}
 
 	photoCalls := mock.GetCalls("sendPhoto")
@@ -269,3 +335,89 @@ func TestIntegration_DeleteCallback_RemovesKeyboardWithoutChain(t *testing.T) {
 		t.Fatalf("reply_markup should not contain chain prefix, got: %s", markup)
 	}
 }
+
+func TestIntegration_DeleteCallback_KeepsSaveButton(t *testing.T) {
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcher(t, &testutil.FakeTweetAPI{})
+
+	saveData := tweet.EncodeSaveCallback("saveuser", "555")
+	update := gotgbot.Update{
+		UpdateId: 10,
+		CallbackQuery: &gotgbot.CallbackQuery{
+			Id:   "cb-delete-save",
+			Data: tweet.EncodeDeleteCallback(999, nil),
+			From: gotgbot.User{Id: 2022, FirstName: "Del"},
+			Message: &gotgbot.Message{
+				MessageId:   1000,
+				Chat:        gotgbot.Chat{Id: 789789, Type: "private"},
+				ReplyMarkup: tweet.AddSaveButton(tweet.BuildKeyboard(999, nil), saveData),
+			},
+		},
+	}
+
+	if err := dispatcher.ProcessUpdate(bot, &update, nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	editCalls := mock.GetCalls("editMessageReplyMarkup")
+	if len(editCalls) != 1 {
+		t.Fatalf("editMessageReplyMarkup calls = %d, want 1", len(editCalls))
+	}
+	markup, _ := editCalls[0].JSONString("reply_markup")
+	if !testutil.ContainsString(markup, saveData) || testutil.ContainsString(markup, tweet.DeleteCallbackPrefix) {
+		t.Fatalf("reply_markup = %s, want only the save button", markup)
+	}
+}
+
+func TestIntegration_RepostCallback_SendsTweetAndRemovesNotice(t *testing.T) {
+	fakeAPI := &testutil.FakeTweetAPI{
+		Tweets: map[string]*twitterxapi.Tweet{
+			"user/333333": {
+				ID:     "333333",
+				URL:    "https://x.com/user/status/333333",
+				Text:   "Shared before",
+				Author: twitterxapi.Author{Name: "User", ScreenName: "user"},
+			},
+		},
+	}
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcher(t, fakeAPI)
+
+	const (
+		chatID   = int64(777777)
+		msgID    = int64(500)
+		noticeID = int64(501)
+	)
+
+	update := gotgbot.Update{
+		UpdateId: 5,
+		CallbackQuery: &gotgbot.CallbackQuery{
+			Id:   "cb-repost",
+			Data: tweet.EncodeRepostCallback("user", "333333", msgID),
+			From: gotgbot.User{Id: 1005, FirstName: "Eve"},
+			Message: &gotgbot.Message{
+				MessageId: noticeID,
+				Date:      1000004,
+				Chat:      gotgbot.Chat{Id: chatID, Type: "private"},
+			},
+		},
+	}
+
+	if err := dispatcher.ProcessUpdate(bot, &update, nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	msgCalls := mock.GetCalls("sendMessage")
+	if len(msgCalls) != 1 {
+		t.Fatalf("sendMessage calls = %d, want 1", len(msgCalls))
+	}
+	if replyID, _ := msgCalls[0].JSONInt64("reply_parameters.message_id"); replyID != msgID {
+		t.Errorf("tweet replies to %d, want original message %d", replyID, msgID)
+	}
+
+	deleteCalls := mock.GetCalls("deleteMessage")
+	if len(deleteCalls) != 1 {
+		t.Fatalf("deleteMessage calls = %d, want 1", len(deleteCalls))
+	}
+	if id, _ := deleteCalls[0].JSONInt64("message_id"); id != noticeID {
+		t.Errorf("deleted message %d, want notice %d", id, noticeID)
+	}
+}
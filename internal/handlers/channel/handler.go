@@ -0,0 +1,127 @@
+// Package channel reformats bare tweet links posted in channels into rendered tweets.
+package channel
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+
+	"twitterx-bot/internal/chatsettings"
+	"twitterx-bot/internal/handlers/shared"
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/stats"
+	"twitterx-bot/internal/storage"
+	"twitterx-bot/internal/telegram/tweet"
+	"twitterx-bot/internal/twitterurl"
+	"twitterx-bot/internal/usecase/tweetsvc/sendtweet"
+)
+
+// Handler replaces channel posts consisting of a single tweet link with the rendered tweet.
+// Text-only tweets are edited into the post itself; tweets with media are posted as a new
+// message and the link post is deleted. The bot needs the rights to edit and delete posts.
+type Handler struct {
+	log     *logger.Logger
+	fetcher sendtweet.TweetFetcher
+	timeout time.Duration
+	sender  tweet.Sender
+	stats   *stats.Recorder
+}
+
+// New creates a channel post handler. The sender carries optional dependencies and the
+// chat settings; Bot and Log are set per update. recorder may be nil.
+func New(log *logger.Logger, fetcher sendtweet.TweetFetcher, timeout time.Duration, sender tweet.Sender, recorder *stats.Recorder) *Handler {
+	return &Handler{log: log, fetcher: fetcher, timeout: timeout, sender: sender, stats: recorder}
+}
+
+// IsBareLink reports whether the post is nothing but a tweet link.
+func IsBareLink(msg *gotgbot.Message) bool {
+	if msg == nil || msg.Chat.Type != gotgbot.ChatTypeChannel {
+		return false
+	}
+	text := strings.TrimSpace(msg.Text)
+	if text == "" || strings.ContainsAny(text, " \t\n") {
+		return false
+	}
+	_, _, ok := twitterurl.ParseTweetURL(text)
+	return ok
+}
+
+// Handle reformats a channel post with a bare tweet link.
+func (h *Handler) Handle(b *gotgbot.Bot, ctx *ext.Context) error {
+	post := ctx.EffectiveMessage
+	chat := ctx.EffectiveChat
+	log := h.log.With("component", "channel", "chat_id", chat.Id, "message_id", post.MessageId)
+
+	username, tweetID, ok := twitterurl.ParseTweetURL(strings.TrimSpace(post.Text))
+	if !ok {
+		return nil
+	}
+
+	reqCtx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+
+	settings := chatsettings.Load(reqCtx, h.sender.Settings, chat.Id, h.log)
+	if settings.DisableChannelMode {
+		log.Debug("channel post ignored: channel mode is off")
+		return nil
+	}
+
+	tw, err := h.fetcher.GetTweet(reqCtx, username, tweetID)
+	if err != nil {
+		// The link stays as posted
+		log.Warn("fetch tweet failed", "tweet_username", username, "tweet_id", tweetID, "err", err)
+		h.stats.Record(reqCtx, shared.UsageEvent(storage.EventFailed, chat.Id, nil, username))
+		return nil
+	}
+
+	sender := h.sender
+	sender.Bot = b
+	sender.Log = log
+	opts := &tweet.SendResponseOpts{
+		RequesterUsername: post.AuthorSignature,
+		Signature:         signature(chat, settings.Signature),
+	}
+
+	edited, err := sender.EditTweet(reqCtx, chat.Id, post.MessageId, tw, opts)
+	if err != nil {
+		log.Debug("edit channel post failed, posting instead", "err", err)
+	}
+	if !edited {
+		if err := sender.SendTweet(reqCtx, chat.Id, 0, tw, opts); err != nil {
+			log.Error("send tweet failed", "tweet_username", username, "tweet_id", tweetID, "err", err)
+			h.stats.Record(reqCtx, shared.UsageEvent(storage.EventFailed, chat.Id, nil, username))
+			return nil
+		}
+		if _, err := b.DeleteMessage(chat.Id, post.MessageId, nil); err != nil {
+			log.Warn("delete link post failed", "err", err)
+		}
+	}
+	h.stats.Record(reqCtx, shared.UsageEvent(storage.EventTweetSent, chat.Id, nil, username))
+
+	log.Info("channel post reformatted", "tweet_username", username, "tweet_id", tweetID, "edited", edited)
+	return nil
+}
+
+// signature returns the HTML line appended to posts of the channel.
+func signature(chat *gotgbot.Chat, mode chatsettings.SignatureMode) string {
+	title := html.EscapeString(chat.Title)
+	if title == "" {
+		return ""
+	}
+	switch mode {
+	case chatsettings.SignatureTitle:
+		return "— " + title
+	case chatsettings.SignatureLink:
+		if chat.Username == "" {
+			return "— " + title
+		}
+		return fmt.Sprintf(`— <a href="https://t.me/%s">%s</a>`, html.EscapeString(chat.Username), title)
+	default:
+		return ""
+	}
+}
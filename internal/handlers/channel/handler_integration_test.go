@@ -0,0 +1,130 @@
+package channel_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+
+	"twitterx-bot/internal/chatsettings"
+	"twitterx-bot/internal/handlers"
+	"twitterx-bot/internal/handlers/testutil"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+const channelID = int64(-1001234)
+
+func channelPost(updateID, msgID int64, text, signature string) *gotgbot.Update {
+	channel := gotgbot.Chat{Id: channelID, Type: "channel", Title: "Space News", Username: "spacenews"}
+	return &gotgbot.Update{
+		UpdateId: updateID,
+		ChannelPost: &gotgbot.Message{
+			MessageId:       msgID,
+			Text:            text,
+			Chat:            channel,
+			SenderChat:      &channel,
+			AuthorSignature: signature,
+		},
+	}
+}
+
+func fakeAPI() *testutil.FakeTweetAPI {
+	return &testutil.FakeTweetAPI{
+		Tweets: map[string]*twitterxapi.Tweet{
+			"nasa/1": {
+				ID:     "1",
+				URL:    "https://x.com/nasa/status/1",
+				Text:   "Launch today",
+				Author: twitterxapi.Author{Name: "NASA", ScreenName: "nasa"},
+			},
+			"nasa/2": {
+				ID:     "2",
+				URL:    "https://x.com/nasa/status/2",
+				Text:   "Look at this",
+				Author: twitterxapi.Author{Name: "NASA", ScreenName: "nasa"},
+				Media:  &twitterxapi.Media{Photos: []twitterxapi.Photo{{URL: "https://pbs.twimg.com/media/a.jpg"}}},
+			},
+		},
+	}
+}
+
+func TestIntegration_ChannelPost_EditsTextTweetIntoPost(t *testing.T) {
+	settings := chatsettings.NewMemoryStore()
+	_ = settings.Save(context.Background(), channelID, chatsettings.Settings{Signature: chatsettings.SignatureLink})
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, fakeAPI(), nil, handlers.WithChatSettings(settings))
+
+	if err := dispatcher.ProcessUpdate(bot, channelPost(1, 10, "https://x.com/nasa/status/1", "Erin"), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	edits := mock.GetCalls("editMessageText")
+	if len(edits) != 1 {
+		t.Fatalf("editMessageText calls = %d, want 1", len(edits))
+	}
+	if msgID, _ := edits[0].JSONInt64("message_id"); msgID != 10 {
+		t.Errorf("edited message_id = %d, want the link post", msgID)
+	}
+	text, _ := edits[0].JSONString("text")
+	if !strings.Contains(text, "Launch today") || !strings.Contains(text, "by Erin") {
+		t.Errorf("edited text = %q, want tweet with attribution", text)
+	}
+	if !strings.Contains(text, `<a href="https://t.me/spacenews">Space News</a>`) {
+		t.Errorf("edited text = %q, want channel signature", text)
+	}
+	if n := len(mock.GetCalls("sendMessage")) + len(mock.GetCalls("deleteMessage")); n != 0 {
+		t.Errorf("send/delete calls = %d, want the post edited in place", n)
+	}
+}
+
+func TestIntegration_ChannelPost_RepostsMediaTweetAndDeletesLink(t *testing.T) {
+	settings := chatsettings.NewMemoryStore()
+	_ = settings.Save(context.Background(), channelID, chatsettings.Settings{HideAttribution: true})
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, fakeAPI(), nil, handlers.WithChatSettings(settings))
+
+	if err := dispatcher.ProcessUpdate(bot, channelPost(1, 10, "https://x.com/nasa/status/2", "Erin"), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	photos := mock.GetCalls("sendPhoto")
+	if len(photos) != 1 {
+		t.Fatalf("sendPhoto calls = %d, want 1", len(photos))
+	}
+	caption, _ := photos[0].JSONString("caption")
+	if !strings.Contains(caption, "Look at this") || strings.Contains(caption, "Erin") {
+		t.Errorf("caption = %q, want tweet without attribution", caption)
+	}
+	if _, ok := photos[0].JSONInt64("reply_parameters.message_id"); ok {
+		t.Error("tweet posted as a reply to the link post")
+	}
+	deletes := mock.GetCalls("deleteMessage")
+	if len(deletes) != 1 {
+		t.Fatalf("deleteMessage calls = %d, want 1", len(deletes))
+	}
+	if msgID, _ := deletes[0].JSONInt64("message_id"); msgID != 10 {
+		t.Errorf("deleted message_id = %d, want the link post", msgID)
+	}
+	if len(mock.GetCalls("editMessageText")) != 0 {
+		t.Error("media tweet edited into a text post")
+	}
+}
+
+func TestIntegration_ChannelPost_IgnoresCommentedLinksAndDisabledMode(t *testing.T) {
+	settings := chatsettings.NewMemoryStore()
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, fakeAPI(), nil, handlers.WithChatSettings(settings))
+
+	if err := dispatcher.ProcessUpdate(bot, channelPost(1, 10, "Big day https://x.com/nasa/status/1", ""), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	_ = settings.Save(context.Background(), channelID, chatsettings.Settings{DisableChannelMode: true})
+	if err := dispatcher.ProcessUpdate(bot, channelPost(2, 11, "https://x.com/nasa/status/1", ""), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	for _, method := range []string{"editMessageText", "sendMessage", "deleteMessage"} {
+		if n := len(mock.GetCalls(method)); n != 0 {
+			t.Errorf("%s calls = %d, want 0", method, n)
+		}
+	}
+}
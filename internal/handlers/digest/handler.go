@@ -0,0 +1,275 @@
+// Package digest implements /digest, which schedules a summary of the tweets shared in a chat.
+package digest
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+
+	digestsvc "twitterx-bot/internal/digest"
+	"twitterx-bot/internal/handlers/shared"
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/storage"
+)
+
+const (
+	requestTimeout = 2 * time.Minute
+
+	usageText = "Usage:\n" +
+		"<code>/digest daily 09:00</code> — every day at 09:00 UTC\n" +
+		"<code>/digest weekly mon 09:00</code> — every Monday at 09:00 UTC\n" +
+		"<code>/digest page on|off</code> — add a page with the full tweets\n" +
+		"<code>/digest now</code> — preview the next digest\n" +
+		"<code>/digest off</code> — stop the digest"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Handler manages the digest schedule of a chat.
+type Handler struct {
+	log      *logger.Logger
+	digests  storage.DigestRepository
+	history  storage.HistoryRepository
+	chats    storage.ChatRepository
+	fetcher  digestsvc.TweetFetcher
+	articles ArticleCreator
+	now      func() time.Time
+}
+
+// New creates the /digest command handler. articles may be nil, which disables digest pages.
+func New(log *logger.Logger, store storage.Storage, fetcher digestsvc.TweetFetcher, articles ArticleCreator) *Handler {
+	return &Handler{
+		log:      log,
+		digests:  store.Digests(),
+		history:  store.History(),
+		chats:    store.Chats(),
+		fetcher:  fetcher,
+		articles: articles,
+		now:      time.Now,
+	}
+}
+
+// Publisher returns the publisher the scheduler posts digests with.
+func (h *Handler) Publisher(b *gotgbot.Bot) Publisher {
+	return Publisher{Bot: b, Log: h.log, Chats: h.chats, Articles: h.articles}
+}
+
+// Handle processes "/digest" and its subcommands.
+func (h *Handler) Handle(b *gotgbot.Bot, ctx *ext.Context) error {
+	log := h.logger(ctx)
+	msg := ctx.EffectiveMessage
+	chatID := ctx.EffectiveChat.Id
+
+	reqCtx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	current, ok, err := h.digests.Get(reqCtx, chatID)
+	if err != nil {
+		log.Error("load digest failed", "err", err)
+		return h.reply(b, msg, "Cannot load the digest settings, try again later.")
+	}
+
+	args := ctx.Args()[1:]
+	if len(args) == 0 {
+		return h.reply(b, msg, describe(current, ok)+"\n\n"+usageText)
+	}
+
+	if allowed, err := h.canManage(b, ctx); err != nil || !allowed {
+		return h.denied(b, msg, log, err)
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "off":
+		if _, err := h.digests.Delete(reqCtx, chatID); err != nil {
+			log.Error("delete digest failed", "err", err)
+			return h.reply(b, msg, "Cannot save the digest settings, try again later.")
+		}
+		log.Info("digest disabled")
+		return h.reply(b, msg, "Digest is off.")
+
+	case "now":
+		if !ok {
+			return h.reply(b, msg, "No digest is scheduled.\n\n"+usageText)
+		}
+		return h.preview(reqCtx, b, msg, log, current)
+
+	case "page":
+		if !ok || len(args) != 2 {
+			return h.reply(b, msg, usageText)
+		}
+		switch strings.ToLower(args[1]) {
+		case "on":
+			if h.articles == nil {
+				return h.reply(b, msg, "Digest pages are not available on this bot.")
+			}
+			current.Article = true
+		case "off":
+			current.Article = false
+		default:
+			return h.reply(b, msg, usageText)
+		}
+
+	default:
+		next, valid := parseSchedule(args)
+		if !valid {
+			return h.reply(b, msg, usageText)
+		}
+		next.ChatID = chatID
+		next.Article = current.Article
+		next.LastSentAt = current.LastSentAt
+		current = next
+	}
+
+	if ctx.EffectiveUser != nil {
+		current.UpdatedBy = ctx.EffectiveUser.Id
+	}
+	current.UpdatedAt = h.now()
+	if err := h.digests.Set(reqCtx, current); err != nil {
+		log.Error("save digest failed", "err", err)
+		return h.reply(b, msg, "Cannot save the digest settings, try again later.")
+	}
+
+	log.Info("digest updated", "frequency", current.Frequency, "minute", current.Minute, "article", current.Article)
+	return h.reply(b, msg, "✅ "+describe(current, true))
+}
+
+// preview posts the digest for the current period without marking it as sent.
+func (h *Handler) preview(ctx context.Context, b *gotgbot.Bot, msg *gotgbot.Message, log *logger.Logger, d storage.Digest) error {
+	now := h.now()
+	result, err := digestsvc.Compile(ctx, h.history, h.fetcher, d, digestsvc.Since(d, now), now, digestsvc.DefaultTop)
+	if err != nil {
+		log.Error("compile digest failed", "err", err)
+		return h.reply(b, msg, "Cannot compile the digest, try again later.")
+	}
+	if result.Shared == 0 {
+		return h.reply(b, msg, "No tweets were shared here since the last digest.")
+	}
+	return h.Publisher(b).Publish(ctx, result)
+}
+
+// parseSchedule parses "daily HH:MM" and "weekly [day] HH:MM"; weekly digests default to Monday.
+func parseSchedule(args []string) (storage.Digest, bool) {
+	d := storage.Digest{Frequency: storage.DigestFrequency(strings.ToLower(args[0]))}
+	rest := args[1:]
+	switch d.Frequency {
+	case storage.DigestDaily:
+		if len(rest) != 1 {
+			return storage.Digest{}, false
+		}
+	case storage.DigestWeekly:
+		d.Weekday = time.Monday
+		if len(rest) == 2 {
+			day, ok := parseWeekday(rest[0])
+			if !ok {
+				return storage.Digest{}, false
+			}
+			d.Weekday = day
+			rest = rest[1:]
+		}
+		if len(rest) != 1 {
+			return storage.Digest{}, false
+		}
+	default:
+		return storage.Digest{}, false
+	}
+
+	minute, ok := parseClock(rest[0])
+	if !ok {
+		return storage.Digest{}, false
+	}
+	d.Minute = minute
+	return d, true
+}
+
+// parseWeekday parses a day name such as "fri" or "Friday" by its first three letters.
+func parseWeekday(s string) (time.Weekday, bool) {
+	// Lowercasing may change the byte length of non-ASCII input, so the prefix is taken afterwards
+	name := strings.ToLower(s)
+	day, ok := weekdays[name[:min(3, len(name))]]
+	return day, ok
+}
+
+// parseClock parses "HH:MM" into minutes since midnight.
+func parseClock(s string) (int, bool) {
+	hh, mm, ok := strings.Cut(s, ":")
+	if !ok || len(mm) != 2 || len(hh) == 0 || len(hh) > 2 {
+		return 0, false
+	}
+	hours, err := strconv.Atoi(hh)
+	if err != nil || hours < 0 || hours > 23 {
+		return 0, false
+	}
+	minutes, err := strconv.Atoi(mm)
+	if err != nil || minutes < 0 || minutes > 59 {
+		return 0, false
+	}
+	return hours*60 + minutes, true
+}
+
+// describe returns a human readable description of the digest schedule.
+func describe(d storage.Digest, ok bool) string {
+	if !ok {
+		return "No digest is scheduled for this chat."
+	}
+	clock := fmt.Sprintf("%02d:%02d UTC", d.Minute/60, d.Minute%60)
+	when := "every day at " + clock
+	if d.Frequency == storage.DigestWeekly {
+		when = fmt.Sprintf("every %s at %s", d.Weekday, clock)
+	}
+	page := "without a page"
+	if d.Article {
+		page = "with a page of the full tweets"
+	}
+	return fmt.Sprintf("The digest of shared tweets is posted %s, %s.", html.EscapeString(when), page)
+}
+
+// canManage reports whether the sender may change the digest. Channel posts and anonymous
+// admins speak for the chat; in groups only administrators may.
+func (h *Handler) canManage(b *gotgbot.Bot, ctx *ext.Context) (bool, error) {
+	if shared.IsAnonymousAdmin(ctx.EffectiveMessage) {
+		return true, nil
+	}
+	if ctx.EffectiveUser == nil {
+		return false, nil
+	}
+	return shared.CanManageChat(b, ctx.EffectiveChat, ctx.EffectiveUser.Id)
+}
+
+func (h *Handler) denied(b *gotgbot.Bot, msg *gotgbot.Message, log *logger.Logger, err error) error {
+	if err != nil {
+		log.Error("check chat admin failed", "err", err)
+		return h.reply(b, msg, "Cannot check your permissions, try again later.")
+	}
+	log.Info("digest denied: not an admin")
+	return h.reply(b, msg, "Only chat admins can change the digest.")
+}
+
+func (h *Handler) logger(ctx *ext.Context) *logger.Logger {
+	log := h.log.With("component", "digest")
+	if ctx.EffectiveChat != nil {
+		log = log.With("chat_id", ctx.EffectiveChat.Id)
+	}
+	if ctx.EffectiveUser != nil {
+		log = log.With("user_id", ctx.EffectiveUser.Id, "username", ctx.EffectiveUser.Username)
+	}
+	return log
+}
+
+func (h *Handler) reply(b *gotgbot.Bot, msg *gotgbot.Message, text string) error {
+	_, err := msg.Reply(b, text, &gotgbot.SendMessageOpts{
+		ParseMode: "HTML",
+		LinkPreviewOptions: &gotgbot.LinkPreviewOptions{
+			IsDisabled: true,
+		},
+	})
+	return err
+}
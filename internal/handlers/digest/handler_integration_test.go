@@ -0,0 +1,167 @@
+package digest_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+
+	"twitterx-bot/internal/handlers"
+	"twitterx-bot/internal/handlers/testutil"
+	"twitterx-bot/internal/storage"
+	"twitterx-bot/internal/telegraph"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+const channelID = int64(-1001234)
+
+func channelPost(updateID, msgID int64, text string) *gotgbot.Update {
+	channel := gotgbot.Chat{Id: channelID, Type: "channel", Title: "News"}
+	return &gotgbot.Update{
+		UpdateId: updateID,
+		ChannelPost: &gotgbot.Message{
+			MessageId:  msgID,
+			Text:       text,
+			Chat:       channel,
+			SenderChat: &channel,
+		},
+	}
+}
+
+type fakeArticles struct {
+	entries []telegraph.DigestEntry
+}
+
+func (f *fakeArticles) CreateDigestArticle(_ context.Context, _ string, entries []telegraph.DigestEntry) (string, error) {
+	f.entries = entries
+	return "https://telegra.ph/Digest-10-18", nil
+}
+
+func TestIntegration_DigestSchedule(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemory()
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, nil, nil, handlers.WithStorage(store))
+
+	for i, text := range []string{"/digest weekly fri 18:30", "/digest daily 25:00", "/digest page on"} {
+		if err := dispatcher.ProcessUpdate(bot, channelPost(int64(i+1), int64(10+i), text), nil); err != nil {
+			t.Fatalf("ProcessUpdate(%q) error = %v", text, err)
+		}
+	}
+
+	d, ok, err := store.Digests().Get(ctx, channelID)
+	if err != nil || !ok {
+		t.Fatalf("Get() = %v, %v; want digest", ok, err)
+	}
+	if d.Frequency != storage.DigestWeekly || d.Weekday != time.Friday || d.Minute != 18*60+30 || d.Article {
+		t.Fatalf("digest = %+v, want weekly on Friday 18:30 without page", d)
+	}
+
+	calls := mock.GetCalls("sendMessage")
+	if len(calls) != 3 {
+		t.Fatalf("sendMessage calls = %d, want 3", len(calls))
+	}
+	if text, _ := calls[0].JSONString("text"); !strings.Contains(text, "every Friday at 18:30 UTC") {
+		t.Errorf("reply = %q, want schedule confirmation", text)
+	}
+	if text, _ := calls[1].JSONString("text"); !strings.Contains(text, "Usage") {
+		t.Errorf("reply = %q, want usage for an invalid time", text)
+	}
+	if text, _ := calls[2].JSONString("text"); !strings.Contains(text, "not available") {
+		t.Errorf("reply = %q, want pages unavailable without a creator", text)
+	}
+
+	if err := dispatcher.ProcessUpdate(bot, channelPost(4, 20, "/digest off"), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+	if _, ok, _ := store.Digests().Get(ctx, channelID); ok {
+		t.Fatal("digest still scheduled after /digest off")
+	}
+}
+
+func TestIntegration_DigestSchedule_NonASCIIDay(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemory()
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, nil, nil, handlers.WithStorage(store))
+
+	// The Kelvin sign lowercases to a one-byte "k"
+	for i, text := range []string{"/digest weekly \u212a 09:00", "/digest weekly пн 09:00", "/digest weekly MONDAY 09:00"} {
+		if err := dispatcher.ProcessUpdate(bot, channelPost(int64(i+1), int64(10+i), text), nil); err != nil {
+			t.Fatalf("ProcessUpdate(%q) error = %v", text, err)
+		}
+	}
+
+	calls := mock.GetCalls("sendMessage")
+	if len(calls) != 3 {
+		t.Fatalf("sendMessage calls = %d, want 3", len(calls))
+	}
+	for _, call := range calls[:2] {
+		if text, _ := call.JSONString("text"); !strings.Contains(text, "Usage") {
+			t.Errorf("reply = %q, want usage for an unknown day", text)
+		}
+	}
+	d, ok, err := store.Digests().Get(ctx, channelID)
+	if err != nil || !ok || d.Weekday != time.Monday || d.Minute != 9*60 {
+		t.Fatalf("Get() = %+v, %v, %v; want weekly on Monday 09:00", d, ok, err)
+	}
+}
+
+func TestIntegration_DigestNow_LinksMessagesAndPage(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemory()
+	fakeAPI := &testutil.FakeTweetAPI{
+		Tweets: map[string]*twitterxapi.Tweet{
+			"nasa/1": {ID: "1", URL: "https://x.com/nasa/status/1", Text: "Launch today", Likes: 1500, Author: twitterxapi.Author{Name: "NASA", ScreenName: "nasa"}},
+			"esa/2":  {ID: "2", URL: "https://x.com/esa/status/2", Text: "Landing soon", Likes: 20, Author: twitterxapi.Author{Name: "ESA", ScreenName: "esa"}},
+		},
+	}
+	articles := &fakeArticles{}
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, fakeAPI, nil,
+		handlers.WithStorage(store),
+		handlers.WithDigestArticles(articles),
+	)
+
+	now := time.Now()
+	shares := []storage.HistoryEntry{
+		{ChatID: channelID, MessageID: 100, TweetID: "2", Author: "esa", SentAt: now.Add(-3 * time.Hour)},
+		{ChatID: channelID, MessageID: 101, TweetID: "1", Author: "nasa", SentAt: now.Add(-2 * time.Hour)},
+		{ChatID: channelID, MessageID: 102, TweetID: "1", Author: "nasa", SentAt: now.Add(-time.Hour)},
+	}
+	for _, entry := range shares {
+		if err := store.History().Add(ctx, entry); err != nil {
+			t.Fatalf("History().Add() error = %v", err)
+		}
+	}
+
+	for i, text := range []string{"/digest daily 09:00", "/digest page on", "/digest now"} {
+		if err := dispatcher.ProcessUpdate(bot, channelPost(int64(i+1), int64(10+i), text), nil); err != nil {
+			t.Fatalf("ProcessUpdate(%q) error = %v", text, err)
+		}
+	}
+
+	calls := mock.GetCalls("sendMessage")
+	if len(calls) != 3 {
+		t.Fatalf("sendMessage calls = %d, want 3", len(calls))
+	}
+	text, _ := calls[2].JSONString("text")
+	nasa := strings.Index(text, `<a href="https://t.me/c/1234/101">@nasa</a>`)
+	esa := strings.Index(text, `<a href="https://t.me/c/1234/100">@esa</a>`)
+	if nasa < 0 || esa < 0 || nasa > esa {
+		t.Fatalf("digest = %q, want nasa (2 shares) before esa, linking the first messages", text)
+	}
+	if !strings.Contains(text, "3 tweets shared") || !strings.Contains(text, "❤️ 1.5K") {
+		t.Errorf("digest = %q, want share count and likes", text)
+	}
+	if !strings.Contains(text, "https://telegra.ph/Digest-10-18") {
+		t.Errorf("digest = %q, want page link", text)
+	}
+	if len(articles.entries) != 2 || articles.entries[0].Tweet.ID != "1" {
+		t.Errorf("page entries = %+v, want both tweets ranked", articles.entries)
+	}
+
+	// A preview does not count as the scheduled digest
+	if d, _, _ := store.Digests().Get(ctx, channelID); !d.LastSentAt.IsZero() {
+		t.Errorf("LastSentAt = %v, want unchanged by /digest now", d.LastSentAt)
+	}
+}
@@ -0,0 +1,151 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+
+	digestsvc "twitterx-bot/internal/digest"
+	"twitterx-bot/internal/handlers/shared"
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/storage"
+	"twitterx-bot/internal/telegram/tweet"
+	"twitterx-bot/internal/telegraph"
+)
+
+const snippetLength = 80
+
+// ArticleCreator publishes the full content of a digest as a single page.
+type ArticleCreator interface {
+	CreateDigestArticle(ctx context.Context, title string, entries []telegraph.DigestEntry) (string, error)
+}
+
+// Publisher posts digests as a ranked summary linking to the bot messages the tweets were
+// sent in. Articles is optional; without it digests have no page.
+type Publisher struct {
+	Bot      *gotgbot.Bot
+	Log      *logger.Logger
+	Chats    storage.ChatRepository
+	Articles ArticleCreator
+}
+
+// Publish sends the summary of the compiled digest to its chat.
+func (p Publisher) Publish(ctx context.Context, result digestsvc.Result) error {
+	chatID := result.Digest.ChatID
+	chat := &gotgbot.Chat{Id: chatID}
+	if p.Chats != nil {
+		if known, ok, err := p.Chats.Get(ctx, chatID); err == nil && ok {
+			chat.Username = known.Username
+		}
+	}
+
+	var pageURL string
+	if result.Digest.Article && p.Articles != nil {
+		url, err := p.Articles.CreateDigestArticle(ctx, title(result), articleEntries(result))
+		if err != nil && p.Log != nil {
+			// The summary is still useful without the page
+			p.Log.Warn("create digest article failed", "component", "digest", "chat_id", chatID, "err", err)
+		}
+		pageURL = url
+	}
+
+	_, err := p.Bot.SendMessageWithContext(ctx, chatID, summaryText(chat, result, pageURL), &gotgbot.SendMessageOpts{
+		ParseMode: "HTML",
+		LinkPreviewOptions: &gotgbot.LinkPreviewOptions{
+			IsDisabled: pageURL == "",
+			Url:        pageURL,
+		},
+	})
+	return err
+}
+
+func title(result digestsvc.Result) string {
+	name := "Daily digest"
+	if result.Digest.Frequency == storage.DigestWeekly {
+		name = "Weekly digest"
+	}
+	return fmt.Sprintf("%s — %s", name, result.Until.UTC().Format("2 Jan 2006"))
+}
+
+// summaryText lists the ranked tweets, each linking to its first bot message in the chat,
+// or to the tweet itself where messages cannot be linked.
+func summaryText(chat *gotgbot.Chat, result digestsvc.Result, pageURL string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📰 <b>%s</b>\n", html.EscapeString(title(result))))
+	sb.WriteString(fmt.Sprintf("%s shared since %s UTC\n\n", plural(result.Shared, "tweet"), result.Since.UTC().Format("2 Jan 15:04")))
+
+	for i, item := range result.Items {
+		link := shared.MessageLink(chat, item.MessageID)
+		if link == "" {
+			link = tweetURL(item)
+		}
+		sb.WriteString(fmt.Sprintf(`%d. <a href="%s">@%s</a>`, i+1, html.EscapeString(link), html.EscapeString(item.Author)))
+		if item.Tweet != nil {
+			if text := snippet(item.Tweet.Text); text != "" {
+				sb.WriteString(" — " + html.EscapeString(text))
+			}
+		}
+		sb.WriteString(fmt.Sprintf("\n    🔁 %d", item.Shares))
+		if item.Tweet != nil {
+			sb.WriteString(" · ❤️ " + compactCount(item.Tweet.Likes) + " · 🔄 " + compactCount(item.Tweet.Retweets))
+		}
+		sb.WriteString("\n")
+	}
+	if result.More > 0 {
+		sb.WriteString(fmt.Sprintf("…and %s more.\n", plural(result.More, "tweet")))
+	}
+	if pageURL != "" {
+		sb.WriteString(fmt.Sprintf("\n📖 <a href=\"%s\">Read the digest in full</a>", html.EscapeString(pageURL)))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func articleEntries(result digestsvc.Result) []telegraph.DigestEntry {
+	entries := make([]telegraph.DigestEntry, 0, len(result.Items))
+	for i, item := range result.Items {
+		if item.Tweet == nil {
+			continue
+		}
+		entries = append(entries, telegraph.DigestEntry{
+			Tweet: item.Tweet,
+			Label: fmt.Sprintf("%d. @%s · shared %s", i+1, item.Author, plural(item.Shares, "time")),
+		})
+	}
+	return entries
+}
+
+func tweetURL(item digestsvc.Item) string {
+	if item.Tweet != nil && item.Tweet.URL != "" {
+		return item.Tweet.URL
+	}
+	return fmt.Sprintf("https://x.com/%s/status/%s", item.Author, item.TweetID)
+}
+
+// snippet returns the first line of the tweet text, shortened for the summary.
+func snippet(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	return tweet.TruncateText(text, snippetLength)
+}
+
+// compactCount formats counters the way X shows them: 950, 1.2K, 3.4M.
+func compactCount(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return strings.TrimSuffix(strconv.FormatFloat(float64(n)/1_000_000, 'f', 1, 64), ".0") + "M"
+	case n >= 1_000:
+		return strings.TrimSuffix(strconv.FormatFloat(float64(n)/1_000, 'f', 1, 64), ".0") + "K"
+	default:
+		return strconv.Itoa(n)
+	}
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
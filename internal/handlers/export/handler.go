@@ -0,0 +1,191 @@
+// Package export implements /export and the "📄 Export" button under chains, which send a
+// tweet with its thread as a Markdown, HTML or JSON document.
+package export
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+
+	exportsvc "twitterx-bot/internal/export"
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/telegram/tweet"
+	"twitterx-bot/internal/twitterurl"
+)
+
+const usageText = "Usage: <code>/export &lt;link&gt; [md|html|json]</code>\n" +
+	"Sends the tweet with its thread as a document: Markdown (default), HTML with embedded images, or JSON."
+
+// Handler exports tweets and their threads as documents.
+type Handler struct {
+	log      *logger.Logger
+	exporter *exportsvc.Exporter
+	timeout  time.Duration
+}
+
+// New creates the export handlers; timeout bounds fetching the thread and its images.
+func New(log *logger.Logger, exporter *exportsvc.Exporter, timeout time.Duration) *Handler {
+	return &Handler{log: log, exporter: exporter, timeout: timeout}
+}
+
+// Command handles "/export <link> [md|html|json]".
+func (h *Handler) Command(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+	log := h.log.With("component", "export", "chat_id", msg.Chat.Id)
+	if ctx.EffectiveUser != nil {
+		log = log.With("user_id", ctx.EffectiveUser.Id, "username", ctx.EffectiveUser.Username)
+	}
+
+	args := ctx.Args()
+	if len(args) < 2 || len(args) > 3 {
+		return h.reply(b, msg, usageText)
+	}
+	username, tweetID, ok := twitterurl.ParseTweetURL(args[1])
+	if !ok {
+		return h.reply(b, msg, "That is not a tweet link.\n"+usageText)
+	}
+	format := exportsvc.Markdown
+	if len(args) == 3 {
+		if format, ok = exportsvc.ParseFormat(args[2]); !ok {
+			return h.reply(b, msg, fmt.Sprintf("Unknown format <code>%s</code>.\n%s", html.EscapeString(args[2]), usageText))
+		}
+	}
+
+	doc, err := h.export(username, tweetID, format)
+	if err != nil {
+		log.Warn("export failed", "tweet_username", username, "tweet_id", tweetID, "format", format, "err", err)
+		return h.reply(b, msg, failureText(err))
+	}
+	if err := sendDocument(b, msg.Chat.Id, msg.MessageId, doc); err != nil {
+		log.Error("send export failed", "err", err)
+		return err
+	}
+	log.Info("tweet exported", "tweet_username", username, "tweet_id", tweetID, "format", format, "tweets", doc.Tweets)
+	return nil
+}
+
+// Callback handles the "📄 Export" button, which offers the formats, and the format buttons,
+// which send the document in place of the picker.
+func (h *Handler) Callback(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.CallbackQuery
+	log := h.log.With("component", "export", "callback_id", cb.Id, "user_id", cb.From.Id, "username", cb.From.Username)
+
+	username, tweetID, name, ok := tweet.DecodeExportCallback(cb.Data)
+	if !ok || cb.Message == nil {
+		log.Error("decode export callback failed", "data", cb.Data)
+		return answer(b, cb, "Invalid callback data")
+	}
+	chatID := cb.Message.GetChat().Id
+	msgID := cb.Message.GetMessageId()
+	log = log.With("chat_id", chatID, "tweet_username", username, "tweet_id", tweetID)
+
+	if name == "" {
+		if _, err := b.SendMessage(chatID, "📄 Export the thread as:", &gotgbot.SendMessageOpts{
+			ReplyParameters: &gotgbot.ReplyParameters{MessageId: msgID, AllowSendingWithoutReply: true},
+			ReplyMarkup:     formatKeyboard(username, tweetID),
+		}); err != nil {
+			log.Error("send export formats failed", "err", err)
+			return answer(b, cb, "Cannot export the thread, try again later")
+		}
+		return answer(b, cb, "")
+	}
+
+	format, ok := exportsvc.ParseFormat(name)
+	if !ok {
+		log.Error("unknown export format", "data", cb.Data)
+		return answer(b, cb, "Invalid callback data")
+	}
+	if err := answer(b, cb, "Exporting..."); err != nil {
+		log.Debug("answer callback failed", "err", err)
+	}
+
+	doc, err := h.export(username, tweetID, format)
+	if err != nil {
+		log.Warn("export failed", "format", format, "err", err)
+		_, err := b.SendMessage(chatID, failureText(err), &gotgbot.SendMessageOpts{ParseMode: "HTML"})
+		return err
+	}
+	if err := sendDocument(b, chatID, replyTarget(cb.Message), doc); err != nil {
+		log.Error("send export failed", "err", err)
+		return err
+	}
+	if _, err := b.DeleteMessage(chatID, msgID, nil); err != nil {
+		log.Debug("delete export formats failed", "err", err)
+	}
+	log.Info("thread exported", "format", format, "tweets", doc.Tweets)
+	return nil
+}
+
+func (h *Handler) export(username, tweetID string, format exportsvc.Format) (exportsvc.Document, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+	return h.exporter.Export(ctx, username, tweetID, format)
+}
+
+func (h *Handler) reply(b *gotgbot.Bot, msg *gotgbot.Message, text string) error {
+	_, err := msg.Reply(b, text, &gotgbot.SendMessageOpts{ParseMode: "HTML"})
+	return err
+}
+
+// sendDocument sends the export to the chat, replying to replyTo when it is set.
+func sendDocument(b *gotgbot.Bot, chatID, replyTo int64, doc exportsvc.Document) error {
+	opts := &gotgbot.SendDocumentOpts{Caption: caption(doc)}
+	if replyTo != 0 {
+		opts.ReplyParameters = &gotgbot.ReplyParameters{MessageId: replyTo, AllowSendingWithoutReply: true}
+	}
+	_, err := b.SendDocument(chatID, gotgbot.InputFileByReader(doc.FileName, bytes.NewReader(doc.Data)), opts)
+	return err
+}
+
+// caption describes the document, e.g. "📄 Thread by @nasa · 3 tweets · HTML".
+func caption(doc exportsvc.Document) string {
+	if doc.Tweets > 1 {
+		return fmt.Sprintf("📄 Thread by @%s · %d tweets · %s", doc.Author, doc.Tweets, doc.Format.Label())
+	}
+	return fmt.Sprintf("📄 Tweet by @%s · %s", doc.Author, doc.Format.Label())
+}
+
+func failureText(err error) string {
+	if errors.Is(err, exportsvc.ErrFetchTweet) || errors.Is(err, exportsvc.ErrTweetNotFound) {
+		return "Cannot load the tweet, try again later."
+	}
+	return "Cannot export the tweet, try again later."
+}
+
+// formatKeyboard offers the export formats of the tweet.
+func formatKeyboard(username, tweetID string) gotgbot.InlineKeyboardMarkup {
+	row := make([]gotgbot.InlineKeyboardButton, 0, len(exportsvc.Formats))
+	for _, format := range exportsvc.Formats {
+		row = append(row, gotgbot.InlineKeyboardButton{
+			Text:         format.Label(),
+			CallbackData: tweet.EncodeExportCallback(username, tweetID, string(format)),
+		})
+	}
+	return gotgbot.InlineKeyboardMarkup{InlineKeyboard: [][]gotgbot.InlineKeyboardButton{row}}
+}
+
+// replyTarget returns the chain message the format picker replies to, or 0 when it is unknown.
+func replyTarget(msg gotgbot.MaybeInaccessibleMessage) int64 {
+	var reply *gotgbot.Message
+	switch m := msg.(type) {
+	case gotgbot.Message:
+		reply = m.ReplyToMessage
+	case *gotgbot.Message:
+		reply = m.ReplyToMessage
+	}
+	if reply == nil {
+		return 0
+	}
+	return reply.MessageId
+}
+
+func answer(b *gotgbot.Bot, cb *gotgbot.CallbackQuery, text string) error {
+	_, err := cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: text})
+	return err
+}
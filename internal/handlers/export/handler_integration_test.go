@@ -0,0 +1,170 @@
+package export_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+
+	"twitterx-bot/internal/handlers/testutil"
+	"twitterx-bot/internal/telegram/tweet"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+var group = gotgbot.Chat{Id: -100, Type: "supergroup"}
+
+// thread is a reply by @bob to @alice.
+func thread() *testutil.FakeTweetAPI {
+	parent := &twitterxapi.Tweet{
+		ID:     "1212092628029698048",
+		URL:    "https://x.com/alice/status/1212092628029698048",
+		Text:   "Launch is at noon",
+		Author: twitterxapi.Author{Name: "Alice", ScreenName: "alice"},
+		Media:  &twitterxapi.Media{Photos: []twitterxapi.Photo{{URL: "https://img/rocket.jpg"}}},
+	}
+	reply := testutil.ReplyTweet(&twitterxapi.Tweet{
+		ID:     "1212092628029698049",
+		URL:    "https://x.com/bob/status/1212092628029698049",
+		Text:   "See you there",
+		Author: twitterxapi.Author{Name: "Bob", ScreenName: "bob"},
+	}, parent.ID, "alice")
+	return &testutil.FakeTweetAPI{Tweets: map[string]*twitterxapi.Tweet{
+		"alice/" + parent.ID: parent,
+		"bob/" + reply.ID:    reply,
+	}}
+}
+
+func command(updateID, msgID int64, text string) *gotgbot.Update {
+	return &gotgbot.Update{
+		UpdateId: updateID,
+		Message: &gotgbot.Message{
+			MessageId: msgID,
+			Text:      text,
+			Chat:      group,
+			From:      &gotgbot.User{Id: 1, FirstName: "Sam"},
+		},
+	}
+}
+
+func callback(updateID int64, data string, msg *gotgbot.Message) *gotgbot.Update {
+	return &gotgbot.Update{
+		UpdateId: updateID,
+		CallbackQuery: &gotgbot.CallbackQuery{
+			Id:      fmt.Sprintf("cb-%d", updateID),
+			Data:    data,
+			From:    gotgbot.User{Id: 1, FirstName: "Sam"},
+			Message: msg,
+		},
+	}
+}
+
+func TestIntegration_ExportCommand_SendsThreadDocument(t *testing.T) {
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcher(t, thread())
+
+	if err := dispatcher.ProcessUpdate(bot, command(1, 10, "/export https://x.com/bob/status/1212092628029698049 html"), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	calls := mock.GetCalls("sendDocument")
+	if len(calls) != 1 {
+		t.Fatalf("sendDocument calls = %d, want 1", len(calls))
+	}
+	if got := calls[0].Form.Get("caption"); got != "📄 Thread by @bob · 2 tweets · HTML" {
+		t.Errorf("caption = %q", got)
+	}
+	if got := calls[0].Form.Get("reply_parameters"); !strings.Contains(got, `"message_id":10`) {
+		t.Errorf("reply_parameters = %q, want a reply to the command", got)
+	}
+	body := string(calls[0].RawBody)
+	for _, want := range []string{
+		"bob-1212092628029698049-thread.html",
+		"<h1>Thread by Bob (@bob)</h1>",
+		"Launch is at noon",
+		`<img src="https://img/rocket.jpg" alt="Photo 1">`,
+		"See you there",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("document missing %q:\n%s", want, body)
+		}
+	}
+	if n := len(mock.GetCalls("sendMessage")); n != 0 {
+		t.Errorf("sendMessage calls = %d, want only the document", n)
+	}
+}
+
+func TestIntegration_ExportCommand_Usage(t *testing.T) {
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcher(t, thread())
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"/export", "Usage:"},
+		{"/export bob", "not a tweet link"},
+		{"/export https://x.com/bob/status/1212092628029698049 pdf", "Unknown format <code>pdf</code>"},
+		{"/export https://x.com/bob/status/1", "Cannot load the tweet"},
+	}
+	for i, tt := range tests {
+		if err := dispatcher.ProcessUpdate(bot, command(int64(i+1), 10, tt.text), nil); err != nil {
+			t.Fatalf("ProcessUpdate(%q) error = %v", tt.text, err)
+		}
+
+		calls := mock.GetCalls("sendMessage")
+		if len(calls) != i+1 {
+			t.Fatalf("%q: sendMessage calls = %d, want %d", tt.text, len(calls), i+1)
+		}
+		if got, _ := calls[i].JSONString("text"); !strings.Contains(got, tt.want) {
+			t.Errorf("%q: reply = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+	if n := len(mock.GetCalls("sendDocument")); n != 0 {
+		t.Errorf("sendDocument calls = %d, want 0", n)
+	}
+}
+
+func TestIntegration_ExportButton_PicksFormatAndSendsDocument(t *testing.T) {
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcher(t, thread())
+
+	chainMsg := &gotgbot.Message{MessageId: 30, Chat: group}
+	if err := dispatcher.ProcessUpdate(bot, callback(1, tweet.EncodeExportCallback("bob", "1212092628029698049", ""), chainMsg), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+	picker := mock.GetCalls("sendMessage")
+	if len(picker) != 1 {
+		t.Fatalf("sendMessage calls = %d, want the format picker", len(picker))
+	}
+	markup, _ := picker[0].JSONString("reply_markup")
+	for _, want := range []string{"export:bob:1212092628029698049:md", "export:bob:1212092628029698049:html", "export:bob:1212092628029698049:json"} {
+		if !strings.Contains(markup, want) {
+			t.Errorf("picker reply_markup = %s, want %s", markup, want)
+		}
+	}
+	if n := len(mock.GetCalls("sendDocument")); n != 0 {
+		t.Fatalf("sendDocument calls = %d before a format was picked", n)
+	}
+
+	pickerMsg := &gotgbot.Message{MessageId: 31, Chat: group, ReplyToMessage: chainMsg}
+	if err := dispatcher.ProcessUpdate(bot, callback(2, tweet.EncodeExportCallback("bob", "1212092628029698049", "json"), pickerMsg), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+	docs := mock.GetCalls("sendDocument")
+	if len(docs) != 1 {
+		t.Fatalf("sendDocument calls = %d, want 1", len(docs))
+	}
+	if got := docs[0].Form.Get("reply_parameters"); !strings.Contains(got, `"message_id":30`) {
+		t.Errorf("reply_parameters = %q, want a reply to the chain message", got)
+	}
+	body := string(docs[0].RawBody)
+	if !strings.Contains(body, "bob-1212092628029698049-thread.json") || !strings.Contains(body, `"replying_to": "alice"`) {
+		t.Errorf("json document unexpected:\n%s", body)
+	}
+
+	deleted := mock.GetCalls("deleteMessage")
+	if len(deleted) != 1 {
+		t.Fatalf("deleteMessage calls = %d, want the picker removed", len(deleted))
+	}
+	if id, _ := deleted[0].JSONInt64("message_id"); id != 31 {
+		t.Errorf("deleted message_id = %d, want 31", id)
+	}
+}
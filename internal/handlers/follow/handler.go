@@ -0,0 +1,213 @@
+// Package follow implements /follow, /unfollow and /following, which subscribe a chat
+// to new tweets of an account.
+package follow
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+
+	followsvc "twitterx-bot/internal/follow"
+	"twitterx-bot/internal/handlers/shared"
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/storage"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+const (
+	// maxFollowsPerChat keeps a single chat from making the poller fetch too many timelines.
+	maxFollowsPerChat = 20
+	requestTimeout    = 15 * time.Second
+)
+
+// Handler manages the accounts a chat follows.
+type Handler struct {
+	log      *logger.Logger
+	follows  storage.FollowRepository
+	timeline followsvc.TimelineFetcher
+	now      func() time.Time
+}
+
+// New creates the follow command handlers. The timeline is fetched once on /follow to check
+// the account and to start its cursor at the newest tweet.
+func New(log *logger.Logger, follows storage.FollowRepository, timeline followsvc.TimelineFetcher) *Handler {
+	return &Handler{log: log, follows: follows, timeline: timeline, now: time.Now}
+}
+
+// Follow handles "/follow @username".
+func (h *Handler) Follow(b *gotgbot.Bot, ctx *ext.Context) error {
+	log := h.logger(ctx)
+	msg := ctx.EffectiveMessage
+	chatID := ctx.EffectiveChat.Id
+
+	username, ok := usernameArg(ctx)
+	if !ok {
+		return h.reply(b, msg, "Usage: <code>/follow @username</code>\nNew tweets of the account will be posted here.")
+	}
+	if allowed, err := h.canManage(b, ctx); err != nil || !allowed {
+		return h.denied(b, msg, log, err)
+	}
+
+	reqCtx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	follows, err := h.follows.ListByChat(reqCtx, chatID)
+	if err != nil {
+		log.Error("list follows failed", "err", err)
+		return h.reply(b, msg, "Cannot load the followed accounts, try again later.")
+	}
+	for _, f := range follows {
+		if f.Username == username {
+			return h.reply(b, msg, fmt.Sprintf("This chat already follows %s.", accountLink(username)))
+		}
+	}
+	if len(follows) >= maxFollowsPerChat {
+		return h.reply(b, msg, fmt.Sprintf("This chat already follows %d accounts, the maximum. Use <code>/unfollow @username</code> first.", maxFollowsPerChat))
+	}
+
+	tweets, err := h.timeline.GetTimeline(reqCtx, username, twitterxapi.TimelineOpts{Limit: 1})
+	if err != nil {
+		log.Warn("fetch timeline failed", "username", username, "err", err)
+		return h.reply(b, msg, fmt.Sprintf("Cannot load tweets of %s. Check the username and try again.", html.EscapeString("@"+username)))
+	}
+
+	var addedBy int64
+	if ctx.EffectiveUser != nil {
+		addedBy = ctx.EffectiveUser.Id
+	}
+	if _, err := h.follows.Add(reqCtx, storage.Follow{ChatID: chatID, Username: username, AddedBy: addedBy, CreatedAt: h.now()}); err != nil {
+		log.Error("add follow failed", "err", err)
+		return h.reply(b, msg, "Cannot save the followed account, try again later.")
+	}
+	if err := h.startCursor(reqCtx, username, tweets); err != nil {
+		// The poller initializes a missing cursor on its first poll
+		log.Warn("start follow cursor failed", "username", username, "err", err)
+	}
+
+	log.Info("account followed", "username", username)
+	return h.reply(b, msg, fmt.Sprintf("✅ This chat now follows %s. New tweets will be posted here.", accountLink(username)))
+}
+
+// Unfollow handles "/unfollow @username".
+func (h *Handler) Unfollow(b *gotgbot.Bot, ctx *ext.Context) error {
+	log := h.logger(ctx)
+	msg := ctx.EffectiveMessage
+
+	username, ok := usernameArg(ctx)
+	if !ok {
+		return h.reply(b, msg, "Usage: <code>/unfollow @username</code>")
+	}
+	if allowed, err := h.canManage(b, ctx); err != nil || !allowed {
+		return h.denied(b, msg, log, err)
+	}
+
+	removed, err := h.follows.Remove(context.Background(), ctx.EffectiveChat.Id, username)
+	if err != nil {
+		log.Error("remove follow failed", "err", err)
+		return h.reply(b, msg, "Cannot update the followed accounts, try again later.")
+	}
+	if !removed {
+		return h.reply(b, msg, fmt.Sprintf("This chat does not follow %s.", accountLink(username)))
+	}
+
+	log.Info("account unfollowed", "username", username)
+	return h.reply(b, msg, fmt.Sprintf("Unfollowed %s.", accountLink(username)))
+}
+
+// Following handles "/following" and lists the accounts the chat follows.
+func (h *Handler) Following(b *gotgbot.Bot, ctx *ext.Context) error {
+	log := h.logger(ctx)
+	msg := ctx.EffectiveMessage
+
+	follows, err := h.follows.ListByChat(context.Background(), ctx.EffectiveChat.Id)
+	if err != nil {
+		log.Error("list follows failed", "err", err)
+		return h.reply(b, msg, "Cannot load the followed accounts, try again later.")
+	}
+	if len(follows) == 0 {
+		return h.reply(b, msg, "This chat does not follow any accounts.\nUse <code>/follow @username</code> to post new tweets here.")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📡 This chat follows %d of %d accounts:\n", len(follows), maxFollowsPerChat))
+	for _, f := range follows {
+		sb.WriteString("• " + accountLink(f.Username) + "\n")
+	}
+	sb.WriteString("\nUse <code>/unfollow @username</code> to stop.")
+	return h.reply(b, msg, sb.String())
+}
+
+// startCursor sets the cursor of a newly followed account to its newest tweet, so only
+// tweets posted after /follow are sent. An existing cursor is kept.
+func (h *Handler) startCursor(ctx context.Context, username string, tweets []*twitterxapi.Tweet) error {
+	if _, ok, err := h.follows.Cursor(ctx, username); err != nil || ok {
+		return err
+	}
+	var newest string
+	for _, tw := range tweets {
+		if tw != nil && followsvc.NewerID(tw.ID, newest) {
+			newest = tw.ID
+		}
+	}
+	return h.follows.SetCursor(ctx, storage.FollowCursor{Username: username, LastTweetID: newest, CheckedAt: h.now()})
+}
+
+// canManage reports whether the sender may change what the chat follows. Channel posts
+// and anonymous admins speak for the chat; in groups only administrators may.
+func (h *Handler) canManage(b *gotgbot.Bot, ctx *ext.Context) (bool, error) {
+	if shared.IsAnonymousAdmin(ctx.EffectiveMessage) {
+		return true, nil
+	}
+	if ctx.EffectiveUser == nil {
+		return false, nil
+	}
+	return shared.CanManageChat(b, ctx.EffectiveChat, ctx.EffectiveUser.Id)
+}
+
+func (h *Handler) denied(b *gotgbot.Bot, msg *gotgbot.Message, log *logger.Logger, err error) error {
+	if err != nil {
+		log.Error("check chat admin failed", "err", err)
+		return h.reply(b, msg, "Cannot check your permissions, try again later.")
+	}
+	log.Info("follow denied: not an admin")
+	return h.reply(b, msg, "Only chat admins can change the followed accounts.")
+}
+
+func (h *Handler) logger(ctx *ext.Context) *logger.Logger {
+	log := h.log.With("component", "follow")
+	if ctx.EffectiveChat != nil {
+		log = log.With("chat_id", ctx.EffectiveChat.Id)
+	}
+	if ctx.EffectiveUser != nil {
+		log = log.With("user_id", ctx.EffectiveUser.Id, "username", ctx.EffectiveUser.Username)
+	}
+	return log
+}
+
+func (h *Handler) reply(b *gotgbot.Bot, msg *gotgbot.Message, text string) error {
+	_, err := msg.Reply(b, text, &gotgbot.SendMessageOpts{
+		ParseMode: "HTML",
+		LinkPreviewOptions: &gotgbot.LinkPreviewOptions{
+			IsDisabled: true,
+		},
+	})
+	return err
+}
+
+// usernameArg parses the account from the first command argument.
+func usernameArg(ctx *ext.Context) (string, bool) {
+	args := ctx.Args()
+	if len(args) < 2 {
+		return "", false
+	}
+	return followsvc.ParseUsername(args[1])
+}
+
+func accountLink(username string) string {
+	return fmt.Sprintf(`<a href="https://x.com/%s">@%s</a>`, html.EscapeString(username), html.EscapeString(username))
+}
@@ -0,0 +1,177 @@
+package follow_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+
+	followsvc "twitterx-bot/internal/follow"
+	"twitterx-bot/internal/handlers"
+	followhandler "twitterx-bot/internal/handlers/follow"
+	"twitterx-bot/internal/handlers/testutil"
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/storage"
+	"twitterx-bot/internal/telegram/tweet"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+const channelID = int64(-1001234)
+
+func channelPost(updateID, msgID int64, text string) *gotgbot.Update {
+	channel := gotgbot.Chat{Id: channelID, Type: "channel", Title: "News"}
+	return &gotgbot.Update{
+		UpdateId: updateID,
+		ChannelPost: &gotgbot.Message{
+			MessageId:  msgID,
+			Text:       text,
+			Chat:       channel,
+			SenderChat: &channel,
+		},
+	}
+}
+
+func groupMessage(updateID, chatID, msgID int64, text string) *gotgbot.Update {
+	return &gotgbot.Update{
+		UpdateId: updateID,
+		Message: &gotgbot.Message{
+			MessageId: msgID,
+			Text:      text,
+			Chat:      gotgbot.Chat{Id: chatID, Type: "group"},
+			From:      &gotgbot.User{Id: 5005, FirstName: "Erin"},
+		},
+	}
+}
+
+func nasaTweet(id, text string) *twitterxapi.Tweet {
+	return &twitterxapi.Tweet{
+		ID:     id,
+		URL:    "https://x.com/NASA/status/" + id,
+		Text:   text,
+		Author: twitterxapi.Author{Name: "NASA", ScreenName: "NASA"},
+	}
+}
+
+func TestIntegration_FollowInChannel_PostsNewTweets(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemory()
+	fakeAPI := &testutil.FakeTweetAPI{
+		Timelines: map[string][]*twitterxapi.Tweet{"nasa": {nasaTweet("100", "Old news")}},
+	}
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, fakeAPI, nil,
+		handlers.WithStorage(store),
+		handlers.WithFollowing(fakeAPI),
+	)
+
+	if err := dispatcher.ProcessUpdate(bot, channelPost(1, 10, "/follow @NASA"), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	calls := mock.GetCalls("sendMessage")
+	if len(calls) != 1 {
+		t.Fatalf("sendMessage calls = %d, want 1", len(calls))
+	}
+	if text, _ := calls[0].JSONString("text"); !strings.Contains(text, "now follows") || !strings.Contains(text, "@nasa") {
+		t.Errorf("reply = %q, want follow confirmation", text)
+	}
+	if chatID, _ := calls[0].JSONInt64("chat_id"); chatID != channelID {
+		t.Errorf("reply chat_id = %d, want %d", chatID, channelID)
+	}
+	cursor, ok, _ := store.Follows().Cursor(ctx, "nasa")
+	if !ok || cursor.LastTweetID != "100" {
+		t.Errorf("Cursor() = %+v, %v; want started at 100", cursor, ok)
+	}
+
+	// A new tweet appears and the poller posts it with the regular tweet rendering
+	fakeAPI.Timelines["nasa"] = []*twitterxapi.Tweet{nasaTweet("101", "Launch today"), nasaTweet("100", "Old news")}
+	poller := followsvc.NewPoller(logger.New(true), store.Follows(), fakeAPI, followhandler.Publisher{Sender: tweet.Sender{Bot: bot}})
+	if err := poller.Poll(ctx, "nasa"); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+
+	calls = mock.GetCalls("sendMessage")
+	if len(calls) != 2 {
+		t.Fatalf("sendMessage calls = %d, want tweet posted", len(calls))
+	}
+	text, _ := calls[1].JSONString("text")
+	if !strings.Contains(text, "Launch today") || strings.Contains(text, "Old news") {
+		t.Errorf("posted text = %q, want only the new tweet", text)
+	}
+	if chatID, _ := calls[1].JSONInt64("chat_id"); chatID != channelID {
+		t.Errorf("posted chat_id = %d, want %d", chatID, channelID)
+	}
+	if _, ok := calls[1].JSONInt64("reply_parameters.message_id"); ok {
+		t.Error("followed tweet sent as a reply")
+	}
+}
+
+func TestIntegration_FollowingAndUnfollow(t *testing.T) {
+	store := storage.NewMemory()
+	fakeAPI := &testutil.FakeTweetAPI{
+		Timelines: map[string][]*twitterxapi.Tweet{"nasa": nil, "esa": nil},
+	}
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, fakeAPI, nil,
+		handlers.WithStorage(store),
+		handlers.WithFollowing(fakeAPI),
+	)
+
+	for i, text := range []string{"/follow nasa", "/follow https://x.com/ESA", "/follow @nasa", "/follow @unknown_user", "/following"} {
+		if err := dispatcher.ProcessUpdate(bot, channelPost(int64(i+1), int64(10+i), text), nil); err != nil {
+			t.Fatalf("ProcessUpdate(%q) error = %v", text, err)
+		}
+	}
+
+	calls := mock.GetCalls("sendMessage")
+	if len(calls) != 5 {
+		t.Fatalf("sendMessage calls = %d, want 5", len(calls))
+	}
+	if text, _ := calls[2].JSONString("text"); !strings.Contains(text, "already follows") {
+		t.Errorf("duplicate reply = %q", text)
+	}
+	if text, _ := calls[3].JSONString("text"); !strings.Contains(text, "Cannot load tweets of @unknown_user") {
+		t.Errorf("unknown account reply = %q", text)
+	}
+	text, _ := calls[4].JSONString("text")
+	if !strings.Contains(text, "follows 2 of") || strings.Index(text, "@esa") > strings.Index(text, "@nasa") {
+		t.Errorf("/following = %q, want esa and nasa", text)
+	}
+
+	if err := dispatcher.ProcessUpdate(bot, channelPost(10, 20, "/unfollow @nasa"), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+	follows, _ := store.Follows().ListByChat(context.Background(), channelID)
+	if len(follows) != 1 || follows[0].Username != "esa" {
+		t.Errorf("follows = %+v, want only esa", follows)
+	}
+}
+
+func TestIntegration_FollowInGroup_RequiresAdmin(t *testing.T) {
+	store := storage.NewMemory()
+	fakeAPI := &testutil.FakeTweetAPI{Timelines: map[string][]*twitterxapi.Tweet{"nasa": nil}}
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, fakeAPI, nil,
+		handlers.WithStorage(store),
+		handlers.WithFollowing(fakeAPI),
+	)
+	if err := mock.SetResponse("getChatMember", map[string]any{
+		"status": "member",
+		"user":   map[string]any{"id": 5005, "is_bot": false, "first_name": "Erin"},
+	}); err != nil {
+		t.Fatalf("SetResponse() error = %v", err)
+	}
+
+	if err := dispatcher.ProcessUpdate(bot, groupMessage(1, -100, 10, "/follow @nasa"), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	calls := mock.GetCalls("sendMessage")
+	if len(calls) != 1 {
+		t.Fatalf("sendMessage calls = %d, want 1", len(calls))
+	}
+	if text, _ := calls[0].JSONString("text"); !strings.Contains(text, "Only chat admins") {
+		t.Errorf("reply = %q, want admin-only notice", text)
+	}
+	if follows, _ := store.Follows().ListByChat(context.Background(), -100); len(follows) != 0 {
+		t.Errorf("follows = %+v, want none", follows)
+	}
+}
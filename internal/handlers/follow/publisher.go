@@ -0,0 +1,27 @@
+package follow
+
+import (
+	"context"
+	"fmt"
+
+	"twitterx-bot/internal/follow"
+	"twitterx-bot/internal/handlers/shared"
+	"twitterx-bot/internal/telegram/tweet"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+// Publisher posts followed tweets with the same rendering as shared links.
+// Sender must have its Bot set.
+type Publisher struct {
+	Sender tweet.Sender
+}
+
+// Publish sends the tweet to the chat as a standalone message. Chats the bot was removed
+// from fail with follow.ErrChatGone.
+func (p Publisher) Publish(ctx context.Context, chatID int64, tw *twitterxapi.Tweet) error {
+	err := p.Sender.SendTweet(ctx, chatID, 0, tw, nil)
+	if shared.IsBotRemoved(err) {
+		return fmt.Errorf("%w: %w", follow.ErrChatGone, err)
+	}
+	return err
+}
@@ -2,30 +2,99 @@ package inline
 
 import (
 	"context"
+	"errors"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/PaulSonOfLars/gotgbot/v2"
 	"github.com/PaulSonOfLars/gotgbot/v2/ext"
 
+	"twitterx-bot/internal/handlers/shared"
 	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/stats"
+	"twitterx-bot/internal/storage"
+	"twitterx-bot/internal/telegram/tweet"
 	"twitterx-bot/internal/twitterurl"
 	inlineuc "twitterx-bot/internal/usecase/tweetsvc/inline"
 )
 
+const (
+	// savedPageSize is the number of saved tweets offered per page of an empty inline query.
+	savedPageSize = 20
+
+	// tweetCacheTime is how long Telegram may reuse the answer for a tweet link, in seconds.
+	tweetCacheTime = 300
+
+	// tweetIDDigits is the length of tweet IDs since 2013. Longer IDs cannot be valid and are
+	// rejected at once. A shorter ID at the end of the query may still be being typed, so it is
+	// fetched only after typingDelay without a newer query: links to older tweets, whose IDs are
+	// shorter, are answered half a second later in exchange for not fetching every typed prefix.
+	tweetIDDigits = 19
+	typingDelay   = 500 * time.Millisecond
+)
+
+// BookmarkLister lists the tweets saved by a user.
+type BookmarkLister interface {
+	List(ctx context.Context, filter storage.BookmarkFilter) ([]storage.Bookmark, int, error)
+}
+
 // Handler wraps the dependencies needed to respond to inline queries.
 type Handler struct {
-	log     *logger.Logger
-	uc      *inlineuc.UseCase
-	timeout time.Duration
+	log       *logger.Logger
+	uc        *inlineuc.UseCase
+	timeout   time.Duration
+	stats     *stats.Recorder
+	bookmarks BookmarkLister
+	personal  bool
+
+	mu       sync.Mutex
+	inflight map[int64]*inflightQuery // by user ID
+}
+
+// inflightQuery is the query of a user being answered; a newer query from the user cancels it.
+type inflightQuery struct {
+	cancel context.CancelFunc
+}
+
+// Option configures optional inline handler behavior.
+type Option func(*Handler)
+
+// WithStats records chosen inline results and failed lookups as usage events.
+func WithStats(recorder *stats.Recorder) Option {
+	return func(h *Handler) {
+		h.stats = recorder
+	}
+}
+
+// WithBookmarks answers empty inline queries with the saved tweets of the user.
+func WithBookmarks(bookmarks BookmarkLister) Option {
+	return func(h *Handler) {
+		h.bookmarks = bookmarks
+	}
+}
+
+// WithPersonalAnswers keeps Telegram from sharing cached tweet answers between users. It is
+// needed when access to the bot is restricted, as a denied user would get the answer cached
+// for an allowed one.
+func WithPersonalAnswers() Option {
+	return func(h *Handler) {
+		h.personal = true
+	}
 }
 
 // New creates a handler for inline queries.
-func New(log *logger.Logger, uc *inlineuc.UseCase, timeout time.Duration) *Handler {
-	return &Handler{log: log, uc: uc, timeout: timeout}
+func New(log *logger.Logger, uc *inlineuc.UseCase, timeout time.Duration, opts ...Option) *Handler {
+	h := &Handler{log: log, uc: uc, timeout: timeout, inflight: make(map[int64]*inflightQuery)}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
-// Handle answers inline queries that contain Twitter URLs.
+// Handle answers inline queries that contain Twitter URLs with the ways to send the tweet,
+// a page at a time.
 func (h *Handler) Handle(b *gotgbot.Bot, ctx *ext.Context) error {
 	query := strings.TrimSpace(ctx.InlineQuery.Query)
 	log := h.log.With("component", "inline")
@@ -37,55 +106,155 @@ func (h *Handler) Handle(b *gotgbot.Bot, ctx *ext.Context) error {
 	}
 	log.Debug("inline query received", "query", query)
 
+	reqCtx, done := h.begin(ctx.InlineQuery.From.Id)
+	defer done()
+
+	if query == "" && h.bookmarks != nil && ctx.EffectiveUser != nil {
+		return h.answerSaved(reqCtx, b, ctx, log)
+	}
+
 	username, tweetID, ok := twitterurl.ParseTweetURL(query)
-	if !ok {
+	if !ok || len(tweetID) > tweetIDDigits {
 		log.Debug("inline query ignored: no tweet url")
-		_, err := ctx.InlineQuery.Answer(b, nil, &gotgbot.AnswerInlineQueryOpts{
-			CacheTime:  0,
-			IsPersonal: true,
-		})
-		return err
+		return h.answerNotice(b, ctx.InlineQuery, noticePasteLink)
 	}
 
 	log.Info("tweet url parsed", "tweet_username", username, "tweet_id", tweetID)
 	log.Debug("inline query details", "query", query, "tweet_username", username, "tweet_id", tweetID)
 
-	reqCtx, cancel := context.WithTimeout(context.Background(), h.timeout)
-	defer cancel()
+	if len(tweetID) < tweetIDDigits && strings.HasSuffix(query, tweetID) {
+		select {
+		case <-reqCtx.Done():
+			log.Debug("inline query superseded while typing", "tweet_id", tweetID)
+			return nil
+		case <-time.After(typingDelay):
+		}
+	}
 
-	result, ok, err := h.uc.BuildInlineResult(reqCtx, username, tweetID)
+	offset, _ := strconv.Atoi(ctx.InlineQuery.Offset)
+	results, nextOffset, err := h.uc.BuildInlineResults(reqCtx, username, tweetID, offset)
+	if superseded(reqCtx) {
+		log.Debug("inline query superseded", "tweet_id", tweetID)
+		return nil
+	}
 	if err != nil {
 		log.Error("build inline result failed", "tweet_username", username, "tweet_id", tweetID, "err", err)
-		_, answerErr := ctx.InlineQuery.Answer(b, nil, &gotgbot.AnswerInlineQueryOpts{
-			CacheTime:  0,
-			IsPersonal: true,
-		})
-		if answerErr != nil {
-			return answerErr
-		}
-		return nil
+		h.stats.Record(reqCtx, shared.UsageEvent(storage.EventFailed, 0, ctx.EffectiveUser, username))
+		return h.answerNotice(b, ctx.InlineQuery, fetchNotice(err))
 	}
 
-	if !ok {
-		log.Warn("no suitable inline result", "tweet_id", tweetID)
-		_, answerErr := ctx.InlineQuery.Answer(b, nil, &gotgbot.AnswerInlineQueryOpts{
-			CacheTime:  0,
-			IsPersonal: true,
-		})
-		if answerErr != nil {
-			return answerErr
+	if len(results) == 0 {
+		if offset > 0 {
+			return h.answer(b, ctx.InlineQuery, nil, gotgbot.AnswerInlineQueryOpts{IsPersonal: true})
 		}
+		log.Warn("no suitable inline result", "tweet_id", tweetID)
+		return h.answerNotice(b, ctx.InlineQuery, noticeNotFound)
+	}
+
+	// The choices are the same for everyone, so Telegram may share them between users
+	// unless access to the bot differs between them
+	err = h.answer(b, ctx.InlineQuery, results, gotgbot.AnswerInlineQueryOpts{
+		CacheTime:  tweetCacheTime,
+		IsPersonal: h.personal,
+		NextOffset: nextOffset,
+	})
+	if err == nil {
+		log.Info("inline result sent", "tweet_id", tweetID, "count", len(results), "offset", offset)
+	}
+	return err
+}
+
+// answerSaved offers the saved tweets of the user, newest first, paginated with the query offset.
+func (h *Handler) answerSaved(reqCtx context.Context, b *gotgbot.Bot, ctx *ext.Context, log *logger.Logger) error {
+	offset, _ := strconv.Atoi(ctx.InlineQuery.Offset)
+	if offset < 0 {
+		offset = 0
+	}
+
+	list, total, err := h.bookmarks.List(reqCtx, storage.BookmarkFilter{UserID: ctx.EffectiveUser.Id, Offset: offset, Limit: savedPageSize})
+	if superseded(reqCtx) {
 		return nil
 	}
+	if err != nil {
+		log.Error("list bookmarks failed", "err", err)
+		list, total = nil, 0
+	}
 
-	results := []gotgbot.InlineQueryResult{result}
+	builder := tweet.InlineBuilder{SaveButton: h.uc != nil && h.uc.SaveButton}
+	results := make([]gotgbot.InlineQueryResult, 0, len(list))
+	for _, bookmark := range list {
+		if result, ok := builder.Build(bookmark.Tweet, bookmark.TweetID); ok {
+			results = append(results, result)
+		}
+	}
+	if len(results) == 0 && offset == 0 {
+		return h.answerNotice(b, ctx.InlineQuery, noticePasteLink)
+	}
 
-	_, err = ctx.InlineQuery.Answer(b, results, &gotgbot.AnswerInlineQueryOpts{
-		CacheTime:  0,
+	var nextOffset string
+	if offset+len(list) < total {
+		nextOffset = strconv.Itoa(offset + len(list))
+	}
+	err = h.answer(b, ctx.InlineQuery, results, gotgbot.AnswerInlineQueryOpts{
 		IsPersonal: true,
+		NextOffset: nextOffset,
 	})
 	if err == nil {
-		log.Info("inline result sent", "tweet_id", tweetID)
+		log.Info("saved tweets offered", "count", len(results), "offset", offset)
 	}
 	return err
 }
+
+// answer answers the query with the help button above the results.
+func (h *Handler) answer(b *gotgbot.Bot, query *gotgbot.InlineQuery, results []gotgbot.InlineQueryResult, opts gotgbot.AnswerInlineQueryOpts) error {
+	opts.Button = helpButton
+	_, err := query.Answer(b, results, &opts)
+	return err
+}
+
+// answerNotice answers the query with a single notice. Notices are not cached: their cause may pass.
+func (h *Handler) answerNotice(b *gotgbot.Bot, query *gotgbot.InlineQuery, n notice) error {
+	result := n.result(strings.TrimSpace(query.Query), b.Username)
+	return h.answer(b, query, []gotgbot.InlineQueryResult{result}, gotgbot.AnswerInlineQueryOpts{IsPersonal: true})
+}
+
+// begin registers a query of the user, canceling the previous one still in flight, and returns
+// its context. done has to be called once the query is answered.
+func (h *Handler) begin(userID int64) (reqCtx context.Context, done func()) {
+	reqCtx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	query := &inflightQuery{cancel: cancel}
+
+	h.mu.Lock()
+	if prev := h.inflight[userID]; prev != nil {
+		prev.cancel()
+	}
+	h.inflight[userID] = query
+	h.mu.Unlock()
+
+	return reqCtx, func() {
+		h.mu.Lock()
+		if h.inflight[userID] == query {
+			delete(h.inflight, userID)
+		}
+		h.mu.Unlock()
+		cancel()
+	}
+}
+
+// superseded reports whether the query was canceled by a newer one; its answer would be stale.
+func superseded(ctx context.Context) bool {
+	return errors.Is(ctx.Err(), context.Canceled)
+}
+
+// Chosen records an inline result the user sent to a chat. Telegram delivers these
+// updates only when inline feedback is enabled for the bot in @BotFather.
+func (h *Handler) Chosen(_ *gotgbot.Bot, ctx *ext.Context) error {
+	chosen := ctx.ChosenInlineResult
+	username, _, ok := twitterurl.ParseTweetURL(strings.TrimSpace(chosen.Query))
+	if !ok {
+		return nil
+	}
+	h.log.With("component", "inline", "user_id", chosen.From.Id, "result_id", chosen.ResultId).Debug("inline result chosen", "tweet_username", username)
+	h.stats.Record(context.Background(), shared.UsageEvent(storage.EventInlineChosen, 0, &chosen.From, username))
+	return nil
+}
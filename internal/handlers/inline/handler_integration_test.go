@@ -1,12 +1,24 @@
 package inline_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
 
+	"twitterx-bot/internal/handlers"
+	"twitterx-bot/internal/handlers/inline"
 	"twitterx-bot/internal/handlers/testutil"
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/storage"
 	"twitterx-bot/internal/twitterxapi"
+	inlineuc "twitterx-bot/internal/usecase/tweetsvc/inline"
+	testtelegram "twitterx-bot/pkg/testutil/telegram"
 )
 
 func TestIntegration_InlineQuery_ReturnsResult(t *testing.T) {
@@ -61,6 +73,38 @@ func TestIntegration_InlineQuery_ReturnsResult(t *testing.T) {
 	}
 }
 
+func TestIntegration_InlineQuery_PersonalWithAccessControl(t *testing.T) {
+	fakeAPI := &testutil.FakeTweetAPI{
+		Tweets: map[string]*twitterxapi.Tweet{
+			"inlineuser/987": {ID: "987", URL: "https://x.com/inlineuser/status/987", Text: "Inline response", Author: twitterxapi.Author{ScreenName: "inlineuser"}},
+		},
+	}
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, fakeAPI, nil, handlers.WithStorage(storage.NewMemory()))
+
+	update := gotgbot.Update{
+		UpdateId: 6,
+		InlineQuery: &gotgbot.InlineQuery{
+			Id:    "inline-personal",
+			Query: "https://x.com/inlineuser/status/987",
+			From:  gotgbot.User{Id: 2001, FirstName: "Inline"},
+		},
+	}
+	if err := dispatcher.ProcessUpdate(bot, &update, nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	calls := mock.GetCalls("answerInlineQuery")
+	if len(calls) != 1 {
+		t.Fatalf("answerInlineQuery calls = %d, want 1", len(calls))
+	}
+	if personal := fmt.Sprint(calls[0].JSON["is_personal"]); personal != "true" {
+		t.Errorf("is_personal = %s, want answers kept per user while access is controlled", personal)
+	}
+	if cache, _ := calls[0].JSONInt64("cache_time"); cache <= 0 {
+		t.Errorf("cache_time = %d, want tweet results cached", cache)
+	}
+}
+
 func TestIntegration_InlineQuery_InvalidQuery(t *testing.T) {
 	bot, mock, dispatcher := testutil.SetupBotAndDispatcher(t, &testutil.FakeTweetAPI{})
 
@@ -84,7 +128,252 @@ func TestIntegration_InlineQuery_InvalidQuery(t *testing.T) {
 
 	call := calls[0]
 	results := testutil.DecodeInlineResults(t, call)
-	if len(results) != 0 {
-		t.Fatalf("results len = %d, want 0", len(results))
+	if len(results) != 1 {
+		t.Fatalf("results len = %d, want 1", len(results))
+	}
+	if title := results[0].(map[string]any)["title"]; title != "Paste a tweet link" {
+		t.Errorf("result title = %v, want the paste link notice", title)
+	}
+	if button, _ := call.JSONString("button"); !strings.Contains(button, `"start_parameter":"help"`) {
+		t.Errorf("button = %s, want a help deep link", button)
+	}
+}
+
+func TestIntegration_ChosenInlineResult_RecordsEvent(t *testing.T) {
+	store := storage.NewMemory()
+	bot, _, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, &testutil.FakeTweetAPI{}, nil, handlers.WithStorage(store))
+
+	update := gotgbot.Update{
+		UpdateId: 7,
+		ChosenInlineResult: &gotgbot.ChosenInlineResult{
+			ResultId: "987",
+			From:     gotgbot.User{Id: 2001, FirstName: "Inline"},
+			Query:    "https://x.com/inlineuser/status/987",
+		},
+	}
+	if err := dispatcher.ProcessUpdate(bot, &update, nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	events, err := store.Events().List(context.Background(), storage.EventFilter{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(events) != 1 || events[0].Kind != storage.EventInlineChosen || events[0].UserID != 2001 || events[0].Author != "inlineuser" {
+		t.Errorf("events = %+v, want one inline event by user 2001 for inlineuser", events)
+	}
+}
+
+func TestIntegration_InlineQuery_PaginatesChoices(t *testing.T) {
+	fakeAPI := &testutil.FakeTweetAPI{
+		Tweets: map[string]*twitterxapi.Tweet{
+			"inlineuser/988": {
+				ID:     "988",
+				URL:    "https://x.com/inlineuser/status/988",
+				Text:   "Album",
+				Author: twitterxapi.Author{Name: "Inline", ScreenName: "inlineuser"},
+				Quote:  &twitterxapi.Tweet{ID: "5", Text: "Quoted", Author: twitterxapi.Author{ScreenName: "other"}},
+				Media: &twitterxapi.Media{Photos: []twitterxapi.Photo{
+					{URL: "https://img/1.jpg"}, {URL: "https://img/2.jpg"}, {URL: "https://img/3.jpg"}, {URL: "https://img/4.jpg"},
+				}},
+			},
+		},
+	}
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcher(t, fakeAPI)
+
+	query := func(updateID int64, offset string) {
+		update := gotgbot.Update{
+			UpdateId: updateID,
+			InlineQuery: &gotgbot.InlineQuery{
+				Id:     "inline-page",
+				Query:  "https://x.com/inlineuser/status/988",
+				Offset: offset,
+				From:   gotgbot.User{Id: 2003, FirstName: "Inline"},
+			},
+		}
+		if err := dispatcher.ProcessUpdate(bot, &update, nil); err != nil {
+			t.Fatalf("ProcessUpdate() error = %v", err)
+		}
+	}
+
+	query(1, "")
+	query(2, "5")
+
+	calls := mock.GetCalls("answerInlineQuery")
+	if len(calls) != 2 {
+		t.Fatalf("answerInlineQuery calls = %d, want 2", len(calls))
+	}
+	if results := testutil.DecodeInlineResults(t, calls[0]); len(results) != 5 {
+		t.Errorf("first page results = %d, want 5", len(results))
+	}
+	if next, _ := calls[0].JSONString("next_offset"); next != "5" {
+		t.Errorf("first page next_offset = %q, want 5", next)
+	}
+	results := testutil.DecodeInlineResults(t, calls[1])
+	if len(results) != 2 {
+		t.Fatalf("second page results = %d, want the last two photos", len(results))
+	}
+	if id := results[1].(map[string]any)["id"]; id != "988:photo:4" {
+		t.Errorf("last result id = %v, want 988:photo:4", id)
+	}
+	if next, _ := calls[1].JSONString("next_offset"); next != "" {
+		t.Errorf("second page next_offset = %q, want none", next)
+	}
+}
+
+// blockingAPI holds back the tweet "slow" until the query is canceled.
+type blockingAPI struct {
+	started chan string
+	tweets  map[string]*twitterxapi.Tweet
+}
+
+func (f *blockingAPI) GetTweet(ctx context.Context, username, tweetID string) (*twitterxapi.Tweet, error) {
+	f.started <- tweetID
+	if username == "slow" {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return f.tweets[username+"/"+tweetID], nil
+}
+
+func inlineContext(bot *gotgbot.Bot, id, query string) *ext.Context {
+	return ext.NewContext(bot, &gotgbot.Update{
+		InlineQuery: &gotgbot.InlineQuery{Id: id, Query: query, From: gotgbot.User{Id: 2004, FirstName: "Fast"}},
+	}, nil)
+}
+
+func TestIntegration_InlineQuery_NewerQueryCancelsInFlight(t *testing.T) {
+	mock := testtelegram.NewMockServer()
+	t.Cleanup(mock.Close)
+	bot := testutil.NewTestBot(t, mock)
+
+	api := &blockingAPI{started: make(chan string, 2), tweets: map[string]*twitterxapi.Tweet{
+		"fast/1111111111111111112": {ID: "1111111111111111112", URL: "https://x.com/fast/status/1111111111111111112", Text: "Fast", Author: twitterxapi.Author{ScreenName: "fast"}},
+	}}
+	h := inline.New(logger.New(true), inlineuc.New(api), 10*time.Second)
+
+	slow := make(chan error, 1)
+	go func() {
+		slow <- h.Handle(bot, inlineContext(bot, "old", "https://x.com/slow/status/1111111111111111111"))
+	}()
+	<-api.started
+
+	if err := h.Handle(bot, inlineContext(bot, "new", "https://x.com/fast/status/1111111111111111112")); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+	select {
+	case err := <-slow:
+		if err != nil {
+			t.Fatalf("Handle() of the superseded query error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("superseded query was not canceled")
+	}
+
+	calls := mock.GetCalls("answerInlineQuery")
+	if len(calls) != 1 {
+		t.Fatalf("answerInlineQuery calls = %d, want only the newer query answered", len(calls))
+	}
+	if id, _ := calls[0].JSONString("inline_query_id"); id != "new" {
+		t.Errorf("answered query = %q, want new", id)
+	}
+	if cache, _ := calls[0].JSONInt64("cache_time"); cache <= 0 {
+		t.Errorf("cache_time = %d, want tweet results cached", cache)
+	}
+}
+
+func TestIntegration_InlineQuery_RejectsIncompleteIDs(t *testing.T) {
+	mock := testtelegram.NewMockServer()
+	t.Cleanup(mock.Close)
+	bot := testutil.NewTestBot(t, mock)
+
+	api := &blockingAPI{started: make(chan string, 4), tweets: map[string]*twitterxapi.Tweet{
+		"fast/1234567890123456789": {ID: "1234567890123456789", URL: "https://x.com/fast/status/1234567890123456789", Text: "Fast", Author: twitterxapi.Author{ScreenName: "fast"}},
+	}}
+	h := inline.New(logger.New(true), inlineuc.New(api), 10*time.Second)
+
+	// Too long to be a tweet ID: answered without a lookup
+	if err := h.Handle(bot, inlineContext(bot, "long", "https://x.com/fast/status/12345678901234567890")); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	// A prefix of the ID being typed is dropped once the rest arrives
+	typing := make(chan error, 1)
+	go func() {
+		typing <- h.Handle(bot, inlineContext(bot, "typing", "https://x.com/fast/status/12345"))
+	}()
+	time.Sleep(50 * time.Millisecond)
+	if err := h.Handle(bot, inlineContext(bot, "full", "https://x.com/fast/status/1234567890123456789")); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+	if err := <-typing; err != nil {
+		t.Fatalf("Handle() of the partial ID error = %v", err)
+	}
+
+	close(api.started)
+	var fetched []string
+	for id := range api.started {
+		fetched = append(fetched, id)
+	}
+	if len(fetched) != 1 || fetched[0] != "1234567890123456789" {
+		t.Errorf("fetched tweets = %v, want only the complete ID", fetched)
+	}
+
+	calls := mock.GetCalls("answerInlineQuery")
+	if len(calls) != 2 {
+		t.Fatalf("answerInlineQuery calls = %d, want the long and the full query", len(calls))
+	}
+	if cache, _ := calls[0].JSONInt64("cache_time"); cache != 0 {
+		t.Errorf("rejected query cache_time = %d, want 0", cache)
+	}
+}
+
+// failingAPI fails every tweet lookup with err.
+type failingAPI struct {
+	err error
+}
+
+func (f failingAPI) GetTweet(context.Context, string, string) (*twitterxapi.Tweet, error) {
+	return nil, f.err
+}
+
+func TestIntegration_InlineQuery_ExplainsFailures(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"missing", nil, "Tweet not found"},
+		{"not found", &twitterxapi.APIError{Code: 404, Message: "NOT_FOUND"}, "Tweet not found"},
+		{"protected", &twitterxapi.APIError{Code: 401, Message: "PRIVATE_TWEET"}, "Account is protected"},
+		{"backend down", errors.New("connection refused"), "Backend unavailable, try again"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := testtelegram.NewMockServer()
+			t.Cleanup(mock.Close)
+			bot := testutil.NewTestBot(t, mock)
+			h := inline.New(logger.New(true), inlineuc.New(failingAPI{err: tt.err}), 10*time.Second)
+
+			if err := h.Handle(bot, inlineContext(bot, "failed", "https://x.com/gone/status/1234567890123456789")); err != nil {
+				t.Fatalf("Handle() error = %v", err)
+			}
+
+			calls := mock.GetCalls("answerInlineQuery")
+			if len(calls) != 1 {
+				t.Fatalf("answerInlineQuery calls = %d, want 1", len(calls))
+			}
+			results := testutil.DecodeInlineResults(t, calls[0])
+			if len(results) != 1 {
+				t.Fatalf("results len = %d, want 1", len(results))
+			}
+			result := results[0].(map[string]any)
+			if result["type"] != "article" || result["title"] != tt.want {
+				t.Errorf("result = %v, want an article titled %q", result, tt.want)
+			}
+			if cache, _ := calls[0].JSONInt64("cache_time"); cache != 0 {
+				t.Errorf("cache_time = %d, want notices not cached", cache)
+			}
+		})
 	}
 }
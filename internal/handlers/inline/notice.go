@@ -0,0 +1,83 @@
+package inline
+
+import (
+	"errors"
+	"html"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+
+	"twitterx-bot/internal/twitterxapi"
+)
+
+// helpStartParameter opens the private chat with the bot on /start, which replies with the help.
+const helpStartParameter = "help"
+
+// helpButton is shown above every inline answer and deep-links to the private chat for help.
+var helpButton = &gotgbot.InlineQueryResultsButton{
+	Text:           "How to use the bot",
+	StartParameter: helpStartParameter,
+}
+
+// notice is an informative result shown when there is no tweet to offer.
+type notice struct {
+	id          string
+	title       string
+	description string
+}
+
+var (
+	noticePasteLink = notice{
+		id:          "notice:paste",
+		title:       "Paste a tweet link",
+		description: "Type a link such as https://x.com/user/status/123 to share the tweet",
+	}
+	noticeNotFound = notice{
+		id:          "notice:not-found",
+		title:       "Tweet not found",
+		description: "It may have been deleted, or the link is mistyped",
+	}
+	noticeProtected = notice{
+		id:          "notice:protected",
+		title:       "Account is protected",
+		description: "Tweets of protected accounts cannot be shared",
+	}
+	noticeUnavailable = notice{
+		id:          "notice:unavailable",
+		title:       "Backend unavailable, try again",
+		description: "The tweet could not be loaded right now",
+	}
+)
+
+// fetchNotice explains why the tweet could not be loaded.
+func fetchNotice(err error) notice {
+	switch {
+	case errors.Is(err, twitterxapi.ErrNotFound):
+		return noticeNotFound
+	case errors.Is(err, twitterxapi.ErrProtected):
+		return noticeProtected
+	default:
+		return noticeUnavailable
+	}
+}
+
+// result returns the notice as an article. Choosing it sends the query as is, or a hint on
+// using the bot when the query is empty.
+func (n notice) result(query, botUsername string) gotgbot.InlineQueryResult {
+	message := html.EscapeString(query)
+	switch {
+	case message != "":
+	case botUsername != "":
+		message = "Share tweets in any chat: type <code>@" + html.EscapeString(botUsername) + "</code> and paste a tweet link."
+	default:
+		message = "Share tweets in any chat: type the bot's username and paste a tweet link."
+	}
+	return gotgbot.InlineQueryResultArticle{
+		Id:          n.id,
+		Title:       n.title,
+		Description: n.description,
+		InputMessageContent: gotgbot.InputTextMessageContent{
+			MessageText: message,
+			ParseMode:   "HTML",
+		},
+	}
+}
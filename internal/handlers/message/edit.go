@@ -0,0 +1,110 @@
+package message
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+
+	"twitterx-bot/internal/storage"
+	"twitterx-bot/internal/twitterurl"
+)
+
+// EditWindow is how long edits are followed. Bots cannot delete their messages in groups
+// after 48 hours, and older messages may predate the reply mapping.
+const EditWindow = 48 * time.Hour
+
+// ReplyStore maps user messages to the bot messages sent for their tweet links.
+type ReplyStore interface {
+	Add(ctx context.Context, reply storage.Reply) error
+	List(ctx context.Context, chatID, sourceMessageID int64) ([]storage.Reply, error)
+	Remove(ctx context.Context, chatID, sourceMessageID int64, tweetID string) error
+}
+
+// WithEdits follows edited messages using the replies recorded for them; see HandleEdit.
+func WithEdits(replies ReplyStore) Option {
+	return func(h *Handler) {
+		h.replies = replies
+	}
+}
+
+// tweetLink is a tweet linked from a message.
+type tweetLink struct {
+	username, tweetID string
+}
+
+// messageLinks returns the tweet links the bot answers in a message text.
+// Like new messages, only the first link is rendered.
+func messageLinks(text string) []tweetLink {
+	username, tweetID, ok := twitterurl.ParseTweetURL(strings.TrimSpace(text))
+	if !ok {
+		return nil
+	}
+	return []tweetLink{{username: username, tweetID: tweetID}}
+}
+
+// HandleEdit brings the bot replies in line with an edited message. The links answered
+// before are taken from the recorded replies and compared with the links of the new text:
+// replies to links that were removed or changed are deleted, and new links are rendered.
+func (h *Handler) HandleEdit(b *gotgbot.Bot, ctx *ext.Context) error {
+	if h.replies == nil {
+		return nil
+	}
+	msg := ctx.EffectiveMessage
+	log := h.logger(ctx)
+
+	if sent := time.Unix(msg.Date, 0); h.now().Sub(sent) > EditWindow {
+		log.Debug("edit ignored: message too old", "date", sent)
+		return nil
+	}
+
+	reqCtx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+
+	chatID := ctx.EffectiveChat.Id
+	replies, err := h.replies.List(reqCtx, chatID, msg.MessageId)
+	if err != nil {
+		log.Error("list replies failed", "err", err)
+		return nil
+	}
+
+	links := messageLinks(msg.Text)
+	wanted := make(map[string]bool, len(links))
+	for _, link := range links {
+		wanted[link.tweetID] = true
+	}
+
+	// Group the replies by tweet, keeping the send order
+	answered := make(map[string][]int64)
+	var tweetIDs []string
+	for _, reply := range replies {
+		if _, ok := answered[reply.TweetID]; !ok {
+			tweetIDs = append(tweetIDs, reply.TweetID)
+		}
+		answered[reply.TweetID] = append(answered[reply.TweetID], reply.MessageID)
+	}
+
+	for _, tweetID := range tweetIDs {
+		if wanted[tweetID] {
+			continue
+		}
+		if _, err := b.DeleteMessages(chatID, answered[tweetID], nil); err != nil {
+			log.Warn("delete stale replies failed", "tweet_id", tweetID, "err", err)
+		}
+		if err := h.replies.Remove(reqCtx, chatID, msg.MessageId, tweetID); err != nil {
+			log.Warn("remove stale replies failed", "tweet_id", tweetID, "err", err)
+		}
+		log.Info("stale replies removed", "tweet_id", tweetID, "messages", len(answered[tweetID]))
+	}
+
+	for _, link := range links {
+		if _, ok := answered[link.tweetID]; ok {
+			continue
+		}
+		log.Info("tweet url added by edit", "tweet_username", link.username, "tweet_id", link.tweetID)
+		h.sendLink(reqCtx, b, ctx, log, link.username, link.tweetID)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package message_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+
+	"twitterx-bot/internal/handlers"
+	"twitterx-bot/internal/handlers/testutil"
+	"twitterx-bot/internal/storage"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+func groupMessage(updateID, msgID int64, text string, edited bool) *gotgbot.Update {
+	msg := &gotgbot.Message{
+		MessageId: msgID,
+		Text:      text,
+		Chat:      gotgbot.Chat{Id: -100500, Type: "supergroup"},
+		From:      &gotgbot.User{Id: 1001, FirstName: "Alice", Username: "alice"},
+		Date:      time.Now().Add(-time.Minute).Unix(),
+	}
+	if edited {
+		msg.EditDate = time.Now().Unix()
+		return &gotgbot.Update{UpdateId: updateID, EditedMessage: msg}
+	}
+	return &gotgbot.Update{UpdateId: updateID, Message: msg}
+}
+
+func editAPI() *testutil.FakeTweetAPI {
+	return &testutil.FakeTweetAPI{
+		Tweets: map[string]*twitterxapi.Tweet{
+			"nasa/1": {
+				ID:     "1",
+				URL:    "https://x.com/nasa/status/1",
+				Text:   "Two photos",
+				Author: twitterxapi.Author{Name: "NASA", ScreenName: "nasa"},
+				Media: &twitterxapi.Media{Photos: []twitterxapi.Photo{
+					{URL: "https://pbs.twimg.com/media/a.jpg"},
+					{URL: "https://pbs.twimg.com/media/b.jpg"},
+				}},
+			},
+			"nasa/2": {
+				ID:     "2",
+				URL:    "https://x.com/nasa/status/2",
+				Text:   "Fixed link",
+				Author: twitterxapi.Author{Name: "NASA", ScreenName: "nasa"},
+			},
+		},
+	}
+}
+
+func TestIntegration_EditedMessage_ReplacesRepliesOfChangedLink(t *testing.T) {
+	store := storage.NewMemory()
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, editAPI(), nil, handlers.WithStorage(store))
+	// Keep the IDs of sent messages apart from the media group ones
+	if err := mock.SetResponse("sendMessage", map[string]any{
+		"message_id": 99,
+		"date":       0,
+		"chat":       map[string]any{"id": -100500, "type": "supergroup"},
+	}); err != nil {
+		t.Fatalf("SetResponse() error = %v", err)
+	}
+
+	if err := dispatcher.ProcessUpdate(bot, groupMessage(1, 10, "look https://x.com/nasa/status/1", false), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+	replies, _ := store.Replies().List(context.Background(), -100500, 10)
+	if len(replies) != 3 {
+		t.Fatalf("replies = %+v, want both media group messages and the keyboard reply", replies)
+	}
+	sent := len(mock.GetCalls("sendMessage"))
+
+	// An edit that keeps the link changes nothing
+	if err := dispatcher.ProcessUpdate(bot, groupMessage(2, 10, "look at this https://x.com/nasa/status/1", true), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+	if n := len(mock.GetCalls("deleteMessages")) + len(mock.GetCalls("sendMessage")) - sent; n != 0 {
+		t.Fatalf("delete/send calls = %d after an edit keeping the link, want 0", n)
+	}
+
+	if err := dispatcher.ProcessUpdate(bot, groupMessage(3, 10, "look https://x.com/nasa/status/2", true), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	deletes := mock.GetCalls("deleteMessages")
+	if len(deletes) != 1 {
+		t.Fatalf("deleteMessages calls = %d, want 1", len(deletes))
+	}
+	want := fmt.Sprintf("[%d,%d,%d]", replies[0].MessageID, replies[1].MessageID, replies[2].MessageID)
+	if ids, _ := deletes[0].JSONString("message_ids"); ids != want {
+		t.Errorf("deleted message_ids = %s, want %s", ids, want)
+	}
+
+	sends := mock.GetCalls("sendMessage")[sent:]
+	if len(sends) != 1 {
+		t.Fatalf("sendMessage calls = %d, want the new tweet", len(sends))
+	}
+	if text, _ := sends[0].JSONString("text"); !strings.Contains(text, "Fixed link") {
+		t.Errorf("sent text = %q, want the new tweet", text)
+	}
+	if replyTo, _ := sends[0].JSONInt64("reply_parameters.message_id"); replyTo != 10 {
+		t.Errorf("reply_to = %d, want the edited message", replyTo)
+	}
+	replies, _ = store.Replies().List(context.Background(), -100500, 10)
+	if len(replies) != 1 || replies[0].TweetID != "2" {
+		t.Errorf("replies = %+v, want only the new tweet", replies)
+	}
+}
+
+func TestIntegration_EditedMessage_AddsAndRemovesLinks(t *testing.T) {
+	store := storage.NewMemory()
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, editAPI(), nil, handlers.WithStorage(store))
+
+	if err := dispatcher.ProcessUpdate(bot, groupMessage(1, 20, "see below", false), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+	if err := dispatcher.ProcessUpdate(bot, groupMessage(2, 20, "see https://x.com/nasa/status/2", true), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+	if sends := mock.GetCalls("sendMessage"); len(sends) != 1 {
+		t.Fatalf("sendMessage calls = %d, want the added link rendered", len(sends))
+	}
+
+	if err := dispatcher.ProcessUpdate(bot, groupMessage(3, 20, "never mind", true), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+	if deletes := mock.GetCalls("deleteMessages"); len(deletes) != 1 {
+		t.Fatalf("deleteMessages calls = %d, want the reply deleted", len(deletes))
+	}
+	if sends := mock.GetCalls("sendMessage"); len(sends) != 1 {
+		t.Errorf("sendMessage calls = %d, want nothing new", len(sends))
+	}
+	if replies, _ := store.Replies().List(context.Background(), -100500, 20); len(replies) != 0 {
+		t.Errorf("replies = %+v, want none", replies)
+	}
+}
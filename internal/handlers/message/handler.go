@@ -8,10 +8,14 @@ import (
 	"github.com/PaulSonOfLars/gotgbot/v2"
 	"github.com/PaulSonOfLars/gotgbot/v2/ext"
 
+	"twitterx-bot/internal/chatsettings"
 	"twitterx-bot/internal/handlers/shared"
 	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/stats"
+	"twitterx-bot/internal/storage"
 	"twitterx-bot/internal/telegram/tweet"
 	"twitterx-bot/internal/twitterurl"
+	"twitterx-bot/internal/usecase/tweetsvc/sendchain"
 	"twitterx-bot/internal/usecase/tweetsvc/sendtweet"
 )
 
@@ -22,30 +26,43 @@ type TweetFetcher interface {
 
 // Handler encapsulates the dependencies required for processing message-based tweets.
 type Handler struct {
-	log       *logger.Logger
-	fetcher   TweetFetcher
-	timeout   time.Duration
-	telegraph tweet.ArticleCreator
+	log     *logger.Logger
+	fetcher TweetFetcher
+	timeout time.Duration
+	sender  tweet.Sender
+
+	reposts      RepostHistory
+	repostWindow time.Duration
+	replies      ReplyStore
+	now          func() time.Time
+
+	stats *stats.Recorder
 }
 
+// Option configures optional message handler behavior.
+type Option func(*Handler)
+
 // New creates a new message handler with the supplied logger, tweet fetcher, and timeout.
-func New(log *logger.Logger, fetcher TweetFetcher, timeout time.Duration, telegraph tweet.ArticleCreator) *Handler {
-	return &Handler{log: log, fetcher: fetcher, timeout: timeout, telegraph: telegraph}
+// The sender carries optional dependencies (Telegraph, translation); Bot and Log are set per update.
+func New(log *logger.Logger, fetcher TweetFetcher, timeout time.Duration, sender tweet.Sender, opts ...Option) *Handler {
+	h := &Handler{log: log, fetcher: fetcher, timeout: timeout, sender: sender, now: time.Now}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// WithStats records sent tweets and failures as usage events.
+func WithStats(recorder *stats.Recorder) Option {
+	return func(h *Handler) {
+		h.stats = recorder
+	}
 }
 
 // Handle processes incoming Telegram messages that contain Twitter URLs.
 func (h *Handler) Handle(b *gotgbot.Bot, ctx *ext.Context) error {
 	text := strings.TrimSpace(ctx.EffectiveMessage.Text)
-	log := h.log.With("component", "message")
-	if ctx.EffectiveChat != nil {
-		log = log.With("chat_id", ctx.EffectiveChat.Id)
-	}
-	if ctx.EffectiveUser != nil {
-		log = log.With("user_id", ctx.EffectiveUser.Id, "username", ctx.EffectiveUser.Username)
-	}
-	if ctx.EffectiveMessage != nil {
-		log = log.With("message_id", ctx.EffectiveMessage.MessageId)
-	}
+	log := h.logger(ctx)
 	log.Debug("message received", "text", text)
 
 	username, tweetID, ok := twitterurl.ParseTweetURL(text)
@@ -59,17 +76,69 @@ func (h *Handler) Handle(b *gotgbot.Bot, ctx *ext.Context) error {
 	reqCtx, cancel := context.WithTimeout(context.Background(), h.timeout)
 	defer cancel()
 
+	h.sendLink(reqCtx, b, ctx, log, username, tweetID)
+	return nil
+}
+
+// sendLink answers a tweet link in the message of ctx: with the tweet (or its chain), or with
+// a notice when the tweet was shared recently. Failures are logged and recorded, not returned.
+func (h *Handler) sendLink(reqCtx context.Context, b *gotgbot.Bot, ctx *ext.Context, log *logger.Logger, username, tweetID string) {
+	if h.replyIfRecentlyShared(reqCtx, b, ctx, log, username, tweetID) {
+		return
+	}
+
 	_, err := b.SendChatAction(ctx.EffectiveChat.Id, gotgbot.ChatActionTyping, &gotgbot.SendChatActionOpts{})
 	if err != nil {
 		log.Debug("send chat action failed", "err", err)
 	}
 
-	uc := sendtweet.New(h.fetcher, tweet.Sender{Bot: b, Telegraph: h.telegraph, Log: log})
-	if sendErr := uc.SendTweet(reqCtx, ctx.EffectiveChat.Id, ctx.EffectiveMessage.MessageId, username, tweetID, shared.UserDisplayName(ctx.EffectiveUser)); sendErr != nil {
+	chatID := ctx.EffectiveChat.Id
+	msgID := ctx.EffectiveMessage.MessageId
+	requester := shared.UserDisplayName(ctx.EffectiveUser)
+	settings := chatsettings.Load(reqCtx, h.sender.Settings, chatID, h.log)
+
+	sender := h.sender
+	sender.Bot = b
+	sender.Log = log
+
+	kind := storage.EventTweetSent
+	var sendErr error
+	if settings.ChainMode == chatsettings.ChainAuto {
+		kind = storage.EventChainSent
+		uc := sendchain.New(h.fetcher, sender)
+		uc.Settings = h.sender.Settings
+		sendErr = uc.SendChain(reqCtx, chatID, msgID, username, tweetID, requester)
+	} else {
+		uc := sendtweet.New(h.fetcher, sender)
+		uc.Settings = h.sender.Settings
+		sendErr = uc.SendTweet(reqCtx, chatID, msgID, username, tweetID, requester)
+	}
+	if sendErr != nil {
 		log.Error("send tweet failed", "tweet_username", username, "tweet_id", tweetID, "err", sendErr)
-		return nil
+		h.stats.Record(reqCtx, shared.UsageEvent(storage.EventFailed, chatID, ctx.EffectiveUser, username))
+		return
+	}
+	h.stats.Record(reqCtx, shared.UsageEvent(kind, chatID, ctx.EffectiveUser, username))
+
+	if settings.DeleteOriginal {
+		if _, err := b.DeleteMessage(chatID, msgID, nil); err != nil {
+			log.Debug("delete original message failed", "err", err)
+		}
 	}
 
 	log.Info("tweet sent", "tweet_username", username, "tweet_id", tweetID)
-	return nil
+}
+
+func (h *Handler) logger(ctx *ext.Context) *logger.Logger {
+	log := h.log.With("component", "message")
+	if ctx.EffectiveChat != nil {
+		log = log.With("chat_id", ctx.EffectiveChat.Id)
+	}
+	if ctx.EffectiveUser != nil {
+		log = log.With("user_id", ctx.EffectiveUser.Id, "username", ctx.EffectiveUser.Username)
+	}
+	if ctx.EffectiveMessage != nil {
+		log = log.With("message_id", ctx.EffectiveMessage.MessageId)
+	}
+	return log
 }
@@ -1,11 +1,16 @@
 package message_test
 
 import (
+	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/PaulSonOfLars/gotgbot/v2"
 
+	"twitterx-bot/internal/handlers"
 	"twitterx-bot/internal/handlers/testutil"
+	"twitterx-bot/internal/storage"
 	"twitterx-bot/internal/telegram/tweet"
 	"twitterx-bot/internal/twitterxapi"
 )
@@ -248,12 +253,8 @@ func TestIntegration_MessageHandler_UsesTelegraphForLongCaption(t *testing.T) {
 	if !telegraphMock.Called {
 		t.Fatalf("expected Telegraph CreateArticle to be called")
 	}
-	wantText := longTweet.Text + "\n\n" + tweetURL
-	if telegraphMock.GotText != wantText {
-		t.Fatalf("CreateArticle text = %q, want %q", telegraphMock.GotText, wantText)
-	}
-	if telegraphMock.GotTitle != "Tweet by @longuser" {
-		t.Fatalf("CreateArticle title = %q, want %q", telegraphMock.GotTitle, "Tweet by @longuser")
+	if telegraphMock.GotTweet != longTweet {
+		t.Fatalf("CreateArticle tweet = %v, want %v", telegraphMock.GotTweet, longTweet)
 	}
 
 	photoCalls := mock.GetCalls("sendPhoto")
@@ -296,3 +297,113 @@ func TestIntegration_NonTwitterURL_NoResponse(t *testing.T) {
 		t.Errorf("sendChatAction should not be called for non-Twitter URL")
 	}
 }
+
+func TestIntegration_MessageHandler_RecordsChatAndHistory(t *testing.T) {
+	fakeAPI := &testutil.FakeTweetAPI{
+		Tweets: map[string]*twitterxapi.Tweet{
+			"testuser/123456789": {
+				ID:     "123456789",
+				URL:    "https://x.com/testuser/status/123456789",
+				Text:   "Hello storage!",
+				Author: twitterxapi.Author{Name: "Test User", ScreenName: "testuser"},
+			},
+		},
+	}
+	store := storage.NewMemory()
+	bot, _, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, fakeAPI, nil, handlers.WithStorage(store))
+
+	const chatID = int64(-1001)
+	update := gotgbot.Update{
+		UpdateId: 1,
+		Message: &gotgbot.Message{
+			MessageId: 100,
+			Text:      "https://x.com/testuser/status/123456789",
+			Chat:      gotgbot.Chat{Id: chatID, Type: "supergroup", Title: "Readers"},
+			From:      &gotgbot.User{Id: 1001, FirstName: "Alice", Username: "alice"},
+			Date:      1000000,
+		},
+	}
+	if err := dispatcher.ProcessUpdate(bot, &update, nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	ctx := context.Background()
+	chat, ok, err := store.Chats().Get(ctx, chatID)
+	if err != nil || !ok {
+		t.Fatalf("chat not tracked: %v, %v", ok, err)
+	}
+	if chat.Title != "Readers" || chat.Type != "supergroup" {
+		t.Errorf("chat = %+v", chat)
+	}
+
+	entry, ok, err := store.History().Latest(ctx, chatID, "123456789")
+	if err != nil || !ok {
+		t.Fatalf("history not recorded: %v, %v", ok, err)
+	}
+	if entry.SourceMessageID != 100 || entry.Author != "testuser" || entry.MessageID == 0 {
+		t.Errorf("history entry = %+v", entry)
+	}
+}
+
+func TestIntegration_MessageHandler_RepliesAlreadyShared(t *testing.T) {
+	fakeAPI := &testutil.FakeTweetAPI{
+		Tweets: map[string]*twitterxapi.Tweet{
+			"testuser/123456789": {
+				ID:     "123456789",
+				URL:    "https://x.com/testuser/status/123456789",
+				Text:   "Hello again!",
+				Author: twitterxapi.Author{Name: "Test User", ScreenName: "testuser"},
+			},
+		},
+	}
+	store := storage.NewMemory()
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, fakeAPI, nil,
+		handlers.WithStorage(store),
+		handlers.WithRepostDetection(time.Hour),
+	)
+
+	const chatID = int64(-1001234567890)
+	linkUpdate := func(updateID, msgID int64) *gotgbot.Update {
+		return &gotgbot.Update{
+			UpdateId: updateID,
+			Message: &gotgbot.Message{
+				MessageId: msgID,
+				Text:      "https://x.com/testuser/status/123456789",
+				Chat:      gotgbot.Chat{Id: chatID, Type: "supergroup"},
+				From:      &gotgbot.User{Id: 1001, FirstName: "Alice"},
+				Date:      1000000,
+			},
+		}
+	}
+
+	if err := dispatcher.ProcessUpdate(bot, linkUpdate(1, 100), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+	first, ok, _ := store.History().Latest(context.Background(), chatID, "123456789")
+	if !ok {
+		t.Fatal("first share not recorded")
+	}
+
+	if err := dispatcher.ProcessUpdate(bot, linkUpdate(2, 101), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	msgCalls := mock.GetCalls("sendMessage")
+	if len(msgCalls) != 2 {
+		t.Fatalf("sendMessage calls = %d, want 2 (tweet + notice)", len(msgCalls))
+	}
+	notice := msgCalls[1]
+	text, _ := notice.JSONString("text")
+	if !strings.Contains(text, "already shared") || !strings.Contains(text, "https://t.me/c/1234567890/") {
+		t.Errorf("notice text = %q, want already shared with message link", text)
+	}
+	if replyID, _ := notice.JSONInt64("reply_parameters.message_id"); replyID != first.MessageID {
+		t.Errorf("notice replies to %d, want earlier tweet %d", replyID, first.MessageID)
+	}
+	if !strings.Contains(string(notice.RawBody), tweet.EncodeRepostCallback("testuser", "123456789", 101)) {
+		t.Errorf("notice missing send again button: %s", notice.RawBody)
+	}
+	if n := len(mock.GetCalls("sendChatAction")); n != 1 {
+		t.Errorf("sendChatAction calls = %d, want 1 (only for the first share)", n)
+	}
+}
@@ -0,0 +1,76 @@
+package message
+
+import (
+	"context"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+
+	"twitterx-bot/internal/handlers/shared"
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/storage"
+	"twitterx-bot/internal/telegram/tweet"
+)
+
+// RepostHistory finds the latest share of a tweet in a chat.
+type RepostHistory interface {
+	Latest(ctx context.Context, chatID int64, tweetID string) (storage.HistoryEntry, bool, error)
+}
+
+// WithRepostDetection answers tweets shared in the chat within window with an
+// "already shared" notice instead of sending them again.
+func WithRepostDetection(history RepostHistory, window time.Duration) Option {
+	return func(h *Handler) {
+		h.reposts = history
+		h.repostWindow = window
+	}
+}
+
+// replyIfRecentlyShared sends the "already shared" notice when the tweet was sent to the chat
+// within the repost window and reports whether it did.
+func (h *Handler) replyIfRecentlyShared(ctx context.Context, b *gotgbot.Bot, ectx *ext.Context, log *logger.Logger, username, tweetID string) bool {
+	if h.reposts == nil || h.repostWindow <= 0 {
+		return false
+	}
+
+	chat := ectx.EffectiveChat
+	entry, ok, err := h.reposts.Latest(ctx, chat.Id, tweetID)
+	if err != nil {
+		log.Warn("repost lookup failed", "tweet_id", tweetID, "err", err)
+		return false
+	}
+	age := h.now().Sub(entry.SentAt)
+	if !ok || age > h.repostWindow {
+		return false
+	}
+
+	f := h.sender.Formatter
+	text := f.HTMLAlreadyShared(shared.MessageLink(chat, entry.MessageID), entry.Requester, age)
+	notice, err := b.SendMessage(chat.Id, text, &gotgbot.SendMessageOpts{
+		ParseMode: "HTML",
+		// Replying to the earlier tweet lets members jump to it even where messages cannot be linked
+		ReplyParameters: &gotgbot.ReplyParameters{
+			MessageId:                entry.MessageID,
+			AllowSendingWithoutReply: true,
+		},
+		ReplyMarkup:        tweet.BuildRepostKeyboard(username, tweetID, ectx.EffectiveMessage.MessageId),
+		LinkPreviewOptions: &gotgbot.LinkPreviewOptions{IsDisabled: true},
+	})
+	if err != nil {
+		// Better to send the tweet again than to leave the link unanswered
+		log.Warn("send already shared notice failed", "tweet_id", tweetID, "err", err)
+		return false
+	}
+
+	if h.replies != nil {
+		// The notice answers the link like a tweet would, so edits of the message update it too
+		reply := storage.Reply{ChatID: chat.Id, SourceMessageID: ectx.EffectiveMessage.MessageId, TweetID: tweetID, MessageID: notice.MessageId, SentAt: h.now()}
+		if err := h.replies.Add(ctx, reply); err != nil {
+			log.Warn("record already shared notice failed", "err", err)
+		}
+	}
+
+	log.Info("tweet already shared", "tweet_id", tweetID, "earlier_message_id", entry.MessageID, "age", age)
+	return true
+}
@@ -9,21 +9,46 @@ import (
 	"github.com/PaulSonOfLars/gotgbot/v2/ext"
 	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers"
 
+	"twitterx-bot/internal/chatsettings"
+	"twitterx-bot/internal/digest"
+	"twitterx-bot/internal/export"
+	"twitterx-bot/internal/follow"
+	"twitterx-bot/internal/handlers/admin"
+	"twitterx-bot/internal/handlers/bookmarks"
 	"twitterx-bot/internal/handlers/callback"
+	"twitterx-bot/internal/handlers/channel"
+	digesthandler "twitterx-bot/internal/handlers/digest"
+	exporthandler "twitterx-bot/internal/handlers/export"
+	followhandler "twitterx-bot/internal/handlers/follow"
 	"twitterx-bot/internal/handlers/inline"
 	"twitterx-bot/internal/handlers/message"
+	settingshandler "twitterx-bot/internal/handlers/settings"
+	"twitterx-bot/internal/handlers/shared"
 	"twitterx-bot/internal/handlers/start"
+	statshandler "twitterx-bot/internal/handlers/stats"
+	"twitterx-bot/internal/handlers/translate"
+	watchhandler "twitterx-bot/internal/handlers/watch"
 	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/stats"
+	"twitterx-bot/internal/storage"
 	"twitterx-bot/internal/telegram/tweet"
+	"twitterx-bot/internal/translation"
 	"twitterx-bot/internal/twitterurl"
 	"twitterx-bot/internal/twitterxapi"
 	inlineuc "twitterx-bot/internal/usecase/tweetsvc/inline"
+	"twitterx-bot/internal/watch"
 )
 
 const (
 	inlineQueryTimeout = 10 * time.Second
 	messageTimeout     = 10 * time.Second
 	chainTimeout       = 30 * time.Second
+	exportTimeout      = 2 * time.Minute
+
+	// accessGroup runs after chat tracking and ahead of the regular handlers.
+	accessGroup = -5
+	// throttleGroup runs after the access checks so blocked updates do not spend tokens.
+	throttleGroup = -4
 )
 
 // TweetFetcher fetches tweets by username and tweet ID.
@@ -31,29 +56,340 @@ type TweetFetcher interface {
 	GetTweet(ctx context.Context, username, tweetID string) (*twitterxapi.Tweet, error)
 }
 
-func Register(d *ext.Dispatcher, log *logger.Logger, api *twitterxapi.Client, telegraph tweet.ArticleCreator) {
+// Job is a background task started by the app once the bot is ready, such as polling followed accounts.
+type Job struct {
+	Name string
+	Run  func(ctx context.Context, b *gotgbot.Bot) error
+}
+
+// Option configures optional handler dependencies.
+type Option func(*options)
+
+type options struct {
+	translator translation.Translator
+	languages  tweet.LanguageDetector
+	settings   chatsettings.Store
+	storage    storage.Storage
+	admins     []int64
+	allowlist  bool
+	rateLimits RateLimits
+	sendQueue  tweet.SendQueue
+
+	chainArticles         tweet.ChainArticleCreator
+	chainArticleThreshold int
+
+	repostWindow time.Duration
+
+	timeline   follow.TimelineFetcher
+	followOpts []follow.Option
+
+	digestArticles digesthandler.ArticleCreator
+
+	exportImages export.ImageFetcher
+}
+
+// WithTranslator enables automatic translation of tweets using the given translator.
+func WithTranslator(translator translation.Translator) Option {
+	return func(o *options) {
+		o.translator = translator
+	}
+}
+
+// WithLanguageLabels labels tweets that are not translated with their detected language,
+// in chats that have a translation target.
+func WithLanguageLabels(detector tweet.LanguageDetector) Option {
+	return func(o *options) {
+		o.languages = detector
+	}
+}
+
+// WithChatSettings sets the store for per-chat settings.
+// Defaults to an in-memory store.
+func WithChatSettings(settings chatsettings.Store) Option {
+	return func(o *options) {
+		o.settings = settings
+	}
+}
+
+// WithStorage persists chat settings, known chats and sent tweet history.
+// An explicit WithChatSettings store takes precedence for settings.
+func WithStorage(store storage.Storage) Option {
+	return func(o *options) {
+		o.storage = store
+	}
+}
+
+// WithAdmins sets the bot owners allowed to run global commands such as "/stats global".
+func WithAdmins(ids ...int64) Option {
+	return func(o *options) {
+		o.admins = append(o.admins, ids...)
+	}
+}
+
+// WithAllowlist serves only chats allowed with /allow. Bot admins are always served.
+// It needs WithStorage for the allowlist.
+func WithAllowlist(enabled bool) Option {
+	return func(o *options) {
+		o.allowlist = enabled
+	}
+}
+
+// WithRateLimits drops tweet links, commands, callbacks and inline queries over the limits.
+func WithRateLimits(limits RateLimits) Option {
+	return func(o *options) {
+		o.rateLimits = limits
+	}
+}
+
+// WithSendQueue routes tweet messages through queue, which paces them and retries
+// after flood limits so chains are delivered completely.
+func WithSendQueue(queue tweet.SendQueue) Option {
+	return func(o *options) {
+		o.sendQueue = queue
+	}
+}
+
+// WithRepostDetection answers links to tweets already shared in the chat within window
+// with a link to the earlier message. It needs WithStorage for the share history.
+func WithRepostDetection(window time.Duration) Option {
+	return func(o *options) {
+		o.repostWindow = window
+	}
+}
+
+// WithFollowing enables /follow, which posts new tweets of followed accounts to the chat.
+// The accounts are polled from timeline by a background Job. It needs WithStorage.
+func WithFollowing(timeline follow.TimelineFetcher, opts ...follow.Option) Option {
+	return func(o *options) {
+		o.timeline = timeline
+		o.followOpts = append(o.followOpts, opts...)
+	}
+}
+
+// WithDigestArticles lets chats add a page with the full tweets to their /digest.
+// Digests themselves only need WithStorage.
+func WithDigestArticles(creator digesthandler.ArticleCreator) Option {
+	return func(o *options) {
+		o.digestArticles = creator
+	}
+}
+
+// WithExportImages embeds the photos of HTML exports, downloading them with images.
+// Without it, HTML exports link to the photos.
+func WithExportImages(images export.ImageFetcher) Option {
+	return func(o *options) {
+		o.exportImages = images
+	}
+}
+
+// WithChainArticles publishes chains with at least threshold tweets as a single article.
+func WithChainArticles(creator tweet.ChainArticleCreator, threshold int) Option {
+	return func(o *options) {
+		o.chainArticles = creator
+		o.chainArticleThreshold = threshold
+	}
+}
+
+// Register registers the handlers and returns the background jobs the app has to run.
+func Register(d *ext.Dispatcher, log *logger.Logger, api *twitterxapi.Client, telegraph tweet.ArticleCreator, opts ...Option) []Job {
 	if api == nil {
 		api = twitterxapi.NewClient("")
 	}
-	RegisterWithFetcher(d, log, api, telegraph)
+	return RegisterWithFetcher(d, log, api, telegraph, opts...)
 }
 
 // RegisterWithFetcher registers handlers using a custom TweetFetcher implementation.
 // This is useful for testing with mock implementations.
-func RegisterWithFetcher(d *ext.Dispatcher, log *logger.Logger, fetcher TweetFetcher, telegraph tweet.ArticleCreator) {
+func RegisterWithFetcher(d *ext.Dispatcher, log *logger.Logger, fetcher TweetFetcher, telegraph tweet.ArticleCreator, opts ...Option) []Job {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.settings == nil && o.storage != nil {
+		o.settings = o.storage.Settings()
+	}
+	if o.settings == nil {
+		o.settings = chatsettings.NewMemoryStore()
+	}
+
+	sender := tweet.Sender{
+		Telegraph:  telegraph,
+		Translator: o.translator,
+		Settings:   o.settings,
+		Languages:  o.languages,
+
+		ChainArticles:         o.chainArticles,
+		ChainArticleThreshold: o.chainArticleThreshold,
+
+		Queue: o.sendQueue,
+
+		ExportButton: true,
+	}
+	var recorder *stats.Recorder
+	var watcher *watch.Notifier
+	if o.storage != nil {
+		watcher = watch.NewNotifier(log, o.storage.Watches(), o.storage.Chats())
+		sender.History = historyRecorders{
+			historyRecorder{history: o.storage.History(), now: time.Now},
+			replyRecorder{replies: o.storage.Replies(), now: time.Now},
+			watcher,
+		}
+		sender.SaveButton = true
+		d.AddHandlerToGroup(chatTracker{log: log, chats: o.storage.Chats(), now: time.Now}, trackingGroup)
+		recorder = stats.NewRecorder(log, o.storage.Events())
+	}
+	admins := shared.NewBotAdmins(o.admins...)
+
+	d.AddHandlerToGroup(newThrottle(log, o.rateLimits, admins), throttleGroup)
+
+	// Owner commands and the access middleware
+	if o.storage != nil {
+		d.AddHandlerToGroup(admin.NewGuard(log, o.storage.Access(), admins, o.allowlist), accessGroup)
+
+		adminHandler := admin.New(log, o.storage.Chats(), o.storage.Access(), o.allowlist)
+		d.AddHandler(handlers.NewCommand("allow", adminHandler.Allow))
+		d.AddHandler(handlers.NewCommand("deny", adminHandler.Deny))
+		d.AddHandler(handlers.NewCommand("ban", adminHandler.Ban))
+		d.AddHandler(handlers.NewCommand("unban", adminHandler.Unban))
+		d.AddHandler(handlers.NewCommand("chats", adminHandler.Chats))
+		d.AddHandler(handlers.NewCommand("broadcast", adminHandler.Broadcast))
+	}
+
 	// Start and help commands
 	d.AddHandler(handlers.NewCommand("start", start.Handler))
 	d.AddHandler(handlers.NewCommand("help", start.Handler))
 
+	// Chat settings commands
+	translateHandler := translate.New(log, o.settings)
+	d.AddHandler(handlers.NewCommand("translate", translateHandler.Handle).SetAllowChannel(true))
+	settingsHandler := settingshandler.New(log, o.settings)
+	d.AddHandler(handlers.NewCommand("settings", settingsHandler.Command).SetAllowChannel(true))
+	d.AddHandler(handlers.NewCallback(func(cq *gotgbot.CallbackQuery) bool {
+		return strings.HasPrefix(cq.Data, settingshandler.CallbackPrefix)
+	}, settingsHandler.Callback))
+
+	// Thread exports as documents
+	var exportOpts []export.Option
+	if o.exportImages != nil {
+		exportOpts = append(exportOpts, export.WithImages(o.exportImages))
+	}
+	exportHandler := exporthandler.New(log, export.New(fetcher, exportOpts...), exportTimeout)
+	d.AddHandler(handlers.NewCommand("export", exportHandler.Command).SetAllowChannel(true))
+	d.AddHandler(handlers.NewCallback(func(cq *gotgbot.CallbackQuery) bool {
+		return strings.HasPrefix(cq.Data, tweet.ExportCallbackPrefix)
+	}, exportHandler.Callback))
+
+	if o.storage != nil {
+		statsHandler := statshandler.New(log, o.storage.Events(), o.storage.Chats(), admins)
+		d.AddHandler(handlers.NewCommand("stats", statsHandler.Command))
+	}
+
+	var jobs []Job
+
+	// Watch rules alerting users in private
+	if watcher != nil {
+		watchHandler := watchhandler.New(log, o.storage.Watches())
+		d.AddHandler(handlers.NewCommand("watch", watchHandler.Watch))
+		d.AddHandler(handlers.NewCommand("unwatch", watchHandler.Unwatch))
+
+		jobs = append(jobs, Job{
+			Name: "watch_notifier",
+			Run: func(ctx context.Context, b *gotgbot.Bot) error {
+				s := sender
+				s.Bot = b
+				s.Log = log
+				s.History = nil
+				return watcher.Run(ctx, watchhandler.Alerter{Bot: b, Sender: s})
+			},
+		})
+	}
+
+	// Followed accounts, also managed from channels
+	if o.storage != nil && o.timeline != nil {
+		followHandler := followhandler.New(log, o.storage.Follows(), o.timeline)
+		d.AddHandler(handlers.NewCommand("follow", followHandler.Follow).SetAllowChannel(true))
+		d.AddHandler(handlers.NewCommand("unfollow", followHandler.Unfollow).SetAllowChannel(true))
+		d.AddHandler(handlers.NewCommand("following", followHandler.Following).SetAllowChannel(true))
+
+		jobs = append(jobs, Job{
+			Name: "follow_poller",
+			Run: func(ctx context.Context, b *gotgbot.Bot) error {
+				s := sender
+				s.Bot = b
+				s.Log = log
+				publisher := followhandler.Publisher{Sender: s}
+				policy := follow.WithChatPolicy(admin.NewChatPolicy(o.storage.Chats(), o.storage.Access(), o.allowlist))
+				return follow.NewPoller(log, o.storage.Follows(), o.timeline, publisher, append([]follow.Option{policy}, o.followOpts...)...).Run(ctx)
+			},
+		})
+	}
+
+	// Scheduled digests of the shared tweets
+	if o.storage != nil {
+		digestHandler := digesthandler.New(log, o.storage, fetcher, o.digestArticles)
+		d.AddHandler(handlers.NewCommand("digest", digestHandler.Handle).SetAllowChannel(true))
+
+		jobs = append(jobs, Job{
+			Name: "digest_scheduler",
+			Run: func(ctx context.Context, b *gotgbot.Bot) error {
+				return digest.NewScheduler(log, o.storage.Digests(), o.storage.History(), fetcher, digestHandler.Publisher(b)).Run(ctx)
+			},
+		})
+	}
+
+	// Stored data that is no longer needed
+	if o.storage != nil {
+		jobs = append(jobs, Job{
+			Name: "storage_pruner",
+			Run: func(ctx context.Context, _ *gotgbot.Bot) error {
+				return storagePruner{
+					log:         log,
+					replies:     o.storage.Replies(),
+					events:      o.storage.Events(),
+					history:     o.storage.History(),
+					keepHistory: max(historyRetention, o.repostWindow),
+					now:         time.Now,
+				}.Run(ctx)
+			},
+		})
+	}
+
+	// Saved tweets of users
+	inlineOpts := []inline.Option{inline.WithStats(recorder)}
+	if o.storage != nil {
+		bookmarksHandler := bookmarks.New(log, o.storage.Bookmarks(), fetcher, messageTimeout, sender)
+		d.AddHandler(handlers.NewCommand("saved", bookmarksHandler.Saved))
+		d.AddHandler(handlers.NewCallback(func(cq *gotgbot.CallbackQuery) bool {
+			return strings.HasPrefix(cq.Data, tweet.SaveCallbackPrefix)
+		}, bookmarksHandler.Save))
+		d.AddHandler(handlers.NewCallback(func(cq *gotgbot.CallbackQuery) bool {
+			return strings.HasPrefix(cq.Data, bookmarks.CallbackPrefix)
+		}, bookmarksHandler.Callback))
+		// Bans and the allowlist of the access guard differ between users
+		inlineOpts = append(inlineOpts, inline.WithBookmarks(o.storage.Bookmarks()), inline.WithPersonalAnswers())
+	}
+
 	// Inline query handler
 	inlineUC := inlineuc.New(fetcher)
-	inlineHandler := inline.New(log, inlineUC, inlineQueryTimeout)
+	inlineUC.SaveButton = sender.SaveButton
+	inlineUC.ChainArticles = o.chainArticles
+	inlineHandler := inline.New(log, inlineUC, inlineQueryTimeout, inlineOpts...)
 	d.AddHandler(handlers.NewInlineQuery(func(iq *gotgbot.InlineQuery) bool {
 		return true
 	}, inlineHandler.Handle))
+	d.AddHandler(handlers.NewChosenInlineResult(func(cir *gotgbot.ChosenInlineResult) bool {
+		return true
+	}, inlineHandler.Chosen))
 
 	// Message handler for Twitter URLs
-	messageHandler := message.New(log, fetcher, messageTimeout, telegraph)
+	messageOpts := []message.Option{message.WithStats(recorder)}
+	if o.storage != nil && o.repostWindow > 0 {
+		messageOpts = append(messageOpts, message.WithRepostDetection(o.storage.History(), o.repostWindow))
+	}
+	if o.storage != nil {
+		messageOpts = append(messageOpts, message.WithEdits(o.storage.Replies()))
+	}
+	messageHandler := message.New(log, fetcher, messageTimeout, sender, messageOpts...)
 	d.AddHandler(handlers.NewMessage(func(msg *gotgbot.Message) bool {
 		if msg.Text == "" {
 			return false
@@ -61,13 +397,27 @@ func RegisterWithFetcher(d *ext.Dispatcher, log *logger.Logger, fetcher TweetFet
 		_, _, ok := twitterurl.ParseTweetURL(msg.Text)
 		return ok
 	}, messageHandler.Handle))
+	if o.storage != nil {
+		d.AddHandler(handlers.NewMessage(func(msg *gotgbot.Message) bool {
+			return msg.EditDate != 0
+		}, messageHandler.HandleEdit).SetAllowEdited(true))
+	}
+
+	// Bare tweet links posted in channels
+	channelHandler := channel.New(log, fetcher, messageTimeout, sender, recorder)
+	d.AddHandler(handlers.NewMessage(channel.IsBareLink, channelHandler.Handle).SetAllowChannel(true))
 
 	// Callback handlers
-	callbackHandlers := callback.New(log, fetcher, chainTimeout, telegraph)
+	callbackHandlers := callback.New(log, fetcher, chainTimeout, sender, callback.WithStats(recorder))
 	d.AddHandler(handlers.NewCallback(func(cq *gotgbot.CallbackQuery) bool {
 		return strings.HasPrefix(cq.Data, tweet.ChainCallbackPrefix)
 	}, callbackHandlers.Chain))
 	d.AddHandler(handlers.NewCallback(func(cq *gotgbot.CallbackQuery) bool {
 		return strings.HasPrefix(cq.Data, tweet.DeleteCallbackPrefix)
 	}, callbackHandlers.Delete))
+	d.AddHandler(handlers.NewCallback(func(cq *gotgbot.CallbackQuery) bool {
+		return strings.HasPrefix(cq.Data, tweet.RepostCallbackPrefix)
+	}, callbackHandlers.Repost))
+
+	return jobs
 }
@@ -0,0 +1,168 @@
+// Package settings implements the /settings command and its inline-keyboard menu.
+package settings
+
+import (
+	"context"
+	"strings"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+
+	"twitterx-bot/internal/chatsettings"
+	"twitterx-bot/internal/handlers/shared"
+	"twitterx-bot/internal/logger"
+)
+
+// Handler shows and updates per-chat settings.
+type Handler struct {
+	log      *logger.Logger
+	settings chatsettings.Store
+}
+
+// New creates a /settings handler backed by the given settings store.
+func New(log *logger.Logger, settings chatsettings.Store) *Handler {
+	return &Handler{log: log, settings: settings}
+}
+
+// Command replies to /settings with the settings menu. In groups only admins may open it.
+func (h *Handler) Command(b *gotgbot.Bot, ctx *ext.Context) error {
+	log := h.log.With("component", "settings")
+	if ctx.EffectiveChat != nil {
+		log = log.With("chat_id", ctx.EffectiveChat.Id)
+	}
+	if ctx.EffectiveUser != nil {
+		log = log.With("user_id", ctx.EffectiveUser.Id, "username", ctx.EffectiveUser.Username)
+	}
+
+	msg := ctx.EffectiveMessage
+	allowed := shared.IsAnonymousAdmin(msg)
+	if !allowed && ctx.EffectiveUser != nil {
+		var err error
+		allowed, err = shared.CanManageChat(b, ctx.EffectiveChat, ctx.EffectiveUser.Id)
+		if err != nil {
+			log.Error("check chat admin failed", "err", err)
+			return h.reply(b, msg, "Cannot check your permissions, try again later.")
+		}
+	}
+	if !allowed {
+		log.Info("settings denied: not an admin")
+		return h.reply(b, msg, "Only chat admins can change settings.")
+	}
+
+	settings, err := h.settings.Get(context.Background(), ctx.EffectiveChat.Id)
+	if err != nil {
+		log.Error("load chat settings failed", "err", err)
+		return h.reply(b, msg, "Cannot load chat settings, try again later.")
+	}
+
+	text, markup := mainMenu(settings, ctx.EffectiveChat.Type == gotgbot.ChatTypeChannel)
+	_, err = msg.Reply(b, text, &gotgbot.SendMessageOpts{
+		ParseMode:   "HTML",
+		ReplyMarkup: &markup,
+	})
+	return err
+}
+
+// Callback handles presses in the settings menu: navigation, picking values and toggles.
+func (h *Handler) Callback(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.CallbackQuery
+	log := h.log.With("component", "settings", "callback_id", cb.Id, "user_id", cb.From.Id, "username", cb.From.Username)
+
+	chat := ctx.EffectiveChat
+	if chat == nil || cb.Message == nil {
+		return h.answer(b, cb, "This menu is no longer available", false)
+	}
+	log = log.With("chat_id", chat.Id)
+
+	allowed, err := shared.CanManageChat(b, chat, cb.From.Id)
+	if err != nil {
+		log.Error("check chat admin failed", "err", err)
+		return h.answer(b, cb, "Cannot check your permissions, try again later", false)
+	}
+	if !allowed {
+		return h.answer(b, cb, "Only chat admins can change settings", true)
+	}
+
+	action, arg, _ := strings.Cut(strings.TrimPrefix(cb.Data, CallbackPrefix), ":")
+	if action == "close" {
+		if _, err := cb.Message.Delete(b, nil); err != nil {
+			log.Debug("delete settings menu failed", "err", err)
+		}
+		return h.answer(b, cb, "", false)
+	}
+
+	reqCtx := context.Background()
+	settings, err := h.settings.Get(reqCtx, chat.Id)
+	if err != nil {
+		log.Error("load chat settings failed", "err", err)
+		return h.answer(b, cb, "Cannot load chat settings, try again later", false)
+	}
+
+	var text string
+	var markup gotgbot.InlineKeyboardMarkup
+	changed := false
+	channel := chat.Type == gotgbot.ChatTypeChannel
+
+	switch action {
+	case "menu":
+		text, markup = mainMenu(settings, channel)
+	case "open":
+		sec, ok := findSection(arg)
+		if !ok {
+			return h.answer(b, cb, "Invalid callback data", false)
+		}
+		text, markup = sectionMenu(sec, settings)
+	case "pick":
+		key, value, _ := strings.Cut(arg, ":")
+		sec, ok := findSection(key)
+		if !ok {
+			return h.answer(b, cb, "Invalid callback data", false)
+		}
+		sec.set(&settings, value)
+		changed = true
+		text, markup = mainMenu(settings, channel)
+	case "toggle":
+		t, ok := findToggle(arg)
+		if !ok {
+			return h.answer(b, cb, "Invalid callback data", false)
+		}
+		t.set(&settings, !t.get(settings))
+		changed = true
+		text, markup = mainMenu(settings, channel)
+	default:
+		log.Error("decode settings callback failed", "data", cb.Data)
+		return h.answer(b, cb, "Invalid callback data", false)
+	}
+
+	if changed {
+		if err := h.settings.Save(reqCtx, chat.Id, settings); err != nil {
+			log.Error("save chat settings failed", "err", err)
+			return h.answer(b, cb, "Cannot save chat settings, try again later", false)
+		}
+		log.Info("chat settings updated", "data", cb.Data)
+	}
+
+	if _, _, err := b.EditMessageText(text, &gotgbot.EditMessageTextOpts{
+		ChatId:      chat.Id,
+		MessageId:   cb.Message.GetMessageId(),
+		ParseMode:   "HTML",
+		ReplyMarkup: markup,
+	}); err != nil {
+		log.Debug("edit settings menu failed", "err", err)
+	}
+
+	if changed {
+		return h.answer(b, cb, "Saved", false)
+	}
+	return h.answer(b, cb, "", false)
+}
+
+func (h *Handler) reply(b *gotgbot.Bot, msg *gotgbot.Message, text string) error {
+	_, err := msg.Reply(b, text, &gotgbot.SendMessageOpts{ParseMode: "HTML"})
+	return err
+}
+
+func (h *Handler) answer(b *gotgbot.Bot, cb *gotgbot.CallbackQuery, text string, alert bool) error {
+	_, err := cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: text, ShowAlert: alert})
+	return err
+}
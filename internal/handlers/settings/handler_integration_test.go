@@ -0,0 +1,203 @@
+package settings_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+
+	"twitterx-bot/internal/chatsettings"
+	"twitterx-bot/internal/handlers"
+	"twitterx-bot/internal/handlers/testutil"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+func commandUpdate(chatID int64, chatType string) *gotgbot.Update {
+	return &gotgbot.Update{
+		UpdateId: 1,
+		Message: &gotgbot.Message{
+			MessageId: 10,
+			Text:      "/settings",
+			Chat:      gotgbot.Chat{Id: chatID, Type: chatType},
+			From:      &gotgbot.User{Id: 5005, FirstName: "Erin"},
+			Entities:  []gotgbot.MessageEntity{{Type: "bot_command", Offset: 0, Length: 9}},
+		},
+	}
+}
+
+func callbackUpdate(chatID int64, chatType, data string) *gotgbot.Update {
+	return &gotgbot.Update{
+		UpdateId: 2,
+		CallbackQuery: &gotgbot.CallbackQuery{
+			Id:   "cb-settings",
+			Data: data,
+			From: gotgbot.User{Id: 5005, FirstName: "Erin"},
+			Message: &gotgbot.Message{
+				MessageId: 11,
+				Date:      1000000,
+				Chat:      gotgbot.Chat{Id: chatID, Type: chatType},
+			},
+		},
+	}
+}
+
+func TestIntegration_Settings_OpensMenuInPrivateChat(t *testing.T) {
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcher(t, nil)
+
+	if err := dispatcher.ProcessUpdate(bot, commandUpdate(5005, "private"), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	calls := mock.GetCalls("sendMessage")
+	if len(calls) != 1 {
+		t.Fatalf("sendMessage calls = %d, want 1", len(calls))
+	}
+	text, _ := calls[0].JSONString("text")
+	if !strings.Contains(text, "Chat settings") {
+		t.Errorf("text = %q, want settings overview", text)
+	}
+	if !strings.Contains(string(calls[0].RawBody), "set:toggle:delete") {
+		t.Errorf("menu keyboard missing toggles: %s", calls[0].RawBody)
+	}
+	if len(mock.GetCalls("getChatMember")) != 0 {
+		t.Errorf("getChatMember called for a private chat")
+	}
+}
+
+func TestIntegration_Settings_DeniedForGroupMembers(t *testing.T) {
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcher(t, nil)
+	if err := mock.SetResponse("getChatMember", map[string]any{
+		"status": "member",
+		"user":   map[string]any{"id": 5005, "is_bot": false, "first_name": "Erin"},
+	}); err != nil {
+		t.Fatalf("SetResponse() error = %v", err)
+	}
+
+	if err := dispatcher.ProcessUpdate(bot, commandUpdate(-100, "supergroup"), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	calls := mock.GetCalls("sendMessage")
+	if len(calls) != 1 {
+		t.Fatalf("sendMessage calls = %d, want 1", len(calls))
+	}
+	if text, _ := calls[0].JSONString("text"); !strings.Contains(text, "Only chat admins") {
+		t.Errorf("text = %q, want admin-only notice", text)
+	}
+}
+
+func TestIntegration_Settings_AdminChangesSettings(t *testing.T) {
+	settings := chatsettings.NewMemoryStore()
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, nil, nil, handlers.WithChatSettings(settings))
+	if err := mock.SetResponse("getChatMember", map[string]any{
+		"status": "administrator",
+		"user":   map[string]any{"id": 5005, "is_bot": false, "first_name": "Erin"},
+	}); err != nil {
+		t.Fatalf("SetResponse() error = %v", err)
+	}
+
+	const chatID = int64(-100)
+	for _, data := range []string{"set:open:media", "set:pick:media:text", "set:toggle:attribution"} {
+		if err := dispatcher.ProcessUpdate(bot, callbackUpdate(chatID, "supergroup", data), nil); err != nil {
+			t.Fatalf("ProcessUpdate(%s) error = %v", data, err)
+		}
+	}
+
+	got, err := settings.Get(context.Background(), chatID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.MediaMode != chatsettings.MediaText || !got.HideAttribution {
+		t.Fatalf("settings = %+v, want text media mode and hidden attribution", got)
+	}
+
+	edits := mock.GetCalls("editMessageText")
+	if len(edits) != 3 {
+		t.Fatalf("editMessageText calls = %d, want 3", len(edits))
+	}
+	if text, _ := edits[0].JSONString("text"); !strings.Contains(text, "Media") {
+		t.Errorf("submenu text = %q, want media section", text)
+	}
+	if text, _ := edits[2].JSONString("text"); !strings.Contains(text, "Text only") {
+		t.Errorf("menu text = %q, want updated media mode", text)
+	}
+}
+
+func TestIntegration_Settings_TextOnlyModeSkipsMedia(t *testing.T) {
+	fakeAPI := &testutil.FakeTweetAPI{
+		Tweets: map[string]*twitterxapi.Tweet{
+			"alice/1": {
+				ID:     "1",
+				URL:    "https://x.com/alice/status/1",
+				Text:   "Look at this",
+				Author: twitterxapi.Author{Name: "Alice", ScreenName: "alice"},
+				Media:  &twitterxapi.Media{Photos: []twitterxapi.Photo{{URL: "https://img/1.jpg"}}},
+			},
+		},
+	}
+	settings := chatsettings.NewMemoryStore()
+	const chatID = int64(-200)
+	_ = settings.Save(context.Background(), chatID, chatsettings.Settings{MediaMode: chatsettings.MediaText, DeleteOriginal: true})
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, fakeAPI, nil, handlers.WithChatSettings(settings))
+
+	update := &gotgbot.Update{
+		UpdateId: 3,
+		Message: &gotgbot.Message{
+			MessageId: 30,
+			Text:      "https://x.com/alice/status/1",
+			Chat:      gotgbot.Chat{Id: chatID, Type: "group"},
+			From:      &gotgbot.User{Id: 5005, FirstName: "Erin"},
+		},
+	}
+	if err := dispatcher.ProcessUpdate(bot, update, nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	if n := len(mock.GetCalls("sendPhoto")); n != 0 {
+		t.Errorf("sendPhoto calls = %d, want 0 in text-only mode", n)
+	}
+	if n := len(mock.GetCalls("sendMessage")); n != 1 {
+		t.Fatalf("sendMessage calls = %d, want 1", n)
+	}
+	deletes := mock.GetCalls("deleteMessage")
+	if len(deletes) != 1 {
+		t.Fatalf("deleteMessage calls = %d, want 1", len(deletes))
+	}
+	if id, _ := deletes[0].JSONInt64("message_id"); id != 30 {
+		t.Errorf("deleted message_id = %d, want 30", id)
+	}
+}
+
+func TestIntegration_Settings_ChannelOnlyOptions(t *testing.T) {
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcher(t, nil)
+
+	channel := gotgbot.Chat{Id: -1001234, Type: "channel", Title: "News"}
+	update := &gotgbot.Update{
+		UpdateId: 1,
+		ChannelPost: &gotgbot.Message{
+			MessageId:  10,
+			Text:       "/settings",
+			Chat:       channel,
+			SenderChat: &channel,
+			Entities:   []gotgbot.MessageEntity{{Type: "bot_command", Offset: 0, Length: 9}},
+		},
+	}
+	if err := dispatcher.ProcessUpdate(bot, update, nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+	if err := dispatcher.ProcessUpdate(bot, commandUpdate(5005, "private"), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	calls := mock.GetCalls("sendMessage")
+	if len(calls) != 2 {
+		t.Fatalf("sendMessage calls = %d, want 2", len(calls))
+	}
+	if body := string(calls[0].RawBody); !strings.Contains(body, "set:open:signature") || !strings.Contains(body, "set:toggle:channel") {
+		t.Errorf("channel menu missing channel options: %s", body)
+	}
+	if body := string(calls[1].RawBody); strings.Contains(body, "set:open:signature") {
+		t.Errorf("private menu shows channel options: %s", body)
+	}
+}
@@ -0,0 +1,236 @@
+package settings
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+
+	"twitterx-bot/internal/chatsettings"
+	"twitterx-bot/internal/translation"
+)
+
+// CallbackPrefix marks callback data of the settings menu.
+// Format: set:menu, set:open:<section>, set:pick:<section>:<value>, set:toggle:<toggle>, set:close
+const CallbackPrefix = "set:"
+
+// choice is one of the mutually exclusive values of a section.
+type choice struct {
+	value string
+	label string
+}
+
+// section is a submenu picking one value of a setting.
+type section struct {
+	key     string
+	title   string
+	choices []choice
+	get     func(chatsettings.Settings) string
+	set     func(*chatsettings.Settings, string)
+	// channelOnly hides the section outside channels.
+	channelOnly bool
+}
+
+// toggle is an on/off setting switched right from the main menu.
+type toggle struct {
+	key   string
+	title string
+	get   func(chatsettings.Settings) bool
+	set   func(*chatsettings.Settings, bool)
+	// channelOnly hides the toggle outside channels.
+	channelOnly bool
+}
+
+// translateTargets are the languages offered in the translation submenu;
+// any other code can still be set with /translate.
+var translateTargets = []string{"en", "uk", "de", "fr", "es", "it", "pt", "pl", "ja"}
+
+var sections = []section{
+	{
+		key:   "media",
+		title: "🖼 Media",
+		choices: []choice{
+			{string(chatsettings.MediaFull), "Photos and videos"},
+			{string(chatsettings.MediaPreview), "Link preview"},
+			{string(chatsettings.MediaText), "Text only"},
+		},
+		get: func(s chatsettings.Settings) string { return string(s.MediaMode) },
+		set: func(s *chatsettings.Settings, v string) { s.MediaMode = chatsettings.MediaMode(v) },
+	},
+	{
+		key:   "sensitive",
+		title: "🔞 Sensitive media",
+		choices: []choice{
+			{string(chatsettings.SensitiveShow), "Show"},
+			{string(chatsettings.SensitiveSpoiler), "Behind a spoiler"},
+			{string(chatsettings.SensitiveHide), "Hide media"},
+		},
+		get: func(s chatsettings.Settings) string { return string(s.SensitiveMedia) },
+		set: func(s *chatsettings.Settings, v string) { s.SensitiveMedia = chatsettings.SensitivePolicy(v) },
+	},
+	{
+		key:   "chain",
+		title: "🧵 Reply chains",
+		choices: []choice{
+			{string(chatsettings.ChainButton), "\"Send full chain\" button"},
+			{string(chatsettings.ChainAuto), "Send automatically"},
+			{string(chatsettings.ChainOff), "Off"},
+		},
+		get: func(s chatsettings.Settings) string { return string(s.ChainMode) },
+		set: func(s *chatsettings.Settings, v string) { s.ChainMode = chatsettings.ChainMode(v) },
+	},
+	{
+		key:     "translate",
+		title:   "🌐 Translation",
+		choices: translateChoices(),
+		get:     func(s chatsettings.Settings) string { return s.TranslateTo },
+		set:     func(s *chatsettings.Settings, v string) { s.TranslateTo = v },
+	},
+	{
+		key:   "signature",
+		title: "✍️ Signature",
+		choices: []choice{
+			{string(chatsettings.SignatureOff), "None"},
+			{string(chatsettings.SignatureTitle), "Channel name"},
+			{string(chatsettings.SignatureLink), "Channel link"},
+		},
+		get:         func(s chatsettings.Settings) string { return string(s.Signature) },
+		set:         func(s *chatsettings.Settings, v string) { s.Signature = chatsettings.SignatureMode(v) },
+		channelOnly: true,
+	},
+}
+
+var toggles = []toggle{
+	{
+		key:   "attribution",
+		title: "👤 Show who shared",
+		get:   func(s chatsettings.Settings) bool { return !s.HideAttribution },
+		set:   func(s *chatsettings.Settings, on bool) { s.HideAttribution = !on },
+	},
+	{
+		key:   "articles",
+		title: "📰 Articles for long tweets",
+		get:   func(s chatsettings.Settings) bool { return !s.DisableArticles },
+		set:   func(s *chatsettings.Settings, on bool) { s.DisableArticles = !on },
+	},
+	{
+		key:   "delete",
+		title: "🗑 Delete original message",
+		get:   func(s chatsettings.Settings) bool { return s.DeleteOriginal },
+		set:   func(s *chatsettings.Settings, on bool) { s.DeleteOriginal = on },
+	},
+	{
+		key:         "channel",
+		title:       "📣 Reformat posted links",
+		get:         func(s chatsettings.Settings) bool { return !s.DisableChannelMode },
+		set:         func(s *chatsettings.Settings, on bool) { s.DisableChannelMode = !on },
+		channelOnly: true,
+	},
+}
+
+func translateChoices() []choice {
+	choices := []choice{{"", "Off"}}
+	for _, iso := range translateTargets {
+		choices = append(choices, choice{iso, translation.LanguageFromISO(iso).Name})
+	}
+	return choices
+}
+
+func findSection(key string) (section, bool) {
+	for _, sec := range sections {
+		if sec.key == key {
+			return sec, true
+		}
+	}
+	return section{}, false
+}
+
+func findToggle(key string) (toggle, bool) {
+	for _, t := range toggles {
+		if t.key == key {
+			return t, true
+		}
+	}
+	return toggle{}, false
+}
+
+// label returns the label of the current value, or the raw value if it is not offered in the menu.
+func (sec section) label(settings chatsettings.Settings) string {
+	current := sec.get(settings)
+	for _, c := range sec.choices {
+		if c.value == current {
+			return c.label
+		}
+	}
+	return current
+}
+
+// mainMenu renders the settings overview with a button per section and toggle.
+// Channel-only settings are shown in channels only.
+func mainMenu(settings chatsettings.Settings, channel bool) (string, gotgbot.InlineKeyboardMarkup) {
+	var sb strings.Builder
+	sb.WriteString("⚙️ <b>Chat settings</b>\n")
+	for _, sec := range sections {
+		if sec.channelOnly && !channel {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\n%s: <b>%s</b>", sec.title, html.EscapeString(sec.label(settings))))
+	}
+	for _, t := range toggles {
+		if t.channelOnly && !channel {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\n%s: <b>%s</b>", t.title, onOff(t.get(settings))))
+	}
+
+	var rows [][]gotgbot.InlineKeyboardButton
+	for _, sec := range sections {
+		if sec.channelOnly && !channel {
+			continue
+		}
+		rows = append(rows, []gotgbot.InlineKeyboardButton{{
+			Text:         sec.title + " · " + sec.label(settings),
+			CallbackData: CallbackPrefix + "open:" + sec.key,
+		}})
+	}
+	for _, t := range toggles {
+		if t.channelOnly && !channel {
+			continue
+		}
+		rows = append(rows, []gotgbot.InlineKeyboardButton{{
+			Text:         t.title + " · " + onOff(t.get(settings)),
+			CallbackData: CallbackPrefix + "toggle:" + t.key,
+		}})
+	}
+	rows = append(rows, []gotgbot.InlineKeyboardButton{{Text: "✖️ Close", CallbackData: CallbackPrefix + "close"}})
+
+	return sb.String(), gotgbot.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// sectionMenu renders the choices of a section, marking the current one.
+func sectionMenu(sec section, settings chatsettings.Settings) (string, gotgbot.InlineKeyboardMarkup) {
+	current := sec.get(settings)
+
+	var rows [][]gotgbot.InlineKeyboardButton
+	for _, c := range sec.choices {
+		text := c.label
+		if c.value == current {
+			text = "✅ " + text
+		}
+		rows = append(rows, []gotgbot.InlineKeyboardButton{{
+			Text:         text,
+			CallbackData: CallbackPrefix + "pick:" + sec.key + ":" + c.value,
+		}})
+	}
+	rows = append(rows, []gotgbot.InlineKeyboardButton{{Text: "« Back", CallbackData: CallbackPrefix + "menu"}})
+
+	return "⚙️ <b>" + html.EscapeString(sec.title) + "</b>", gotgbot.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+func onOff(on bool) string {
+	if on {
+		return "On"
+	}
+	return "Off"
+}
@@ -0,0 +1,44 @@
+package shared
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+// CanManageChat reports whether userID may change the bot settings of chat.
+// Everyone manages their private chat; in groups only administrators do.
+func CanManageChat(b *gotgbot.Bot, chat *gotgbot.Chat, userID int64) (bool, error) {
+	if chat == nil {
+		return false, nil
+	}
+	if chat.Type == gotgbot.ChatTypePrivate {
+		return true, nil
+	}
+
+	member, err := b.GetChatMember(chat.Id, userID, nil)
+	if err != nil {
+		return false, err
+	}
+	switch member.GetStatus() {
+	case "creator", "administrator":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// IsAnonymousAdmin reports whether msg was sent by an anonymous administrator on behalf of its chat.
+func IsAnonymousAdmin(msg *gotgbot.Message) bool {
+	return msg != nil && msg.SenderChat != nil && msg.SenderChat.Id == msg.Chat.Id
+}
+
+// IsBotRemoved reports whether the chat is gone for the bot: blocked, kicked or deleted.
+func IsBotRemoved(err error) bool {
+	var tgErr *gotgbot.TelegramError
+	if !errors.As(err, &tgErr) {
+		return false
+	}
+	return tgErr.Code == 403 || (tgErr.Code == 400 && strings.Contains(tgErr.Description, "chat not found"))
+}
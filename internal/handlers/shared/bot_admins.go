@@ -0,0 +1,19 @@
+package shared
+
+// BotAdmins is the set of bot owners (ADMIN_IDS) allowed to run global commands.
+type BotAdmins map[int64]struct{}
+
+// NewBotAdmins creates the admin set from user IDs.
+func NewBotAdmins(ids ...int64) BotAdmins {
+	admins := make(BotAdmins, len(ids))
+	for _, id := range ids {
+		admins[id] = struct{}{}
+	}
+	return admins
+}
+
+// Contains reports whether userID is a bot admin.
+func (a BotAdmins) Contains(userID int64) bool {
+	_, ok := a[userID]
+	return ok
+}
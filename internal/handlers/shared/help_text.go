@@ -5,14 +5,26 @@ const HelpText = `<b>TwitterX Bot</b> 🐦
 <b>Direct Messages &amp; Groups</b>
 Just send any Twitter/X link and I'll fetch the content for you.
 
+<b>Channels</b>
+Make me an admin and post a bare tweet link — I'll turn it into the full tweet. Signature and attribution are in /settings.
+
 <b>Inline Mode</b>
 Use me in any chat by typing:
 <code>@twitter_x_bot &lt;link&gt;</code>
 
 Example:
 <code>@twitter_x_bot https://x.com/user/status/123</code>
+Leave the link out to pick one of your saved tweets.
 
 <b>Commands</b>
 /start — Start the bot
 /help — Show this message
+/translate &lt;lang&gt; — Translate tweets in this chat (<code>off</code> to disable)
+/settings — Configure how tweets are sent in this chat
+/follow @user — Post new tweets of an account here (/unfollow, /following)
+/watch &lt;keyword|@author&gt; — Get a private message when matching tweets are shared (/unwatch)
+/digest daily|weekly HH:MM — Post a summary of the most shared tweets here
+/export &lt;link&gt; [md|html|json] — Get a tweet with its thread as a document
+/saved [words] — Browse the tweets you saved with ⭐ Save
+/stats — Show who shares what in this chat (<code>csv</code> to export)
 `
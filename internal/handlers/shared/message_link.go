@@ -0,0 +1,26 @@
+package shared
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+// MessageLink returns a t.me link to a message, or "" for chats whose messages cannot be linked
+// (private chats and basic groups).
+func MessageLink(chat *gotgbot.Chat, messageID int64) string {
+	if chat == nil || messageID == 0 {
+		return ""
+	}
+	if chat.Username != "" {
+		return fmt.Sprintf("https://t.me/%s/%d", chat.Username, messageID)
+	}
+	// Supergroups and channels have IDs of the form -100<internal id>
+	id := strconv.FormatInt(chat.Id, 10)
+	if internal, ok := strings.CutPrefix(id, "-100"); ok && internal != "" {
+		return fmt.Sprintf("https://t.me/c/%s/%d", internal, messageID)
+	}
+	return ""
+}
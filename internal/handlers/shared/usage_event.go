@@ -0,0 +1,17 @@
+package shared
+
+import (
+	"github.com/PaulSonOfLars/gotgbot/v2"
+
+	"twitterx-bot/internal/storage"
+)
+
+// UsageEvent returns a statistics event of kind caused by user in chatID.
+func UsageEvent(kind storage.EventKind, chatID int64, user *gotgbot.User, author string) storage.Event {
+	event := storage.Event{Kind: kind, ChatID: chatID, Author: author}
+	if user != nil {
+		event.UserID = user.Id
+		event.Username = UserDisplayName(user)
+	}
+	return event
+}
@@ -0,0 +1,110 @@
+package stats
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"twitterx-bot/internal/stats"
+	"twitterx-bot/internal/storage"
+)
+
+const maxBarWidth = 12
+
+// formatChat renders the report of a single chat.
+func formatChat(report stats.Report) string {
+	var sb strings.Builder
+	sb.WriteString("📊 <b>Stats for this chat</b> (last 30 days)\n")
+	// Chosen inline results are not counted: Telegram does not tell the bot which chat they were sent to
+	writeTotals(&sb, report, false)
+	writeRanking(&sb, "Top posters", report.TopUsers, func(c stats.Count) string { return c.Name })
+	writeRanking(&sb, "Top authors", report.TopAuthors, func(c stats.Count) string { return c.Name })
+	writeDaily(&sb, report.Daily)
+	return strings.TrimSpace(sb.String())
+}
+
+// formatGlobal renders the report over all chats; titles name the ranked chats.
+func formatGlobal(report stats.Report, titles map[int64]string) string {
+	var sb strings.Builder
+	sb.WriteString("📊 <b>Global stats</b> (last 30 days)\n")
+	writeTotals(&sb, report, true)
+	writeRanking(&sb, "Top chats", report.TopChats, func(c stats.Count) string {
+		if title, ok := titles[c.ID]; ok {
+			return title
+		}
+		return fmt.Sprintf("%d", c.ID)
+	})
+	writeRanking(&sb, "Top users", report.TopUsers, func(c stats.Count) string { return c.Name })
+	writeRanking(&sb, "Top authors", report.TopAuthors, func(c stats.Count) string { return c.Name })
+	writeDaily(&sb, report.Daily)
+	return strings.TrimSpace(sb.String())
+}
+
+func writeTotals(sb *strings.Builder, report stats.Report, inline bool) {
+	fmt.Fprintf(sb, "Tweets: %d · Chains: %d", report.Totals[storage.EventTweetSent], report.Totals[storage.EventChainSent])
+	if inline {
+		fmt.Fprintf(sb, " · Inline: %d", report.Totals[storage.EventInlineChosen])
+	}
+	fmt.Fprintf(sb, "\nDeleted: %d · Failed: %d\n", report.Totals[storage.EventDeleted], report.Totals[storage.EventFailed])
+}
+
+// exportCaption describes a CSV export of count events recorded since the given time.
+func exportCaption(count int, since time.Time) string {
+	caption := fmt.Sprintf("📊 %d events since %s", count, since.UTC().Format("Jan 02"))
+	if count >= maxExportEvents {
+		caption += " (export limit reached)"
+	}
+	return caption
+}
+
+func writeRanking(sb *strings.Builder, title string, counts []stats.Count, name func(stats.Count) string) {
+	if len(counts) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "\n<b>%s</b>\n", title)
+	for i, count := range counts {
+		label := name(count)
+		if label == "" {
+			label = fmt.Sprintf("user %d", count.ID)
+		}
+		fmt.Fprintf(sb, "%d. %s — %d\n", i+1, html.EscapeString(label), count.Count)
+	}
+}
+
+func writeDaily(sb *strings.Builder, days []stats.Day) {
+	max := 0
+	for _, day := range days {
+		if day.Count > max {
+			max = day.Count
+		}
+	}
+	if max == 0 {
+		sb.WriteString("\nNo tweets shared in the last 7 days.\n")
+		return
+	}
+
+	sb.WriteString("\n<b>Daily activity</b>\n<pre>")
+	for _, day := range days {
+		width := day.Count * maxBarWidth / max
+		if day.Count > 0 && width == 0 {
+			width = 1
+		}
+		fmt.Fprintf(sb, "%s %-*s %d\n", day.Date.Format("Jan 02"), maxBarWidth, strings.Repeat("▇", width), day.Count)
+	}
+	sb.WriteString("</pre>\n")
+}
+
+// chatTitle names a chat for the global ranking.
+func chatTitle(chat storage.Chat) string {
+	switch {
+	case chat.Title != "" && chat.Username != "":
+		return chat.Title + " (@" + chat.Username + ")"
+	case chat.Title != "":
+		return chat.Title
+	case chat.Username != "":
+		return "@" + chat.Username
+	default:
+		return fmt.Sprintf("%d", chat.ID)
+	}
+}
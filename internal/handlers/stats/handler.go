@@ -0,0 +1,165 @@
+// Package stats implements the /stats command with per-chat and global usage reports.
+package stats
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+
+	"twitterx-bot/internal/handlers/shared"
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/stats"
+	"twitterx-bot/internal/storage"
+)
+
+const (
+	reportPeriod = 30 * 24 * time.Hour
+	reportDays   = 7
+	reportTop    = 5
+	queryTimeout = 10 * time.Second
+	// maxExportEvents caps a CSV export so a busy period does not load every event into memory.
+	maxExportEvents = 50_000
+)
+
+const usageText = `Usage:
+/stats — activity in this chat for the last 30 days
+/stats csv — export the events of this chat for the last 30 days as CSV
+/stats global [csv] — all chats (bot admins only)`
+
+// Handler answers /stats with usage reports built from recorded events.
+type Handler struct {
+	log    *logger.Logger
+	events storage.EventRepository
+	chats  storage.ChatRepository
+	admins shared.BotAdmins
+	now    func() time.Time
+}
+
+// New creates a /stats handler. Global reports are limited to admins.
+func New(log *logger.Logger, events storage.EventRepository, chats storage.ChatRepository, admins shared.BotAdmins) *Handler {
+	return &Handler{log: log, events: events, chats: chats, admins: admins, now: time.Now}
+}
+
+// Command replies to "/stats [global] [csv]".
+func (h *Handler) Command(b *gotgbot.Bot, ctx *ext.Context) error {
+	log := h.log.With("component", "stats")
+	if ctx.EffectiveChat != nil {
+		log = log.With("chat_id", ctx.EffectiveChat.Id)
+	}
+	if ctx.EffectiveUser != nil {
+		log = log.With("user_id", ctx.EffectiveUser.Id, "username", ctx.EffectiveUser.Username)
+	}
+	msg := ctx.EffectiveMessage
+
+	var global, export bool
+	for _, arg := range ctx.Args()[1:] {
+		switch strings.ToLower(arg) {
+		case "global", "all":
+			global = true
+		case "csv", "export":
+			export = true
+		default:
+			return h.reply(b, msg, usageText)
+		}
+	}
+
+	filter := storage.EventFilter{ChatID: ctx.EffectiveChat.Id}
+	if global {
+		if ctx.EffectiveUser == nil || !h.admins.Contains(ctx.EffectiveUser.Id) {
+			log.Info("global stats denied: not a bot admin")
+			return h.reply(b, msg, "Global stats are available to bot admins only.")
+		}
+		if ctx.EffectiveChat.Type != gotgbot.ChatTypePrivate {
+			return h.reply(b, msg, "Global stats are only shown in a private chat with the bot.")
+		}
+		filter.ChatID = 0
+	}
+
+	reqCtx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	filter.Since = h.now().Add(-reportPeriod)
+	if export {
+		return h.sendCSV(reqCtx, b, msg, filter, log)
+	}
+
+	events, err := h.events.List(reqCtx, filter)
+	if err != nil {
+		log.Error("list events failed", "err", err)
+		return h.reply(b, msg, "Cannot load statistics, try again later.")
+	}
+
+	report := stats.Summarize(events, h.now(), reportDays, reportTop)
+	var text string
+	if global {
+		text = formatGlobal(report, h.chatTitles(reqCtx, report.TopChats, log))
+	} else {
+		text = formatChat(report)
+	}
+	_, err = msg.Reply(b, text, &gotgbot.SendMessageOpts{
+		ParseMode:          "HTML",
+		LinkPreviewOptions: &gotgbot.LinkPreviewOptions{IsDisabled: true},
+	})
+	return err
+}
+
+// sendCSV replies with the matching events as a CSV document, at most maxExportEvents of them.
+func (h *Handler) sendCSV(ctx context.Context, b *gotgbot.Bot, msg *gotgbot.Message, filter storage.EventFilter, log *logger.Logger) error {
+	filter.Limit = maxExportEvents
+	events, err := h.events.List(ctx, filter)
+	if err != nil {
+		log.Error("list events failed", "err", err)
+		return h.reply(b, msg, "Cannot load statistics, try again later.")
+	}
+	if len(events) == 0 {
+		return h.reply(b, msg, "No activity recorded yet.")
+	}
+
+	var buf bytes.Buffer
+	if err := stats.WriteCSV(&buf, events); err != nil {
+		log.Error("write stats csv failed", "err", err)
+		return h.reply(b, msg, "Cannot export statistics, try again later.")
+	}
+
+	name := fmt.Sprintf("stats-%d.csv", filter.ChatID)
+	if filter.ChatID == 0 {
+		name = "stats-global.csv"
+	}
+	_, err = b.SendDocument(msg.Chat.Id, gotgbot.InputFileByReader(name, &buf), &gotgbot.SendDocumentOpts{
+		Caption: exportCaption(len(events), filter.Since),
+		ReplyParameters: &gotgbot.ReplyParameters{
+			MessageId:                msg.MessageId,
+			AllowSendingWithoutReply: true,
+		},
+	})
+	if err == nil {
+		log.Info("stats exported", "events", len(events))
+	}
+	return err
+}
+
+// chatTitles resolves titles of the ranked chats; unknown chats are left out.
+func (h *Handler) chatTitles(ctx context.Context, counts []stats.Count, log *logger.Logger) map[int64]string {
+	titles := make(map[int64]string, len(counts))
+	for _, count := range counts {
+		chat, ok, err := h.chats.Get(ctx, count.ID)
+		if err != nil {
+			log.Warn("get chat failed", "ranked_chat_id", count.ID, "err", err)
+			continue
+		}
+		if ok {
+			titles[count.ID] = chatTitle(chat)
+		}
+	}
+	return titles
+}
+
+func (h *Handler) reply(b *gotgbot.Bot, msg *gotgbot.Message, text string) error {
+	_, err := msg.Reply(b, text, nil)
+	return err
+}
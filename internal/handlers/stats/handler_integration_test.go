@@ -0,0 +1,158 @@
+package stats_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+
+	"twitterx-bot/internal/handlers"
+	"twitterx-bot/internal/handlers/testutil"
+	"twitterx-bot/internal/storage"
+	"twitterx-bot/internal/twitterxapi"
+	testtelegram "twitterx-bot/pkg/testutil/telegram"
+)
+
+const (
+	groupID = int64(-1001234567890)
+	adminID = int64(42)
+	userID  = int64(1001)
+)
+
+func setup(t *testing.T) (*gotgbot.Bot, *testtelegram.MockServer, *ext.Dispatcher) {
+	return setupWithStorage(t, storage.NewMemory())
+}
+
+func setupWithStorage(t *testing.T, store storage.Storage) (*gotgbot.Bot, *testtelegram.MockServer, *ext.Dispatcher) {
+	fakeAPI := &testutil.FakeTweetAPI{
+		Tweets: map[string]*twitterxapi.Tweet{
+			"testuser/123": {
+				ID:     "123",
+				URL:    "https://x.com/testuser/status/123",
+				Text:   "Counted",
+				Author: twitterxapi.Author{Name: "Test User", ScreenName: "testuser"},
+			},
+		},
+	}
+	return testutil.SetupBotAndDispatcherWithOptions(t, fakeAPI, nil,
+		handlers.WithStorage(store),
+		handlers.WithAdmins(adminID),
+	)
+}
+
+func textUpdate(chat gotgbot.Chat, fromID int64, text string) *gotgbot.Update {
+	msg := &gotgbot.Message{
+		MessageId: 10,
+		Text:      text,
+		Chat:      chat,
+		From:      &gotgbot.User{Id: fromID, FirstName: "Alice"},
+	}
+	if strings.HasPrefix(text, "/") {
+		command, _, _ := strings.Cut(text, " ")
+		msg.Entities = []gotgbot.MessageEntity{{Type: "bot_command", Offset: 0, Length: int64(len(command))}}
+	}
+	return &gotgbot.Update{UpdateId: 1, Message: msg}
+}
+
+func process(t *testing.T, bot *gotgbot.Bot, dispatcher *ext.Dispatcher, update *gotgbot.Update) {
+	t.Helper()
+	if err := dispatcher.ProcessUpdate(bot, update, nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+}
+
+func lastText(t *testing.T, mock *testtelegram.MockServer) string {
+	t.Helper()
+	calls := mock.GetCalls("sendMessage")
+	if len(calls) == 0 {
+		t.Fatal("no sendMessage calls")
+	}
+	text, _ := calls[len(calls)-1].JSONString("text")
+	return text
+}
+
+func TestIntegration_Stats_ChatReport(t *testing.T) {
+	bot, mock, dispatcher := setup(t)
+	group := gotgbot.Chat{Id: groupID, Type: "supergroup", Title: "Friends"}
+
+	process(t, bot, dispatcher, textUpdate(group, userID, "https://x.com/testuser/status/123"))
+	process(t, bot, dispatcher, textUpdate(group, userID, "/stats"))
+
+	text := lastText(t, mock)
+	for _, want := range []string{"Stats for this chat", "Tweets: 1", "Top posters", "1. Alice — 1", "1. @testuser — 1", "Daily activity"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("report missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "Inline") {
+		t.Errorf("chat report counts inline results, which have no chat:\n%s", text)
+	}
+}
+
+func TestIntegration_Stats_ExportsCSV(t *testing.T) {
+	bot, mock, dispatcher := setup(t)
+	group := gotgbot.Chat{Id: groupID, Type: "supergroup", Title: "Friends"}
+
+	process(t, bot, dispatcher, textUpdate(group, userID, "https://x.com/testuser/status/123"))
+	process(t, bot, dispatcher, textUpdate(group, userID, "/stats csv"))
+
+	calls := mock.GetCalls("sendDocument")
+	if len(calls) != 1 {
+		t.Fatalf("sendDocument calls = %d, want 1", len(calls))
+	}
+	body := string(calls[0].RawBody)
+	if !strings.Contains(body, "time,kind,chat_id,user_id,username,author") || !strings.Contains(body, ",tweet,-1001234567890,1001,Alice,testuser") {
+		t.Errorf("csv document missing rows:\n%s", body)
+	}
+	if !strings.Contains(body, "stats--1001234567890.csv") {
+		t.Errorf("csv document has unexpected name:\n%s", body)
+	}
+}
+
+func TestIntegration_Stats_ExportSkipsEventsOutsideReportPeriod(t *testing.T) {
+	store := storage.NewMemory()
+	old := storage.Event{Kind: storage.EventTweetSent, ChatID: groupID, UserID: userID, Username: "Old", Author: "archive", CreatedAt: time.Now().AddDate(0, -2, 0)}
+	if err := store.Events().Add(context.Background(), old); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	bot, mock, dispatcher := setupWithStorage(t, store)
+	group := gotgbot.Chat{Id: groupID, Type: "supergroup", Title: "Friends"}
+
+	process(t, bot, dispatcher, textUpdate(group, userID, "https://x.com/testuser/status/123"))
+	process(t, bot, dispatcher, textUpdate(group, userID, "/stats csv"))
+
+	calls := mock.GetCalls("sendDocument")
+	if len(calls) != 1 {
+		t.Fatalf("sendDocument calls = %d, want 1", len(calls))
+	}
+	body := string(calls[0].RawBody)
+	if strings.Contains(body, "archive") || !strings.Contains(body, ",Alice,testuser") {
+		t.Errorf("csv document should hold only the last 30 days:\n%s", body)
+	}
+	if !strings.Contains(body, "1 events since") {
+		t.Errorf("csv caption should count the exported events:\n%s", body)
+	}
+}
+
+func TestIntegration_Stats_GlobalForAdminsOnly(t *testing.T) {
+	bot, mock, dispatcher := setup(t)
+	group := gotgbot.Chat{Id: groupID, Type: "supergroup", Title: "Friends"}
+
+	process(t, bot, dispatcher, textUpdate(group, userID, "https://x.com/testuser/status/123"))
+
+	process(t, bot, dispatcher, textUpdate(gotgbot.Chat{Id: userID, Type: "private"}, userID, "/stats global"))
+	if text := lastText(t, mock); !strings.Contains(text, "bot admins only") {
+		t.Errorf("non-admin got %q, want denial", text)
+	}
+
+	process(t, bot, dispatcher, textUpdate(gotgbot.Chat{Id: adminID, Type: "private"}, adminID, "/stats global"))
+	text := lastText(t, mock)
+	for _, want := range []string{"Global stats", "Inline: 0", "Top chats", "1. Friends — 1", "Top users"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("global report missing %q:\n%s", want, text)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+
+	"twitterx-bot/internal/handlers/message"
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/storage"
+	"twitterx-bot/internal/telegram/tweet"
+)
+
+const (
+	// trackingGroup runs before the regular handlers so every update is seen.
+	trackingGroup = -10
+
+	pruneInterval = time.Hour
+	// eventRetention is how long usage events are kept; /stats reports cover the last 30 days.
+	eventRetention = 90 * 24 * time.Hour
+	// historyRetention is how long sent tweets are kept; digests look back a week at most.
+	// A longer repost window keeps them for the window instead.
+	historyRetention = 90 * 24 * time.Hour
+)
+
+// historyRecorder stores sent tweets in the history repository.
+type historyRecorder struct {
+	history storage.HistoryRepository
+	now     func() time.Time
+}
+
+func (r historyRecorder) RecordSent(ctx context.Context, sent tweet.SentTweet) error {
+	return r.history.Add(ctx, storage.HistoryEntry{
+		ChatID:          sent.ChatID,
+		MessageID:       sent.MessageID,
+		SourceMessageID: sent.ReplyToMsgID,
+		TweetID:         sent.Tweet.ID,
+		Author:          sent.Tweet.Author.ScreenName,
+		Requester:       sent.Requester,
+		SentAt:          r.now(),
+	})
+}
+
+// replyRecorder maps the user message a tweet was sent for to all bot messages of the tweet.
+type replyRecorder struct {
+	replies storage.ReplyRepository
+	now     func() time.Time
+}
+
+func (r replyRecorder) RecordSent(ctx context.Context, sent tweet.SentTweet) error {
+	if sent.ReplyToMsgID == 0 {
+		return nil
+	}
+	ids := sent.MessageIDs
+	if len(ids) == 0 {
+		ids = []int64{sent.MessageID}
+	}
+	for _, id := range ids {
+		if err := r.replies.Add(ctx, storage.Reply{
+			ChatID:          sent.ChatID,
+			SourceMessageID: sent.ReplyToMsgID,
+			TweetID:         sent.Tweet.ID,
+			MessageID:       id,
+			SentAt:          r.now(),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// storagePruner deletes stored data that is no longer needed, so the tables do not grow forever:
+// replies to messages too old to be followed by edits (see message.EditWindow), usage
+// events older than eventRetention and sent tweets older than keepHistory.
+type storagePruner struct {
+	log         *logger.Logger
+	replies     storage.ReplyRepository
+	events      storage.EventRepository
+	history     storage.HistoryRepository
+	keepHistory time.Duration
+	now         func() time.Time
+}
+
+// Run prunes every hour until ctx is cancelled.
+func (p storagePruner) Run(ctx context.Context) error {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		p.Prune(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p storagePruner) Prune(ctx context.Context) {
+	log := p.log.With("component", "storage")
+	now := p.now()
+	if pruned, err := p.replies.Prune(ctx, now.Add(-message.EditWindow)); err != nil {
+		log.Warn("prune replies failed", "err", err)
+	} else if pruned > 0 {
+		log.Debug("replies pruned", "count", pruned)
+	}
+	if pruned, err := p.events.Prune(ctx, now.Add(-eventRetention)); err != nil {
+		log.Warn("prune events failed", "err", err)
+	} else if pruned > 0 {
+		log.Debug("events pruned", "count", pruned)
+	}
+	if pruned, err := p.history.Prune(ctx, now.Add(-p.keepHistory)); err != nil {
+		log.Warn("prune history failed", "err", err)
+	} else if pruned > 0 {
+		log.Debug("history pruned", "count", pruned)
+	}
+}
+
+// historyRecorders passes every sent tweet to each recorder and returns the first error.
+type historyRecorders []tweet.HistoryRecorder
+
+func (rs historyRecorders) RecordSent(ctx context.Context, sent tweet.SentTweet) error {
+	var first error
+	for _, r := range rs {
+		if err := r.RecordSent(ctx, sent); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// chatTracker records the chat of every update and lets the update through.
+// my_chat_member updates mark chats the bot was removed from (or blocked in) as left.
+type chatTracker struct {
+	log   *logger.Logger
+	chats storage.ChatRepository
+	now   func() time.Time
+}
+
+func (t chatTracker) CheckUpdate(_ *gotgbot.Bot, ctx *ext.Context) bool {
+	return ctx.EffectiveChat != nil
+}
+
+func (t chatTracker) HandleUpdate(_ *gotgbot.Bot, ctx *ext.Context) error {
+	chat := ctx.EffectiveChat
+	title := chat.Title
+	if title == "" {
+		title = chat.FirstName
+	}
+	if err := t.chats.Upsert(context.Background(), storage.Chat{
+		ID:       chat.Id,
+		Type:     chat.Type,
+		Title:    title,
+		Username: chat.Username,
+		LastSeen: t.now(),
+		Left:     botLeft(ctx.MyChatMember),
+	}); err != nil {
+		t.log.With("component", "storage", "chat_id", chat.Id).Warn("track chat failed", "err", err)
+	}
+	return nil
+}
+
+func (t chatTracker) Name() string {
+	return "chat_tracker"
+}
+
+// botLeft reports whether the membership update removed the bot from the chat.
+func botLeft(update *gotgbot.ChatMemberUpdated) bool {
+	if update == nil {
+		return false
+	}
+	switch update.NewChatMember.GetStatus() {
+	case "left", "kicked":
+		return true
+	default:
+		return false
+	}
+}
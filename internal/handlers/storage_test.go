@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"twitterx-bot/internal/handlers/message"
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/storage"
+)
+
+func TestStoragePruner_PrunesExpiredData(t *testing.T) {
+	ctx := context.Background()
+	now := time.UnixMilli(1_700_000_000_000)
+	store := storage.NewMemory()
+
+	for _, reply := range []storage.Reply{
+		{ChatID: -100, SourceMessageID: 10, TweetID: "1", MessageID: 11, SentAt: now.Add(-message.EditWindow - time.Minute)},
+		{ChatID: -100, SourceMessageID: 20, TweetID: "2", MessageID: 21, SentAt: now.Add(-time.Hour)},
+	} {
+		if err := store.Replies().Add(ctx, reply); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	for _, event := range []storage.Event{
+		{Kind: storage.EventTweetSent, ChatID: -100, CreatedAt: now.Add(-eventRetention - time.Minute)},
+		{Kind: storage.EventChainSent, ChatID: -100, CreatedAt: now.Add(-time.Hour)},
+	} {
+		if err := store.Events().Add(ctx, event); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	for _, entry := range []storage.HistoryEntry{
+		{ChatID: -100, MessageID: 11, TweetID: "1", SentAt: now.Add(-historyRetention - time.Minute)},
+		{ChatID: -100, MessageID: 21, TweetID: "2", SentAt: now.Add(-time.Hour)},
+	} {
+		if err := store.History().Add(ctx, entry); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	storagePruner{
+		log:         logger.New(true),
+		replies:     store.Replies(),
+		events:      store.Events(),
+		history:     store.History(),
+		keepHistory: historyRetention,
+		now:         func() time.Time { return now },
+	}.Prune(ctx)
+
+	if list, _ := store.Replies().List(ctx, -100, 10); len(list) != 0 {
+		t.Errorf("replies older than the edit window = %+v, want pruned", list)
+	}
+	if list, _ := store.Replies().List(ctx, -100, 20); len(list) != 1 {
+		t.Errorf("recent replies = %+v, want kept", list)
+	}
+	if events, _ := store.Events().List(ctx, storage.EventFilter{}); len(events) != 1 || events[0].Kind != storage.EventChainSent {
+		t.Errorf("events = %+v, want only the recent one", events)
+	}
+	if history, _ := store.History().List(ctx, storage.HistoryFilter{}); len(history) != 1 || history[0].TweetID != "2" {
+		t.Errorf("history = %+v, want only the recent entry", history)
+	}
+}
@@ -4,6 +4,7 @@ package testutil
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -20,6 +21,8 @@ import (
 // FakeTweetAPI is a minimal fake for testing that returns configured tweets.
 type FakeTweetAPI struct {
 	Tweets map[string]*twitterxapi.Tweet
+	// Timelines maps a lower-cased username to its tweets, newest first.
+	Timelines map[string][]*twitterxapi.Tweet
 }
 
 // GetTweet returns a tweet from the configured map or nil if not found.
@@ -31,6 +34,25 @@ func (f *FakeTweetAPI) GetTweet(_ context.Context, username, tweetID string) (*t
 	return nil, nil
 }
 
+// GetTimeline returns the configured timeline newer than opts.SinceID, or an error for unknown users.
+func (f *FakeTweetAPI) GetTimeline(_ context.Context, username string, opts twitterxapi.TimelineOpts) ([]*twitterxapi.Tweet, error) {
+	timeline, ok := f.Timelines[username]
+	if !ok {
+		return nil, fmt.Errorf("api error 404: user %s not found", username)
+	}
+	var tweets []*twitterxapi.Tweet
+	for _, tw := range timeline {
+		if opts.SinceID != "" && len(tw.ID) == len(opts.SinceID) && tw.ID <= opts.SinceID {
+			continue
+		}
+		tweets = append(tweets, tw)
+		if opts.Limit > 0 && len(tweets) == opts.Limit {
+			break
+		}
+	}
+	return tweets, nil
+}
+
 // NewTestBot creates a gotgbot.Bot that points at the provided mock server.
 func NewTestBot(t *testing.T, mock *testtelegram.MockServer) *gotgbot.Bot {
 	t.Helper()
@@ -52,6 +74,13 @@ func SetupBotAndDispatcher(t *testing.T, fakeAPI *FakeTweetAPI) (*gotgbot.Bot, *
 
 // SetupBotAndDispatcherWithTelegraph wires the handlers to a dispatcher with an optional Telegraph service.
 func SetupBotAndDispatcherWithTelegraph(t *testing.T, fakeAPI *FakeTweetAPI, telegraph tweet.ArticleCreator) (*gotgbot.Bot, *testtelegram.MockServer, *ext.Dispatcher) {
+	t.Helper()
+	return SetupBotAndDispatcherWithOptions(t, fakeAPI, telegraph)
+}
+
+// SetupBotAndDispatcherWithOptions wires the handlers to a dispatcher with an optional Telegraph service
+// and additional handler options.
+func SetupBotAndDispatcherWithOptions(t *testing.T, fakeAPI *FakeTweetAPI, telegraph tweet.ArticleCreator, opts ...handlers.Option) (*gotgbot.Bot, *testtelegram.MockServer, *ext.Dispatcher) {
 	t.Helper()
 	if fakeAPI == nil {
 		fakeAPI = &FakeTweetAPI{}
@@ -69,7 +98,7 @@ func SetupBotAndDispatcherWithTelegraph(t *testing.T, fakeAPI *FakeTweetAPI, tel
 		},
 	})
 
-	handlers.RegisterWithFetcher(dispatcher, logger.New(true), fakeAPI, telegraph)
+	handlers.RegisterWithFetcher(dispatcher, logger.New(true), fakeAPI, telegraph, opts...)
 
 	return bot, mock, dispatcher
 }
@@ -1,19 +1,31 @@
 package testutil
 
-import "context"
+import (
+	"context"
+
+	"twitterx-bot/internal/chain"
+	"twitterx-bot/internal/twitterxapi"
+)
 
 // FakeTelegraph is a test double for Telegraph article creation.
 type FakeTelegraph struct {
 	Called   bool
-	GotText  string
-	GotTitle string
+	GotTweet *twitterxapi.Tweet
 	URL      string
 	Err      error
+
+	ChainCalled bool
+	GotChain    []chain.ChainItem
 }
 
-func (f *FakeTelegraph) CreateArticle(_ context.Context, text, title string) (string, error) {
+func (f *FakeTelegraph) CreateArticle(_ context.Context, tweet *twitterxapi.Tweet) (string, error) {
 	f.Called = true
-	f.GotText = text
-	f.GotTitle = title
+	f.GotTweet = tweet
+	return f.URL, f.Err
+}
+
+func (f *FakeTelegraph) CreateChainArticle(_ context.Context, items []chain.ChainItem) (string, error) {
+	f.ChainCalled = true
+	f.GotChain = items
 	return f.URL, f.Err
 }
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+
+	"twitterx-bot/internal/handlers/shared"
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/ratelimit"
+	"twitterx-bot/internal/twitterurl"
+)
+
+// RateLimits configures token buckets per user, per chat and for the whole bot.
+// A zero Limit disables that scope.
+type RateLimits struct {
+	User   ratelimit.Limit
+	Chat   ratelimit.Limit
+	Global ratelimit.Limit
+}
+
+// throttle drops updates that exceed the rate limits before they reach the handlers.
+// Only updates that make the bot work count: tweet links and commands in messages, edited
+// messages and channel posts, callbacks and inline queries with a tweet link.
+// Bot admins are not limited.
+type throttle struct {
+	log    *logger.Logger
+	user   *ratelimit.Limiter
+	chat   *ratelimit.Limiter
+	global *ratelimit.Limiter
+	admins shared.BotAdmins
+}
+
+func newThrottle(log *logger.Logger, limits RateLimits, admins shared.BotAdmins) throttle {
+	return throttle{
+		log:    log,
+		user:   ratelimit.New(limits.User),
+		chat:   ratelimit.New(limits.Chat),
+		global: ratelimit.New(limits.Global),
+		admins: admins,
+	}
+}
+
+func (t throttle) CheckUpdate(_ *gotgbot.Bot, ctx *ext.Context) bool {
+	switch {
+	case ctx.CallbackQuery != nil:
+		return true
+	case ctx.InlineQuery != nil:
+		_, _, ok := twitterurl.ParseTweetURL(strings.TrimSpace(ctx.InlineQuery.Query))
+		return ok
+	case ctx.EffectiveMessage != nil:
+		text := strings.TrimSpace(ctx.EffectiveMessage.Text)
+		if strings.HasPrefix(text, "/") {
+			return true
+		}
+		_, _, ok := twitterurl.ParseTweetURL(text)
+		return ok
+	default:
+		return false
+	}
+}
+
+func (t throttle) HandleUpdate(b *gotgbot.Bot, ctx *ext.Context) error {
+	user := ctx.EffectiveUser
+	if user != nil && t.admins.Contains(user.Id) {
+		return nil
+	}
+
+	scope := t.allow(ctx)
+	if scope == "" {
+		return nil
+	}
+
+	log := t.log.With("component", "throttle", "scope", scope)
+	if ctx.EffectiveChat != nil {
+		log = log.With("chat_id", ctx.EffectiveChat.Id)
+	}
+	if user != nil {
+		log = log.With("user_id", user.Id, "username", user.Username)
+	}
+
+	switch {
+	case ctx.CallbackQuery != nil:
+		log.Info("callback throttled")
+		if _, err := ctx.CallbackQuery.Answer(b, &gotgbot.AnswerCallbackQueryOpts{
+			Text: "Slow down! Try again in a few seconds.",
+		}); err != nil {
+			log.Debug("answer throttled callback failed", "err", err)
+		}
+	case ctx.InlineQuery != nil:
+		log.Info("inline query throttled")
+		if _, err := ctx.InlineQuery.Answer(b, nil, &gotgbot.AnswerInlineQueryOpts{IsPersonal: true}); err != nil {
+			log.Debug("answer throttled inline query failed", "err", err)
+		}
+	default:
+		log.Info("message throttled", "message_id", ctx.EffectiveMessage.MessageId)
+	}
+	return ext.EndGroups
+}
+
+// allow takes a token from every limit that applies to the update and returns the scope of
+// the first limit that refused it, or "" when all allow it. Tokens taken before a refusal
+// are returned, so a request dropped by the chat or global limit does not cost the user.
+func (t throttle) allow(ctx *ext.Context) string {
+	type check struct {
+		scope   string
+		limiter *ratelimit.Limiter
+		key     int64
+	}
+	var checks []check
+	if ctx.EffectiveUser != nil {
+		checks = append(checks, check{"user", t.user, ctx.EffectiveUser.Id})
+	}
+	if ctx.EffectiveChat != nil {
+		checks = append(checks, check{"chat", t.chat, ctx.EffectiveChat.Id})
+	}
+	checks = append(checks, check{"global", t.global, 0})
+
+	for i, c := range checks {
+		if c.limiter.Allow(c.key) {
+			continue
+		}
+		for _, taken := range checks[:i] {
+			taken.limiter.Refund(taken.key)
+		}
+		return c.scope
+	}
+	return ""
+}
+
+func (t throttle) Name() string {
+	return "throttle"
+}
@@ -0,0 +1,143 @@
+package handlers_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+
+	"twitterx-bot/internal/handlers"
+	"twitterx-bot/internal/handlers/testutil"
+	"twitterx-bot/internal/ratelimit"
+	"twitterx-bot/internal/storage"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+func TestIntegration_Throttle_LimitsMessagesAndCallbacks(t *testing.T) {
+	fakeAPI := &testutil.FakeTweetAPI{
+		Tweets: map[string]*twitterxapi.Tweet{
+			"testuser/123": {
+				ID:     "123",
+				URL:    "https://x.com/testuser/status/123",
+				Text:   "Hello",
+				Author: twitterxapi.Author{Name: "Test User", ScreenName: "testuser"},
+			},
+		},
+	}
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, fakeAPI, nil,
+		handlers.WithAdmins(42),
+		handlers.WithRateLimits(handlers.RateLimits{
+			User: ratelimit.Limit{Burst: 2, Per: time.Hour},
+		}),
+	)
+
+	chat := gotgbot.Chat{Id: -100, Type: "supergroup"}
+	message := func(fromID int64, text string) *gotgbot.Update {
+		return &gotgbot.Update{Message: &gotgbot.Message{
+			MessageId: 10,
+			Text:      text,
+			Chat:      chat,
+			From:      &gotgbot.User{Id: fromID, FirstName: "User"},
+		}}
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := dispatcher.ProcessUpdate(bot, message(1001, "https://x.com/testuser/status/123"), nil); err != nil {
+			t.Fatalf("ProcessUpdate() error = %v", err)
+		}
+	}
+	// Plain chatter does not spend tokens and admins are not limited
+	if err := dispatcher.ProcessUpdate(bot, message(1001, "nice"), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := dispatcher.ProcessUpdate(bot, message(42, "https://x.com/testuser/status/123"), nil); err != nil {
+			t.Fatalf("ProcessUpdate() error = %v", err)
+		}
+	}
+	if calls := mock.GetCalls("sendMessage"); len(calls) != 5 {
+		t.Errorf("sendMessage calls = %d, want 2 for the user and 3 for the admin", len(calls))
+	}
+
+	callback := &gotgbot.Update{CallbackQuery: &gotgbot.CallbackQuery{
+		Id:      "cb-flood",
+		Data:    "chain:testuser:123:10",
+		From:    gotgbot.User{Id: 1001, FirstName: "User"},
+		Message: &gotgbot.Message{MessageId: 11, Date: 1000000, Chat: chat},
+	}}
+	if err := dispatcher.ProcessUpdate(bot, callback, nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+	answers := mock.GetCalls("answerCallbackQuery")
+	if len(answers) != 1 {
+		t.Fatalf("answerCallbackQuery calls = %d, want 1", len(answers))
+	}
+	if text, _ := answers[0].JSONString("text"); !strings.Contains(text, "Slow down") {
+		t.Errorf("callback answer = %q, want slow down", text)
+	}
+}
+
+func TestIntegration_Throttle_LimitsEditedMessages(t *testing.T) {
+	fakeAPI := &testutil.FakeTweetAPI{
+		Tweets: map[string]*twitterxapi.Tweet{
+			"testuser/123": {ID: "123", URL: "https://x.com/testuser/status/123", Text: "Hello", Author: twitterxapi.Author{ScreenName: "testuser"}},
+			"testuser/124": {ID: "124", URL: "https://x.com/testuser/status/124", Text: "Again", Author: twitterxapi.Author{ScreenName: "testuser"}},
+		},
+	}
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, fakeAPI, nil,
+		handlers.WithStorage(storage.NewMemory()),
+		handlers.WithRateLimits(handlers.RateLimits{
+			User: ratelimit.Limit{Burst: 1, Per: time.Hour},
+		}),
+	)
+
+	chat := gotgbot.Chat{Id: -100, Type: "supergroup"}
+	from := &gotgbot.User{Id: 1001, FirstName: "User"}
+	if err := dispatcher.ProcessUpdate(bot, &gotgbot.Update{Message: &gotgbot.Message{
+		MessageId: 10, Date: time.Now().Unix(), Text: "https://x.com/testuser/status/123", Chat: chat, From: from,
+	}}, nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+	// Editing the link in is the same work as sending it
+	if err := dispatcher.ProcessUpdate(bot, &gotgbot.Update{EditedMessage: &gotgbot.Message{
+		MessageId: 10, Date: time.Now().Unix(), Text: "https://x.com/testuser/status/124", Chat: chat, From: from, EditDate: time.Now().Unix(),
+	}}, nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	for _, call := range mock.GetCalls("sendMessage") {
+		if text, _ := call.JSONString("text"); strings.Contains(text, "Again") {
+			t.Fatalf("edited message sent %q, want it throttled", text)
+		}
+	}
+}
+
+func TestIntegration_Throttle_RefusedByChatDoesNotSpendUserTokens(t *testing.T) {
+	fakeAPI := &testutil.FakeTweetAPI{
+		Tweets: map[string]*twitterxapi.Tweet{
+			"testuser/123": {ID: "123", URL: "https://x.com/testuser/status/123", Text: "Hello", Author: twitterxapi.Author{ScreenName: "testuser"}},
+		},
+	}
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, fakeAPI, nil,
+		handlers.WithRateLimits(handlers.RateLimits{
+			User: ratelimit.Limit{Burst: 2, Per: time.Hour},
+			Chat: ratelimit.Limit{Burst: 1, Per: time.Hour},
+		}),
+	)
+
+	for i, chatID := range []int64{-100, -100, -100, -200} {
+		update := &gotgbot.Update{Message: &gotgbot.Message{
+			MessageId: int64(10 + i),
+			Text:      "https://x.com/testuser/status/123",
+			Chat:      gotgbot.Chat{Id: chatID, Type: "supergroup"},
+			From:      &gotgbot.User{Id: 1001, FirstName: "User"},
+		}}
+		if err := dispatcher.ProcessUpdate(bot, update, nil); err != nil {
+			t.Fatalf("ProcessUpdate() error = %v", err)
+		}
+	}
+	if calls := mock.GetCalls("sendMessage"); len(calls) != 2 {
+		t.Errorf("sendMessage calls = %d, want one per chat", len(calls))
+	}
+}
@@ -0,0 +1,114 @@
+package translate
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+
+	"twitterx-bot/internal/chatsettings"
+	"twitterx-bot/internal/handlers/shared"
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/translation"
+)
+
+var languageCodeRegex = regexp.MustCompile(`^[a-z]{2,3}(-[a-z]{2,4})?$`)
+
+// Handler manages the per-chat translation target via the /translate command.
+type Handler struct {
+	log      *logger.Logger
+	settings chatsettings.Store
+}
+
+// New creates a /translate command handler backed by the given settings store.
+func New(log *logger.Logger, settings chatsettings.Store) *Handler {
+	return &Handler{log: log, settings: settings}
+}
+
+// Handle processes "/translate", "/translate <lang>" and "/translate off".
+// Anyone may view the target; in groups only admins may change it.
+func (h *Handler) Handle(b *gotgbot.Bot, ctx *ext.Context) error {
+	log := h.log.With("component", "translate")
+	if ctx.EffectiveChat != nil {
+		log = log.With("chat_id", ctx.EffectiveChat.Id)
+	}
+	if ctx.EffectiveUser != nil {
+		log = log.With("user_id", ctx.EffectiveUser.Id, "username", ctx.EffectiveUser.Username)
+	}
+
+	chatID := ctx.EffectiveChat.Id
+	reqCtx := context.Background()
+
+	settings, err := h.settings.Get(reqCtx, chatID)
+	if err != nil {
+		log.Error("load chat settings failed", "err", err)
+		return h.reply(b, ctx, "Cannot load chat settings, try again later.")
+	}
+
+	args := ctx.Args()
+	if len(args) < 2 {
+		return h.reply(b, ctx, describe(settings.TranslateTo))
+	}
+
+	if allowed, err := canManage(b, ctx); err != nil || !allowed {
+		if err != nil {
+			log.Error("check chat admin failed", "err", err)
+			return h.reply(b, ctx, "Cannot check your permissions, try again later.")
+		}
+		log.Info("translate denied: not an admin")
+		return h.reply(b, ctx, "Only chat admins can change the translation language.")
+	}
+
+	arg := strings.ToLower(strings.TrimSpace(args[1]))
+	switch {
+	case arg == "off" || arg == "none":
+		settings.TranslateTo = ""
+	case languageCodeRegex.MatchString(arg):
+		settings.TranslateTo = arg
+	default:
+		return h.reply(b, ctx, fmt.Sprintf("Unknown language code <code>%s</code>. Use an ISO code such as <code>uk</code> or <code>en</code>.", html.EscapeString(arg)))
+	}
+
+	if err := h.settings.Save(reqCtx, chatID, settings); err != nil {
+		log.Error("save chat settings failed", "err", err)
+		return h.reply(b, ctx, "Cannot save chat settings, try again later.")
+	}
+
+	log.Info("translation target updated", "translate_to", settings.TranslateTo)
+	return h.reply(b, ctx, describe(settings.TranslateTo))
+}
+
+// canManage reports whether the sender may change the translation target. Channel posts and
+// anonymous admins speak for the chat; in groups only administrators may.
+func canManage(b *gotgbot.Bot, ctx *ext.Context) (bool, error) {
+	if shared.IsAnonymousAdmin(ctx.EffectiveMessage) {
+		return true, nil
+	}
+	if ctx.EffectiveUser == nil {
+		return false, nil
+	}
+	return shared.CanManageChat(b, ctx.EffectiveChat, ctx.EffectiveUser.Id)
+}
+
+func (h *Handler) reply(b *gotgbot.Bot, ctx *ext.Context, text string) error {
+	_, err := ctx.EffectiveMessage.Reply(b, text, &gotgbot.SendMessageOpts{
+		ParseMode: "HTML",
+	})
+	return err
+}
+
+// describe returns a human readable description of the translation target.
+func describe(target string) string {
+	if target == "" {
+		return "Translation is off.\nUse <code>/translate uk</code> to translate tweets in this chat."
+	}
+	name := translation.LanguageFromISO(target).Name
+	if name == "" {
+		name = target
+	}
+	return fmt.Sprintf("Tweets are translated into %s (<code>%s</code>).\nUse <code>/translate off</code> to disable.", html.EscapeString(name), html.EscapeString(target))
+}
@@ -0,0 +1,153 @@
+package translate_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+
+	"twitterx-bot/internal/chatsettings"
+	"twitterx-bot/internal/handlers"
+	"twitterx-bot/internal/handlers/testutil"
+	"twitterx-bot/internal/translation"
+	"twitterx-bot/internal/twitterxapi"
+	testtelegram "twitterx-bot/pkg/testutil/telegram"
+)
+
+type fakeTranslator struct{}
+
+func (fakeTranslator) Translate(_ context.Context, _ string, to translation.Language) (*translation.Translation, error) {
+	return &translation.Translation{From: translation.LangEnglish, To: to, Text: "Привіт світ"}, nil
+}
+
+func commandUpdate(updateID, chatID, msgID int64, text string) *gotgbot.Update {
+	return &gotgbot.Update{
+		UpdateId: updateID,
+		Message: &gotgbot.Message{
+			MessageId: msgID,
+			Text:      text,
+			Chat:      gotgbot.Chat{Id: chatID, Type: "group"},
+			From:      &gotgbot.User{Id: 3003, FirstName: "Translator"},
+		},
+	}
+}
+
+func setMemberStatus(t *testing.T, mock *testtelegram.MockServer, status string) {
+	t.Helper()
+	if err := mock.SetResponse("getChatMember", map[string]any{
+		"status": status,
+		"user":   map[string]any{"id": 3003, "is_bot": false, "first_name": "Translator"},
+	}); err != nil {
+		t.Fatalf("SetResponse() error = %v", err)
+	}
+}
+
+func TestIntegration_TranslateCommand_SetsTargetAndTranslatesTweets(t *testing.T) {
+	fakeAPI := &testutil.FakeTweetAPI{
+		Tweets: map[string]*twitterxapi.Tweet{
+			"alice/1": {
+				ID:     "1",
+				URL:    "https://x.com/alice/status/1",
+				Text:   "Hello world",
+				Author: twitterxapi.Author{Name: "Alice", ScreenName: "alice"},
+			},
+		},
+	}
+	settings := chatsettings.NewMemoryStore()
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, fakeAPI, nil,
+		handlers.WithTranslator(fakeTranslator{}),
+		handlers.WithChatSettings(settings),
+	)
+	setMemberStatus(t, mock, "administrator")
+
+	const chatID = int64(-100123)
+
+	if err := dispatcher.ProcessUpdate(bot, commandUpdate(1, chatID, 10, "/translate uk"), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	got, err := settings.Get(context.Background(), chatID)
+	if err != nil {
+		t.Fatalf("settings.Get() error = %v", err)
+	}
+	if got.TranslateTo != "uk" {
+		t.Fatalf("TranslateTo = %q, want uk", got.TranslateTo)
+	}
+
+	if err := dispatcher.ProcessUpdate(bot, commandUpdate(2, chatID, 11, "https://x.com/alice/status/1"), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	calls := mock.GetCalls("sendMessage")
+	if len(calls) != 2 {
+		t.Fatalf("sendMessage calls = %d, want 2", len(calls))
+	}
+	text, _ := calls[1].JSONString("text")
+	if !strings.Contains(text, "Hello world") || !strings.Contains(text, "Привіт світ") {
+		t.Fatalf("tweet text = %q, want original and translation", text)
+	}
+}
+
+func TestIntegration_TranslateCommand_Off(t *testing.T) {
+	settings := chatsettings.NewMemoryStore()
+	_ = settings.Save(context.Background(), 5, chatsettings.Settings{TranslateTo: "uk"})
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, nil, nil, handlers.WithChatSettings(settings))
+	setMemberStatus(t, mock, "administrator")
+
+	if err := dispatcher.ProcessUpdate(bot, commandUpdate(1, 5, 10, "/translate off"), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	got, _ := settings.Get(context.Background(), 5)
+	if got.TranslateTo != "" {
+		t.Fatalf("TranslateTo = %q, want empty", got.TranslateTo)
+	}
+	calls := mock.GetCalls("sendMessage")
+	if len(calls) != 1 {
+		t.Fatalf("sendMessage calls = %d, want 1", len(calls))
+	}
+	if text, _ := calls[0].JSONString("text"); !strings.Contains(text, "Translation is off") {
+		t.Fatalf("reply text = %q, want off notice", text)
+	}
+}
+
+func TestIntegration_TranslateCommand_RejectsInvalidCode(t *testing.T) {
+	settings := chatsettings.NewMemoryStore()
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, nil, nil, handlers.WithChatSettings(settings))
+	setMemberStatus(t, mock, "administrator")
+
+	if err := dispatcher.ProcessUpdate(bot, commandUpdate(1, 5, 10, "/translate ukrainian!"), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	got, _ := settings.Get(context.Background(), 5)
+	if got.TranslateTo != "" {
+		t.Fatalf("TranslateTo = %q, want empty", got.TranslateTo)
+	}
+	if calls := mock.GetCalls("sendMessage"); len(calls) != 1 {
+		t.Fatalf("sendMessage calls = %d, want 1", len(calls))
+	}
+}
+
+func TestIntegration_TranslateCommand_DeniedForGroupMembers(t *testing.T) {
+	settings := chatsettings.NewMemoryStore()
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, nil, nil, handlers.WithChatSettings(settings))
+	setMemberStatus(t, mock, "member")
+
+	if err := dispatcher.ProcessUpdate(bot, commandUpdate(1, 5, 10, "/translate uk"), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	got, _ := settings.Get(context.Background(), 5)
+	if got.TranslateTo != "" {
+		t.Fatalf("TranslateTo = %q, want unchanged", got.TranslateTo)
+	}
+	calls := mock.GetCalls("sendMessage")
+	if len(calls) != 1 {
+		t.Fatalf("sendMessage calls = %d, want 1", len(calls))
+	}
+	if text, _ := calls[0].JSONString("text"); !strings.Contains(text, "Only chat admins") {
+		t.Fatalf("reply text = %q, want admin-only notice", text)
+	}
+}
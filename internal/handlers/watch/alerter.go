@@ -0,0 +1,66 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"html"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+
+	"twitterx-bot/internal/handlers/shared"
+	"twitterx-bot/internal/telegram/tweet"
+	watchsvc "twitterx-bot/internal/watch"
+)
+
+// Alerter sends watch alerts as private messages: a note about the rule and the chat,
+// followed by the tweet rendered like everywhere else.
+// Sender must have its Bot set and no History, so alerts are not matched again.
+type Alerter struct {
+	Bot    *gotgbot.Bot
+	Sender tweet.Sender
+}
+
+// IsMember reports whether the user is currently in the chat.
+func (a Alerter) IsMember(ctx context.Context, chatID, userID int64) (bool, error) {
+	member, err := a.Bot.GetChatMemberWithContext(ctx, chatID, userID, nil)
+	if err != nil {
+		return false, err
+	}
+	merged := member.MergeChatMember()
+	switch merged.Status {
+	case "creator", "administrator", "member":
+		return true, nil
+	case "restricted":
+		return merged.IsMember, nil
+	default:
+		return false, nil
+	}
+}
+
+// Alert sends the alert to the owner of the rule.
+func (a Alerter) Alert(ctx context.Context, alert watchsvc.Alert) error {
+	userID := alert.Rule.UserID
+	_, err := a.Bot.SendMessageWithContext(ctx, userID, alertText(alert), &gotgbot.SendMessageOpts{
+		ParseMode: "HTML",
+		LinkPreviewOptions: &gotgbot.LinkPreviewOptions{
+			IsDisabled: true,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return a.Sender.SendTweet(ctx, userID, 0, alert.Sighting.Tweet, nil)
+}
+
+// alertText names the rule and links to the message the tweet was seen in when possible.
+func alertText(alert watchsvc.Alert) string {
+	where := "a chat"
+	if title := alert.Chat.Title; title != "" {
+		where = html.EscapeString(title)
+	}
+	chat := &gotgbot.Chat{Id: alert.Sighting.ChatID, Username: alert.Chat.Username}
+	if link := shared.MessageLink(chat, alert.Sighting.MessageID); link != "" {
+		where = fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(link), where)
+	}
+	return fmt.Sprintf("🔔 Watch rule #%d <code>%s</code> matched in %s", alert.Rule.ID, html.EscapeString(alert.Rule.Query), where)
+}
@@ -0,0 +1,203 @@
+// Package watch implements /watch and /unwatch, which manage the rules that alert a user
+// in private about matching tweets.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/storage"
+	watchsvc "twitterx-bot/internal/watch"
+)
+
+// maxRulesPerUser bounds the rules every sent tweet is matched against.
+const maxRulesPerUser = 10
+
+const usageText = `<b>Watch rules</b> send you a private message when I send a matching tweet in a chat you are in.
+
+<code>/watch golang "generic types"</code> — all terms must appear
+<code>/watch @nasa -starship</code> — tweets by @nasa without "starship"
+<code>/watch /go ?1\.2\d/</code> — a regular expression
+<code>/watch mute 3 22:00-07:00</code> — no alerts from rule 3 at night (UTC, <code>off</code> to unmute)
+<code>/unwatch 3</code> — delete rule 3`
+
+// Handler manages the watch rules of the user in a private chat.
+type Handler struct {
+	log   *logger.Logger
+	rules storage.WatchRepository
+	now   func() time.Time
+}
+
+// New creates the /watch and /unwatch handlers.
+func New(log *logger.Logger, rules storage.WatchRepository) *Handler {
+	return &Handler{log: log, rules: rules, now: time.Now}
+}
+
+// Watch handles "/watch", "/watch <rule>" and "/watch mute <id> <HH:MM-HH:MM|off>".
+func (h *Handler) Watch(b *gotgbot.Bot, ctx *ext.Context) error {
+	log := h.logger(ctx)
+	msg := ctx.EffectiveMessage
+	if !isPrivate(ctx) {
+		return h.reply(b, msg, "Watch rules are personal. Send /watch to me in a private chat.")
+	}
+	userID := ctx.EffectiveUser.Id
+	reqCtx := context.Background()
+
+	payload := commandPayload(msg.Text)
+	if payload == "" {
+		return h.list(b, msg, log, userID)
+	}
+	if args := strings.Fields(payload); strings.EqualFold(args[0], "mute") {
+		return h.mute(b, msg, log, userID, args[1:])
+	}
+
+	if _, err := watchsvc.ParseQuery(payload); err != nil {
+		return h.reply(b, msg, fmt.Sprintf("Cannot use this rule: %s.\n\n%s", html.EscapeString(err.Error()), usageText))
+	}
+
+	rules, err := h.rules.ListByUser(reqCtx, userID)
+	if err != nil {
+		log.Error("list watch rules failed", "err", err)
+		return h.reply(b, msg, "Cannot load your watch rules, try again later.")
+	}
+	if len(rules) >= maxRulesPerUser {
+		return h.reply(b, msg, fmt.Sprintf("You already have %d watch rules, the maximum. Delete one with <code>/unwatch &lt;id&gt;</code>.", maxRulesPerUser))
+	}
+
+	rule, err := h.rules.Add(reqCtx, storage.WatchRule{UserID: userID, Query: payload, CreatedAt: h.now()})
+	if err != nil {
+		log.Error("add watch rule failed", "err", err)
+		return h.reply(b, msg, "Cannot save the watch rule, try again later.")
+	}
+
+	log.Info("watch rule added", "rule_id", rule.ID)
+	return h.reply(b, msg, fmt.Sprintf("🔔 Watching <code>%s</code> as rule #%d.", html.EscapeString(rule.Query), rule.ID))
+}
+
+// Unwatch handles "/unwatch <id>".
+func (h *Handler) Unwatch(b *gotgbot.Bot, ctx *ext.Context) error {
+	log := h.logger(ctx)
+	msg := ctx.EffectiveMessage
+	if !isPrivate(ctx) {
+		return h.reply(b, msg, "Watch rules are personal. Send /unwatch to me in a private chat.")
+	}
+
+	args := ctx.Args()
+	if len(args) < 2 {
+		return h.reply(b, msg, "Usage: <code>/unwatch &lt;id&gt;</code>. Send /watch to see your rules.")
+	}
+	id, ok := parseRuleID(args[1])
+	if !ok {
+		return h.reply(b, msg, "Rule ID must be a number such as <code>3</code>.")
+	}
+
+	removed, err := h.rules.Remove(context.Background(), ctx.EffectiveUser.Id, id)
+	if err != nil {
+		log.Error("remove watch rule failed", "err", err)
+		return h.reply(b, msg, "Cannot delete the watch rule, try again later.")
+	}
+	if !removed {
+		return h.reply(b, msg, fmt.Sprintf("You have no watch rule #%d.", id))
+	}
+
+	log.Info("watch rule removed", "rule_id", id)
+	return h.reply(b, msg, fmt.Sprintf("Deleted watch rule #%d.", id))
+}
+
+func (h *Handler) list(b *gotgbot.Bot, msg *gotgbot.Message, log *logger.Logger, userID int64) error {
+	rules, err := h.rules.ListByUser(context.Background(), userID)
+	if err != nil {
+		log.Error("list watch rules failed", "err", err)
+		return h.reply(b, msg, "Cannot load your watch rules, try again later.")
+	}
+	if len(rules) == 0 {
+		return h.reply(b, msg, "You have no watch rules.\n\n"+usageText)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🔔 Your watch rules:\n")
+	for _, rule := range rules {
+		sb.WriteString(fmt.Sprintf("#%d <code>%s</code>", rule.ID, html.EscapeString(rule.Query)))
+		if rule.MuteWindow != "" {
+			sb.WriteString(fmt.Sprintf(" · muted %s UTC", html.EscapeString(rule.MuteWindow)))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n" + usageText)
+	return h.reply(b, msg, sb.String())
+}
+
+func (h *Handler) mute(b *gotgbot.Bot, msg *gotgbot.Message, log *logger.Logger, userID int64, args []string) error {
+	if len(args) != 2 {
+		return h.reply(b, msg, "Usage: <code>/watch mute &lt;id&gt; HH:MM-HH:MM</code> or <code>/watch mute &lt;id&gt; off</code>")
+	}
+	id, ok := parseRuleID(args[0])
+	if !ok {
+		return h.reply(b, msg, "Rule ID must be a number such as <code>3</code>.")
+	}
+
+	var window string
+	if !strings.EqualFold(args[1], "off") {
+		parsed, err := watchsvc.ParseMuteWindow(args[1])
+		if err != nil {
+			return h.reply(b, msg, "Mute window must look like <code>22:00-07:00</code> (UTC).")
+		}
+		window = parsed.String()
+	}
+
+	updated, err := h.rules.SetMuteWindow(context.Background(), userID, id, window)
+	if err != nil {
+		log.Error("mute watch rule failed", "err", err)
+		return h.reply(b, msg, "Cannot update the watch rule, try again later.")
+	}
+	if !updated {
+		return h.reply(b, msg, fmt.Sprintf("You have no watch rule #%d.", id))
+	}
+
+	log.Info("watch rule mute window updated", "rule_id", id, "mute_window", window)
+	if window == "" {
+		return h.reply(b, msg, fmt.Sprintf("Watch rule #%d is no longer muted.", id))
+	}
+	return h.reply(b, msg, fmt.Sprintf("Watch rule #%d is muted daily %s UTC.", id, window))
+}
+
+func (h *Handler) logger(ctx *ext.Context) *logger.Logger {
+	log := h.log.With("component", "watch")
+	if ctx.EffectiveChat != nil {
+		log = log.With("chat_id", ctx.EffectiveChat.Id)
+	}
+	if ctx.EffectiveUser != nil {
+		log = log.With("user_id", ctx.EffectiveUser.Id, "username", ctx.EffectiveUser.Username)
+	}
+	return log
+}
+
+func (h *Handler) reply(b *gotgbot.Bot, msg *gotgbot.Message, text string) error {
+	_, err := msg.Reply(b, text, &gotgbot.SendMessageOpts{
+		ParseMode: "HTML",
+	})
+	return err
+}
+
+func isPrivate(ctx *ext.Context) bool {
+	return ctx.EffectiveChat != nil && ctx.EffectiveChat.Type == gotgbot.ChatTypePrivate && ctx.EffectiveUser != nil
+}
+
+// commandPayload returns the text after the command, keeping its spacing and quotes.
+func commandPayload(text string) string {
+	_, payload, _ := strings.Cut(strings.TrimSpace(text), " ")
+	return strings.TrimSpace(payload)
+}
+
+func parseRuleID(input string) (int64, bool) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(input, "#"), 10, 64)
+	return id, err == nil && id > 0
+}
@@ -0,0 +1,138 @@
+package watch_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+
+	"twitterx-bot/internal/handlers"
+	"twitterx-bot/internal/handlers/testutil"
+	watchhandler "twitterx-bot/internal/handlers/watch"
+	"twitterx-bot/internal/storage"
+	"twitterx-bot/internal/telegram/tweet"
+	"twitterx-bot/internal/twitterxapi"
+	"twitterx-bot/internal/watch"
+)
+
+const userID = int64(7007)
+
+func privateMessage(updateID, msgID int64, text string) *gotgbot.Update {
+	return &gotgbot.Update{
+		UpdateId: updateID,
+		Message: &gotgbot.Message{
+			MessageId: msgID,
+			Text:      text,
+			Chat:      gotgbot.Chat{Id: userID, Type: "private"},
+			From:      &gotgbot.User{Id: userID, FirstName: "Wanda"},
+		},
+	}
+}
+
+func TestIntegration_WatchCommands(t *testing.T) {
+	store := storage.NewMemory()
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, nil, nil, handlers.WithStorage(store))
+
+	texts := []string{
+		`/watch golang "generic types" -rust`,
+		"/watch /[/",
+		"/watch mute 1 22:00-07:00",
+		"/watch",
+	}
+	for i, text := range texts {
+		if err := dispatcher.ProcessUpdate(bot, privateMessage(int64(i+1), int64(10+i), text), nil); err != nil {
+			t.Fatalf("ProcessUpdate(%q) error = %v", text, err)
+		}
+	}
+
+	calls := mock.GetCalls("sendMessage")
+	if len(calls) != len(texts) {
+		t.Fatalf("sendMessage calls = %d, want %d", len(calls), len(texts))
+	}
+	if text, _ := calls[0].JSONString("text"); !strings.Contains(text, "rule #1") {
+		t.Errorf("add reply = %q", text)
+	}
+	if text, _ := calls[1].JSONString("text"); !strings.Contains(text, "invalid regex") {
+		t.Errorf("invalid rule reply = %q", text)
+	}
+	if text, _ := calls[3].JSONString("text"); !strings.Contains(text, `#1 <code>golang &#34;generic types&#34; -rust</code> · muted 22:00-07:00 UTC`) {
+		t.Errorf("list reply = %q", text)
+	}
+
+	rules, _ := store.Watches().ListByUser(context.Background(), userID)
+	if len(rules) != 1 || rules[0].Query != `golang "generic types" -rust` || rules[0].MuteWindow != "22:00-07:00" {
+		t.Fatalf("rules = %+v", rules)
+	}
+
+	if err := dispatcher.ProcessUpdate(bot, privateMessage(10, 20, "/unwatch 1"), nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+	if rules, _ := store.Watches().ListByUser(context.Background(), userID); len(rules) != 0 {
+		t.Errorf("rules after /unwatch = %+v", rules)
+	}
+}
+
+func TestIntegration_WatchInGroup_AsksForPrivateChat(t *testing.T) {
+	store := storage.NewMemory()
+	bot, mock, dispatcher := testutil.SetupBotAndDispatcherWithOptions(t, nil, nil, handlers.WithStorage(store))
+
+	update := privateMessage(1, 10, "/watch golang")
+	update.Message.Chat = gotgbot.Chat{Id: -100, Type: "supergroup"}
+	if err := dispatcher.ProcessUpdate(bot, update, nil); err != nil {
+		t.Fatalf("ProcessUpdate() error = %v", err)
+	}
+
+	calls := mock.GetCalls("sendMessage")
+	if len(calls) != 1 {
+		t.Fatalf("sendMessage calls = %d, want 1", len(calls))
+	}
+	if text, _ := calls[0].JSONString("text"); !strings.Contains(text, "private chat") {
+		t.Errorf("reply = %q", text)
+	}
+	if rules, _ := store.Watches().List(context.Background()); len(rules) != 0 {
+		t.Errorf("rules = %+v, want none", rules)
+	}
+}
+
+func TestAlerter_SendsNoteAndRenderedTweet(t *testing.T) {
+	bot, mock, _ := testutil.SetupBotAndDispatcher(t, nil)
+	if err := mock.SetResponse("getChatMember", map[string]any{
+		"status": "member",
+		"user":   map[string]any{"id": userID, "is_bot": false, "first_name": "Wanda"},
+	}); err != nil {
+		t.Fatalf("SetResponse() error = %v", err)
+	}
+	alerter := watchhandler.Alerter{Bot: bot, Sender: tweet.Sender{Bot: bot}}
+
+	member, err := alerter.IsMember(context.Background(), -1001234, userID)
+	if err != nil || !member {
+		t.Fatalf("IsMember() = %v, %v; want member", member, err)
+	}
+
+	err = alerter.Alert(context.Background(), watch.Alert{
+		Rule:     storage.WatchRule{ID: 3, UserID: userID, Query: "golang"},
+		Sighting: watch.Sighting{ChatID: -1001234, MessageID: 55, Tweet: &twitterxapi.Tweet{ID: "9", Text: "Golang 2 announced", Author: twitterxapi.Author{Name: "Go", ScreenName: "golang"}}},
+		Chat:     storage.Chat{ID: -1001234, Title: "Gophers"},
+	})
+	if err != nil {
+		t.Fatalf("Alert() error = %v", err)
+	}
+
+	calls := mock.GetCalls("sendMessage")
+	if len(calls) != 2 {
+		t.Fatalf("sendMessage calls = %d, want note and tweet", len(calls))
+	}
+	for _, call := range calls {
+		if chatID, _ := call.JSONInt64("chat_id"); chatID != userID {
+			t.Errorf("chat_id = %d, want the watcher %d", chatID, userID)
+		}
+	}
+	note, _ := calls[0].JSONString("text")
+	if !strings.Contains(note, "rule #3") || !strings.Contains(note, `<a href="https://t.me/c/1234/55">Gophers</a>`) {
+		t.Errorf("note = %q", note)
+	}
+	if text, _ := calls[1].JSONString("text"); !strings.Contains(text, "Golang 2 announced") {
+		t.Errorf("tweet text = %q", text)
+	}
+}
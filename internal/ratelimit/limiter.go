@@ -0,0 +1,133 @@
+// Package ratelimit implements token-bucket rate limiting keyed by user, chat or any other ID.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limit allows bursts of Burst events and refills Burst tokens every Per.
+// The zero value disables limiting.
+type Limit struct {
+	Burst int
+	Per   time.Duration
+}
+
+// Enabled reports whether the limit restricts anything.
+func (l Limit) Enabled() bool {
+	return l.Burst > 0 && l.Per > 0
+}
+
+// Limiter keeps one token bucket per key. A nil or disabled Limiter allows everything.
+type Limiter struct {
+	limit Limit
+	now   func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[int64]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// New creates a limiter applying limit to every key.
+func New(limit Limit) *Limiter {
+	return &Limiter{limit: limit, now: time.Now, buckets: make(map[int64]*bucket)}
+}
+
+// Allow takes a token from the bucket of key and reports whether one was available.
+func (l *Limiter) Allow(key int64) bool {
+	if l == nil || !l.limit.Enabled() {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.limit.Burst), updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = l.refill(b, now)
+	b.updated = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Refund returns a token taken by Allow to the bucket of key, e.g. when another limit
+// refused the event after all.
+func (l *Limiter) Refund(key int64) {
+	if l == nil || !l.limit.Enabled() {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.buckets[key]
+	if !ok {
+		return
+	}
+	b.tokens = min(b.tokens+1, float64(l.limit.Burst))
+}
+
+// Reserve takes n tokens from the bucket of key and returns how long the caller must
+// wait before acting on them; 0 when they are available now. Unlike Allow it never
+// refuses: the bucket goes into debt that later callers wait out.
+func (l *Limiter) Reserve(key int64, n int) time.Duration {
+	if l == nil || !l.limit.Enabled() {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.limit.Burst), updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = l.refill(b, now) - float64(n)
+	b.updated = now
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens * float64(l.limit.Per) / float64(l.limit.Burst))
+}
+
+// refill returns the tokens of b at now, capped at the bucket capacity.
+func (l *Limiter) refill(b *bucket, now time.Time) float64 {
+	elapsed := now.Sub(b.updated)
+	if elapsed <= 0 {
+		return b.tokens
+	}
+	tokens := b.tokens + float64(l.limit.Burst)*elapsed.Seconds()/l.limit.Per.Seconds()
+	if tokens > float64(l.limit.Burst) {
+		return float64(l.limit.Burst)
+	}
+	return tokens
+}
+
+// sweep drops buckets that refilled completely; they behave like new ones.
+// It runs at most once per Per so Allow stays cheap.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.limit.Per {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if l.refill(b, now) >= float64(l.limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestLimiter(limit Limit) (*Limiter, *time.Time) {
+	now := time.UnixMilli(1_700_000_000_000)
+	l := New(limit)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestLimiter_BurstAndRefill(t *testing.T) {
+	l, now := newTestLimiter(Limit{Burst: 3, Per: 3 * time.Second})
+
+	for i := 0; i < 3; i++ {
+		if !l.Allow(1) {
+			t.Fatalf("Allow() #%d = false, want burst of 3", i+1)
+		}
+	}
+	if l.Allow(1) {
+		t.Fatal("Allow() = true after the burst")
+	}
+	if !l.Allow(2) {
+		t.Error("Allow() = false for another key")
+	}
+
+	*now = now.Add(time.Second)
+	if !l.Allow(1) {
+		t.Error("Allow() = false after one token refilled")
+	}
+	if l.Allow(1) {
+		t.Error("Allow() = true with the refilled token spent")
+	}
+
+	*now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		if !l.Allow(1) {
+			t.Fatalf("Allow() #%d = false after a long pause, want a full bucket", i+1)
+		}
+	}
+	if l.Allow(1) {
+		t.Error("Allow() = true beyond capacity after a long pause")
+	}
+}
+
+func TestLimiter_Disabled(t *testing.T) {
+	var nilLimiter *Limiter
+	if !nilLimiter.Allow(1) {
+		t.Error("nil Limiter.Allow() = false")
+	}
+	l := New(Limit{})
+	for i := 0; i < 100; i++ {
+		if !l.Allow(1) {
+			t.Fatal("disabled Limiter.Allow() = false")
+		}
+	}
+}
+
+func TestLimiter_SweepsFullBuckets(t *testing.T) {
+	l, now := newTestLimiter(Limit{Burst: 1, Per: time.Second})
+	l.Allow(1)
+	l.Allow(2)
+
+	*now = now.Add(2 * time.Second)
+	l.Allow(3)
+
+	if len(l.buckets) != 1 {
+		t.Errorf("buckets = %d, want idle buckets swept", len(l.buckets))
+	}
+}
+
+func TestLimiter_Reserve(t *testing.T) {
+	l, now := newTestLimiter(Limit{Burst: 2, Per: 2 * time.Second})
+
+	if wait := l.Reserve(1, 2); wait != 0 {
+		t.Errorf("Reserve() = %v, want 0 within the burst", wait)
+	}
+	if wait := l.Reserve(1, 1); wait != time.Second {
+		t.Errorf("Reserve() = %v, want 1s for the next token", wait)
+	}
+	if wait := l.Reserve(1, 1); wait != 2*time.Second {
+		t.Errorf("Reserve() = %v, want 2s queued behind the previous one", wait)
+	}
+	if l.Allow(1) {
+		t.Error("Allow() = true while the bucket is in debt")
+	}
+
+	*now = now.Add(3 * time.Second)
+	if wait := l.Reserve(1, 1); wait != 0 {
+		t.Errorf("Reserve() = %v, want 0 after the debt is repaid", wait)
+	}
+}
+
+func TestLimiter_Refund(t *testing.T) {
+	l, _ := newTestLimiter(Limit{Burst: 1, Per: time.Hour})
+	l.Refund(1) // unknown keys have a full bucket already
+	if !l.Allow(1) || l.Allow(1) {
+		t.Fatal("Allow() should take the only token")
+	}
+	l.Refund(1)
+	if !l.Allow(1) {
+		t.Fatal("Allow() after Refund() = false, want the token back")
+	}
+	l.Refund(1)
+	l.Refund(1)
+	if !l.Allow(1) || l.Allow(1) {
+		t.Fatal("Refund() should not grow the bucket past its burst")
+	}
+}
@@ -0,0 +1,32 @@
+package stats
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"twitterx-bot/internal/storage"
+)
+
+// WriteCSV writes events as CSV with a header row; times are RFC 3339 in UTC.
+func WriteCSV(w io.Writer, events []storage.Event) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"time", "kind", "chat_id", "user_id", "username", "author"}); err != nil {
+		return err
+	}
+	for _, event := range events {
+		if err := cw.Write([]string{
+			event.CreatedAt.UTC().Format(time.RFC3339),
+			string(event.Kind),
+			strconv.FormatInt(event.ChatID, 10),
+			strconv.FormatInt(event.UserID, 10),
+			event.Username,
+			event.Author,
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,25 @@
+package stats
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"twitterx-bot/internal/storage"
+)
+
+func TestWriteCSV(t *testing.T) {
+	var sb strings.Builder
+	err := WriteCSV(&sb, []storage.Event{
+		{Kind: storage.EventTweetSent, ChatID: -100, UserID: 7, Username: "Alice, Bob", Author: "jack", CreatedAt: time.Date(2024, 3, 10, 15, 4, 5, 0, time.UTC)},
+	})
+	if err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+
+	want := "time,kind,chat_id,user_id,username,author\n" +
+		"2024-03-10T15:04:05Z,tweet,-100,7,\"Alice, Bob\",jack\n"
+	if sb.String() != want {
+		t.Errorf("WriteCSV() =\n%s\nwant\n%s", sb.String(), want)
+	}
+}
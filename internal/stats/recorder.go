@@ -0,0 +1,37 @@
+// Package stats records bot usage events and summarizes them into reports.
+package stats
+
+import (
+	"context"
+	"time"
+
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/storage"
+)
+
+// Recorder stores usage events. A nil *Recorder discards them, so handlers
+// can record unconditionally.
+type Recorder struct {
+	log    *logger.Logger
+	events storage.EventRepository
+	now    func() time.Time
+}
+
+// NewRecorder creates a recorder writing to the given repository.
+func NewRecorder(log *logger.Logger, events storage.EventRepository) *Recorder {
+	return &Recorder{log: log, events: events, now: time.Now}
+}
+
+// Record stores the event, stamping it with the current time when CreatedAt is zero.
+// Failures are logged and otherwise ignored: statistics must not break replies.
+func (r *Recorder) Record(ctx context.Context, event storage.Event) {
+	if r == nil {
+		return
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = r.now()
+	}
+	if err := r.events.Add(ctx, event); err != nil {
+		r.log.With("component", "stats", "kind", event.Kind, "chat_id", event.ChatID).Warn("record event failed", "err", err)
+	}
+}
@@ -0,0 +1,140 @@
+package stats
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"twitterx-bot/internal/storage"
+)
+
+// Count is a ranked entry of a report.
+type Count struct {
+	ID    int64
+	Name  string
+	Count int
+}
+
+// Day is the number of shared tweets on a calendar day.
+type Day struct {
+	Date  time.Time
+	Count int
+}
+
+// Report summarizes usage events.
+type Report struct {
+	Totals     map[storage.EventKind]int
+	TopUsers   []Count
+	TopAuthors []Count
+	TopChats   []Count
+	// Daily covers the requested number of days, oldest first, including empty ones.
+	Daily []Day
+}
+
+// Shared is the number of tweets delivered by any means.
+func (r Report) Shared() int {
+	return r.Totals[storage.EventTweetSent] + r.Totals[storage.EventChainSent] + r.Totals[storage.EventInlineChosen]
+}
+
+// Summarize builds a report from events. Rankings keep at most top entries
+// and count only delivered tweets; Daily ends on the day of now.
+func Summarize(events []storage.Event, now time.Time, days, top int) Report {
+	report := Report{Totals: make(map[storage.EventKind]int)}
+	users := newCounter()
+	authors := newCounter()
+	chats := newCounter()
+
+	today := startOfDay(now)
+	first := today.AddDate(0, 0, -(days - 1))
+	daily := make([]Day, days)
+	dayIndex := make(map[time.Time]int, days)
+	for i := range daily {
+		daily[i].Date = first.AddDate(0, 0, i)
+		dayIndex[daily[i].Date] = i
+	}
+
+	for _, event := range events {
+		report.Totals[event.Kind]++
+		if !isShare(event.Kind) {
+			continue
+		}
+		if event.UserID != 0 {
+			users.add(event.UserID, event.Username)
+		}
+		if author := strings.TrimPrefix(strings.TrimSpace(event.Author), "@"); author != "" {
+			authors.add(0, "@"+strings.ToLower(author))
+		}
+		if event.ChatID != 0 {
+			chats.add(event.ChatID, "")
+		}
+		if i, ok := dayIndex[startOfDay(event.CreatedAt.In(now.Location()))]; ok {
+			daily[i].Count++
+		}
+	}
+
+	report.TopUsers = users.top(top)
+	report.TopAuthors = authors.top(top)
+	report.TopChats = chats.top(top)
+	report.Daily = daily
+	return report
+}
+
+func isShare(kind storage.EventKind) bool {
+	return kind == storage.EventTweetSent || kind == storage.EventChainSent || kind == storage.EventInlineChosen
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// counter counts entries by ID, or by name when the ID is 0.
+type counter struct {
+	entries map[counterKey]*Count
+}
+
+type counterKey struct {
+	id   int64
+	name string
+}
+
+func newCounter() *counter {
+	return &counter{entries: make(map[counterKey]*Count)}
+}
+
+func (c *counter) add(id int64, name string) {
+	key := counterKey{id: id}
+	if id == 0 {
+		key.name = name
+	}
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &Count{ID: id}
+		c.entries[key] = entry
+	}
+	// the latest known name wins, users rename themselves
+	if name != "" {
+		entry.Name = name
+	}
+	entry.Count++
+}
+
+func (c *counter) top(n int) []Count {
+	counts := make([]Count, 0, len(c.entries))
+	for _, entry := range c.entries {
+		counts = append(counts, *entry)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		if counts[i].Name != counts[j].Name {
+			return counts[i].Name < counts[j].Name
+		}
+		return counts[i].ID < counts[j].ID
+	})
+	if n > 0 && len(counts) > n {
+		counts = counts[:n]
+	}
+	return counts
+}
@@ -0,0 +1,70 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"twitterx-bot/internal/storage"
+)
+
+func TestSummarize(t *testing.T) {
+	now := time.Date(2024, 3, 10, 15, 0, 0, 0, time.UTC)
+	events := []storage.Event{
+		{Kind: storage.EventTweetSent, ChatID: 1, UserID: 7, Username: "Alice", Author: "Jack", CreatedAt: now.AddDate(0, 0, -2)},
+		{Kind: storage.EventTweetSent, ChatID: 1, UserID: 7, Username: "Alice B", Author: "jack", CreatedAt: now.Add(-time.Hour)},
+		{Kind: storage.EventChainSent, ChatID: 2, UserID: 8, Username: "Bob", Author: "elon", CreatedAt: now},
+		{Kind: storage.EventInlineChosen, UserID: 8, Username: "Bob", Author: "@jack", CreatedAt: now.AddDate(0, 0, -10)},
+		{Kind: storage.EventFailed, ChatID: 1, UserID: 9, Username: "Eve", Author: "ghost", CreatedAt: now},
+		{Kind: storage.EventDeleted, ChatID: 1, UserID: 7, CreatedAt: now},
+	}
+
+	report := Summarize(events, now, 3, 10)
+
+	if report.Shared() != 4 {
+		t.Errorf("Shared() = %d, want 4", report.Shared())
+	}
+	if report.Totals[storage.EventFailed] != 1 || report.Totals[storage.EventDeleted] != 1 {
+		t.Errorf("Totals = %v, want one failure and one deletion", report.Totals)
+	}
+
+	wantUsers := []Count{{ID: 7, Name: "Alice B", Count: 2}, {ID: 8, Name: "Bob", Count: 2}}
+	if len(report.TopUsers) != len(wantUsers) {
+		t.Fatalf("TopUsers = %+v, want %+v", report.TopUsers, wantUsers)
+	}
+	for i, want := range wantUsers {
+		if report.TopUsers[i] != want {
+			t.Errorf("TopUsers[%d] = %+v, want %+v", i, report.TopUsers[i], want)
+		}
+	}
+
+	if len(report.TopAuthors) != 2 || report.TopAuthors[0] != (Count{Name: "@jack", Count: 3}) {
+		t.Errorf("TopAuthors = %+v, want @jack first with 3", report.TopAuthors)
+	}
+	if len(report.TopChats) != 2 || report.TopChats[0] != (Count{ID: 1, Count: 2}) {
+		t.Errorf("TopChats = %+v, want chat 1 first with 2", report.TopChats)
+	}
+
+	wantDaily := []int{1, 0, 2}
+	if len(report.Daily) != len(wantDaily) {
+		t.Fatalf("Daily = %+v, want %d days", report.Daily, len(wantDaily))
+	}
+	for i, want := range wantDaily {
+		if report.Daily[i].Count != want {
+			t.Errorf("Daily[%d] = %+v, want %d", i, report.Daily[i], want)
+		}
+	}
+	if !report.Daily[2].Date.Equal(time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("last day = %v, want today", report.Daily[2].Date)
+	}
+}
+
+func TestSummarize_TopLimit(t *testing.T) {
+	now := time.Now()
+	var events []storage.Event
+	for id := int64(1); id <= 5; id++ {
+		events = append(events, storage.Event{Kind: storage.EventTweetSent, UserID: id, CreatedAt: now})
+	}
+	if got := Summarize(events, now, 1, 3).TopUsers; len(got) != 3 {
+		t.Errorf("TopUsers len = %d, want 3", len(got))
+	}
+}
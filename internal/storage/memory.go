@@ -0,0 +1,558 @@
+package storage
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"twitterx-bot/internal/chatsettings"
+)
+
+// Memory keeps the bot state in process memory; it is lost on restart.
+type Memory struct {
+	mu       sync.RWMutex
+	chats    map[int64]Chat
+	settings *chatsettings.MemoryStore
+	history  []HistoryEntry
+	events   []Event
+	access   map[int64]ChatAccess
+	bans     map[int64]Ban
+	follows  map[int64]map[string]Follow
+	cursors  map[string]FollowCursor
+	watches  []WatchRule
+	digests  map[int64]Digest
+	replies  map[replyKey][]Reply
+	marks    []Bookmark
+	nextID   int64
+}
+
+// replyKey identifies a user message.
+type replyKey struct {
+	chatID, messageID int64
+}
+
+// NewMemory creates an empty in-memory storage.
+func NewMemory() *Memory {
+	return &Memory{
+		chats:    make(map[int64]Chat),
+		settings: chatsettings.NewMemoryStore(),
+		access:   make(map[int64]ChatAccess),
+		bans:     make(map[int64]Ban),
+		follows:  make(map[int64]map[string]Follow),
+		cursors:  make(map[string]FollowCursor),
+		digests:  make(map[int64]Digest),
+		replies:  make(map[replyKey][]Reply),
+	}
+}
+
+func (m *Memory) Chats() ChatRepository         { return memoryChats{m} }
+func (m *Memory) Settings() SettingsRepository  { return m.settings }
+func (m *Memory) History() HistoryRepository    { return memoryHistory{m} }
+func (m *Memory) Events() EventRepository       { return memoryEvents{m} }
+func (m *Memory) Access() AccessRepository      { return memoryAccess{m} }
+func (m *Memory) Follows() FollowRepository     { return memoryFollows{m} }
+func (m *Memory) Watches() WatchRepository      { return memoryWatches{m} }
+func (m *Memory) Digests() DigestRepository     { return memoryDigests{m} }
+func (m *Memory) Replies() ReplyRepository      { return memoryReplies{m} }
+func (m *Memory) Bookmarks() BookmarkRepository { return memoryBookmarks{m} }
+func (m *Memory) Close() error                  { return nil }
+
+type memoryChats struct{ m *Memory }
+
+func (r memoryChats) Upsert(_ context.Context, chat Chat) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	if existing, ok := r.m.chats[chat.ID]; ok && !existing.FirstSeen.IsZero() {
+		chat.FirstSeen = existing.FirstSeen
+	}
+	if chat.FirstSeen.IsZero() {
+		chat.FirstSeen = chat.LastSeen
+	}
+	r.m.chats[chat.ID] = chat
+	return nil
+}
+
+func (r memoryChats) Get(_ context.Context, id int64) (Chat, bool, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	chat, ok := r.m.chats[id]
+	return chat, ok, nil
+}
+
+func (r memoryChats) List(_ context.Context) ([]Chat, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	chats := make([]Chat, 0, len(r.m.chats))
+	for _, chat := range r.m.chats {
+		chats = append(chats, chat)
+	}
+	sort.Slice(chats, func(i, j int) bool { return chats[i].ID < chats[j].ID })
+	return chats, nil
+}
+
+type memoryHistory struct{ m *Memory }
+
+func (r memoryHistory) Add(_ context.Context, entry HistoryEntry) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	r.m.nextID++
+	entry.ID = r.m.nextID
+	r.m.history = append(r.m.history, entry)
+	return nil
+}
+
+func (r memoryHistory) Latest(_ context.Context, chatID int64, tweetID string) (HistoryEntry, bool, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	var latest HistoryEntry
+	found := false
+	for _, entry := range r.m.history {
+		if entry.ChatID != chatID || entry.TweetID != tweetID {
+			continue
+		}
+		if !found || !entry.SentAt.Before(latest.SentAt) {
+			latest = entry
+			found = true
+		}
+	}
+	return latest, found, nil
+}
+
+func (r memoryHistory) List(_ context.Context, filter HistoryFilter) ([]HistoryEntry, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	var entries []HistoryEntry
+	for _, entry := range r.m.history {
+		if filter.matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].SentAt.Equal(entries[j].SentAt) {
+			return entries[i].ID > entries[j].ID
+		}
+		return entries[i].SentAt.After(entries[j].SentAt)
+	})
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[:filter.Limit]
+	}
+	return entries, nil
+}
+
+func (r memoryHistory) Prune(_ context.Context, before time.Time) (int, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	kept := r.m.history[:0]
+	for _, entry := range r.m.history {
+		if !entry.SentAt.Before(before) {
+			kept = append(kept, entry)
+		}
+	}
+	pruned := len(r.m.history) - len(kept)
+	r.m.history = kept
+	return pruned, nil
+}
+
+type memoryEvents struct{ m *Memory }
+
+func (r memoryEvents) Add(_ context.Context, event Event) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	r.m.nextID++
+	event.ID = r.m.nextID
+	r.m.events = append(r.m.events, event)
+	return nil
+}
+
+func (r memoryEvents) List(_ context.Context, filter EventFilter) ([]Event, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	var events []Event
+	for _, event := range r.m.events {
+		if filter.matches(event) {
+			events = append(events, event)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].CreatedAt.Equal(events[j].CreatedAt) {
+			return events[i].ID < events[j].ID
+		}
+		return events[i].CreatedAt.Before(events[j].CreatedAt)
+	})
+	if filter.Limit > 0 && len(events) > filter.Limit {
+		events = events[:filter.Limit]
+	}
+	return events, nil
+}
+
+func (r memoryEvents) Prune(_ context.Context, before time.Time) (int, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	kept := r.m.events[:0]
+	for _, event := range r.m.events {
+		if !event.CreatedAt.Before(before) {
+			kept = append(kept, event)
+		}
+	}
+	pruned := len(r.m.events) - len(kept)
+	r.m.events = kept
+	return pruned, nil
+}
+
+type memoryAccess struct{ m *Memory }
+
+func (r memoryAccess) SetChat(_ context.Context, access ChatAccess) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	r.m.access[access.ChatID] = access
+	return nil
+}
+
+func (r memoryAccess) Chat(_ context.Context, chatID int64) (ChatAccess, bool, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	access, ok := r.m.access[chatID]
+	return access, ok, nil
+}
+
+func (r memoryAccess) ListChats(_ context.Context) ([]ChatAccess, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	list := make([]ChatAccess, 0, len(r.m.access))
+	for _, access := range r.m.access {
+		list = append(list, access)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ChatID < list[j].ChatID })
+	return list, nil
+}
+
+func (r memoryAccess) Ban(_ context.Context, ban Ban) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	r.m.bans[ban.UserID] = ban
+	return nil
+}
+
+func (r memoryAccess) Unban(_ context.Context, userID int64) (bool, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	_, ok := r.m.bans[userID]
+	delete(r.m.bans, userID)
+	return ok, nil
+}
+
+func (r memoryAccess) Banned(_ context.Context, userID int64) (Ban, bool, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	ban, ok := r.m.bans[userID]
+	return ban, ok, nil
+}
+
+type memoryFollows struct{ m *Memory }
+
+func (r memoryFollows) Add(_ context.Context, follow Follow) (bool, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	chat := r.m.follows[follow.ChatID]
+	if chat == nil {
+		chat = make(map[string]Follow)
+		r.m.follows[follow.ChatID] = chat
+	}
+	if _, ok := chat[follow.Username]; ok {
+		return false, nil
+	}
+	chat[follow.Username] = follow
+	return true, nil
+}
+
+func (r memoryFollows) Remove(_ context.Context, chatID int64, username string) (bool, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	chat := r.m.follows[chatID]
+	if _, ok := chat[username]; !ok {
+		return false, nil
+	}
+	delete(chat, username)
+	if len(chat) == 0 {
+		delete(r.m.follows, chatID)
+	}
+	return true, nil
+}
+
+func (r memoryFollows) ListByChat(_ context.Context, chatID int64) ([]Follow, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	var list []Follow
+	for _, follow := range r.m.follows[chatID] {
+		list = append(list, follow)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Username < list[j].Username })
+	return list, nil
+}
+
+func (r memoryFollows) Followers(_ context.Context, username string) ([]Follow, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	var list []Follow
+	for _, chat := range r.m.follows {
+		if follow, ok := chat[username]; ok {
+			list = append(list, follow)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ChatID < list[j].ChatID })
+	return list, nil
+}
+
+func (r memoryFollows) Accounts(_ context.Context) ([]string, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	seen := make(map[string]struct{})
+	var list []string
+	for _, chat := range r.m.follows {
+		for username := range chat {
+			if _, ok := seen[username]; ok {
+				continue
+			}
+			seen[username] = struct{}{}
+			list = append(list, username)
+		}
+	}
+	sort.Strings(list)
+	return list, nil
+}
+
+func (r memoryFollows) Cursor(_ context.Context, username string) (FollowCursor, bool, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	cursor, ok := r.m.cursors[username]
+	return cursor, ok, nil
+}
+
+func (r memoryFollows) SetCursor(_ context.Context, cursor FollowCursor) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	r.m.cursors[cursor.Username] = cursor
+	return nil
+}
+
+type memoryWatches struct{ m *Memory }
+
+func (r memoryWatches) Add(_ context.Context, rule WatchRule) (WatchRule, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	r.m.nextID++
+	rule.ID = r.m.nextID
+	r.m.watches = append(r.m.watches, rule)
+	return rule, nil
+}
+
+func (r memoryWatches) Remove(_ context.Context, userID, id int64) (bool, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	for i, rule := range r.m.watches {
+		if rule.ID == id && rule.UserID == userID {
+			r.m.watches = append(r.m.watches[:i], r.m.watches[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r memoryWatches) SetMuteWindow(_ context.Context, userID, id int64, window string) (bool, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	for i, rule := range r.m.watches {
+		if rule.ID == id && rule.UserID == userID {
+			r.m.watches[i].MuteWindow = window
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r memoryWatches) ListByUser(_ context.Context, userID int64) ([]WatchRule, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	var list []WatchRule
+	for _, rule := range r.m.watches {
+		if rule.UserID == userID {
+			list = append(list, rule)
+		}
+	}
+	return list, nil
+}
+
+func (r memoryWatches) List(_ context.Context) ([]WatchRule, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	return append([]WatchRule(nil), r.m.watches...), nil
+}
+
+type memoryDigests struct{ m *Memory }
+
+func (r memoryDigests) Set(_ context.Context, digest Digest) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	r.m.digests[digest.ChatID] = digest
+	return nil
+}
+
+func (r memoryDigests) Get(_ context.Context, chatID int64) (Digest, bool, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	digest, ok := r.m.digests[chatID]
+	return digest, ok, nil
+}
+
+func (r memoryDigests) Delete(_ context.Context, chatID int64) (bool, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	_, ok := r.m.digests[chatID]
+	delete(r.m.digests, chatID)
+	return ok, nil
+}
+
+func (r memoryDigests) List(_ context.Context) ([]Digest, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	list := make([]Digest, 0, len(r.m.digests))
+	for _, digest := range r.m.digests {
+		list = append(list, digest)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ChatID < list[j].ChatID })
+	return list, nil
+}
+
+func (r memoryDigests) MarkSent(_ context.Context, chatID int64, at time.Time) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	if digest, ok := r.m.digests[chatID]; ok {
+		digest.LastSentAt = at
+		r.m.digests[chatID] = digest
+	}
+	return nil
+}
+
+type memoryReplies struct{ m *Memory }
+
+func (r memoryReplies) Add(_ context.Context, reply Reply) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	key := replyKey{reply.ChatID, reply.SourceMessageID}
+	r.m.replies[key] = append(r.m.replies[key], reply)
+	return nil
+}
+
+func (r memoryReplies) List(_ context.Context, chatID, sourceMessageID int64) ([]Reply, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	return append([]Reply(nil), r.m.replies[replyKey{chatID, sourceMessageID}]...), nil
+}
+
+func (r memoryReplies) Remove(_ context.Context, chatID, sourceMessageID int64, tweetID string) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	key := replyKey{chatID, sourceMessageID}
+	kept := r.m.replies[key][:0]
+	for _, reply := range r.m.replies[key] {
+		if reply.TweetID != tweetID {
+			kept = append(kept, reply)
+		}
+	}
+	if len(kept) == 0 {
+		delete(r.m.replies, key)
+		return nil
+	}
+	r.m.replies[key] = kept
+	return nil
+}
+
+func (r memoryReplies) Prune(_ context.Context, before time.Time) (int, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	pruned := 0
+	for key, replies := range r.m.replies {
+		kept := replies[:0]
+		for _, reply := range replies {
+			if reply.SentAt.Before(before) {
+				pruned++
+				continue
+			}
+			kept = append(kept, reply)
+		}
+		if len(kept) == 0 {
+			delete(r.m.replies, key)
+		} else {
+			r.m.replies[key] = kept
+		}
+	}
+	return pruned, nil
+}
+
+type memoryBookmarks struct{ m *Memory }
+
+func (r memoryBookmarks) Add(_ context.Context, bookmark Bookmark) (Bookmark, bool, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	for _, existing := range r.m.marks {
+		if existing.UserID == bookmark.UserID && existing.TweetID == bookmark.TweetID {
+			return existing, false, nil
+		}
+	}
+	r.m.nextID++
+	bookmark.ID = r.m.nextID
+	r.m.marks = append(r.m.marks, bookmark)
+	return bookmark, true, nil
+}
+
+func (r memoryBookmarks) Get(_ context.Context, userID, id int64) (Bookmark, bool, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	for _, bookmark := range r.m.marks {
+		if bookmark.ID == id && bookmark.UserID == userID {
+			return bookmark, true, nil
+		}
+	}
+	return Bookmark{}, false, nil
+}
+
+func (r memoryBookmarks) Remove(_ context.Context, userID, id int64) (bool, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	for i, bookmark := range r.m.marks {
+		if bookmark.ID == id && bookmark.UserID == userID {
+			r.m.marks = append(r.m.marks[:i], r.m.marks[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r memoryBookmarks) List(_ context.Context, filter BookmarkFilter) ([]Bookmark, int, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	search := strings.ToLower(filter.Search)
+	var list []Bookmark
+	for _, bookmark := range r.m.marks {
+		if bookmark.UserID != filter.UserID || !strings.Contains(bookmarkSearchText(bookmark), search) {
+			continue
+		}
+		list = append(list, bookmark)
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].ID > list[j].ID
+		}
+		return list[i].CreatedAt.After(list[j].CreatedAt)
+	})
+	total := len(list)
+	if filter.Offset > 0 {
+		list = list[min(filter.Offset, total):]
+	}
+	if filter.Limit > 0 && len(list) > filter.Limit {
+		list = list[:filter.Limit]
+	}
+	return list, total, nil
+}
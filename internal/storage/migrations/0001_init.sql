@@ -0,0 +1,29 @@
+CREATE TABLE chats (
+    id         INTEGER PRIMARY KEY,
+    type       TEXT    NOT NULL DEFAULT '',
+    title      TEXT    NOT NULL DEFAULT '',
+    username   TEXT    NOT NULL DEFAULT '',
+    first_seen INTEGER NOT NULL,
+    last_seen  INTEGER NOT NULL
+);
+
+CREATE TABLE chat_settings (
+    chat_id    INTEGER PRIMARY KEY,
+    data       TEXT    NOT NULL,
+    updated_at INTEGER NOT NULL
+);
+
+CREATE TABLE history (
+    id                INTEGER PRIMARY KEY AUTOINCREMENT,
+    chat_id           INTEGER NOT NULL,
+    message_id        INTEGER NOT NULL,
+    source_message_id INTEGER NOT NULL DEFAULT 0,
+    tweet_id          TEXT    NOT NULL,
+    author            TEXT    NOT NULL DEFAULT '',
+    user_id           INTEGER NOT NULL DEFAULT 0,
+    requester         TEXT    NOT NULL DEFAULT '',
+    sent_at           INTEGER NOT NULL
+);
+
+CREATE INDEX history_chat_tweet ON history (chat_id, tweet_id, sent_at);
+CREATE INDEX history_sent_at ON history (sent_at);
@@ -0,0 +1,12 @@
+CREATE TABLE events (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind       TEXT    NOT NULL,
+    chat_id    INTEGER NOT NULL DEFAULT 0,
+    user_id    INTEGER NOT NULL DEFAULT 0,
+    username   TEXT    NOT NULL DEFAULT '',
+    author     TEXT    NOT NULL DEFAULT '',
+    created_at INTEGER NOT NULL
+);
+
+CREATE INDEX events_chat_created ON events (chat_id, created_at);
+CREATE INDEX events_created ON events (created_at);
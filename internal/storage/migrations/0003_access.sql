@@ -0,0 +1,15 @@
+ALTER TABLE chats ADD COLUMN left_chat INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE chat_access (
+    chat_id    INTEGER PRIMARY KEY,
+    allowed    INTEGER NOT NULL,
+    updated_by INTEGER NOT NULL DEFAULT 0,
+    updated_at INTEGER NOT NULL
+);
+
+CREATE TABLE bans (
+    user_id   INTEGER PRIMARY KEY,
+    reason    TEXT    NOT NULL DEFAULT '',
+    banned_by INTEGER NOT NULL DEFAULT 0,
+    banned_at INTEGER NOT NULL
+);
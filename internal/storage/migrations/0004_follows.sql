@@ -0,0 +1,15 @@
+CREATE TABLE follows (
+    chat_id    INTEGER NOT NULL,
+    username   TEXT    NOT NULL,
+    added_by   INTEGER NOT NULL DEFAULT 0,
+    created_at INTEGER NOT NULL,
+    PRIMARY KEY (chat_id, username)
+);
+
+CREATE INDEX follows_username ON follows (username);
+
+CREATE TABLE follow_cursors (
+    username      TEXT PRIMARY KEY,
+    last_tweet_id TEXT    NOT NULL DEFAULT '',
+    checked_at    INTEGER NOT NULL
+);
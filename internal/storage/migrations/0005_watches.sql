@@ -0,0 +1,9 @@
+CREATE TABLE watch_rules (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id     INTEGER NOT NULL,
+    query       TEXT    NOT NULL,
+    mute_window TEXT    NOT NULL DEFAULT '',
+    created_at  INTEGER NOT NULL
+);
+
+CREATE INDEX watch_rules_user ON watch_rules (user_id);
@@ -0,0 +1,10 @@
+CREATE TABLE digests (
+    chat_id      INTEGER PRIMARY KEY,
+    frequency    TEXT    NOT NULL,
+    weekday      INTEGER NOT NULL DEFAULT 0,
+    minute       INTEGER NOT NULL,
+    article      INTEGER NOT NULL DEFAULT 0,
+    updated_by   INTEGER NOT NULL DEFAULT 0,
+    updated_at   INTEGER NOT NULL,
+    last_sent_at INTEGER NOT NULL DEFAULT 0
+);
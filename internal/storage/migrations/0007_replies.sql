@@ -0,0 +1,8 @@
+CREATE TABLE replies (
+    chat_id           INTEGER NOT NULL,
+    source_message_id INTEGER NOT NULL,
+    tweet_id          TEXT    NOT NULL,
+    message_id        INTEGER NOT NULL,
+    sent_at           INTEGER NOT NULL,
+    PRIMARY KEY (chat_id, source_message_id, message_id)
+);
@@ -0,0 +1,10 @@
+CREATE TABLE bookmarks (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id    INTEGER NOT NULL,
+    tweet_id   TEXT    NOT NULL,
+    author     TEXT    NOT NULL,
+    search     TEXT    NOT NULL,
+    tweet      TEXT    NOT NULL,
+    created_at INTEGER NOT NULL,
+    UNIQUE (user_id, tweet_id)
+);
@@ -0,0 +1 @@
+CREATE INDEX replies_sent_at ON replies (sent_at);
@@ -0,0 +1,881 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite" // pure-Go SQLite driver
+
+	"twitterx-bot/internal/chatsettings"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+// SQLite keeps the bot state in an SQLite database file.
+type SQLite struct {
+	db *sql.DB
+}
+
+// OpenSQLite opens (creating if needed) the database at path and applies pending migrations.
+func OpenSQLite(ctx context.Context, path string) (*SQLite, error) {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, fmt.Errorf("create storage dir: %w", err)
+		}
+	}
+
+	dsn := "file:" + path + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open storage: %w", err)
+	}
+	// SQLite allows a single writer; one connection avoids SQLITE_BUSY between our own goroutines
+	db.SetMaxOpenConns(1)
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("open storage: %w", err)
+	}
+	if err := migrate(ctx, db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate storage: %w", err)
+	}
+	return &SQLite{db: db}, nil
+}
+
+func (s *SQLite) Chats() ChatRepository         { return sqliteChats{s.db} }
+func (s *SQLite) Settings() SettingsRepository  { return sqliteSettings{s.db} }
+func (s *SQLite) History() HistoryRepository    { return sqliteHistory{s.db} }
+func (s *SQLite) Events() EventRepository       { return sqliteEvents{s.db} }
+func (s *SQLite) Access() AccessRepository      { return sqliteAccess{s.db} }
+func (s *SQLite) Follows() FollowRepository     { return sqliteFollows{s.db} }
+func (s *SQLite) Watches() WatchRepository      { return sqliteWatches{s.db} }
+func (s *SQLite) Digests() DigestRepository     { return sqliteDigests{s.db} }
+func (s *SQLite) Replies() ReplyRepository      { return sqliteReplies{s.db} }
+func (s *SQLite) Bookmarks() BookmarkRepository { return sqliteBookmarks{s.db} }
+func (s *SQLite) Close() error                  { return s.db.Close() }
+
+type sqliteChats struct{ db *sql.DB }
+
+func (r sqliteChats) Upsert(ctx context.Context, chat Chat) error {
+	firstSeen := chat.FirstSeen
+	if firstSeen.IsZero() {
+		firstSeen = chat.LastSeen
+	}
+	_, err := r.db.ExecContext(ctx, `INSERT INTO chats (id, type, title, username, first_seen, last_seen, left_chat)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			type = excluded.type,
+			title = excluded.title,
+			username = excluded.username,
+			last_seen = excluded.last_seen,
+			left_chat = excluded.left_chat`,
+		chat.ID, chat.Type, chat.Title, chat.Username, toMillis(firstSeen), toMillis(chat.LastSeen), chat.Left)
+	if err != nil {
+		return fmt.Errorf("upsert chat: %w", err)
+	}
+	return nil
+}
+
+func (r sqliteChats) Get(ctx context.Context, id int64) (Chat, bool, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT id, type, title, username, first_seen, last_seen, left_chat FROM chats WHERE id = ?`, id)
+	chat, err := scanChat(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Chat{}, false, nil
+	}
+	if err != nil {
+		return Chat{}, false, fmt.Errorf("get chat: %w", err)
+	}
+	return chat, true, nil
+}
+
+func (r sqliteChats) List(ctx context.Context) ([]Chat, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, type, title, username, first_seen, last_seen, left_chat FROM chats ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("list chats: %w", err)
+	}
+	defer rows.Close()
+
+	var chats []Chat
+	for rows.Next() {
+		chat, err := scanChat(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list chats: %w", err)
+		}
+		chats = append(chats, chat)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list chats: %w", err)
+	}
+	return chats, nil
+}
+
+type sqliteSettings struct{ db *sql.DB }
+
+// Settings are stored as a JSON document so new options do not need a migration.
+func (r sqliteSettings) Get(ctx context.Context, chatID int64) (chatsettings.Settings, error) {
+	var data string
+	err := r.db.QueryRowContext(ctx, `SELECT data FROM chat_settings WHERE chat_id = ?`, chatID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return chatsettings.Settings{}, nil
+	}
+	if err != nil {
+		return chatsettings.Settings{}, fmt.Errorf("get chat settings: %w", err)
+	}
+
+	var settings chatsettings.Settings
+	if err := json.Unmarshal([]byte(data), &settings); err != nil {
+		return chatsettings.Settings{}, fmt.Errorf("decode chat settings: %w", err)
+	}
+	return settings, nil
+}
+
+func (r sqliteSettings) Save(ctx context.Context, chatID int64, settings chatsettings.Settings) error {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("encode chat settings: %w", err)
+	}
+	_, err = r.db.ExecContext(ctx, `INSERT INTO chat_settings (chat_id, data, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT (chat_id) DO UPDATE SET data = excluded.data, updated_at = excluded.updated_at`,
+		chatID, string(data), time.Now().UnixMilli())
+	if err != nil {
+		return fmt.Errorf("save chat settings: %w", err)
+	}
+	return nil
+}
+
+type sqliteHistory struct{ db *sql.DB }
+
+const historyColumns = `id, chat_id, message_id, source_message_id, tweet_id, author, requester, sent_at`
+
+func (r sqliteHistory) Add(ctx context.Context, entry HistoryEntry) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO history
+		(chat_id, message_id, source_message_id, tweet_id, author, requester, sent_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		entry.ChatID, entry.MessageID, entry.SourceMessageID, entry.TweetID, entry.Author,
+		entry.Requester, toMillis(entry.SentAt))
+	if err != nil {
+		return fmt.Errorf("add history: %w", err)
+	}
+	return nil
+}
+
+func (r sqliteHistory) Latest(ctx context.Context, chatID int64, tweetID string) (HistoryEntry, bool, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+historyColumns+` FROM history
+		WHERE chat_id = ? AND tweet_id = ? ORDER BY sent_at DESC, id DESC LIMIT 1`, chatID, tweetID)
+	entry, err := scanHistory(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return HistoryEntry{}, false, nil
+	}
+	if err != nil {
+		return HistoryEntry{}, false, fmt.Errorf("latest history: %w", err)
+	}
+	return entry, true, nil
+}
+
+func (r sqliteHistory) List(ctx context.Context, filter HistoryFilter) ([]HistoryEntry, error) {
+	var where []string
+	var args []any
+	if filter.ChatID != 0 {
+		where = append(where, "chat_id = ?")
+		args = append(args, filter.ChatID)
+	}
+	if !filter.Since.IsZero() {
+		where = append(where, "sent_at >= ?")
+		args = append(args, filter.Since.UnixMilli())
+	}
+
+	query := `SELECT ` + historyColumns + ` FROM history`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY sent_at DESC, id DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list history: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []HistoryEntry
+	for rows.Next() {
+		entry, err := scanHistory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list history: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list history: %w", err)
+	}
+	return entries, nil
+}
+
+func (r sqliteHistory) Prune(ctx context.Context, before time.Time) (int, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM history WHERE sent_at < ?`, toMillis(before))
+	if err != nil {
+		return 0, fmt.Errorf("prune history: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("prune history: %w", err)
+	}
+	return int(n), nil
+}
+
+type sqliteEvents struct{ db *sql.DB }
+
+func (r sqliteEvents) Add(ctx context.Context, event Event) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO events (kind, chat_id, user_id, username, author, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		string(event.Kind), event.ChatID, event.UserID, event.Username, event.Author, toMillis(event.CreatedAt))
+	if err != nil {
+		return fmt.Errorf("add event: %w", err)
+	}
+	return nil
+}
+
+func (r sqliteEvents) List(ctx context.Context, filter EventFilter) ([]Event, error) {
+	var where []string
+	var args []any
+	if filter.ChatID != 0 {
+		where = append(where, "chat_id = ?")
+		args = append(args, filter.ChatID)
+	}
+	if !filter.Since.IsZero() {
+		where = append(where, "created_at >= ?")
+		args = append(args, filter.Since.UnixMilli())
+	}
+
+	query := `SELECT id, kind, chat_id, user_id, username, author, created_at FROM events`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY created_at, id"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		var event Event
+		var kind string
+		var createdAt int64
+		if err := rows.Scan(&event.ID, &kind, &event.ChatID, &event.UserID, &event.Username, &event.Author, &createdAt); err != nil {
+			return nil, fmt.Errorf("list events: %w", err)
+		}
+		event.Kind = EventKind(kind)
+		event.CreatedAt = fromMillis(createdAt)
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list events: %w", err)
+	}
+	return events, nil
+}
+
+func (r sqliteEvents) Prune(ctx context.Context, before time.Time) (int, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM events WHERE created_at < ?`, toMillis(before))
+	if err != nil {
+		return 0, fmt.Errorf("prune events: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("prune events: %w", err)
+	}
+	return int(n), nil
+}
+
+type sqliteAccess struct{ db *sql.DB }
+
+func (r sqliteAccess) SetChat(ctx context.Context, access ChatAccess) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO chat_access (chat_id, allowed, updated_by, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (chat_id) DO UPDATE SET
+			allowed = excluded.allowed,
+			updated_by = excluded.updated_by,
+			updated_at = excluded.updated_at`,
+		access.ChatID, access.Allowed, access.UpdatedBy, toMillis(access.UpdatedAt))
+	if err != nil {
+		return fmt.Errorf("set chat access: %w", err)
+	}
+	return nil
+}
+
+func (r sqliteAccess) Chat(ctx context.Context, chatID int64) (ChatAccess, bool, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT chat_id, allowed, updated_by, updated_at FROM chat_access WHERE chat_id = ?`, chatID)
+	access, err := scanChatAccess(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ChatAccess{}, false, nil
+	}
+	if err != nil {
+		return ChatAccess{}, false, fmt.Errorf("get chat access: %w", err)
+	}
+	return access, true, nil
+}
+
+func (r sqliteAccess) ListChats(ctx context.Context) ([]ChatAccess, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT chat_id, allowed, updated_by, updated_at FROM chat_access ORDER BY chat_id`)
+	if err != nil {
+		return nil, fmt.Errorf("list chat access: %w", err)
+	}
+	defer rows.Close()
+
+	var list []ChatAccess
+	for rows.Next() {
+		access, err := scanChatAccess(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list chat access: %w", err)
+		}
+		list = append(list, access)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list chat access: %w", err)
+	}
+	return list, nil
+}
+
+func (r sqliteAccess) Ban(ctx context.Context, ban Ban) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO bans (user_id, reason, banned_by, banned_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET
+			reason = excluded.reason,
+			banned_by = excluded.banned_by,
+			banned_at = excluded.banned_at`,
+		ban.UserID, ban.Reason, ban.BannedBy, toMillis(ban.BannedAt))
+	if err != nil {
+		return fmt.Errorf("ban user: %w", err)
+	}
+	return nil
+}
+
+func (r sqliteAccess) Unban(ctx context.Context, userID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM bans WHERE user_id = ?`, userID)
+	if err != nil {
+		return false, fmt.Errorf("unban user: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("unban user: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (r sqliteAccess) Banned(ctx context.Context, userID int64) (Ban, bool, error) {
+	var ban Ban
+	var bannedAt int64
+	err := r.db.QueryRowContext(ctx, `SELECT user_id, reason, banned_by, banned_at FROM bans WHERE user_id = ?`, userID).
+		Scan(&ban.UserID, &ban.Reason, &ban.BannedBy, &bannedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Ban{}, false, nil
+	}
+	if err != nil {
+		return Ban{}, false, fmt.Errorf("get ban: %w", err)
+	}
+	ban.BannedAt = fromMillis(bannedAt)
+	return ban, true, nil
+}
+
+type sqliteFollows struct{ db *sql.DB }
+
+func (r sqliteFollows) Add(ctx context.Context, follow Follow) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `INSERT INTO follows (chat_id, username, added_by, created_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (chat_id, username) DO NOTHING`,
+		follow.ChatID, follow.Username, follow.AddedBy, toMillis(follow.CreatedAt))
+	if err != nil {
+		return false, fmt.Errorf("add follow: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("add follow: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (r sqliteFollows) Remove(ctx context.Context, chatID int64, username string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM follows WHERE chat_id = ? AND username = ?`, chatID, username)
+	if err != nil {
+		return false, fmt.Errorf("remove follow: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("remove follow: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (r sqliteFollows) ListByChat(ctx context.Context, chatID int64) ([]Follow, error) {
+	return r.list(ctx, `SELECT chat_id, username, added_by, created_at FROM follows WHERE chat_id = ? ORDER BY username`, chatID)
+}
+
+func (r sqliteFollows) Followers(ctx context.Context, username string) ([]Follow, error) {
+	return r.list(ctx, `SELECT chat_id, username, added_by, created_at FROM follows WHERE username = ? ORDER BY chat_id`, username)
+}
+
+func (r sqliteFollows) list(ctx context.Context, query string, arg any) ([]Follow, error) {
+	rows, err := r.db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, fmt.Errorf("list follows: %w", err)
+	}
+	defer rows.Close()
+
+	var list []Follow
+	for rows.Next() {
+		var follow Follow
+		var createdAt int64
+		if err := rows.Scan(&follow.ChatID, &follow.Username, &follow.AddedBy, &createdAt); err != nil {
+			return nil, fmt.Errorf("list follows: %w", err)
+		}
+		follow.CreatedAt = fromMillis(createdAt)
+		list = append(list, follow)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list follows: %w", err)
+	}
+	return list, nil
+}
+
+func (r sqliteFollows) Accounts(ctx context.Context) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT DISTINCT username FROM follows ORDER BY username`)
+	if err != nil {
+		return nil, fmt.Errorf("list followed accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var list []string
+	for rows.Next() {
+		var username string
+		if err := rows.Scan(&username); err != nil {
+			return nil, fmt.Errorf("list followed accounts: %w", err)
+		}
+		list = append(list, username)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list followed accounts: %w", err)
+	}
+	return list, nil
+}
+
+func (r sqliteFollows) Cursor(ctx context.Context, username string) (FollowCursor, bool, error) {
+	var cursor FollowCursor
+	var checkedAt int64
+	err := r.db.QueryRowContext(ctx, `SELECT username, last_tweet_id, checked_at FROM follow_cursors WHERE username = ?`, username).
+		Scan(&cursor.Username, &cursor.LastTweetID, &checkedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return FollowCursor{}, false, nil
+	}
+	if err != nil {
+		return FollowCursor{}, false, fmt.Errorf("get follow cursor: %w", err)
+	}
+	cursor.CheckedAt = fromMillis(checkedAt)
+	return cursor, true, nil
+}
+
+func (r sqliteFollows) SetCursor(ctx context.Context, cursor FollowCursor) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO follow_cursors (username, last_tweet_id, checked_at) VALUES (?, ?, ?)
+		ON CONFLICT (username) DO UPDATE SET
+			last_tweet_id = excluded.last_tweet_id,
+			checked_at = excluded.checked_at`,
+		cursor.Username, cursor.LastTweetID, toMillis(cursor.CheckedAt))
+	if err != nil {
+		return fmt.Errorf("set follow cursor: %w", err)
+	}
+	return nil
+}
+
+type sqliteWatches struct{ db *sql.DB }
+
+func (r sqliteWatches) Add(ctx context.Context, rule WatchRule) (WatchRule, error) {
+	res, err := r.db.ExecContext(ctx, `INSERT INTO watch_rules (user_id, query, mute_window, created_at) VALUES (?, ?, ?, ?)`,
+		rule.UserID, rule.Query, rule.MuteWindow, toMillis(rule.CreatedAt))
+	if err != nil {
+		return WatchRule{}, fmt.Errorf("add watch rule: %w", err)
+	}
+	if rule.ID, err = res.LastInsertId(); err != nil {
+		return WatchRule{}, fmt.Errorf("add watch rule: %w", err)
+	}
+	return rule, nil
+}
+
+func (r sqliteWatches) Remove(ctx context.Context, userID, id int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM watch_rules WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("remove watch rule: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("remove watch rule: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (r sqliteWatches) SetMuteWindow(ctx context.Context, userID, id int64, window string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `UPDATE watch_rules SET mute_window = ? WHERE id = ? AND user_id = ?`, window, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("mute watch rule: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("mute watch rule: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (r sqliteWatches) ListByUser(ctx context.Context, userID int64) ([]WatchRule, error) {
+	return r.list(ctx, `SELECT id, user_id, query, mute_window, created_at FROM watch_rules WHERE user_id = ? ORDER BY id`, userID)
+}
+
+func (r sqliteWatches) List(ctx context.Context) ([]WatchRule, error) {
+	return r.list(ctx, `SELECT id, user_id, query, mute_window, created_at FROM watch_rules ORDER BY id`)
+}
+
+func (r sqliteWatches) list(ctx context.Context, query string, args ...any) ([]WatchRule, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list watch rules: %w", err)
+	}
+	defer rows.Close()
+
+	var list []WatchRule
+	for rows.Next() {
+		var rule WatchRule
+		var createdAt int64
+		if err := rows.Scan(&rule.ID, &rule.UserID, &rule.Query, &rule.MuteWindow, &createdAt); err != nil {
+			return nil, fmt.Errorf("list watch rules: %w", err)
+		}
+		rule.CreatedAt = fromMillis(createdAt)
+		list = append(list, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list watch rules: %w", err)
+	}
+	return list, nil
+}
+
+type sqliteDigests struct{ db *sql.DB }
+
+func (r sqliteDigests) Set(ctx context.Context, digest Digest) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO digests (chat_id, frequency, weekday, minute, article, updated_by, updated_at, last_sent_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (chat_id) DO UPDATE SET
+			frequency = excluded.frequency,
+			weekday = excluded.weekday,
+			minute = excluded.minute,
+			article = excluded.article,
+			updated_by = excluded.updated_by,
+			updated_at = excluded.updated_at,
+			last_sent_at = excluded.last_sent_at`,
+		digest.ChatID, string(digest.Frequency), int(digest.Weekday), digest.Minute, digest.Article,
+		digest.UpdatedBy, toMillis(digest.UpdatedAt), toMillis(digest.LastSentAt))
+	if err != nil {
+		return fmt.Errorf("set digest: %w", err)
+	}
+	return nil
+}
+
+func (r sqliteDigests) Get(ctx context.Context, chatID int64) (Digest, bool, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT chat_id, frequency, weekday, minute, article, updated_by, updated_at, last_sent_at
+		FROM digests WHERE chat_id = ?`, chatID)
+	digest, err := scanDigest(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Digest{}, false, nil
+	}
+	if err != nil {
+		return Digest{}, false, fmt.Errorf("get digest: %w", err)
+	}
+	return digest, true, nil
+}
+
+func (r sqliteDigests) Delete(ctx context.Context, chatID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM digests WHERE chat_id = ?`, chatID)
+	if err != nil {
+		return false, fmt.Errorf("delete digest: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("delete digest: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (r sqliteDigests) List(ctx context.Context) ([]Digest, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT chat_id, frequency, weekday, minute, article, updated_by, updated_at, last_sent_at
+		FROM digests ORDER BY chat_id`)
+	if err != nil {
+		return nil, fmt.Errorf("list digests: %w", err)
+	}
+	defer rows.Close()
+
+	var list []Digest
+	for rows.Next() {
+		digest, err := scanDigest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list digests: %w", err)
+		}
+		list = append(list, digest)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list digests: %w", err)
+	}
+	return list, nil
+}
+
+func (r sqliteDigests) MarkSent(ctx context.Context, chatID int64, at time.Time) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE digests SET last_sent_at = ? WHERE chat_id = ?`, toMillis(at), chatID); err != nil {
+		return fmt.Errorf("mark digest sent: %w", err)
+	}
+	return nil
+}
+
+type sqliteReplies struct{ db *sql.DB }
+
+func (r sqliteReplies) Add(ctx context.Context, reply Reply) error {
+	_, err := r.db.ExecContext(ctx, `INSERT OR REPLACE INTO replies (chat_id, source_message_id, tweet_id, message_id, sent_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		reply.ChatID, reply.SourceMessageID, reply.TweetID, reply.MessageID, toMillis(reply.SentAt))
+	if err != nil {
+		return fmt.Errorf("add reply: %w", err)
+	}
+	return nil
+}
+
+func (r sqliteReplies) List(ctx context.Context, chatID, sourceMessageID int64) ([]Reply, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT chat_id, source_message_id, tweet_id, message_id, sent_at
+		FROM replies WHERE chat_id = ? AND source_message_id = ? ORDER BY sent_at, message_id`, chatID, sourceMessageID)
+	if err != nil {
+		return nil, fmt.Errorf("list replies: %w", err)
+	}
+	defer rows.Close()
+
+	var list []Reply
+	for rows.Next() {
+		var reply Reply
+		var sentAt int64
+		if err := rows.Scan(&reply.ChatID, &reply.SourceMessageID, &reply.TweetID, &reply.MessageID, &sentAt); err != nil {
+			return nil, fmt.Errorf("list replies: %w", err)
+		}
+		reply.SentAt = fromMillis(sentAt)
+		list = append(list, reply)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list replies: %w", err)
+	}
+	return list, nil
+}
+
+func (r sqliteReplies) Remove(ctx context.Context, chatID, sourceMessageID int64, tweetID string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM replies WHERE chat_id = ? AND source_message_id = ? AND tweet_id = ?`,
+		chatID, sourceMessageID, tweetID); err != nil {
+		return fmt.Errorf("remove replies: %w", err)
+	}
+	return nil
+}
+
+func (r sqliteReplies) Prune(ctx context.Context, before time.Time) (int, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM replies WHERE sent_at < ?`, toMillis(before))
+	if err != nil {
+		return 0, fmt.Errorf("prune replies: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("prune replies: %w", err)
+	}
+	return int(n), nil
+}
+
+type sqliteBookmarks struct{ db *sql.DB }
+
+const bookmarkColumns = `id, user_id, tweet_id, author, tweet, created_at`
+
+func (r sqliteBookmarks) Add(ctx context.Context, bookmark Bookmark) (Bookmark, bool, error) {
+	data, err := json.Marshal(bookmark.Tweet)
+	if err != nil {
+		return Bookmark{}, false, fmt.Errorf("add bookmark: %w", err)
+	}
+	res, err := r.db.ExecContext(ctx, `INSERT INTO bookmarks (user_id, tweet_id, author, search, tweet, created_at)
+		VALUES (?, ?, ?, ?, ?, ?) ON CONFLICT (user_id, tweet_id) DO NOTHING`,
+		bookmark.UserID, bookmark.TweetID, bookmark.Author, bookmarkSearchText(bookmark), string(data), toMillis(bookmark.CreatedAt))
+	if err != nil {
+		return Bookmark{}, false, fmt.Errorf("add bookmark: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return Bookmark{}, false, fmt.Errorf("add bookmark: %w", err)
+	}
+	if n == 0 {
+		existing, err := scanBookmark(r.db.QueryRowContext(ctx, `SELECT `+bookmarkColumns+` FROM bookmarks WHERE user_id = ? AND tweet_id = ?`,
+			bookmark.UserID, bookmark.TweetID))
+		if err != nil {
+			return Bookmark{}, false, fmt.Errorf("add bookmark: %w", err)
+		}
+		return existing, false, nil
+	}
+	if bookmark.ID, err = res.LastInsertId(); err != nil {
+		return Bookmark{}, false, fmt.Errorf("add bookmark: %w", err)
+	}
+	return bookmark, true, nil
+}
+
+func (r sqliteBookmarks) Get(ctx context.Context, userID, id int64) (Bookmark, bool, error) {
+	bookmark, err := scanBookmark(r.db.QueryRowContext(ctx, `SELECT `+bookmarkColumns+` FROM bookmarks WHERE id = ? AND user_id = ?`, id, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Bookmark{}, false, nil
+	}
+	if err != nil {
+		return Bookmark{}, false, fmt.Errorf("get bookmark: %w", err)
+	}
+	return bookmark, true, nil
+}
+
+func (r sqliteBookmarks) Remove(ctx context.Context, userID, id int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM bookmarks WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("remove bookmark: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("remove bookmark: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (r sqliteBookmarks) List(ctx context.Context, filter BookmarkFilter) ([]Bookmark, int, error) {
+	where := ` FROM bookmarks WHERE user_id = ?`
+	args := []any{filter.UserID}
+	if filter.Search != "" {
+		where += ` AND instr(search, ?) > 0`
+		args = append(args, strings.ToLower(filter.Search))
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*)`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("list bookmarks: %w", err)
+	}
+
+	query := `SELECT ` + bookmarkColumns + where + ` ORDER BY created_at DESC, id DESC`
+	if filter.Limit > 0 || filter.Offset > 0 {
+		limit := filter.Limit
+		if limit <= 0 {
+			limit = -1
+		}
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, limit, filter.Offset)
+	}
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list bookmarks: %w", err)
+	}
+	defer rows.Close()
+
+	var list []Bookmark
+	for rows.Next() {
+		bookmark, err := scanBookmark(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("list bookmarks: %w", err)
+		}
+		list = append(list, bookmark)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("list bookmarks: %w", err)
+	}
+	return list, total, nil
+}
+
+func scanBookmark(row scanner) (Bookmark, error) {
+	var bookmark Bookmark
+	var data string
+	var createdAt int64
+	if err := row.Scan(&bookmark.ID, &bookmark.UserID, &bookmark.TweetID, &bookmark.Author, &data, &createdAt); err != nil {
+		return Bookmark{}, err
+	}
+	var tw twitterxapi.Tweet
+	if err := json.Unmarshal([]byte(data), &tw); err != nil {
+		return Bookmark{}, fmt.Errorf("decode bookmarked tweet: %w", err)
+	}
+	bookmark.Tweet = &tw
+	bookmark.CreatedAt = fromMillis(createdAt)
+	return bookmark, nil
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanChat(row scanner) (Chat, error) {
+	var chat Chat
+	var firstSeen, lastSeen int64
+	if err := row.Scan(&chat.ID, &chat.Type, &chat.Title, &chat.Username, &firstSeen, &lastSeen, &chat.Left); err != nil {
+		return Chat{}, err
+	}
+	chat.FirstSeen = fromMillis(firstSeen)
+	chat.LastSeen = fromMillis(lastSeen)
+	return chat, nil
+}
+
+func scanChatAccess(row scanner) (ChatAccess, error) {
+	var access ChatAccess
+	var updatedAt int64
+	if err := row.Scan(&access.ChatID, &access.Allowed, &access.UpdatedBy, &updatedAt); err != nil {
+		return ChatAccess{}, err
+	}
+	access.UpdatedAt = fromMillis(updatedAt)
+	return access, nil
+}
+
+func scanDigest(row scanner) (Digest, error) {
+	var digest Digest
+	var frequency string
+	var weekday int
+	var updatedAt, lastSentAt int64
+	if err := row.Scan(&digest.ChatID, &frequency, &weekday, &digest.Minute, &digest.Article,
+		&digest.UpdatedBy, &updatedAt, &lastSentAt); err != nil {
+		return Digest{}, err
+	}
+	digest.Frequency = DigestFrequency(frequency)
+	digest.Weekday = time.Weekday(weekday)
+	digest.UpdatedAt = fromMillis(updatedAt)
+	digest.LastSentAt = fromMillis(lastSentAt)
+	return digest, nil
+}
+
+func scanHistory(row scanner) (HistoryEntry, error) {
+	var entry HistoryEntry
+	var sentAt int64
+	if err := row.Scan(&entry.ID, &entry.ChatID, &entry.MessageID, &entry.SourceMessageID, &entry.TweetID,
+		&entry.Author, &entry.Requester, &sentAt); err != nil {
+		return HistoryEntry{}, err
+	}
+	entry.SentAt = fromMillis(sentAt)
+	return entry, nil
+}
+
+// Timestamps are stored as Unix milliseconds.
+func toMillis(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func fromMillis(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
@@ -0,0 +1,345 @@
+// Package storage persists bot state (chats, per-chat settings, sent tweet history, usage events,
+// access rules, followed accounts, watch rules, digest schedules and bookmarks)
+// behind repository interfaces with SQLite and in-memory implementations.
+package storage
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"twitterx-bot/internal/chatsettings"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+// Storage groups the repositories of the bot state.
+type Storage interface {
+	Chats() ChatRepository
+	Settings() SettingsRepository
+	History() HistoryRepository
+	Events() EventRepository
+	Access() AccessRepository
+	Follows() FollowRepository
+	Watches() WatchRepository
+	Digests() DigestRepository
+	Replies() ReplyRepository
+	Bookmarks() BookmarkRepository
+	Close() error
+}
+
+// Chat is a chat the bot has seen an update from.
+type Chat struct {
+	ID        int64
+	Type      string
+	Title     string
+	Username  string
+	FirstSeen time.Time
+	LastSeen  time.Time
+	// Left is set when the bot was removed from the chat or blocked by the user.
+	Left bool
+}
+
+// ChatRepository keeps the chats the bot is used in.
+type ChatRepository interface {
+	// Upsert records the chat, keeping FirstSeen of an already known chat.
+	Upsert(ctx context.Context, chat Chat) error
+	Get(ctx context.Context, id int64) (Chat, bool, error)
+	// List returns all known chats ordered by ID.
+	List(ctx context.Context) ([]Chat, error)
+}
+
+// SettingsRepository keeps per-chat settings. It satisfies chatsettings.Store.
+type SettingsRepository interface {
+	chatsettings.Store
+}
+
+// HistoryEntry is a tweet the bot sent to a chat.
+type HistoryEntry struct {
+	ID     int64
+	ChatID int64
+	// MessageID is the bot message with the tweet.
+	MessageID int64
+	// SourceMessageID is the user message with the link, 0 if unknown.
+	SourceMessageID int64
+	TweetID         string
+	Author          string
+	Requester       string
+	SentAt          time.Time
+}
+
+// HistoryFilter narrows History.List; zero fields match everything.
+type HistoryFilter struct {
+	ChatID int64
+	Since  time.Time
+	// Limit caps the number of entries; 0 returns all of them.
+	Limit int
+}
+
+// HistoryRepository keeps the tweets sent by the bot.
+type HistoryRepository interface {
+	Add(ctx context.Context, entry HistoryEntry) error
+	// Latest returns the most recent entry for the tweet in the chat.
+	Latest(ctx context.Context, chatID int64, tweetID string) (HistoryEntry, bool, error)
+	// List returns matching entries, newest first.
+	List(ctx context.Context, filter HistoryFilter) ([]HistoryEntry, error)
+	// Prune deletes the entries sent before the given time and returns how many were deleted.
+	Prune(ctx context.Context, before time.Time) (int, error)
+}
+
+// matches reports whether the entry passes the filter.
+func (f HistoryFilter) matches(entry HistoryEntry) bool {
+	if f.ChatID != 0 && entry.ChatID != f.ChatID {
+		return false
+	}
+	if !f.Since.IsZero() && entry.SentAt.Before(f.Since) {
+		return false
+	}
+	return true
+}
+
+// EventKind is the kind of a usage event.
+type EventKind string
+
+const (
+	EventTweetSent    EventKind = "tweet"
+	EventChainSent    EventKind = "chain"
+	EventInlineChosen EventKind = "inline"
+	EventDeleted      EventKind = "delete"
+	EventFailed       EventKind = "failure"
+)
+
+// Event is a single bot action counted in usage statistics.
+type Event struct {
+	ID     int64
+	Kind   EventKind
+	ChatID int64
+	UserID int64
+	// Username is the display name of the user at the time of the event.
+	Username string
+	// Author is the screen name of the tweet author, empty when unknown.
+	Author    string
+	CreatedAt time.Time
+}
+
+// EventFilter narrows Events.List; zero fields match everything.
+type EventFilter struct {
+	ChatID int64
+	Since  time.Time
+	// Limit caps the number of events; 0 returns all of them.
+	Limit int
+}
+
+// EventRepository keeps usage events for statistics.
+type EventRepository interface {
+	Add(ctx context.Context, event Event) error
+	// List returns matching events, oldest first.
+	List(ctx context.Context, filter EventFilter) ([]Event, error)
+	// Prune deletes the events recorded before the given time and returns how many were deleted.
+	Prune(ctx context.Context, before time.Time) (int, error)
+}
+
+// matches reports whether the event passes the filter.
+func (f EventFilter) matches(event Event) bool {
+	if f.ChatID != 0 && event.ChatID != f.ChatID {
+		return false
+	}
+	if !f.Since.IsZero() && event.CreatedAt.Before(f.Since) {
+		return false
+	}
+	return true
+}
+
+// ChatAccess is an explicit decision whether the bot may be used in a chat.
+// For private chats ChatID is the user ID.
+type ChatAccess struct {
+	ChatID    int64
+	Allowed   bool
+	UpdatedBy int64
+	UpdatedAt time.Time
+}
+
+// Ban blocks a user from using the bot anywhere.
+type Ban struct {
+	UserID   int64
+	Reason   string
+	BannedBy int64
+	BannedAt time.Time
+}
+
+// AccessRepository keeps the chat allow/deny list and banned users.
+type AccessRepository interface {
+	// SetChat stores the decision for a chat, replacing an earlier one.
+	SetChat(ctx context.Context, access ChatAccess) error
+	Chat(ctx context.Context, chatID int64) (ChatAccess, bool, error)
+	// ListChats returns all decisions ordered by chat ID.
+	ListChats(ctx context.Context) ([]ChatAccess, error)
+
+	Ban(ctx context.Context, ban Ban) error
+	// Unban lifts the ban and reports whether the user was banned.
+	Unban(ctx context.Context, userID int64) (bool, error)
+	Banned(ctx context.Context, userID int64) (Ban, bool, error)
+}
+
+// Follow subscribes a chat to new tweets of an account.
+type Follow struct {
+	ChatID int64
+	// Username is the lower-cased screen name without "@".
+	Username  string
+	AddedBy   int64
+	CreatedAt time.Time
+}
+
+// FollowCursor is the polling position of a followed account, shared by all its followers.
+type FollowCursor struct {
+	Username string
+	// LastTweetID is the newest tweet already seen, empty before the first poll.
+	LastTweetID string
+	CheckedAt   time.Time
+}
+
+// FollowRepository keeps the accounts chats follow and the polling cursors.
+type FollowRepository interface {
+	// Add subscribes the chat and reports whether it did not follow the account yet.
+	Add(ctx context.Context, follow Follow) (bool, error)
+	// Remove unsubscribes the chat and reports whether it followed the account.
+	Remove(ctx context.Context, chatID int64, username string) (bool, error)
+	// ListByChat returns the accounts followed by the chat ordered by username.
+	ListByChat(ctx context.Context, chatID int64) ([]Follow, error)
+	// Followers returns the chats following the account ordered by chat ID.
+	Followers(ctx context.Context, username string) ([]Follow, error)
+	// Accounts returns the followed usernames ordered by name.
+	Accounts(ctx context.Context) ([]string, error)
+
+	Cursor(ctx context.Context, username string) (FollowCursor, bool, error)
+	SetCursor(ctx context.Context, cursor FollowCursor) error
+}
+
+// WatchRule alerts a user in private about tweets matching Query wherever the bot sees them.
+type WatchRule struct {
+	ID     int64
+	UserID int64
+	// Query is the rule as typed by the user; it is parsed when the rule is evaluated.
+	Query string
+	// MuteWindow is a daily "HH:MM-HH:MM" UTC window without alerts, empty when never muted.
+	MuteWindow string
+	CreatedAt  time.Time
+}
+
+// WatchRepository keeps the watch rules of users.
+type WatchRepository interface {
+	// Add stores the rule and returns it with its assigned ID.
+	Add(ctx context.Context, rule WatchRule) (WatchRule, error)
+	// Remove deletes a rule of the user and reports whether it existed.
+	Remove(ctx context.Context, userID, id int64) (bool, error)
+	// SetMuteWindow updates the mute window of a rule of the user and reports whether it exists.
+	SetMuteWindow(ctx context.Context, userID, id int64, window string) (bool, error)
+	// ListByUser returns the rules of the user ordered by ID.
+	ListByUser(ctx context.Context, userID int64) ([]WatchRule, error)
+	// List returns all rules ordered by ID.
+	List(ctx context.Context) ([]WatchRule, error)
+}
+
+// DigestFrequency is how often a chat digest is posted.
+type DigestFrequency string
+
+const (
+	DigestDaily  DigestFrequency = "daily"
+	DigestWeekly DigestFrequency = "weekly"
+)
+
+// Digest schedules a summary of the tweets shared in a chat.
+type Digest struct {
+	ChatID    int64
+	Frequency DigestFrequency
+	// Weekday is the day weekly digests are posted on.
+	Weekday time.Weekday
+	// Minute is the UTC time of day the digest is posted, in minutes since midnight.
+	Minute int
+	// Article adds a page with the full tweets to the digest.
+	Article   bool
+	UpdatedBy int64
+	UpdatedAt time.Time
+	// LastSentAt is when the last digest was compiled, zero before the first one.
+	LastSentAt time.Time
+}
+
+// DigestRepository keeps the digest schedules of chats.
+type DigestRepository interface {
+	// Set stores the schedule of the chat, replacing an earlier one.
+	Set(ctx context.Context, digest Digest) error
+	Get(ctx context.Context, chatID int64) (Digest, bool, error)
+	// Delete removes the schedule and reports whether the chat had one.
+	Delete(ctx context.Context, chatID int64) (bool, error)
+	// List returns all schedules ordered by chat ID.
+	List(ctx context.Context) ([]Digest, error)
+	// MarkSent records when the last digest of the chat was compiled.
+	MarkSent(ctx context.Context, chatID int64, at time.Time) error
+}
+
+// Reply is a bot message sent in response to a tweet link in a user message.
+type Reply struct {
+	ChatID int64
+	// SourceMessageID is the user message with the link.
+	SourceMessageID int64
+	TweetID         string
+	MessageID       int64
+	SentAt          time.Time
+}
+
+// ReplyRepository maps user messages to the bot messages sent for their tweet links,
+// so the replies can follow edits of the user message.
+type ReplyRepository interface {
+	Add(ctx context.Context, reply Reply) error
+	// List returns the replies to the source message in the order they were sent.
+	List(ctx context.Context, chatID, sourceMessageID int64) ([]Reply, error)
+	// Remove deletes the replies to the source message for the tweet.
+	Remove(ctx context.Context, chatID, sourceMessageID int64, tweetID string) error
+	// Prune deletes the replies sent before the given time and returns how many were deleted.
+	Prune(ctx context.Context, before time.Time) (int, error)
+}
+
+// Bookmark is a tweet a user saved with the "⭐ Save" button.
+type Bookmark struct {
+	ID      int64
+	UserID  int64
+	TweetID string
+	// Author is the screen name of the tweet author.
+	Author string
+	// Tweet is the tweet as it was when saved, so it can be sent again without the API.
+	Tweet     *twitterxapi.Tweet
+	CreatedAt time.Time
+}
+
+// BookmarkFilter selects a page of the bookmarks of a user.
+type BookmarkFilter struct {
+	UserID int64
+	// Search keeps bookmarks whose text or author contains it, ignoring case.
+	Search string
+	Offset int
+	// Limit caps the number of bookmarks; 0 returns all of them.
+	Limit int
+}
+
+// BookmarkRepository keeps the tweets saved by users.
+type BookmarkRepository interface {
+	// Add saves the bookmark and reports whether the user had not saved the tweet yet.
+	// An existing bookmark of the tweet is returned unchanged.
+	Add(ctx context.Context, bookmark Bookmark) (Bookmark, bool, error)
+	Get(ctx context.Context, userID, id int64) (Bookmark, bool, error)
+	// Remove deletes a bookmark of the user and reports whether it existed.
+	Remove(ctx context.Context, userID, id int64) (bool, error)
+	// List returns the page of matching bookmarks, newest first, and the number of matches.
+	List(ctx context.Context, filter BookmarkFilter) ([]Bookmark, int, error)
+}
+
+// bookmarkSearchText is the lowercased text searched by BookmarkFilter.Search.
+func bookmarkSearchText(bookmark Bookmark) string {
+	parts := []string{bookmark.Author}
+	if tw := bookmark.Tweet; tw != nil {
+		parts = append(parts, tw.Author.Name, tw.Text)
+		if tw.Quote != nil {
+			parts = append(parts, tw.Quote.Author.ScreenName, tw.Quote.Text)
+		}
+	}
+	return strings.ToLower(strings.Join(parts, "\n"))
+}
@@ -0,0 +1,633 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"twitterx-bot/internal/chatsettings"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+// backends runs the test against every Storage implementation.
+func backends(t *testing.T, test func(t *testing.T, s Storage)) {
+	t.Run("memory", func(t *testing.T) {
+		test(t, NewMemory())
+	})
+	t.Run("sqlite", func(t *testing.T) {
+		s, err := OpenSQLite(context.Background(), filepath.Join(t.TempDir(), "bot.db"))
+		if err != nil {
+			t.Fatalf("OpenSQLite() error = %v", err)
+		}
+		t.Cleanup(func() { s.Close() })
+		test(t, s)
+	})
+}
+
+func TestChats_UpsertKeepsFirstSeen(t *testing.T) {
+	backends(t, func(t *testing.T, s Storage) {
+		ctx := context.Background()
+		first := time.UnixMilli(1_700_000_000_000)
+		later := first.Add(time.Hour)
+
+		if err := s.Chats().Upsert(ctx, Chat{ID: -100, Type: "group", Title: "Old", LastSeen: first}); err != nil {
+			t.Fatalf("Upsert() error = %v", err)
+		}
+		if err := s.Chats().Upsert(ctx, Chat{ID: -100, Type: "supergroup", Title: "New", LastSeen: later}); err != nil {
+			t.Fatalf("Upsert() error = %v", err)
+		}
+
+		chat, ok, err := s.Chats().Get(ctx, -100)
+		if err != nil || !ok {
+			t.Fatalf("Get() = %v, %v", ok, err)
+		}
+		if chat.Title != "New" || chat.Type != "supergroup" {
+			t.Errorf("chat = %+v, want updated title and type", chat)
+		}
+		if !chat.FirstSeen.Equal(first) || !chat.LastSeen.Equal(later) {
+			t.Errorf("seen = %v..%v, want %v..%v", chat.FirstSeen, chat.LastSeen, first, later)
+		}
+
+		if _, ok, _ := s.Chats().Get(ctx, 1); ok {
+			t.Error("Get() found unknown chat")
+		}
+	})
+}
+
+func TestChats_ListOrderedByID(t *testing.T) {
+	backends(t, func(t *testing.T, s Storage) {
+		ctx := context.Background()
+		now := time.Now()
+		for _, id := range []int64{3, -1, 2} {
+			if err := s.Chats().Upsert(ctx, Chat{ID: id, LastSeen: now}); err != nil {
+				t.Fatalf("Upsert() error = %v", err)
+			}
+		}
+
+		chats, err := s.Chats().List(ctx)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if len(chats) != 3 || chats[0].ID != -1 || chats[1].ID != 2 || chats[2].ID != 3 {
+			t.Fatalf("List() = %+v, want ids -1, 2, 3", chats)
+		}
+	})
+}
+
+func TestSettings_SaveAndGet(t *testing.T) {
+	backends(t, func(t *testing.T, s Storage) {
+		ctx := context.Background()
+
+		got, err := s.Settings().Get(ctx, 42)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if got != (chatsettings.Settings{}) {
+			t.Fatalf("Get() = %+v, want defaults", got)
+		}
+
+		if err := s.Settings().Save(ctx, 42, chatsettings.Settings{TranslateTo: "uk"}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		if err := s.Settings().Save(ctx, 42, chatsettings.Settings{TranslateTo: "de"}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+
+		got, err = s.Settings().Get(ctx, 42)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if got.TranslateTo != "de" {
+			t.Errorf("TranslateTo = %q, want %q", got.TranslateTo, "de")
+		}
+	})
+}
+
+func TestHistory_LatestAndList(t *testing.T) {
+	backends(t, func(t *testing.T, s Storage) {
+		ctx := context.Background()
+		base := time.UnixMilli(1_700_000_000_000)
+		entries := []HistoryEntry{
+			{ChatID: 1, MessageID: 10, TweetID: "a", SentAt: base},
+			{ChatID: 1, MessageID: 11, TweetID: "b", SentAt: base.Add(time.Minute)},
+			{ChatID: 1, MessageID: 12, TweetID: "a", SentAt: base.Add(2 * time.Minute)},
+			{ChatID: 2, MessageID: 13, TweetID: "a", SentAt: base.Add(3 * time.Minute)},
+		}
+		for _, entry := range entries {
+			if err := s.History().Add(ctx, entry); err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+		}
+
+		latest, ok, err := s.History().Latest(ctx, 1, "a")
+		if err != nil || !ok {
+			t.Fatalf("Latest() = %v, %v", ok, err)
+		}
+		if latest.MessageID != 12 || !latest.SentAt.Equal(base.Add(2*time.Minute)) {
+			t.Errorf("Latest() = %+v, want message 12", latest)
+		}
+		if _, ok, _ := s.History().Latest(ctx, 3, "a"); ok {
+			t.Error("Latest() found entry in unknown chat")
+		}
+
+		chatEntries, err := s.History().List(ctx, HistoryFilter{ChatID: 1})
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if len(chatEntries) != 3 || chatEntries[0].MessageID != 12 || chatEntries[2].MessageID != 10 {
+			t.Errorf("List(chat) = %+v, want newest first", chatEntries)
+		}
+
+		filtered, err := s.History().List(ctx, HistoryFilter{ChatID: 2, Since: base.Add(time.Second), Limit: 5})
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if len(filtered) != 1 || filtered[0].MessageID != 13 {
+			t.Errorf("List(chat, since) = %+v, want message 13", filtered)
+		}
+
+		limited, err := s.History().List(ctx, HistoryFilter{Limit: 2})
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if len(limited) != 2 || limited[0].MessageID != 13 {
+			t.Errorf("List(limit) = %+v, want 2 newest", limited)
+		}
+	})
+}
+
+func TestHistory_Prune(t *testing.T) {
+	backends(t, func(t *testing.T, s Storage) {
+		ctx := context.Background()
+		now := time.UnixMilli(1_700_000_000_000)
+		for _, entry := range []HistoryEntry{
+			{ChatID: 1, MessageID: 10, TweetID: "a", SentAt: now.Add(-time.Hour)},
+			{ChatID: 1, MessageID: 11, TweetID: "b", SentAt: now},
+		} {
+			if err := s.History().Add(ctx, entry); err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+		}
+
+		pruned, err := s.History().Prune(ctx, now)
+		if err != nil {
+			t.Fatalf("Prune() error = %v", err)
+		}
+		if pruned != 1 {
+			t.Errorf("Prune() = %d, want 1", pruned)
+		}
+		if list, _ := s.History().List(ctx, HistoryFilter{}); len(list) != 1 || list[0].MessageID != 11 {
+			t.Errorf("List() after Prune() = %+v, want only the entry sent at the cutoff", list)
+		}
+	})
+}
+
+func TestEvents_AddAndList(t *testing.T) {
+	backends(t, func(t *testing.T, s Storage) {
+		ctx := context.Background()
+		base := time.UnixMilli(1_700_000_000_000)
+		events := []Event{
+			{Kind: EventChainSent, ChatID: 1, UserID: 7, Username: "Alice", Author: "jack", CreatedAt: base.Add(time.Minute)},
+			{Kind: EventTweetSent, ChatID: 1, UserID: 7, Username: "Alice", Author: "jack", CreatedAt: base},
+			{Kind: EventInlineChosen, UserID: 8, Author: "elon", CreatedAt: base.Add(2 * time.Minute)},
+		}
+		for _, event := range events {
+			if err := s.Events().Add(ctx, event); err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+		}
+
+		all, err := s.Events().List(ctx, EventFilter{})
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if len(all) != 3 || all[0].Kind != EventTweetSent || all[2].Kind != EventInlineChosen {
+			t.Errorf("List() = %+v, want oldest first", all)
+		}
+		if all[0].Username != "Alice" || all[0].Author != "jack" || !all[0].CreatedAt.Equal(base) {
+			t.Errorf("List()[0] = %+v, want stored fields", all[0])
+		}
+
+		chat, err := s.Events().List(ctx, EventFilter{ChatID: 1, Since: base.Add(time.Second)})
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if len(chat) != 1 || chat[0].Kind != EventChainSent {
+			t.Errorf("List(chat, since) = %+v, want the chain event", chat)
+		}
+
+		limited, err := s.Events().List(ctx, EventFilter{Limit: 2})
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if len(limited) != 2 || limited[1].Kind != EventChainSent {
+			t.Errorf("List(limit) = %+v, want 2 oldest", limited)
+		}
+
+		pruned, err := s.Events().Prune(ctx, base.Add(time.Minute))
+		if err != nil {
+			t.Fatalf("Prune() error = %v", err)
+		}
+		if pruned != 1 {
+			t.Errorf("Prune() = %d, want 1", pruned)
+		}
+		if rest, _ := s.Events().List(ctx, EventFilter{}); len(rest) != 2 || rest[0].Kind != EventChainSent {
+			t.Errorf("List() after Prune() = %+v, want the events from the cutoff on", rest)
+		}
+	})
+}
+
+func TestChats_Left(t *testing.T) {
+	backends(t, func(t *testing.T, s Storage) {
+		ctx := context.Background()
+		now := time.UnixMilli(1_700_000_000_000)
+		if err := s.Chats().Upsert(ctx, Chat{ID: -100, LastSeen: now, Left: true}); err != nil {
+			t.Fatalf("Upsert() error = %v", err)
+		}
+		if chat, _, _ := s.Chats().Get(ctx, -100); !chat.Left {
+			t.Errorf("chat = %+v, want left", chat)
+		}
+		if err := s.Chats().Upsert(ctx, Chat{ID: -100, LastSeen: now}); err != nil {
+			t.Fatalf("Upsert() error = %v", err)
+		}
+		if chat, _, _ := s.Chats().Get(ctx, -100); chat.Left {
+			t.Errorf("chat = %+v, want member again", chat)
+		}
+	})
+}
+
+func TestAccess_ChatsAndBans(t *testing.T) {
+	backends(t, func(t *testing.T, s Storage) {
+		ctx := context.Background()
+		now := time.UnixMilli(1_700_000_000_000)
+
+		if err := s.Access().SetChat(ctx, ChatAccess{ChatID: -200, Allowed: true, UpdatedBy: 42, UpdatedAt: now}); err != nil {
+			t.Fatalf("SetChat() error = %v", err)
+		}
+		if err := s.Access().SetChat(ctx, ChatAccess{ChatID: -100, Allowed: true, UpdatedAt: now}); err != nil {
+			t.Fatalf("SetChat() error = %v", err)
+		}
+		if err := s.Access().SetChat(ctx, ChatAccess{ChatID: -100, Allowed: false, UpdatedBy: 42, UpdatedAt: now}); err != nil {
+			t.Fatalf("SetChat() error = %v", err)
+		}
+
+		access, ok, err := s.Access().Chat(ctx, -100)
+		if err != nil || !ok {
+			t.Fatalf("Chat() = %v, %v", ok, err)
+		}
+		if access.Allowed || access.UpdatedBy != 42 || !access.UpdatedAt.Equal(now) {
+			t.Errorf("Chat() = %+v, want denied by 42", access)
+		}
+		if _, ok, _ := s.Access().Chat(ctx, 1); ok {
+			t.Error("Chat() found unknown chat")
+		}
+		list, err := s.Access().ListChats(ctx)
+		if err != nil {
+			t.Fatalf("ListChats() error = %v", err)
+		}
+		if len(list) != 2 || list[0].ChatID != -200 || !list[0].Allowed {
+			t.Errorf("ListChats() = %+v, want 2 ordered by ID", list)
+		}
+
+		if err := s.Access().Ban(ctx, Ban{UserID: 7, Reason: "spam", BannedBy: 42, BannedAt: now}); err != nil {
+			t.Fatalf("Ban() error = %v", err)
+		}
+		ban, ok, err := s.Access().Banned(ctx, 7)
+		if err != nil || !ok || ban.Reason != "spam" || !ban.BannedAt.Equal(now) {
+			t.Errorf("Banned() = %+v, %v, %v; want spam ban", ban, ok, err)
+		}
+		if removed, err := s.Access().Unban(ctx, 7); err != nil || !removed {
+			t.Errorf("Unban() = %v, %v; want removed", removed, err)
+		}
+		if removed, _ := s.Access().Unban(ctx, 7); removed {
+			t.Error("Unban() removed a ban twice")
+		}
+		if _, ok, _ := s.Access().Banned(ctx, 7); ok {
+			t.Error("Banned() after Unban()")
+		}
+	})
+}
+
+func TestFollows_SubscriptionsAndCursors(t *testing.T) {
+	backends(t, func(t *testing.T, s Storage) {
+		ctx := context.Background()
+		now := time.UnixMilli(1_700_000_000_000)
+
+		for _, follow := range []Follow{
+			{ChatID: -100, Username: "nasa", AddedBy: 42, CreatedAt: now},
+			{ChatID: -100, Username: "elonmusk", CreatedAt: now},
+			{ChatID: -200, Username: "nasa", CreatedAt: now},
+		} {
+			if added, err := s.Follows().Add(ctx, follow); err != nil || !added {
+				t.Fatalf("Add(%+v) = %v, %v; want added", follow, added, err)
+			}
+		}
+		if added, err := s.Follows().Add(ctx, Follow{ChatID: -100, Username: "nasa", CreatedAt: now}); err != nil || added {
+			t.Errorf("Add() duplicate = %v, %v; want not added", added, err)
+		}
+
+		list, err := s.Follows().ListByChat(ctx, -100)
+		if err != nil {
+			t.Fatalf("ListByChat() error = %v", err)
+		}
+		if len(list) != 2 || list[0].Username != "elonmusk" || list[1].AddedBy != 42 || !list[1].CreatedAt.Equal(now) {
+			t.Errorf("ListByChat() = %+v, want 2 ordered by username", list)
+		}
+		followers, err := s.Follows().Followers(ctx, "nasa")
+		if err != nil {
+			t.Fatalf("Followers() error = %v", err)
+		}
+		if len(followers) != 2 || followers[0].ChatID != -200 {
+			t.Errorf("Followers() = %+v, want 2 ordered by chat ID", followers)
+		}
+		accounts, err := s.Follows().Accounts(ctx)
+		if err != nil {
+			t.Fatalf("Accounts() error = %v", err)
+		}
+		if len(accounts) != 2 || accounts[0] != "elonmusk" || accounts[1] != "nasa" {
+			t.Errorf("Accounts() = %v, want [elonmusk nasa]", accounts)
+		}
+
+		if removed, err := s.Follows().Remove(ctx, -100, "elonmusk"); err != nil || !removed {
+			t.Errorf("Remove() = %v, %v; want removed", removed, err)
+		}
+		if removed, _ := s.Follows().Remove(ctx, -100, "elonmusk"); removed {
+			t.Error("Remove() removed a follow twice")
+		}
+		if accounts, _ := s.Follows().Accounts(ctx); len(accounts) != 1 {
+			t.Errorf("Accounts() after Remove() = %v, want [nasa]", accounts)
+		}
+
+		if _, ok, _ := s.Follows().Cursor(ctx, "nasa"); ok {
+			t.Error("Cursor() found cursor before SetCursor()")
+		}
+		for _, id := range []string{"100", "105"} {
+			if err := s.Follows().SetCursor(ctx, FollowCursor{Username: "nasa", LastTweetID: id, CheckedAt: now}); err != nil {
+				t.Fatalf("SetCursor() error = %v", err)
+			}
+		}
+		cursor, ok, err := s.Follows().Cursor(ctx, "nasa")
+		if err != nil || !ok || cursor.LastTweetID != "105" || !cursor.CheckedAt.Equal(now) {
+			t.Errorf("Cursor() = %+v, %v, %v; want 105", cursor, ok, err)
+		}
+	})
+}
+
+func TestWatches_AddListMuteRemove(t *testing.T) {
+	backends(t, func(t *testing.T, s Storage) {
+		ctx := context.Background()
+		now := time.UnixMilli(1_700_000_000_000)
+
+		first, err := s.Watches().Add(ctx, WatchRule{UserID: 7, Query: "golang -rust", CreatedAt: now})
+		if err != nil || first.ID == 0 {
+			t.Fatalf("Add() = %+v, %v; want an ID", first, err)
+		}
+		second, err := s.Watches().Add(ctx, WatchRule{UserID: 8, Query: "@nasa", CreatedAt: now})
+		if err != nil || second.ID <= first.ID {
+			t.Fatalf("Add() = %+v, %v; want a newer ID", second, err)
+		}
+
+		if ok, err := s.Watches().SetMuteWindow(ctx, 7, first.ID, "22:00-07:00"); err != nil || !ok {
+			t.Errorf("SetMuteWindow() = %v, %v; want updated", ok, err)
+		}
+		if ok, _ := s.Watches().SetMuteWindow(ctx, 8, first.ID, "01:00-02:00"); ok {
+			t.Error("SetMuteWindow() updated a rule of another user")
+		}
+
+		rules, err := s.Watches().ListByUser(ctx, 7)
+		if err != nil {
+			t.Fatalf("ListByUser() error = %v", err)
+		}
+		if len(rules) != 1 || rules[0].Query != "golang -rust" || rules[0].MuteWindow != "22:00-07:00" || !rules[0].CreatedAt.Equal(now) {
+			t.Errorf("ListByUser() = %+v", rules)
+		}
+		if all, _ := s.Watches().List(ctx); len(all) != 2 || all[0].ID != first.ID {
+			t.Errorf("List() = %+v, want 2 ordered by ID", all)
+		}
+
+		if removed, _ := s.Watches().Remove(ctx, 8, first.ID); removed {
+			t.Error("Remove() removed a rule of another user")
+		}
+		if removed, err := s.Watches().Remove(ctx, 7, first.ID); err != nil || !removed {
+			t.Errorf("Remove() = %v, %v; want removed", removed, err)
+		}
+		if all, _ := s.Watches().List(ctx); len(all) != 1 {
+			t.Errorf("List() after Remove() = %+v", all)
+		}
+	})
+}
+
+func TestDigests_SetListMarkSent(t *testing.T) {
+	backends(t, func(t *testing.T, s Storage) {
+		ctx := context.Background()
+		now := time.UnixMilli(1_700_000_000_000)
+
+		weekly := Digest{ChatID: -100, Frequency: DigestWeekly, Weekday: time.Friday, Minute: 18 * 60, Article: true, UpdatedBy: 42, UpdatedAt: now}
+		if err := s.Digests().Set(ctx, weekly); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		if err := s.Digests().Set(ctx, Digest{ChatID: -200, Frequency: DigestDaily, Minute: 9 * 60, UpdatedAt: now}); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+
+		got, ok, err := s.Digests().Get(ctx, -100)
+		if err != nil || !ok || got != weekly {
+			t.Errorf("Get() = %+v, %v, %v; want %+v", got, ok, err, weekly)
+		}
+		if _, ok, _ := s.Digests().Get(ctx, 1); ok {
+			t.Error("Get() found unknown chat")
+		}
+
+		sentAt := now.Add(time.Hour)
+		if err := s.Digests().MarkSent(ctx, -100, sentAt); err != nil {
+			t.Fatalf("MarkSent() error = %v", err)
+		}
+		list, err := s.Digests().List(ctx)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if len(list) != 2 || list[0].ChatID != -200 || !list[1].LastSentAt.Equal(sentAt) {
+			t.Errorf("List() = %+v, want 2 ordered by chat ID", list)
+		}
+
+		if deleted, err := s.Digests().Delete(ctx, -200); err != nil || !deleted {
+			t.Errorf("Delete() = %v, %v; want deleted", deleted, err)
+		}
+		if deleted, _ := s.Digests().Delete(ctx, -200); deleted {
+			t.Error("Delete() deleted twice")
+		}
+	})
+}
+
+func TestOpenSQLite_PersistsAcrossReopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "nested", "bot.db")
+
+	s, err := OpenSQLite(ctx, path)
+	if err != nil {
+		t.Fatalf("OpenSQLite() error = %v", err)
+	}
+	if err := s.Settings().Save(ctx, 1, chatsettings.Settings{TranslateTo: "uk"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	s.Close()
+
+	// Reopening must not re-apply migrations
+	s, err = OpenSQLite(ctx, path)
+	if err != nil {
+		t.Fatalf("reopen error = %v", err)
+	}
+	defer s.Close()
+
+	got, err := s.Settings().Get(ctx, 1)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.TranslateTo != "uk" {
+		t.Errorf("TranslateTo = %q after reopen, want %q", got.TranslateTo, "uk")
+	}
+
+	var applied int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM schema_migrations`).Scan(&applied); err != nil {
+		t.Fatalf("count migrations: %v", err)
+	}
+	migrations, _ := loadMigrations()
+	if applied != len(migrations) {
+		t.Errorf("applied migrations = %d, want %d", applied, len(migrations))
+	}
+
+	var userIDColumns int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM pragma_table_info('history') WHERE name = 'user_id'`).Scan(&userIDColumns); err != nil {
+		t.Fatalf("history columns: %v", err)
+	}
+	if userIDColumns != 0 {
+		t.Error("history.user_id still exists, want it dropped")
+	}
+}
+
+func TestReplies_AddListRemove(t *testing.T) {
+	backends(t, func(t *testing.T, s Storage) {
+		ctx := context.Background()
+		now := time.UnixMilli(1_700_000_000_000)
+
+		replies := []Reply{
+			{ChatID: -100, SourceMessageID: 10, TweetID: "1", MessageID: 11, SentAt: now},
+			{ChatID: -100, SourceMessageID: 10, TweetID: "1", MessageID: 12, SentAt: now},
+			{ChatID: -100, SourceMessageID: 10, TweetID: "2", MessageID: 13, SentAt: now.Add(time.Second)},
+			{ChatID: -100, SourceMessageID: 20, TweetID: "1", MessageID: 21, SentAt: now},
+		}
+		for _, reply := range replies {
+			if err := s.Replies().Add(ctx, reply); err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+		}
+
+		list, err := s.Replies().List(ctx, -100, 10)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if len(list) != 3 || list[0] != replies[0] || list[2] != replies[2] {
+			t.Errorf("List() = %+v, want the replies to message 10 in send order", list)
+		}
+
+		if err := s.Replies().Remove(ctx, -100, 10, "1"); err != nil {
+			t.Fatalf("Remove() error = %v", err)
+		}
+		list, _ = s.Replies().List(ctx, -100, 10)
+		if len(list) != 1 || list[0].TweetID != "2" {
+			t.Errorf("List() after Remove() = %+v, want only tweet 2", list)
+		}
+		if list, _ := s.Replies().List(ctx, -100, 20); len(list) != 1 {
+			t.Errorf("List() of another message = %+v, want untouched", list)
+		}
+	})
+}
+
+func TestReplies_Prune(t *testing.T) {
+	backends(t, func(t *testing.T, s Storage) {
+		ctx := context.Background()
+		now := time.UnixMilli(1_700_000_000_000)
+
+		replies := []Reply{
+			{ChatID: -100, SourceMessageID: 10, TweetID: "1", MessageID: 11, SentAt: now.Add(-time.Hour)},
+			{ChatID: -100, SourceMessageID: 10, TweetID: "2", MessageID: 12, SentAt: now},
+			{ChatID: -100, SourceMessageID: 20, TweetID: "1", MessageID: 21, SentAt: now.Add(-time.Hour)},
+		}
+		for _, reply := range replies {
+			if err := s.Replies().Add(ctx, reply); err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+		}
+
+		pruned, err := s.Replies().Prune(ctx, now)
+		if err != nil {
+			t.Fatalf("Prune() error = %v", err)
+		}
+		if pruned != 2 {
+			t.Errorf("Prune() = %d, want 2", pruned)
+		}
+		if list, _ := s.Replies().List(ctx, -100, 10); len(list) != 1 || list[0] != replies[1] {
+			t.Errorf("List() after Prune() = %+v, want only the reply sent at the cutoff", list)
+		}
+		if list, _ := s.Replies().List(ctx, -100, 20); len(list) != 0 {
+			t.Errorf("List() of a pruned message = %+v, want none", list)
+		}
+	})
+}
+
+func TestBookmarks_AddSearchPageRemove(t *testing.T) {
+	backends(t, func(t *testing.T, s Storage) {
+		ctx := context.Background()
+		now := time.UnixMilli(1_700_000_000_000)
+
+		bookmarks := []Bookmark{
+			{UserID: 7, TweetID: "1", Author: "nasa", Tweet: &twitterxapi.Tweet{ID: "1", Text: "Launch day"}, CreatedAt: now},
+			{UserID: 7, TweetID: "2", Author: "golang", Tweet: &twitterxapi.Tweet{ID: "2", Text: "Go 1.22 is out"}, CreatedAt: now.Add(time.Second)},
+			{UserID: 7, TweetID: "3", Author: "nasa", Tweet: &twitterxapi.Tweet{ID: "3", Text: "Moon photos"}, CreatedAt: now.Add(2 * time.Second)},
+			{UserID: 8, TweetID: "1", Author: "nasa", Tweet: &twitterxapi.Tweet{ID: "1", Text: "Launch day"}, CreatedAt: now},
+		}
+		var ids []int64
+		for _, bookmark := range bookmarks {
+			added, ok, err := s.Bookmarks().Add(ctx, bookmark)
+			if err != nil || !ok {
+				t.Fatalf("Add() = %v, %v, want a new bookmark", ok, err)
+			}
+			ids = append(ids, added.ID)
+		}
+
+		again, ok, err := s.Bookmarks().Add(ctx, Bookmark{UserID: 7, TweetID: "1", Author: "nasa", CreatedAt: now.Add(time.Hour)})
+		if err != nil || ok || again.ID != ids[0] {
+			t.Errorf("Add() of a saved tweet = %+v, %v, %v, want bookmark %d unchanged", again, ok, err, ids[0])
+		}
+
+		page, total, err := s.Bookmarks().List(ctx, BookmarkFilter{UserID: 7, Offset: 1, Limit: 1})
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if total != 3 || len(page) != 1 || page[0].TweetID != "2" || page[0].Tweet.Text != "Go 1.22 is out" {
+			t.Errorf("List() page = %+v (total %d), want tweet 2 of 3", page, total)
+		}
+
+		found, total, _ := s.Bookmarks().List(ctx, BookmarkFilter{UserID: 7, Search: "NASA"})
+		if total != 2 || len(found) != 2 || found[0].TweetID != "3" {
+			t.Errorf("List() search = %+v (total %d), want tweets 3 and 1", found, total)
+		}
+		if found, _, _ := s.Bookmarks().List(ctx, BookmarkFilter{UserID: 7, Search: "moon"}); len(found) != 1 {
+			t.Errorf("List() text search = %+v, want tweet 3", found)
+		}
+
+		if removed, err := s.Bookmarks().Remove(ctx, 8, ids[0]); err != nil || removed {
+			t.Errorf("Remove() of another user's bookmark = %v, %v, want false", removed, err)
+		}
+		if removed, err := s.Bookmarks().Remove(ctx, 7, ids[0]); err != nil || !removed {
+			t.Errorf("Remove() = %v, %v, want true", removed, err)
+		}
+		if _, ok, _ := s.Bookmarks().Get(ctx, 7, ids[0]); ok {
+			t.Error("Get() after Remove() found the bookmark")
+		}
+		if bookmark, ok, _ := s.Bookmarks().Get(ctx, 8, ids[3]); !ok || bookmark.Tweet.Text != "Launch day" {
+			t.Errorf("Get() = %+v, %v, want the other user's bookmark kept", bookmark, ok)
+		}
+	})
+}
@@ -0,0 +1,211 @@
+// Package outbox paces outgoing Telegram messages: sends to one chat are serialized,
+// kept under the global and per-group Bot API limits, and retried after 429 responses.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/ratelimit"
+)
+
+// Bot API broadcast limits: about 30 messages per second overall and 20 per minute in a group.
+var (
+	DefaultGlobalLimit = ratelimit.Limit{Burst: 30, Per: time.Second}
+	DefaultGroupLimit  = ratelimit.Limit{Burst: 20, Per: time.Minute}
+)
+
+const (
+	defaultMaxRetries    = 3
+	defaultMaxRetryAfter = time.Minute
+)
+
+// Queue serializes and paces sends. The zero value is not usable; create it with New.
+type Queue struct {
+	log           *logger.Logger
+	global        *ratelimit.Limiter
+	group         *ratelimit.Limiter
+	maxRetries    int
+	maxRetryAfter time.Duration
+	sleep         func(ctx context.Context, d time.Duration) error
+
+	mu    sync.Mutex
+	lanes map[int64]*lane
+}
+
+// lane orders the sends to one chat: turn holds a token while a caller has the chat's turn.
+// refs counts the callers using it.
+type lane struct {
+	turn chan struct{}
+	refs int
+}
+
+// heldLane is the context key marking that the caller holds the turn of a chat, see Hold.
+type heldLane struct {
+	q      *Queue
+	chatID int64
+}
+
+// Option configures a Queue.
+type Option func(*Queue)
+
+// WithLimits overrides the global and per-group limits; a zero Limit disables pacing for that scope.
+func WithLimits(global, group ratelimit.Limit) Option {
+	return func(q *Queue) {
+		q.global = ratelimit.New(global)
+		q.group = ratelimit.New(group)
+	}
+}
+
+// WithRetry sets how many times a send is retried after a 429 response and the longest
+// retry_after that is waited out; longer waits fail the send.
+func WithRetry(maxRetries int, maxRetryAfter time.Duration) Option {
+	return func(q *Queue) {
+		q.maxRetries = maxRetries
+		q.maxRetryAfter = maxRetryAfter
+	}
+}
+
+// New creates a queue with the default Bot API limits.
+func New(log *logger.Logger, opts ...Option) *Queue {
+	q := &Queue{
+		log:           log,
+		global:        ratelimit.New(DefaultGlobalLimit),
+		group:         ratelimit.New(DefaultGroupLimit),
+		maxRetries:    defaultMaxRetries,
+		maxRetryAfter: defaultMaxRetryAfter,
+		sleep:         sleep,
+		lanes:         make(map[int64]*lane),
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// Hold waits for chatID's turn and keeps it until release is called, so that a sequence of
+// sends, such as a chain of replies, is not interleaved with the sends of other callers.
+// Do called with the returned context sends within the held turn.
+func (q *Queue) Hold(ctx context.Context, chatID int64) (held context.Context, release func(), err error) {
+	if q.holds(ctx, chatID) {
+		return ctx, func() {}, nil
+	}
+	l, err := q.acquire(ctx, chatID)
+	if err != nil {
+		return ctx, func() {}, err
+	}
+	var once sync.Once
+	return context.WithValue(ctx, heldLane{q: q, chatID: chatID}, true), func() {
+		once.Do(func() { q.release(chatID, l) })
+	}, nil
+}
+
+// Do calls send once it is chatID's turn and the limits allow the given number of
+// messages (a media group counts each item), retrying it after 429 responses.
+// Waits end with the error of ctx once it is done.
+func (q *Queue) Do(ctx context.Context, chatID int64, messages int, send func() error) error {
+	if !q.holds(ctx, chatID) {
+		l, err := q.acquire(ctx, chatID)
+		if err != nil {
+			return err
+		}
+		defer q.release(chatID, l)
+	}
+
+	for attempt := 0; ; attempt++ {
+		wait := q.global.Reserve(0, messages)
+		// Negative IDs are groups, supergroups and channels
+		if chatID < 0 {
+			if groupWait := q.group.Reserve(chatID, messages); groupWait > wait {
+				wait = groupWait
+			}
+		}
+		if err := q.sleep(ctx, wait); err != nil {
+			return err
+		}
+
+		err := send()
+		retryAfter, ok := RetryAfter(err)
+		if !ok {
+			return err
+		}
+		if attempt >= q.maxRetries || retryAfter > q.maxRetryAfter {
+			return err
+		}
+		q.log.With("component", "outbox", "chat_id", chatID).Warn("flood limit hit, retrying", "retry_after", retryAfter, "attempt", attempt+1)
+		if err := q.sleep(ctx, retryAfter); err != nil {
+			return err
+		}
+	}
+}
+
+// holds reports whether ctx was returned by Hold for chatID.
+func (q *Queue) holds(ctx context.Context, chatID int64) bool {
+	held, _ := ctx.Value(heldLane{q: q, chatID: chatID}).(bool)
+	return held
+}
+
+// acquire waits for chatID's turn, or until ctx is done.
+func (q *Queue) acquire(ctx context.Context, chatID int64) (*lane, error) {
+	q.mu.Lock()
+	l, ok := q.lanes[chatID]
+	if !ok {
+		l = &lane{turn: make(chan struct{}, 1)}
+		q.lanes[chatID] = l
+	}
+	l.refs++
+	q.mu.Unlock()
+
+	select {
+	case l.turn <- struct{}{}:
+		return l, nil
+	case <-ctx.Done():
+		q.unref(chatID, l)
+		return nil, ctx.Err()
+	}
+}
+
+func (q *Queue) release(chatID int64, l *lane) {
+	<-l.turn
+	q.unref(chatID, l)
+}
+
+func (q *Queue) unref(chatID int64, l *lane) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	l.refs--
+	if l.refs == 0 {
+		delete(q.lanes, chatID)
+	}
+}
+
+// RetryAfter returns the delay requested by a 429 Too Many Requests error.
+func RetryAfter(err error) (time.Duration, bool) {
+	var tgErr *gotgbot.TelegramError
+	if !errors.As(err, &tgErr) || tgErr.Code != 429 {
+		return 0, false
+	}
+	if tgErr.ResponseParams == nil || tgErr.ResponseParams.RetryAfter <= 0 {
+		return time.Second, true
+	}
+	return time.Duration(tgErr.ResponseParams.RetryAfter) * time.Second, true
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
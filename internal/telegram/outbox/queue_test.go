@@ -0,0 +1,374 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+
+	"twitterx-bot/internal/chain"
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/ratelimit"
+	"twitterx-bot/internal/telegram/tweet"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+// newTestQueue returns a queue whose sleeps are recorded instead of waited.
+func newTestQueue(opts ...Option) (*Queue, *[]time.Duration) {
+	var mu sync.Mutex
+	var sleeps []time.Duration
+	q := New(logger.Default(), opts...)
+	q.sleep = func(ctx context.Context, d time.Duration) error {
+		if d > 0 {
+			mu.Lock()
+			sleeps = append(sleeps, d)
+			mu.Unlock()
+		}
+		return ctx.Err()
+	}
+	return q, &sleeps
+}
+
+func floodErr(retryAfter int64) error {
+	return &gotgbot.TelegramError{
+		Code:           429,
+		Description:    "Too Many Requests: retry after",
+		ResponseParams: &gotgbot.ResponseParameters{RetryAfter: retryAfter},
+	}
+}
+
+func TestQueue_RetriesAfterFloodWait(t *testing.T) {
+	q, sleeps := newTestQueue()
+
+	calls := 0
+	err := q.Do(context.Background(), 1, 1, func() error {
+		calls++
+		if calls == 1 {
+			return floodErr(7)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("send calls = %d, want 2", calls)
+	}
+	if len(*sleeps) != 1 || (*sleeps)[0] != 7*time.Second {
+		t.Errorf("sleeps = %v, want retry_after of 7s", *sleeps)
+	}
+}
+
+func TestQueue_GivesUp(t *testing.T) {
+	t.Run("too many retries", func(t *testing.T) {
+		q, _ := newTestQueue(WithRetry(2, time.Minute))
+		calls := 0
+		err := q.Do(context.Background(), 1, 1, func() error {
+			calls++
+			return floodErr(1)
+		})
+		if _, ok := RetryAfter(err); !ok {
+			t.Errorf("Do() error = %v, want the flood error", err)
+		}
+		if calls != 3 {
+			t.Errorf("send calls = %d, want 3", calls)
+		}
+	})
+
+	t.Run("retry_after too long", func(t *testing.T) {
+		q, sleeps := newTestQueue(WithRetry(3, 10*time.Second))
+		calls := 0
+		_ = q.Do(context.Background(), 1, 1, func() error {
+			calls++
+			return floodErr(60)
+		})
+		if calls != 1 || len(*sleeps) != 0 {
+			t.Errorf("send calls = %d, sleeps = %v; want no retry", calls, *sleeps)
+		}
+	})
+
+	t.Run("other errors", func(t *testing.T) {
+		q, _ := newTestQueue()
+		want := errors.New("bad request")
+		calls := 0
+		if err := q.Do(context.Background(), 1, 1, func() error {
+			calls++
+			return want
+		}); !errors.Is(err, want) || calls != 1 {
+			t.Errorf("Do() = %v after %d calls, want the error without retries", err, calls)
+		}
+	})
+}
+
+func TestQueue_PacesGroups(t *testing.T) {
+	q, sleeps := newTestQueue(WithLimits(ratelimit.Limit{}, ratelimit.Limit{Burst: 2, Per: time.Minute}))
+	send := func() error { return nil }
+
+	for i := 0; i < 2; i++ {
+		if err := q.Do(context.Background(), -100, 1, send); err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+	}
+	if len(*sleeps) != 0 {
+		t.Fatalf("sleeps = %v, want the burst sent at once", *sleeps)
+	}
+
+	if err := q.Do(context.Background(), -100, 1, send); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if len(*sleeps) != 1 || (*sleeps)[0] < 29*time.Second {
+		t.Errorf("sleeps = %v, want about 30s for the third message", *sleeps)
+	}
+
+	// Private chats are only subject to the global limit
+	for i := 0; i < 5; i++ {
+		if err := q.Do(context.Background(), 100, 1, send); err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+	}
+	if len(*sleeps) != 1 {
+		t.Errorf("sleeps = %v, want private chats unpaced", *sleeps)
+	}
+}
+
+func TestQueue_SerializesChat(t *testing.T) {
+	q := New(logger.Default(), WithLimits(ratelimit.Limit{}, ratelimit.Limit{}))
+
+	var inFlight, maxInFlight atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = q.Do(context.Background(), -100, 1, func() error {
+				n := inFlight.Add(1)
+				for {
+					m := maxInFlight.Load()
+					if n <= m || maxInFlight.CompareAndSwap(m, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				inFlight.Add(-1)
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight.Load() != 1 {
+		t.Errorf("concurrent sends to one chat = %d, want 1", maxInFlight.Load())
+	}
+	if len(q.lanes) != 0 {
+		t.Errorf("lanes = %d, want released", len(q.lanes))
+	}
+}
+
+func TestQueue_StopsOnCancel(t *testing.T) {
+	q := New(logger.Default(), WithLimits(ratelimit.Limit{}, ratelimit.Limit{}))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	err := q.Do(ctx, 1, 1, func() error {
+		calls++
+		return floodErr(30)
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Do() error = %v, want context.Canceled", err)
+	}
+	if calls > 1 {
+		t.Errorf("send calls = %d, want no retry after cancel", calls)
+	}
+}
+
+// floodingBot fails the message with index floodAt once with a 429 response.
+type floodingBot struct {
+	floodAt int
+	flooded bool
+	texts   []string
+	replyTo []int64
+}
+
+func (b *floodingBot) SendVideo(int64, gotgbot.InputFileOrString, *gotgbot.SendVideoOpts) (*gotgbot.Message, error) {
+	return nil, errors.New("unexpected video")
+}
+
+func (b *floodingBot) SendPhoto(int64, gotgbot.InputFileOrString, *gotgbot.SendPhotoOpts) (*gotgbot.Message, error) {
+	return nil, errors.New("unexpected photo")
+}
+
+func (b *floodingBot) SendMediaGroup(int64, []gotgbot.InputMedia, *gotgbot.SendMediaGroupOpts) ([]gotgbot.Message, error) {
+	return nil, errors.New("unexpected media group")
+}
+
+func (b *floodingBot) SendMessage(_ int64, text string, opts *gotgbot.SendMessageOpts) (*gotgbot.Message, error) {
+	if len(b.texts) == b.floodAt && !b.flooded {
+		b.flooded = true
+		return nil, floodErr(3)
+	}
+	b.texts = append(b.texts, text)
+	b.replyTo = append(b.replyTo, opts.ReplyParameters.MessageId)
+	return &gotgbot.Message{MessageId: int64(100 + len(b.texts))}, nil
+}
+
+func TestQueue_ChainCompletesAfterFloodLimit(t *testing.T) {
+	q, sleeps := newTestQueue()
+	bot := &floodingBot{floodAt: 1}
+	sender := tweet.Sender{Bot: bot, Queue: q}
+
+	var items []chain.ChainItem
+	for _, text := range []string{"first", "second", "third"} {
+		items = append(items, chain.ChainItem{
+			Tweet: &twitterxapi.Tweet{ID: text, Text: text, Author: twitterxapi.Author{Name: "User", ScreenName: "user"}},
+			Type:  chain.ChainTypeReply,
+		})
+	}
+
+	if err := sender.SendChainResponse(-100, items, 10, nil); err != nil {
+		t.Fatalf("SendChainResponse() error = %v", err)
+	}
+	if len(bot.texts) != 3 {
+		t.Fatalf("messages sent = %d, want the whole chain", len(bot.texts))
+	}
+	wantReplyTo := []int64{10, 101, 102}
+	for i, want := range wantReplyTo {
+		if bot.replyTo[i] != want {
+			t.Errorf("message %d replies to %d, want %d", i, bot.replyTo[i], want)
+		}
+	}
+	if len(*sleeps) != 1 || (*sleeps)[0] != 3*time.Second {
+		t.Errorf("sleeps = %v, want one 3s flood wait", *sleeps)
+	}
+}
+
+// pausingBot records the texts sent and pauses on the first one until resume is closed.
+type pausingBot struct {
+	floodingBot
+	mu      sync.Mutex
+	paused  chan struct{}
+	resume  chan struct{}
+	pausing sync.Once
+}
+
+func (b *pausingBot) SendMessage(chatID int64, text string, opts *gotgbot.SendMessageOpts) (*gotgbot.Message, error) {
+	b.pausing.Do(func() {
+		close(b.paused)
+		<-b.resume
+	})
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.floodingBot.SendMessage(chatID, text, opts)
+}
+
+func TestQueue_ChainsToOneChatDoNotInterleave(t *testing.T) {
+	q := New(logger.Default(), WithLimits(ratelimit.Limit{}, ratelimit.Limit{}))
+	bot := &pausingBot{floodingBot: floodingBot{floodAt: -1}, paused: make(chan struct{}), resume: make(chan struct{})}
+	sender := tweet.Sender{Bot: bot, Queue: q}
+
+	chainOf := func(prefix string) []chain.ChainItem {
+		var items []chain.ChainItem
+		for _, n := range []string{"1", "2", "3"} {
+			items = append(items, chain.ChainItem{
+				Tweet: &twitterxapi.Tweet{ID: prefix + n, Text: prefix + n, Author: twitterxapi.Author{ScreenName: "user"}},
+				Type:  chain.ChainTypeReply,
+			})
+		}
+		return items
+	}
+
+	first := make(chan error, 1)
+	go func() { first <- sender.SendChain(context.Background(), -100, chainOf("a"), 10, nil) }()
+	<-bot.paused
+
+	second := make(chan error, 1)
+	go func() { second <- sender.SendChain(context.Background(), -100, chainOf("b"), 20, nil) }()
+	// The second chain waits for the chat's turn while the first is in the middle of sending
+	time.Sleep(20 * time.Millisecond)
+	close(bot.resume)
+
+	for _, done := range []chan error{first, second} {
+		if err := <-done; err != nil {
+			t.Fatalf("SendChain() error = %v", err)
+		}
+	}
+	want := []string{"a1", "a2", "a3", "b1", "b2", "b3"}
+	if len(bot.texts) != len(want) {
+		t.Fatalf("texts = %v, want %v", bot.texts, want)
+	}
+	for i, text := range bot.texts {
+		if !strings.Contains(text, want[i]) {
+			t.Fatalf("texts = %v, want the chains in order %v", bot.texts, want)
+		}
+	}
+	if len(q.lanes) != 0 {
+		t.Errorf("lanes = %d, want released", len(q.lanes))
+	}
+}
+
+func TestQueue_ChainOverGroupBudgetOutlivesFetchDeadline(t *testing.T) {
+	// Two messages per 100ms: the chain of six waits about 200ms for the group budget
+	q := New(logger.Default(), WithLimits(ratelimit.Limit{}, ratelimit.Limit{Burst: 2, Per: 100 * time.Millisecond}))
+	bot := &floodingBot{floodAt: -1}
+	sender := tweet.Sender{Bot: bot, Queue: q}
+
+	var items []chain.ChainItem
+	for i := 1; i <= 6; i++ {
+		id := strconv.Itoa(i)
+		items = append(items, chain.ChainItem{
+			Tweet: &twitterxapi.Tweet{ID: id, Text: "tweet " + id, Author: twitterxapi.Author{ScreenName: "user"}},
+			Type:  chain.ChainTypeReply,
+		})
+	}
+
+	// The handler deadline covers fetching the chain, not waiting for the queue
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := sender.SendChain(ctx, -100, items, 10, nil); err != nil {
+		t.Fatalf("SendChain() error = %v", err)
+	}
+	if len(bot.texts) != len(items) {
+		t.Errorf("messages sent = %d, want the whole chain of %d", len(bot.texts), len(items))
+	}
+}
+
+func TestQueue_WaitForTurnEndsWithContext(t *testing.T) {
+	q := New(logger.Default(), WithLimits(ratelimit.Limit{}, ratelimit.Limit{}))
+	_, release, err := q.Hold(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Hold() error = %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	calls := 0
+	err = q.Do(ctx, 1, 1, func() error {
+		calls++
+		return nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) || calls != 0 {
+		t.Errorf("Do() = %v after %d sends, want the deadline before sending", err, calls)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	if d, ok := RetryAfter(floodErr(5)); !ok || d != 5*time.Second {
+		t.Errorf("RetryAfter(429) = %v, %v; want 5s", d, ok)
+	}
+	if d, ok := RetryAfter(&gotgbot.TelegramError{Code: 429}); !ok || d != time.Second {
+		t.Errorf("RetryAfter(429 without params) = %v, %v; want 1s", d, ok)
+	}
+	if _, ok := RetryAfter(&gotgbot.TelegramError{Code: 400}); ok {
+		t.Error("RetryAfter(400) ok = true")
+	}
+	if _, ok := RetryAfter(nil); ok {
+		t.Error("RetryAfter(nil) ok = true")
+	}
+}
@@ -1,8 +1,12 @@
 package tweet
 
-import "context"
+import (
+	"context"
 
-// ArticleCreator - інтерфейс для створення статей (Telegraph)
+	"twitterx-bot/internal/twitterxapi"
+)
+
+// ArticleCreator - інтерфейс для створення статей (Telegraph) з твітів
 type ArticleCreator interface {
-	CreateArticle(ctx context.Context, text, title string) (string, error)
+	CreateArticle(ctx context.Context, tweet *twitterxapi.Tweet) (string, error)
 }
@@ -10,6 +10,16 @@ import (
 const (
 	ChainCallbackPrefix  = "chain:"
 	DeleteCallbackPrefix = "del:"
+	RepostCallbackPrefix = "repost:"
+	SaveCallbackPrefix   = "save:"
+	ExportCallbackPrefix = "export:"
+)
+
+const (
+	// saveButtonText is the label of the button bookmarking a tweet.
+	saveButtonText = "⭐ Save"
+	// exportButtonText is the label of the button exporting a chain as a document.
+	exportButtonText = "📄 Export"
 )
 
 // EncodeChainCallback creates callback data for the "Send full chain" button.
@@ -21,11 +31,76 @@ func EncodeChainCallback(username, tweetID string, replyToMsgID int64) string {
 // DecodeChainCallback parses callback data and extracts username, tweetID, and replyToMsgID.
 // Returns ok=false if the format is invalid.
 func DecodeChainCallback(data string) (username, tweetID string, replyToMsgID int64, ok bool) {
-	if !strings.HasPrefix(data, ChainCallbackPrefix) {
+	return decodeTweetCallback(ChainCallbackPrefix, data)
+}
+
+// EncodeRepostCallback creates callback data for the "Send again" button of an "already shared" notice.
+// Format: repost:username:tweetID:replyToMsgID
+func EncodeRepostCallback(username, tweetID string, replyToMsgID int64) string {
+	return RepostCallbackPrefix + username + ":" + tweetID + ":" + strconv.FormatInt(replyToMsgID, 10)
+}
+
+// DecodeRepostCallback parses callback data and extracts username, tweetID, and replyToMsgID.
+// Returns ok=false if the format is invalid.
+func DecodeRepostCallback(data string) (username, tweetID string, replyToMsgID int64, ok bool) {
+	return decodeTweetCallback(RepostCallbackPrefix, data)
+}
+
+// EncodeSaveCallback creates callback data for the "⭐ Save" button.
+// Format: save:username:tweetID
+func EncodeSaveCallback(username, tweetID string) string {
+	return SaveCallbackPrefix + username + ":" + tweetID
+}
+
+// DecodeSaveCallback parses callback data and extracts username and tweetID.
+// Returns ok=false if the format is invalid.
+func DecodeSaveCallback(data string) (username, tweetID string, ok bool) {
+	if !strings.HasPrefix(data, SaveCallbackPrefix) {
+		return "", "", false
+	}
+	username, tweetID, ok = strings.Cut(strings.TrimPrefix(data, SaveCallbackPrefix), ":")
+	if !ok || username == "" || tweetID == "" || strings.Contains(tweetID, ":") {
+		return "", "", false
+	}
+	return username, tweetID, true
+}
+
+// EncodeExportCallback creates callback data for the "📄 Export" button and its format picker.
+// Format: export:username:tweetID, or export:username:tweetID:format once a format is picked.
+func EncodeExportCallback(username, tweetID, format string) string {
+	data := ExportCallbackPrefix + username + ":" + tweetID
+	if format != "" {
+		data += ":" + format
+	}
+	return data
+}
+
+// DecodeExportCallback parses callback data and extracts username, tweetID, and the optional format.
+// Returns ok=false if the format is invalid.
+func DecodeExportCallback(data string) (username, tweetID, format string, ok bool) {
+	if !strings.HasPrefix(data, ExportCallbackPrefix) {
+		return "", "", "", false
+	}
+	parts := strings.Split(strings.TrimPrefix(data, ExportCallbackPrefix), ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", false
+	}
+	if len(parts) == 3 {
+		if parts[2] == "" {
+			return "", "", "", false
+		}
+		format = parts[2]
+	}
+	return parts[0], parts[1], format, true
+}
+
+// decodeTweetCallback parses prefix + username:tweetID:replyToMsgID.
+func decodeTweetCallback(prefix, data string) (username, tweetID string, replyToMsgID int64, ok bool) {
+	if !strings.HasPrefix(data, prefix) {
 		return "", "", 0, false
 	}
 
-	rest := strings.TrimPrefix(data, ChainCallbackPrefix)
+	rest := strings.TrimPrefix(data, prefix)
 	parts := strings.SplitN(rest, ":", 3)
 	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
 		return "", "", 0, false
@@ -147,3 +222,39 @@ func BuildChainOnlyKeyboard(chainCallbackData string) *gotgbot.InlineKeyboardMar
 		},
 	}
 }
+
+// FindSaveButton searches for the "⭐ Save" button in the keyboard and returns its callback data.
+// Returns empty string if not found.
+func FindSaveButton(markup *gotgbot.InlineKeyboardMarkup) string {
+	if markup == nil {
+		return ""
+	}
+	for _, row := range markup.InlineKeyboard {
+		for _, btn := range row {
+			if strings.HasPrefix(btn.CallbackData, SaveCallbackPrefix) {
+				return btn.CallbackData
+			}
+		}
+	}
+	return ""
+}
+
+// AddSaveButton returns a copy of markup (which may be nil) with a "⭐ Save" row below its buttons.
+func AddSaveButton(markup *gotgbot.InlineKeyboardMarkup, saveCallbackData string) *gotgbot.InlineKeyboardMarkup {
+	return addButtonRow(markup, gotgbot.InlineKeyboardButton{Text: saveButtonText, CallbackData: saveCallbackData})
+}
+
+// AddExportButton returns a copy of markup (which may be nil) with a "📄 Export" row below its buttons.
+func AddExportButton(markup *gotgbot.InlineKeyboardMarkup, exportCallbackData string) *gotgbot.InlineKeyboardMarkup {
+	return addButtonRow(markup, gotgbot.InlineKeyboardButton{Text: exportButtonText, CallbackData: exportCallbackData})
+}
+
+// addButtonRow returns a copy of markup (which may be nil) with a row holding button below its buttons.
+func addButtonRow(markup *gotgbot.InlineKeyboardMarkup, button gotgbot.InlineKeyboardButton) *gotgbot.InlineKeyboardMarkup {
+	var rows [][]gotgbot.InlineKeyboardButton
+	if markup != nil {
+		rows = append(rows, markup.InlineKeyboard...)
+	}
+	rows = append(rows, []gotgbot.InlineKeyboardButton{button})
+	return &gotgbot.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
@@ -413,3 +413,84 @@ func TestCallbackDataLength(t *testing.T) {
 		}
 	})
 }
+
+func TestRepostCallbackRoundTrip(t *testing.T) {
+	encoded := EncodeRepostCallback("alice", "123456789", 42)
+	if encoded != "repost:alice:123456789:42" {
+		t.Fatalf("EncodeRepostCallback() = %q", encoded)
+	}
+
+	username, tweetID, replyToMsgID, ok := DecodeRepostCallback(encoded)
+	if !ok || username != "alice" || tweetID != "123456789" || replyToMsgID != 42 {
+		t.Fatalf("DecodeRepostCallback() = %q, %q, %d, %v", username, tweetID, replyToMsgID, ok)
+	}
+
+	if _, _, _, ok := DecodeRepostCallback(EncodeChainCallback("alice", "1", 2)); ok {
+		t.Fatal("DecodeRepostCallback() accepted chain callback data")
+	}
+}
+
+func TestSaveCallbackRoundTrip(t *testing.T) {
+	encoded := EncodeSaveCallback("alice", "1234567890123456789")
+	if encoded != "save:alice:1234567890123456789" {
+		t.Fatalf("EncodeSaveCallback() = %q", encoded)
+	}
+
+	username, tweetID, ok := DecodeSaveCallback(encoded)
+	if !ok || username != "alice" || tweetID != "1234567890123456789" {
+		t.Fatalf("DecodeSaveCallback() = %q, %q, %v", username, tweetID, ok)
+	}
+
+	for _, data := range []string{"save:", "save:alice", "save::1", "save:alice:1:2", EncodeRepostCallback("alice", "1", 2)} {
+		if _, _, ok := DecodeSaveCallback(data); ok {
+			t.Errorf("DecodeSaveCallback(%q) ok = true, want false", data)
+		}
+	}
+}
+
+func TestAddSaveButton(t *testing.T) {
+	keyboard := BuildKeyboard(100, nil)
+	got := AddSaveButton(keyboard, EncodeSaveCallback("alice", "1"))
+
+	if len(keyboard.InlineKeyboard) != 1 {
+		t.Fatalf("AddSaveButton() modified the original keyboard: %+v", keyboard)
+	}
+	if len(got.InlineKeyboard) != 2 || got.InlineKeyboard[1][0].Text != "⭐ Save" {
+		t.Fatalf("AddSaveButton() = %+v, want a save row below the buttons", got)
+	}
+	if FindSaveButton(got) != "save:alice:1" {
+		t.Errorf("FindSaveButton() = %q, want save:alice:1", FindSaveButton(got))
+	}
+	if FindSaveButton(keyboard) != "" {
+		t.Errorf("FindSaveButton() without save button = %q, want empty", FindSaveButton(keyboard))
+	}
+	if only := AddSaveButton(nil, "save:alice:1"); len(only.InlineKeyboard) != 1 {
+		t.Errorf("AddSaveButton(nil) = %+v, want a single row", only)
+	}
+}
+
+func TestExportCallbackRoundTrip(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"", "export:alice:123"},
+		{"html", "export:alice:123:html"},
+	}
+	for _, tt := range tests {
+		encoded := EncodeExportCallback("alice", "123", tt.format)
+		if encoded != tt.want {
+			t.Fatalf("EncodeExportCallback(%q) = %q, want %q", tt.format, encoded, tt.want)
+		}
+		username, tweetID, format, ok := DecodeExportCallback(encoded)
+		if !ok || username != "alice" || tweetID != "123" || format != tt.format {
+			t.Fatalf("DecodeExportCallback(%q) = %q, %q, %q, %v", encoded, username, tweetID, format, ok)
+		}
+	}
+
+	for _, data := range []string{"export:", "export:alice", "export::1", "export:alice:1:", "export:alice:1:md:x", EncodeSaveCallback("alice", "1")} {
+		if _, _, _, ok := DecodeExportCallback(data); ok {
+			t.Errorf("DecodeExportCallback(%q) ok = true, want false", data)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package tweet
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+
+	"twitterx-bot/internal/chain"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+// ChainArticleCreator publishes a whole tweet chain as a single article and returns its URL.
+type ChainArticleCreator interface {
+	CreateChainArticle(ctx context.Context, items []chain.ChainItem) (string, error)
+}
+
+// HTMLChainArticle returns the message text linking to a chain article.
+// Format: 🧵 <a href="article_url">Thread</a> of N tweets from <a href="profile_url">Author Name</a> by @requester
+func (f Formatter) HTMLChainArticle(items []chain.ChainItem, articleURL, requesterUsername string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(`🧵 <a href="%s">Thread</a> of %d tweets`, html.EscapeString(articleURL), countChainTweets(items)))
+
+	if root := chainRoot(items); root != nil {
+		displayName := strings.TrimSpace(root.Author.Name)
+		screenName := strings.TrimSpace(root.Author.ScreenName)
+		if displayName == "" && screenName != "" {
+			displayName = "@" + strings.TrimPrefix(screenName, "@")
+		}
+		if displayName != "" {
+			if profileURL := authorProfileURL(screenName); profileURL != "" {
+				sb.WriteString(fmt.Sprintf(` from <a href="%s">%s</a>`, html.EscapeString(profileURL), html.EscapeString(displayName)))
+			} else {
+				sb.WriteString(fmt.Sprintf(" from %s", html.EscapeString(displayName)))
+			}
+		}
+	}
+
+	if requesterUsername != "" {
+		sb.WriteString(fmt.Sprintf(" by %s", html.EscapeString(requesterUsername)))
+	}
+	return sb.String()
+}
+
+// useChainArticle reports whether the chain is long enough to be published as one article.
+func (s Sender) useChainArticle(items []chain.ChainItem) bool {
+	return s.ChainArticles != nil && s.ChainArticleThreshold > 0 && countChainTweets(items) >= s.ChainArticleThreshold
+}
+
+// sendChainArticle publishes the chain as a single article and replies with one message linking to it.
+func (s Sender) sendChainArticle(ctx context.Context, chatID int64, items []chain.ChainItem, replyToMsgID int64, opts *SendChainResponseOpts) (*gotgbot.Message, error) {
+	articleURL, err := s.ChainArticles.CreateChainArticle(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+
+	f := s.Formatter.withDefaults()
+	msgOpts := &gotgbot.SendMessageOpts{
+		ParseMode: "HTML",
+		LinkPreviewOptions: &gotgbot.LinkPreviewOptions{
+			IsDisabled: false,
+		},
+	}
+	var markup *gotgbot.InlineKeyboardMarkup
+	if replyToMsgID != 0 {
+		msgOpts.ReplyParameters = &gotgbot.ReplyParameters{
+			MessageId:                replyToMsgID,
+			AllowSendingWithoutReply: true,
+		}
+		markup = BuildKeyboard(replyToMsgID, nil)
+	}
+	root := chainRoot(items)
+	if markup = s.exportMarkup(s.saveMarkup(markup, root), root); markup != nil {
+		msgOpts.ReplyMarkup = markup
+	}
+
+	return s.bot(ctx).SendMessage(chatID, f.HTMLChainArticle(items, articleURL, opts.RequesterUsername), msgOpts)
+}
+
+// countChainTweets returns the number of non-nil tweets in the chain.
+func countChainTweets(items []chain.ChainItem) int {
+	count := 0
+	for _, item := range items {
+		if item.Tweet != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// chainRoot returns the tweet the chain was requested for (the last one).
+func chainRoot(items []chain.ChainItem) *twitterxapi.Tweet {
+	for i := len(items) - 1; i >= 0; i-- {
+		if items[i].Tweet != nil {
+			return items[i].Tweet
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package tweet
+
+import (
+	"context"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+
+	"twitterx-bot/internal/chatsettings"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+// MessageEditor edits text messages. *gotgbot.Bot implements it.
+type MessageEditor interface {
+	EditMessageText(text string, opts *gotgbot.EditMessageTextOpts) (*gotgbot.Message, bool, error)
+}
+
+// EditTweet turns the text message messageID into the rendered tweet. A text message cannot
+// gain media, so it reports false without editing when the chat settings send the tweet with
+// photos or videos, or when Bot cannot edit messages.
+func (s Sender) EditTweet(ctx context.Context, chatID, messageID int64, tweet *twitterxapi.Tweet, opts *SendResponseOpts) (bool, error) {
+	if tweet == nil {
+		return false, nil
+	}
+	editor, ok := s.Bot.(MessageEditor)
+	if !ok {
+		return false, nil
+	}
+	if opts == nil {
+		opts = &SendResponseOpts{}
+	}
+
+	settings := chatsettings.Load(ctx, s.Settings, chatID, s.log())
+	mode, _ := mediaDelivery(tweet, settings)
+	if mode == chatsettings.MediaFull && hasMedia(tweet.Media) {
+		return false, nil
+	}
+	requester := opts.RequesterUsername
+	if settings.HideAttribution {
+		requester = ""
+	}
+
+	f := s.Formatter.withDefaults()
+	message := f.HTMLMessageTextWithRequester(tweet, requester)
+	var translationBlock string
+	if tr := s.translate(ctx, chatID, tweet, settings.TranslateTo); tr != nil {
+		translationBlock = f.HTMLTranslation(tr)
+	} else if settings.TranslateTo != "" {
+		message, _ = appendBlock(message, s.languageLabel(tweet, f), f.MaxMessageLength)
+	}
+	message, translated := appendBlock(message, translationBlock, f.MaxMessageLength)
+	message, _ = appendBlock(message, opts.Signature, f.MaxMessageLength)
+
+	editOpts := &gotgbot.EditMessageTextOpts{
+		ChatId:             chatID,
+		MessageId:          messageID,
+		ParseMode:          "HTML",
+		LinkPreviewOptions: linkPreview(tweet, mode),
+	}
+	if markup := s.saveMarkup(opts.ReplyMarkup, tweet); markup != nil {
+		editOpts.ReplyMarkup = *markup
+	}
+	if _, _, err := editor.EditMessageText(message, editOpts); err != nil {
+		return false, err
+	}
+
+	if translationBlock != "" && !translated {
+		s.sendTranslationReply(ctx, chatID, messageID, translationBlock, f)
+	}
+	s.recordSent(ctx, SentTweet{ChatID: chatID, MessageID: messageID, Tweet: tweet, Requester: requester})
+	s.log().Info("tweet edited in", "chat_id", chatID, "message_id", messageID, "tweet_id", tweet.ID)
+	return true, nil
+}
+
+// hasMedia reports whether the tweet has photos or videos to send.
+func hasMedia(media *twitterxapi.Media) bool {
+	return media != nil && (len(media.Videos) > 0 || len(media.Photos) > 0)
+}
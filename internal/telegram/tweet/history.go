@@ -0,0 +1,85 @@
+package tweet
+
+import (
+	"context"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+
+	"twitterx-bot/internal/twitterxapi"
+)
+
+// SentTweet describes a tweet message the bot delivered to a chat.
+type SentTweet struct {
+	ChatID    int64
+	MessageID int64
+	// ReplyToMsgID is the message the tweet was sent in reply to, usually the user's link.
+	ReplyToMsgID int64
+	Tweet        *twitterxapi.Tweet
+	Requester    string
+	// MessageIDs are all messages sent for the tweet in order: media group items, the
+	// messages of a chain and translation replies. MessageID is one of them.
+	MessageIDs []int64
+}
+
+// HistoryRecorder records tweets sent by the bot.
+type HistoryRecorder interface {
+	RecordSent(ctx context.Context, sent SentTweet) error
+}
+
+// recordSent reports a delivered tweet to the History recorder, if any.
+// Recording is best effort and never fails the send.
+func (s Sender) recordSent(ctx context.Context, sent SentTweet) {
+	if s.History == nil || sent.Tweet == nil {
+		return
+	}
+	if err := s.History.RecordSent(ctx, sent); err != nil {
+		s.log().Warn("record sent tweet failed", "chat_id", sent.ChatID, "tweet_id", sent.Tweet.ID, "err", err)
+	}
+}
+
+// capturing returns a copy of the sender that collects the IDs of the messages it sends.
+func (s Sender) capturing() (Sender, *[]int64) {
+	ids := new([]int64)
+	if s.Bot != nil {
+		s.Bot = messageCapture{BotAPI: s.Bot, ids: ids}
+	}
+	return s, ids
+}
+
+// messageCapture appends the ID of every message sent through it to ids.
+type messageCapture struct {
+	BotAPI
+	ids *[]int64
+}
+
+func (c messageCapture) add(msg *gotgbot.Message) {
+	if msg != nil {
+		*c.ids = append(*c.ids, msg.MessageId)
+	}
+}
+
+func (c messageCapture) SendVideo(chatID int64, video gotgbot.InputFileOrString, opts *gotgbot.SendVideoOpts) (*gotgbot.Message, error) {
+	msg, err := c.BotAPI.SendVideo(chatID, video, opts)
+	c.add(msg)
+	return msg, err
+}
+
+func (c messageCapture) SendPhoto(chatID int64, photo gotgbot.InputFileOrString, opts *gotgbot.SendPhotoOpts) (*gotgbot.Message, error) {
+	msg, err := c.BotAPI.SendPhoto(chatID, photo, opts)
+	c.add(msg)
+	return msg, err
+}
+
+func (c messageCapture) SendMediaGroup(chatID int64, media []gotgbot.InputMedia, opts *gotgbot.SendMediaGroupOpts) ([]gotgbot.Message, error) {
+	msgs, err := c.BotAPI.SendMediaGroup(chatID, media, opts)
+	for i := range msgs {
+		c.add(&msgs[i])
+	}
+	return msgs, err
+}
+
+func (c messageCapture) SendMessage(chatID int64, text string, opts *gotgbot.SendMessageOpts) (*gotgbot.Message, error) {
+	msg, err := c.BotAPI.SendMessage(chatID, text, opts)
+	c.add(msg)
+	return msg, err
+}
@@ -1,32 +1,31 @@
 package tweet
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/PaulSonOfLars/gotgbot/v2"
 
+	"twitterx-bot/internal/chain"
 	"twitterx-bot/internal/twitterxapi"
 )
 
 // InlineBuilder builds Telegram inline query results for tweets.
 type InlineBuilder struct {
 	Formatter Formatter
+	// SaveButton adds a "⭐ Save" button bookmarking the tweet to the sent message.
+	SaveButton bool
 }
 
+// Build returns the tweet with its primary media: the first video, else the photos, else the text.
 func (b InlineBuilder) Build(tweet *twitterxapi.Tweet, fallbackID string) (gotgbot.InlineQueryResult, bool) {
 	if tweet == nil {
 		return nil, false
 	}
 
 	f := b.Formatter.withDefaults()
-
-	resultID := strings.TrimSpace(tweet.ID)
-	if resultID == "" {
-		resultID = strings.TrimSpace(fallbackID)
-	}
-	if resultID == "" {
-		resultID = "tweet"
-	}
+	resultID := inlineResultID(tweet, fallbackID)
+	markup := b.markup(tweet)
 
 	title := f.Title(tweet)
 	previewURL, previewKind := MediaPreview(tweet.Media)
@@ -54,6 +53,7 @@ func (b InlineBuilder) Build(tweet *twitterxapi.Tweet, fallbackID string) (gotgb
 				Description:  description,
 				VideoWidth:   int64(video.Width),
 				VideoHeight:  int64(video.Height),
+				ReplyMarkup:  markup,
 			}, true
 		}
 	}
@@ -74,6 +74,7 @@ func (b InlineBuilder) Build(tweet *twitterxapi.Tweet, fallbackID string) (gotgb
 				Description:  description,
 				Caption:      f.HTMLCaption(tweet),
 				ParseMode:    "HTML",
+				ReplyMarkup:  markup,
 			}, true
 		}
 	}
@@ -98,9 +99,147 @@ func (b InlineBuilder) Build(tweet *twitterxapi.Tweet, fallbackID string) (gotgb
 		Url:          strings.TrimSpace(tweet.URL),
 		Description:  description,
 		ThumbnailUrl: thumbURL,
+		ReplyMarkup:  markup,
+	}, true
+}
+
+// BuildChoices returns the ways to send the tweet, in the order they are offered: the tweet with
+// its primary media (Build), the text with a link preview, the text with the quoted tweet, and
+// each photo separately. Choices repeating an earlier one are left out.
+func (b InlineBuilder) BuildChoices(tweet *twitterxapi.Tweet, fallbackID string) []gotgbot.InlineQueryResult {
+	primary, ok := b.Build(tweet, fallbackID)
+	if !ok {
+		return nil
+	}
+	choices := []gotgbot.InlineQueryResult{primary}
+
+	f := b.Formatter.withDefaults()
+	resultID := inlineResultID(tweet, fallbackID)
+	markup := b.markup(tweet)
+	title := f.Title(tweet)
+	thumbURL := inlineThumbnail(tweet)
+	tweetURL := strings.TrimSpace(tweet.URL)
+
+	// A tweet without media already is the text; a link preview adds nothing without a link
+	if _, isText := primary.(gotgbot.InlineQueryResultArticle); !isText && tweetURL != "" {
+		choices = append(choices, gotgbot.InlineQueryResultArticle{
+			Id:    resultID + ":link",
+			Title: title + " · text with link preview",
+			InputMessageContent: gotgbot.InputTextMessageContent{
+				MessageText:        f.HTMLMessageText(tweet),
+				ParseMode:          "HTML",
+				LinkPreviewOptions: &gotgbot.LinkPreviewOptions{Url: tweetURL, PreferLargeMedia: true},
+			},
+			Url:          tweetURL,
+			Description:  f.Description(tweet),
+			ThumbnailUrl: thumbURL,
+			ReplyMarkup:  markup,
+		})
+	}
+
+	if tweet.Quote != nil {
+		choices = append(choices, gotgbot.InlineQueryResultArticle{
+			Id:    resultID + ":quote",
+			Title: title + " · quote included",
+			InputMessageContent: gotgbot.InputTextMessageContent{
+				MessageText:        f.HTMLMessageTextWithQuote(tweet),
+				ParseMode:          "HTML",
+				LinkPreviewOptions: &gotgbot.LinkPreviewOptions{IsDisabled: true},
+			},
+			Url:          tweetURL,
+			Description:  f.Description(tweet.Quote),
+			ThumbnailUrl: thumbURL,
+			ReplyMarkup:  markup,
+		})
+	}
+
+	photos := inlinePhotos(tweet)
+	// A single photo already is the primary choice unless a video took its place
+	if _, isPhoto := primary.(gotgbot.InlineQueryResultPhoto); len(photos) > 1 || (len(photos) == 1 && !isPhoto) {
+		for i, photo := range photos {
+			choices = append(choices, gotgbot.InlineQueryResultPhoto{
+				Id:           fmt.Sprintf("%s:photo:%d", resultID, i+1),
+				PhotoUrl:     photo.URL,
+				ThumbnailUrl: photo.URL,
+				PhotoWidth:   int64(photo.Width),
+				PhotoHeight:  int64(photo.Height),
+				Title:        fmt.Sprintf("%s · photo %d of %d", title, i+1, len(photos)),
+				Description:  f.Description(tweet),
+				Caption:      f.HTMLCaption(tweet),
+				ParseMode:    "HTML",
+				ReplyMarkup:  markup,
+			})
+		}
+	}
+
+	return choices
+}
+
+// BuildChainArticle returns the choice linking to the whole chain published as an article.
+func (b InlineBuilder) BuildChainArticle(items []chain.ChainItem, articleURL string) (gotgbot.InlineQueryResult, bool) {
+	root := chainRoot(items)
+	if root == nil || articleURL == "" {
+		return nil, false
+	}
+
+	f := b.Formatter.withDefaults()
+	return gotgbot.InlineQueryResultArticle{
+		Id:    inlineResultID(root, "") + ":thread",
+		Title: "🧵 Full thread as article",
+		InputMessageContent: gotgbot.InputTextMessageContent{
+			MessageText:        f.HTMLChainArticle(items, articleURL, ""),
+			ParseMode:          "HTML",
+			LinkPreviewOptions: &gotgbot.LinkPreviewOptions{Url: articleURL},
+		},
+		Url:          articleURL,
+		Description:  fmt.Sprintf("%d tweets on one page", countChainTweets(items)),
+		ThumbnailUrl: strings.TrimSpace(root.Author.AvatarURL),
+		ReplyMarkup:  b.markup(root),
 	}, true
 }
 
+// markup returns the keyboard sent with the tweet, or nil when it has no buttons.
+func (b InlineBuilder) markup(tweet *twitterxapi.Tweet) *gotgbot.InlineKeyboardMarkup {
+	if id := strings.TrimSpace(tweet.ID); b.SaveButton && id != "" && tweet.Author.ScreenName != "" {
+		return AddSaveButton(nil, EncodeSaveCallback(tweet.Author.ScreenName, id))
+	}
+	return nil
+}
+
+// inlineResultID returns the base of the result IDs of the tweet.
+func inlineResultID(tweet *twitterxapi.Tweet, fallbackID string) string {
+	resultID := strings.TrimSpace(tweet.ID)
+	if resultID == "" {
+		resultID = strings.TrimSpace(fallbackID)
+	}
+	if resultID == "" {
+		resultID = "tweet"
+	}
+	return resultID
+}
+
+// inlineThumbnail returns the media preview of the tweet, or the author avatar without media.
+func inlineThumbnail(tweet *twitterxapi.Tweet) string {
+	if previewURL, _ := MediaPreview(tweet.Media); previewURL != "" {
+		return previewURL
+	}
+	return strings.TrimSpace(tweet.Author.AvatarURL)
+}
+
+// inlinePhotos returns the photos of the tweet that have a URL.
+func inlinePhotos(tweet *twitterxapi.Tweet) []twitterxapi.Photo {
+	if tweet.Media == nil {
+		return nil
+	}
+	photos := make([]twitterxapi.Photo, 0, len(tweet.Media.Photos))
+	for _, photo := range tweet.Media.Photos {
+		if photo.URL = strings.TrimSpace(photo.URL); photo.URL != "" {
+			photos = append(photos, photo)
+		}
+	}
+	return photos
+}
+
 func BuildInlineResult(tweet *twitterxapi.Tweet, fallbackID string) (gotgbot.InlineQueryResult, bool) {
 	return InlineBuilder{}.Build(tweet, fallbackID)
 }
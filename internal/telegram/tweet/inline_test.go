@@ -1,6 +1,7 @@
 package tweet
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/PaulSonOfLars/gotgbot/v2"
@@ -221,3 +222,50 @@ func TestBuildInlineResult(t *testing.T) {
 		})
 	}
 }
+
+func TestInlineBuilder_BuildChoices(t *testing.T) {
+	tw := &twitterxapi.Tweet{
+		ID:     "42",
+		Text:   "Look",
+		URL:    "https://x.com/alice/status/42",
+		Author: twitterxapi.Author{ScreenName: "alice"},
+		Quote:  &twitterxapi.Tweet{ID: "7", Text: "Original", URL: "https://x.com/bob/status/7", Author: twitterxapi.Author{ScreenName: "bob"}},
+		Media: &twitterxapi.Media{
+			Videos: []twitterxapi.Video{{URL: "https://video/1.mp4", ThumbnailURL: "https://video/1.jpg"}},
+			Photos: []twitterxapi.Photo{{URL: "https://img/1.jpg"}, {URL: "https://img/2.jpg"}},
+		},
+	}
+
+	choices := InlineBuilder{SaveButton: true}.BuildChoices(tw, "")
+	wantIDs := []string{"42:video", "42:link", "42:quote", "42:photo:1", "42:photo:2"}
+	if len(choices) != len(wantIDs) {
+		t.Fatalf("BuildChoices() = %d choices, want %d: %#v", len(choices), len(wantIDs), choices)
+	}
+	for i, choice := range choices {
+		if got := choice.GetId(); got != wantIDs[i] {
+			t.Errorf("choice %d id = %q, want %q", i, got, wantIDs[i])
+		}
+	}
+
+	link := choices[1].(gotgbot.InlineQueryResultArticle)
+	content := link.InputMessageContent.(gotgbot.InputTextMessageContent)
+	if content.LinkPreviewOptions == nil || content.LinkPreviewOptions.Url != tw.URL {
+		t.Errorf("link choice preview = %+v, want a preview of the tweet", content.LinkPreviewOptions)
+	}
+	quote := choices[2].(gotgbot.InlineQueryResultArticle)
+	if text := quote.InputMessageContent.(gotgbot.InputTextMessageContent).MessageText; !strings.Contains(text, "<blockquote>") || !strings.Contains(text, "Original") {
+		t.Errorf("quote choice text = %q, want the quoted tweet in a block quote", text)
+	}
+	if photo := choices[4].(gotgbot.InlineQueryResultPhoto); photo.PhotoUrl != "https://img/2.jpg" || FindSaveButton(photo.ReplyMarkup) != "save:alice:42" {
+		t.Errorf("second photo choice = %+v", photo)
+	}
+
+	single := &twitterxapi.Tweet{ID: "5", Text: "One photo", URL: "https://x.com/a/status/5", Media: &twitterxapi.Media{Photos: []twitterxapi.Photo{{URL: "https://img/1.jpg"}}}}
+	if choices := (InlineBuilder{}).BuildChoices(single, ""); len(choices) != 2 {
+		t.Errorf("BuildChoices() of a single photo = %d choices, want the photo and the link preview", len(choices))
+	}
+	text := &twitterxapi.Tweet{ID: "6", Text: "Just text", URL: "https://x.com/a/status/6"}
+	if choices := (InlineBuilder{}).BuildChoices(text, ""); len(choices) != 1 {
+		t.Errorf("BuildChoices() of a text tweet = %d choices, want 1", len(choices))
+	}
+}
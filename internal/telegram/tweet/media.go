@@ -8,6 +8,9 @@ import (
 
 const MaxMediaGroupSize = 10
 
+// mediaGroupKeyboardText is the reply carrying the keyboard of a media group.
+const mediaGroupKeyboardText = "⬆️ Tweet actions"
+
 func SelectPhoto(media *twitterxapi.Media) (url, thumb string, width, height int) {
 	if media == nil || len(media.Photos) == 0 {
 		return "", "", 0, 0
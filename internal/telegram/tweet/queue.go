@@ -0,0 +1,83 @@
+package tweet
+
+import (
+	"context"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+// SendQueue paces outgoing messages and retries them after flood limits (see outbox.Queue).
+type SendQueue interface {
+	// Hold keeps the chat's turn for a sequence of sends until release is called.
+	Hold(ctx context.Context, chatID int64) (held context.Context, release func(), err error)
+	Do(ctx context.Context, chatID int64, messages int, send func() error) error
+}
+
+// sendTimeout bounds the send phase of a tweet or chain. Waiting for the queue in a busy group
+// takes minutes (see outbox.DefaultGroupLimit), far longer than fetching the tweet.
+const sendTimeout = 5 * time.Minute
+
+// sendContext detaches the send phase from the deadline of ctx, which callers size for fetching
+// the tweet, and bounds it by sendTimeout instead. Cancellation of ctx is not followed either:
+// a tweet or chain is not left half sent.
+func sendContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), sendTimeout)
+}
+
+// hold takes the chat's turn in the queue so that the messages of one tweet or chain are not
+// interleaved with other sends to the chat. Without a queue it returns ctx as is.
+func (s Sender) hold(ctx context.Context, chatID int64) (context.Context, func(), error) {
+	if s.Queue == nil {
+		return ctx, func() {}, nil
+	}
+	return s.Queue.Hold(ctx, chatID)
+}
+
+// bot returns the Bot API the sender talks to, routed through the queue when one is set.
+// Queue waits and retries end with ctx.
+func (s Sender) bot(ctx context.Context) BotAPI {
+	if s.Queue == nil {
+		return s.Bot
+	}
+	return queuedBot{ctx: ctx, bot: s.Bot, queue: s.Queue}
+}
+
+// queuedBot sends every message through a SendQueue.
+type queuedBot struct {
+	ctx   context.Context
+	bot   BotAPI
+	queue SendQueue
+}
+
+func (q queuedBot) SendVideo(chatID int64, video gotgbot.InputFileOrString, opts *gotgbot.SendVideoOpts) (msg *gotgbot.Message, err error) {
+	err = q.queue.Do(q.ctx, chatID, 1, func() error {
+		msg, err = q.bot.SendVideo(chatID, video, opts)
+		return err
+	})
+	return msg, err
+}
+
+func (q queuedBot) SendPhoto(chatID int64, photo gotgbot.InputFileOrString, opts *gotgbot.SendPhotoOpts) (msg *gotgbot.Message, err error) {
+	err = q.queue.Do(q.ctx, chatID, 1, func() error {
+		msg, err = q.bot.SendPhoto(chatID, photo, opts)
+		return err
+	})
+	return msg, err
+}
+
+func (q queuedBot) SendMediaGroup(chatID int64, media []gotgbot.InputMedia, opts *gotgbot.SendMediaGroupOpts) (msgs []gotgbot.Message, err error) {
+	err = q.queue.Do(q.ctx, chatID, len(media), func() error {
+		msgs, err = q.bot.SendMediaGroup(chatID, media, opts)
+		return err
+	})
+	return msgs, err
+}
+
+func (q queuedBot) SendMessage(chatID int64, text string, opts *gotgbot.SendMessageOpts) (msg *gotgbot.Message, err error) {
+	err = q.queue.Do(q.ctx, chatID, 1, func() error {
+		msg, err = q.bot.SendMessage(chatID, text, opts)
+		return err
+	})
+	return msg, err
+}
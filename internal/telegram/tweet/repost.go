@@ -0,0 +1,58 @@
+package tweet
+
+import (
+	"fmt"
+	"html"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+// HTMLAlreadyShared returns the notice sent instead of a tweet that was shared in the chat recently.
+// Format: 🔁 This tweet was <a href="link">already shared</a> here 2 hours ago by Alice.
+func (f Formatter) HTMLAlreadyShared(messageLink, requester string, age time.Duration) string {
+	shared := "already shared"
+	if messageLink != "" {
+		shared = fmt.Sprintf(`<a href="%s">already shared</a>`, html.EscapeString(messageLink))
+	}
+	text := fmt.Sprintf("🔁 This tweet was %s here %s", shared, FormatAge(age))
+	if requester != "" {
+		text += " by " + html.EscapeString(requester)
+	}
+	return text + "."
+}
+
+// FormatAge returns a short human readable duration such as "5 minutes ago".
+func FormatAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return plural(int(age/time.Minute), "minute") + " ago"
+	case age < 24*time.Hour:
+		return plural(int(age/time.Hour), "hour") + " ago"
+	default:
+		return plural(int(age/(24*time.Hour)), "day") + " ago"
+	}
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// BuildRepostKeyboard creates a keyboard with the "Send again" button for an "already shared" notice.
+func BuildRepostKeyboard(username, tweetID string, replyToMsgID int64) *gotgbot.InlineKeyboardMarkup {
+	return &gotgbot.InlineKeyboardMarkup{
+		InlineKeyboard: [][]gotgbot.InlineKeyboardButton{
+			{
+				{
+					Text:         "🔄 Send again",
+					CallbackData: EncodeRepostCallback(username, tweetID, replyToMsgID),
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,41 @@
+package tweet
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		age  time.Duration
+		want string
+	}{
+		{10 * time.Second, "just now"},
+		{time.Minute, "1 minute ago"},
+		{45 * time.Minute, "45 minutes ago"},
+		{3 * time.Hour, "3 hours ago"},
+		{50 * time.Hour, "2 days ago"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatAge(tt.age); got != tt.want {
+			t.Errorf("FormatAge(%v) = %q, want %q", tt.age, got, tt.want)
+		}
+	}
+}
+
+func TestHTMLAlreadyShared(t *testing.T) {
+	f := Formatter{}
+
+	got := f.HTMLAlreadyShared("https://t.me/c/123/45", "Alice <3", 2*time.Hour)
+	want := `🔁 This tweet was <a href="https://t.me/c/123/45">already shared</a> here 2 hours ago by Alice &lt;3.`
+	if got != want {
+		t.Errorf("HTMLAlreadyShared() = %q, want %q", got, want)
+	}
+
+	got = f.HTMLAlreadyShared("", "", 5*time.Minute)
+	if strings.Contains(got, "<a") || !strings.HasSuffix(got, "5 minutes ago.") {
+		t.Errorf("HTMLAlreadyShared() without link = %q", got)
+	}
+}
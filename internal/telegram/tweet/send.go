@@ -9,7 +9,9 @@ import (
 	"github.com/PaulSonOfLars/gotgbot/v2/ext"
 
 	"twitterx-bot/internal/chain"
+	"twitterx-bot/internal/chatsettings"
 	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/translation"
 	"twitterx-bot/internal/twitterxapi"
 )
 
@@ -25,6 +27,8 @@ type BotAPI interface {
 type SendResponseOpts struct {
 	ReplyMarkup       *gotgbot.InlineKeyboardMarkup
 	RequesterUsername string
+	// Signature is an HTML line appended to the tweet, such as the channel name; dropped when it does not fit.
+	Signature string
 }
 
 // Sender sends tweets to Telegram.
@@ -33,6 +37,19 @@ type Sender struct {
 	Formatter Formatter
 	Telegraph ArticleCreator // Optional: for creating articles when text is too long
 	Log       *logger.Logger
+
+	Translator translation.Translator // Optional: for per-chat automatic translation
+	Settings   chatsettings.Provider  // Optional: per-chat settings such as the translation target
+	Languages  LanguageDetector       // Optional: labels untranslated tweets with their language in translating chats
+
+	ChainArticles         ChainArticleCreator // Optional: publishes long chains as a single article
+	ChainArticleThreshold int                 // Chains with at least this many tweets become an article; 0 disables
+
+	History HistoryRecorder // Optional: records sent tweets
+	Queue   SendQueue       // Optional: paces sends and retries them after flood limits
+
+	SaveButton   bool // Adds a "⭐ Save" button bookmarking the tweet to every tweet and to the last message of a chain
+	ExportButton bool // Adds a "📄 Export" button to the last message of a chain
 }
 
 // SendResponse sends a single tweet reply to the chat message in ctx.
@@ -44,7 +61,7 @@ func (s Sender) SendResponse(ctx *ext.Context, tweet *twitterxapi.Tweet, opts *S
 }
 
 // SendTweet sends a single tweet response to the given chat.
-func (s Sender) SendTweet(_ context.Context, chatID, replyToMsgID int64, tweet *twitterxapi.Tweet, opts *SendResponseOpts) error {
+func (s Sender) SendTweet(ctx context.Context, chatID, replyToMsgID int64, tweet *twitterxapi.Tweet, opts *SendResponseOpts) error {
 	log := s.log().With("component", "tweet_sender", "chat_id", chatID)
 	if tweet != nil {
 		log = log.With("tweet_id", tweet.ID)
@@ -59,6 +76,15 @@ func (s Sender) SendTweet(_ context.Context, chatID, replyToMsgID int64, tweet *
 		return errors.New("tweet sender: bot is nil")
 	}
 
+	ctx, cancel := sendContext(ctx)
+	defer cancel()
+	ctx, release, err := s.hold(ctx, chatID)
+	if err != nil {
+		log.Error("send tweet failed: queue", "err", err)
+		return err
+	}
+	defer release()
+
 	var replyParams *gotgbot.ReplyParameters
 	if replyToMsgID != 0 {
 		replyParams = &gotgbot.ReplyParameters{
@@ -68,22 +94,26 @@ func (s Sender) SendTweet(_ context.Context, chatID, replyToMsgID int64, tweet *
 	}
 
 	var replyMarkup *gotgbot.InlineKeyboardMarkup
-	var requesterUsername string
+	var requesterUsername, signature string
 	if opts != nil {
 		replyMarkup = opts.ReplyMarkup
 		requesterUsername = opts.RequesterUsername
+		signature = opts.Signature
 	}
 
-	msg, err := s.sendTweetMessage(chatID, tweet, &sendTweetMessageOpts{
+	s, sent := s.capturing()
+	msg, err := s.sendTweetMessage(ctx, chatID, tweet, &sendTweetMessageOpts{
 		ReplyParams:       replyParams,
-		ReplyMarkup:       replyMarkup,
+		ReplyMarkup:       s.saveMarkup(replyMarkup, tweet),
 		RequesterUsername: requesterUsername,
+		Signature:         signature,
 	})
 	if err != nil {
 		log.Error("send tweet failed", "err", err)
 		return err
 	}
 	if msg != nil {
+		s.recordSent(ctx, SentTweet{ChatID: chatID, MessageID: msg.MessageId, ReplyToMsgID: replyToMsgID, Tweet: tweet, Requester: requesterUsername, MessageIDs: *sent})
 		log.Info("tweet sent", "message_id", msg.MessageId)
 	} else {
 		log.Info("tweet sent")
@@ -96,30 +126,78 @@ type sendTweetMessageOpts struct {
 	ReplyParams       *gotgbot.ReplyParameters
 	ReplyMarkup       *gotgbot.InlineKeyboardMarkup
 	RequesterUsername string
+	Signature         string
 }
 
 // sendTweetMessage sends a tweet as a Telegram message and returns the sent message.
 // This helper is used for both single tweet responses and chain threading.
-func (s Sender) sendTweetMessage(chatID int64, tweet *twitterxapi.Tweet, opts *sendTweetMessageOpts) (*gotgbot.Message, error) {
+// If the chat has a translation target, the translation is appended to the text
+// or sent as a reply when it does not fit.
+func (s Sender) sendTweetMessage(ctx context.Context, chatID int64, tweet *twitterxapi.Tweet, opts *sendTweetMessageOpts) (*gotgbot.Message, error) {
 	if s.Bot == nil {
 		return nil, errors.New("tweet sender: bot is nil")
 	}
 	if opts == nil {
 		opts = &sendTweetMessageOpts{}
 	}
-
 	f := s.Formatter.withDefaults()
+	settings := chatsettings.Load(ctx, s.Settings, chatID, s.log())
+	requester := opts.RequesterUsername
+	if settings.HideAttribution {
+		requester = ""
+	}
+
+	// Check if we need Telegraph for long text
+	caption := s.prepareCaption(ctx, tweet, requester, !settings.DisableArticles, f)
+	message := f.HTMLMessageTextWithRequester(tweet, requester)
+
+	// A translation names the source language itself; without a target the chat gets no label either
+	var translationBlock string
+	if tr := s.translate(ctx, chatID, tweet, settings.TranslateTo); tr != nil {
+		translationBlock = f.HTMLTranslation(tr)
+	} else if settings.TranslateTo != "" {
+		label := s.languageLabel(tweet, f)
+		caption, _ = appendBlock(caption, label, f.MaxCaptionLength)
+		message, _ = appendBlock(message, label, f.MaxMessageLength)
+	}
+	caption, captionTranslated := appendBlock(caption, translationBlock, f.MaxCaptionLength)
+	message, messageTranslated := appendBlock(message, translationBlock, f.MaxMessageLength)
+	caption, _ = appendBlock(caption, opts.Signature, f.MaxCaptionLength)
+	message, _ = appendBlock(message, opts.Signature, f.MaxMessageLength)
+
+	msg, captioned, err := s.sendTweetContent(ctx, chatID, tweet, caption, message, opts, settings)
+	if err != nil || msg == nil || translationBlock == "" {
+		return msg, err
+	}
+
+	translated := messageTranslated
+	if captioned {
+		translated = captionTranslated
+	}
+	if !translated {
+		s.sendTranslationReply(ctx, chatID, msg.MessageId, translationBlock, f)
+	}
+	return msg, nil
+}
+
+// sendTweetContent picks the best Telegram method for the tweet media and sends it.
+// The chat media mode and sensitive-media policy decide whether media is sent at all.
+// It reports whether the caption (media message) or the message text (text-only) was used.
+func (s Sender) sendTweetContent(ctx context.Context, chatID int64, tweet *twitterxapi.Tweet, caption, message string, opts *sendTweetMessageOpts, settings chatsettings.Settings) (*gotgbot.Message, bool, error) {
 	log := s.log().With("component", "tweet_sender", "chat_id", chatID)
 	if tweet != nil {
 		log = log.With("tweet_id", tweet.ID)
 	}
 
-	// Check if we need Telegraph for long text
-	caption := s.prepareCaption(context.Background(), tweet, opts.RequesterUsername, f)
+	mode, spoiler := mediaDelivery(tweet, settings)
+	media := tweet.Media
+	if mode != chatsettings.MediaFull {
+		media = nil
+	}
 
 	// Priority 1: Video
-	if tweet.Media != nil && len(tweet.Media.Videos) > 0 {
-		video := tweet.Media.Videos[0]
+	if media != nil && len(media.Videos) > 0 {
+		video := media.Videos[0]
 		if video.URL != "" {
 			log.Debug("sending video tweet", "width", video.Width, "height", video.Height)
 			videoOpts := &gotgbot.SendVideoOpts{
@@ -127,18 +205,20 @@ func (s Sender) sendTweetMessage(chatID int64, tweet *twitterxapi.Tweet, opts *s
 				ParseMode:       "HTML",
 				Width:           int64(video.Width),
 				Height:          int64(video.Height),
+				HasSpoiler:      spoiler,
 				ReplyParameters: opts.ReplyParams,
 			}
 			if opts.ReplyMarkup != nil {
 				videoOpts.ReplyMarkup = opts.ReplyMarkup
 			}
-			return s.Bot.SendVideo(chatID, gotgbot.InputFileByURL(video.URL), videoOpts)
+			msg, err := s.bot(ctx).SendVideo(chatID, gotgbot.InputFileByURL(video.URL), videoOpts)
+			return msg, true, err
 		}
 	}
 
 	// Priority 2: Multiple photos as media group
-	if tweet.Media != nil && len(tweet.Media.Photos) > 1 {
-		photos := tweet.Media.Photos
+	if media != nil && len(media.Photos) > 1 {
+		photos := media.Photos
 		if len(photos) > MaxMediaGroupSize {
 			photos = photos[:MaxMediaGroupSize]
 		}
@@ -149,7 +229,8 @@ func (s Sender) sendTweetMessage(chatID int64, tweet *twitterxapi.Tweet, opts *s
 				continue
 			}
 			inputPhoto := gotgbot.InputMediaPhoto{
-				Media: gotgbot.InputFileByURL(photo.URL),
+				Media:      gotgbot.InputFileByURL(photo.URL),
+				HasSpoiler: spoiler,
 			}
 			if i == 0 {
 				inputPhoto.Caption = caption
@@ -161,59 +242,94 @@ func (s Sender) sendTweetMessage(chatID int64, tweet *twitterxapi.Tweet, opts *s
 		if len(mediaGroup) > 0 {
 			log.Debug("sending photo group", "count", len(mediaGroup))
 			// SendMediaGroup returns []Message, use first for threading
-			msgs, err := s.Bot.SendMediaGroup(chatID, mediaGroup, &gotgbot.SendMediaGroupOpts{
+			msgs, err := s.bot(ctx).SendMediaGroup(chatID, mediaGroup, &gotgbot.SendMediaGroupOpts{
 				ReplyParameters: opts.ReplyParams,
 			})
 			if err != nil {
-				return nil, err
+				return nil, true, err
 			}
 			if len(msgs) > 0 {
-				return &msgs[0], nil
+				s.sendMediaGroupKeyboard(ctx, chatID, msgs[0].MessageId, opts.ReplyMarkup)
+				return &msgs[0], true, nil
 			}
-			return nil, nil
+			return nil, true, nil
 		}
 	}
 
 	// Priority 3: Single photo
-	if tweet.Media != nil && len(tweet.Media.Photos) == 1 {
-		photo := tweet.Media.Photos[0]
+	if media != nil && len(media.Photos) == 1 {
+		photo := media.Photos[0]
 		if photo.URL != "" {
 			log.Debug("sending single photo")
 			photoOpts := &gotgbot.SendPhotoOpts{
 				Caption:         caption,
 				ParseMode:       "HTML",
+				HasSpoiler:      spoiler,
 				ReplyParameters: opts.ReplyParams,
 			}
 			if opts.ReplyMarkup != nil {
 				photoOpts.ReplyMarkup = opts.ReplyMarkup
 			}
-			return s.Bot.SendPhoto(chatID, gotgbot.InputFileByURL(photo.URL), photoOpts)
+			msg, err := s.bot(ctx).SendPhoto(chatID, gotgbot.InputFileByURL(photo.URL), photoOpts)
+			return msg, true, err
 		}
 	}
 
 	// Priority 4: Text only
-	message := f.HTMLMessageTextWithRequester(tweet, opts.RequesterUsername)
 	if message != "" {
 		log.Debug("sending text tweet", "text_len", len(message))
 		msgOpts := &gotgbot.SendMessageOpts{
-			ParseMode:       "HTML",
-			ReplyParameters: opts.ReplyParams,
-			LinkPreviewOptions: &gotgbot.LinkPreviewOptions{
-				IsDisabled: false,
-			},
+			ParseMode:          "HTML",
+			ReplyParameters:    opts.ReplyParams,
+			LinkPreviewOptions: linkPreview(tweet, mode),
 		}
 		if opts.ReplyMarkup != nil {
 			msgOpts.ReplyMarkup = opts.ReplyMarkup
 		}
-		return s.Bot.SendMessage(chatID, message, msgOpts)
+		msg, err := s.bot(ctx).SendMessage(chatID, message, msgOpts)
+		return msg, false, err
 	}
 
-	return nil, nil
+	return nil, false, nil
+}
+
+// mediaDelivery returns the media mode for the tweet in the chat and whether media goes behind a spoiler.
+func mediaDelivery(tweet *twitterxapi.Tweet, settings chatsettings.Settings) (chatsettings.MediaMode, bool) {
+	mode := settings.MediaMode
+	if tweet == nil || !tweet.PossiblySensitive {
+		return mode, false
+	}
+
+	switch settings.SensitiveMedia {
+	case chatsettings.SensitiveHide:
+		return chatsettings.MediaText, false
+	case chatsettings.SensitiveSpoiler:
+		// Link previews cannot be hidden behind a spoiler
+		if mode == chatsettings.MediaPreview {
+			return chatsettings.MediaText, false
+		}
+		return mode, mode == chatsettings.MediaFull
+	default:
+		return mode, false
+	}
+}
+
+// linkPreview returns the link preview options for a text-only tweet message.
+func linkPreview(tweet *twitterxapi.Tweet, mode chatsettings.MediaMode) *gotgbot.LinkPreviewOptions {
+	switch mode {
+	case chatsettings.MediaText:
+		return &gotgbot.LinkPreviewOptions{IsDisabled: true}
+	case chatsettings.MediaPreview:
+		if url, _ := MediaPreview(tweet.Media); url != "" {
+			return &gotgbot.LinkPreviewOptions{Url: url, PreferLargeMedia: true}
+		}
+	}
+	return &gotgbot.LinkPreviewOptions{IsDisabled: false}
 }
 
 // prepareCaption creates the caption text for a tweet.
-// If Telegraph is configured and the text exceeds limits, it creates a Telegraph article.
-func (s Sender) prepareCaption(ctx context.Context, tweet *twitterxapi.Tweet, requesterUsername string, f Formatter) string {
+// If Telegraph is configured and allowed for the chat and the text exceeds limits, it creates a Telegraph article.
+func (s Sender) prepareCaption(ctx context.Context, tweet *twitterxapi.Tweet, requesterUsername string, articles bool, f Formatter) string {
 	baseCaption := f.HTMLContentWithRequester(tweet, requesterUsername)
 	log := s.log().With("component", "tweet_sender")
 	if tweet != nil {
@@ -224,12 +340,12 @@ func (s Sender) prepareCaption(ctx context.Context, tweet *twitterxapi.Tweet, re
 	if s.Telegraph == nil || len(baseCaption) <= MaxCaptionLength {
 		return f.HTMLCaptionWithRequester(tweet, requesterUsername)
 	}
+	if !articles {
+		return s.fallbackCaption(tweet, requesterUsername, f)
+	}
 
-	// Text is too long, try Telegraph
-	title := f.Title(tweet)
-	fullText := f.Content(tweet)
-
-	articleURL, err := s.Telegraph.CreateArticle(ctx, fullText, title)
+	// Text is too long, try Telegraph with the full tweet (text, media, quote, author)
+	articleURL, err := s.Telegraph.CreateArticle(ctx, tweet)
 	if err != nil {
 		log.Warn("telegraph article failed, falling back", "err", err)
 		// Fallback: truncate text and add link to original tweet
@@ -271,9 +387,17 @@ type SendChainResponseOpts struct {
 }
 
 // SendChainResponse sends a chain of tweets as separate messages, each replying to the previous.
+// See SendChain.
+func (s Sender) SendChainResponse(chatID int64, items []chain.ChainItem, replyToMsgID int64, opts *SendChainResponseOpts) error {
+	return s.SendChain(context.Background(), chatID, items, replyToMsgID, opts)
+}
+
+// SendChain sends a chain of tweets as separate messages, each replying to the previous.
+// Chains reaching ChainArticleThreshold are published as one article with a single message linking to it.
 // If replyToMsgID is provided (non-zero), the first message will reply to that message.
 // The last message in the chain will have a "Delete original" button and requester username.
-func (s Sender) SendChainResponse(chatID int64, items []chain.ChainItem, replyToMsgID int64, opts *SendChainResponseOpts) error {
+// The chain keeps the chat's turn in the queue until its last message is sent.
+func (s Sender) SendChain(ctx context.Context, chatID int64, items []chain.ChainItem, replyToMsgID int64, opts *SendChainResponseOpts) error {
 	if len(items) == 0 {
 		return nil
 	}
@@ -285,6 +409,34 @@ func (s Sender) SendChainResponse(chatID int64, items []chain.ChainItem, replyTo
 	}
 
 	log := s.log().With("component", "tweet_sender", "chat_id", chatID, "chain_length", len(items))
+	ctx, cancel := sendContext(ctx)
+	defer cancel()
+	ctx, release, err := s.hold(ctx, chatID)
+	if err != nil {
+		log.Error("send chain failed: queue", "err", err)
+		return err
+	}
+	defer release()
+	s, sent := s.capturing()
+
+	settings := chatsettings.Load(ctx, s.Settings, chatID, s.log())
+	if settings.HideAttribution {
+		stripped := *opts
+		stripped.RequesterUsername = ""
+		opts = &stripped
+	}
+
+	// Long chains flood the chat, publish them as a single article instead
+	if !settings.DisableArticles && s.useChainArticle(items) {
+		msg, err := s.sendChainArticle(ctx, chatID, items, replyToMsgID, opts)
+		if err == nil {
+			s.recordSent(ctx, SentTweet{ChatID: chatID, MessageID: msg.MessageId, ReplyToMsgID: replyToMsgID, Tweet: chainRoot(items), Requester: opts.RequesterUsername, MessageIDs: *sent})
+			log.Info("chain sent as article")
+			return nil
+		}
+		log.Warn("chain article failed, sending separate messages", "err", err)
+	}
+
 	prevMsgID := replyToMsgID
 
 	for i, item := range items {
@@ -304,15 +456,16 @@ func (s Sender) SendChainResponse(chatID int64, items []chain.ChainItem, replyTo
 			ReplyParams: replyParams,
 		}
 
-		// Add "Delete original" button and requester username only to the last message
+		// Add "Delete original", "Export" and "Save" buttons and requester username only to the last message
 		if i == len(items)-1 {
 			if replyToMsgID != 0 {
 				msgOpts.ReplyMarkup = BuildKeyboard(replyToMsgID, nil)
 			}
+			msgOpts.ReplyMarkup = s.saveMarkup(s.exportMarkup(msgOpts.ReplyMarkup, chainRoot(items)), chainRoot(items))
 			msgOpts.RequesterUsername = opts.RequesterUsername
 		}
 
-		msg, err := s.sendTweetMessage(chatID, item.Tweet, msgOpts)
+		msg, err := s.sendTweetMessage(ctx, chatID, item.Tweet, msgOpts)
 		if err != nil {
 			log.Error("send chain message failed", "index", i, "err", err)
 			return err
@@ -324,6 +477,9 @@ func (s Sender) SendChainResponse(chatID int64, items []chain.ChainItem, replyTo
 		}
 	}
 
+	if prevMsgID != replyToMsgID {
+		s.recordSent(ctx, SentTweet{ChatID: chatID, MessageID: prevMsgID, ReplyToMsgID: replyToMsgID, Tweet: chainRoot(items), Requester: opts.RequesterUsername, MessageIDs: *sent})
+	}
 	log.Info("chain sent", "last_message_id", prevMsgID)
 	return nil
 }
@@ -338,6 +494,41 @@ func SendChainResponse(b *gotgbot.Bot, chatID int64, items []chain.ChainItem, re
 	return sender.SendChainResponse(chatID, items, replyToMsgID, opts)
 }
 
+// saveMarkup adds the "⭐ Save" button for the tweet to markup when SaveButton is set.
+func (s Sender) saveMarkup(markup *gotgbot.InlineKeyboardMarkup, tweet *twitterxapi.Tweet) *gotgbot.InlineKeyboardMarkup {
+	if !s.SaveButton || tweet == nil || tweet.ID == "" || tweet.Author.ScreenName == "" {
+		return markup
+	}
+	return AddSaveButton(markup, EncodeSaveCallback(tweet.Author.ScreenName, tweet.ID))
+}
+
+// sendMediaGroupKeyboard follows a media group, which cannot carry buttons, with a short reply
+// holding its keyboard. Failures are logged and do not fail the send.
+func (s Sender) sendMediaGroupKeyboard(ctx context.Context, chatID, replyToMsgID int64, markup *gotgbot.InlineKeyboardMarkup) {
+	if markup == nil {
+		return
+	}
+	_, err := s.bot(ctx).SendMessage(chatID, mediaGroupKeyboardText, &gotgbot.SendMessageOpts{
+		ReplyParameters: &gotgbot.ReplyParameters{
+			MessageId:                replyToMsgID,
+			AllowSendingWithoutReply: true,
+		},
+		DisableNotification: true,
+		ReplyMarkup:         markup,
+	})
+	if err != nil {
+		s.log().Warn("send media group keyboard failed", "chat_id", chatID, "reply_to_msg_id", replyToMsgID, "err", err)
+	}
+}
+
+// exportMarkup adds the "📄 Export" button for the chain of tweet to markup when ExportButton is set.
+func (s Sender) exportMarkup(markup *gotgbot.InlineKeyboardMarkup, tweet *twitterxapi.Tweet) *gotgbot.InlineKeyboardMarkup {
+	if !s.ExportButton || tweet == nil || tweet.ID == "" || tweet.Author.ScreenName == "" {
+		return markup
+	}
+	return AddExportButton(markup, EncodeExportCallback(tweet.Author.ScreenName, tweet.ID, ""))
+}
+
 func (s Sender) log() *logger.Logger {
 	if s.Log != nil {
 		return s.Log
@@ -0,0 +1,638 @@
+package tweet
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+
+	"twitterx-bot/internal/chain"
+	"twitterx-bot/internal/chatsettings"
+	"twitterx-bot/internal/translation"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+type sentMessage struct {
+	chatID int64
+	text   string
+	opts   *gotgbot.SendMessageOpts
+}
+
+type recordingBot struct {
+	nextID int64
+
+	photoOpts []*gotgbot.SendPhotoOpts
+	messages  []sentMessage
+}
+
+func (b *recordingBot) next() *gotgbot.Message {
+	b.nextID++
+	return &gotgbot.Message{MessageId: b.nextID}
+}
+
+func (b *recordingBot) SendVideo(_ int64, _ gotgbot.InputFileOrString, _ *gotgbot.SendVideoOpts) (*gotgbot.Message, error) {
+	return b.next(), nil
+}
+
+func (b *recordingBot) SendPhoto(_ int64, _ gotgbot.InputFileOrString, opts *gotgbot.SendPhotoOpts) (*gotgbot.Message, error) {
+	b.photoOpts = append(b.photoOpts, opts)
+	return b.next(), nil
+}
+
+func (b *recordingBot) SendMediaGroup(_ int64, media []gotgbot.InputMedia, _ *gotgbot.SendMediaGroupOpts) ([]gotgbot.Message, error) {
+	msgs := make([]gotgbot.Message, 0, len(media))
+	for range media {
+		msgs = append(msgs, *b.next())
+	}
+	return msgs, nil
+}
+
+func (b *recordingBot) SendMessage(chatID int64, text string, opts *gotgbot.SendMessageOpts) (*gotgbot.Message, error) {
+	b.messages = append(b.messages, sentMessage{chatID: chatID, text: text, opts: opts})
+	return b.next(), nil
+}
+
+type fakeTranslator struct {
+	result *translation.Translation
+	err    error
+
+	calls int
+	gotTo translation.Language
+}
+
+func (f *fakeTranslator) Translate(_ context.Context, _ string, to translation.Language) (*translation.Translation, error) {
+	f.calls++
+	f.gotTo = to
+	return f.result, f.err
+}
+
+func translatingSender(bot BotAPI, tr translation.Translator, target string) Sender {
+	settings := chatsettings.NewMemoryStore()
+	_ = settings.Save(context.Background(), 1, chatsettings.Settings{TranslateTo: target})
+	return Sender{Bot: bot, Translator: tr, Settings: settings}
+}
+
+func TestSender_AppendsTranslationToText(t *testing.T) {
+	bot := &recordingBot{}
+	tr := &fakeTranslator{result: &translation.Translation{
+		From: translation.LangEnglish,
+		To:   translation.LangUkrainian,
+		Text: "Привіт світ",
+	}}
+	sender := translatingSender(bot, tr, "uk")
+
+	err := sender.SendTweet(context.Background(), 1, 10, &twitterxapi.Tweet{ID: "1", Text: "Hello world"}, nil)
+	if err != nil {
+		t.Fatalf("SendTweet() error = %v", err)
+	}
+	if tr.gotTo.ISO != "uk" {
+		t.Fatalf("translate target = %q, want uk", tr.gotTo.ISO)
+	}
+	if len(bot.messages) != 1 {
+		t.Fatalf("messages sent = %d, want 1", len(bot.messages))
+	}
+	text := bot.messages[0].text
+	if !strings.Contains(text, "Hello world") || !strings.Contains(text, "Привіт світ") {
+		t.Fatalf("message text = %q, want original and translation", text)
+	}
+	if !strings.Contains(text, "Translated from English") {
+		t.Fatalf("message text = %q, want translation header", text)
+	}
+}
+
+func TestSender_SkipsTranslationWhenSourceMatchesTarget(t *testing.T) {
+	bot := &recordingBot{}
+	tr := &fakeTranslator{result: &translation.Translation{
+		From: translation.LangUkrainian,
+		To:   translation.LangUkrainian,
+		Text: "Привіт",
+	}}
+	sender := translatingSender(bot, tr, "uk")
+
+	err := sender.SendTweet(context.Background(), 1, 10, &twitterxapi.Tweet{ID: "1", Text: "Привіт"}, nil)
+	if err != nil {
+		t.Fatalf("SendTweet() error = %v", err)
+	}
+	if len(bot.messages) != 1 {
+		t.Fatalf("messages sent = %d, want 1", len(bot.messages))
+	}
+	if strings.Contains(bot.messages[0].text, "🌐") {
+		t.Fatalf("message text = %q, want no translation", bot.messages[0].text)
+	}
+}
+
+func TestSender_LabelsUntranslatedTweetLanguage(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		translate *translation.Translation
+		want      string
+	}{
+		{"same language", "Сьогодні дуже гарна погода, чи не так?", &translation.Translation{From: translation.LangUkrainian, Text: "unused"}, "🌐 <i>In Ukrainian</i>"},
+		{"uncertain", "Дякую", nil, ""},
+		{"translated", "Hello world, this is the best news of the week", &translation.Translation{From: translation.LangEnglish, Text: "Привіт світ"}, "Translated from English"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := &recordingBot{}
+			sender := translatingSender(bot, &fakeTranslator{result: tt.translate}, "uk")
+			sender.Languages = translation.NewDetector()
+
+			if err := sender.SendTweet(context.Background(), 1, 10, &twitterxapi.Tweet{ID: "1", Text: tt.text}, nil); err != nil {
+				t.Fatalf("SendTweet() error = %v", err)
+			}
+			text := bot.messages[0].text
+			if tt.want == "" && strings.Contains(text, "🌐") {
+				t.Errorf("message text = %q, want no language label", text)
+			}
+			if tt.want != "" && (!strings.Contains(text, tt.want) || strings.Count(text, "🌐") != 1) {
+				t.Errorf("message text = %q, want a single %q", text, tt.want)
+			}
+		})
+	}
+}
+
+func TestSender_TranslationDisabledForChat(t *testing.T) {
+	bot := &recordingBot{}
+	tr := &fakeTranslator{}
+	sender := translatingSender(bot, tr, "")
+
+	err := sender.SendTweet(context.Background(), 1, 10, &twitterxapi.Tweet{ID: "1", Text: "Hello"}, nil)
+	if err != nil {
+		t.Fatalf("SendTweet() error = %v", err)
+	}
+	if tr.calls != 0 {
+		t.Fatalf("translator calls = %d, want 0", tr.calls)
+	}
+}
+
+func TestSender_NoLanguageLabelWithoutTranslation(t *testing.T) {
+	bot := &recordingBot{}
+	sender := translatingSender(bot, &fakeTranslator{}, "")
+	sender.Languages = translation.NewDetector()
+
+	err := sender.SendTweet(context.Background(), 1, 10, &twitterxapi.Tweet{ID: "1", Text: "Сьогодні дуже гарна погода, чи не так?"}, nil)
+	if err != nil {
+		t.Fatalf("SendTweet() error = %v", err)
+	}
+	if text := bot.messages[0].text; strings.Contains(text, "🌐") {
+		t.Errorf("message text = %q, want no language label", text)
+	}
+}
+
+func TestSender_TranslationErrorStillSendsTweet(t *testing.T) {
+	bot := &recordingBot{}
+	tr := &fakeTranslator{err: errors.New("boom")}
+	sender := translatingSender(bot, tr, "uk")
+
+	err := sender.SendTweet(context.Background(), 1, 10, &twitterxapi.Tweet{ID: "1", Text: "Hello"}, nil)
+	if err != nil {
+		t.Fatalf("SendTweet() error = %v", err)
+	}
+	if len(bot.messages) != 1 {
+		t.Fatalf("messages sent = %d, want 1", len(bot.messages))
+	}
+}
+
+func TestSender_TranslationRepliesWhenCaptionFull(t *testing.T) {
+	bot := &recordingBot{}
+	tr := &fakeTranslator{result: &translation.Translation{
+		From: translation.LangEnglish,
+		To:   translation.LangUkrainian,
+		Text: strings.Repeat("б", 600),
+	}}
+	sender := translatingSender(bot, tr, "uk")
+
+	tw := &twitterxapi.Tweet{
+		ID:    "1",
+		Text:  strings.Repeat("a", 600),
+		Media: &twitterxapi.Media{Photos: []twitterxapi.Photo{{URL: "https://img/1.jpg"}}},
+	}
+	if err := sender.SendTweet(context.Background(), 1, 10, tw, nil); err != nil {
+		t.Fatalf("SendTweet() error = %v", err)
+	}
+
+	if len(bot.photoOpts) != 1 {
+		t.Fatalf("photos sent = %d, want 1", len(bot.photoOpts))
+	}
+	caption := bot.photoOpts[0].Caption
+	if HTMLLength(caption) > MaxCaptionLength {
+		t.Fatalf("caption length = %d, want <= %d", HTMLLength(caption), MaxCaptionLength)
+	}
+	if strings.Contains(caption, "🌐") {
+		t.Fatalf("caption unexpectedly contains translation")
+	}
+	if len(bot.messages) != 1 {
+		t.Fatalf("translation replies = %d, want 1", len(bot.messages))
+	}
+	reply := bot.messages[0]
+	if reply.opts == nil || reply.opts.ReplyParameters == nil || reply.opts.ReplyParameters.MessageId != 1 {
+		t.Fatalf("translation reply not threaded to photo message")
+	}
+	if !strings.Contains(reply.text, "Translated from English") {
+		t.Fatalf("reply text = %q, want translation header", reply.text)
+	}
+}
+
+func TestHTMLLength(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"", 0},
+		{"plain", 5},
+		{`<a href="https://x.com">Tweet</a>`, 5},
+		{"<b>привіт</b>", 6},
+	}
+
+	for _, tt := range tests {
+		if got := HTMLLength(tt.input); got != tt.want {
+			t.Errorf("HTMLLength(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+type fakeChainArticles struct {
+	url string
+	err error
+
+	calls int
+}
+
+func (f *fakeChainArticles) CreateChainArticle(_ context.Context, _ []chain.ChainItem) (string, error) {
+	f.calls++
+	return f.url, f.err
+}
+
+func chainItems(n int) []chain.ChainItem {
+	items := make([]chain.ChainItem, 0, n)
+	for i := 0; i < n; i++ {
+		items = append(items, chain.ChainItem{
+			Tweet: &twitterxapi.Tweet{ID: "1", Text: "tweet", Author: twitterxapi.Author{Name: "User", ScreenName: "user"}},
+			Type:  chain.ChainTypeReply,
+		})
+	}
+	items[n-1].Type = chain.ChainTypeRoot
+	return items
+}
+
+func TestSender_ChainArticleAboveThreshold(t *testing.T) {
+	bot := &recordingBot{}
+	articles := &fakeChainArticles{url: "https://telegra.ph/thread"}
+	sender := Sender{Bot: bot, ChainArticles: articles, ChainArticleThreshold: 3}
+
+	err := sender.SendChainResponse(1, chainItems(3), 10, &SendChainResponseOpts{RequesterUsername: "@req"})
+	if err != nil {
+		t.Fatalf("SendChainResponse() error = %v", err)
+	}
+	if articles.calls != 1 {
+		t.Fatalf("article calls = %d, want 1", articles.calls)
+	}
+	if len(bot.messages) != 1 {
+		t.Fatalf("messages sent = %d, want 1", len(bot.messages))
+	}
+	text := bot.messages[0].text
+	if !strings.Contains(text, "https://telegra.ph/thread") || !strings.Contains(text, "3 tweets") || !strings.Contains(text, "@req") {
+		t.Fatalf("message text = %q, want article link, count and requester", text)
+	}
+}
+
+func TestSender_ChainBelowThresholdSendsMessages(t *testing.T) {
+	bot := &recordingBot{}
+	articles := &fakeChainArticles{url: "https://telegra.ph/thread"}
+	sender := Sender{Bot: bot, ChainArticles: articles, ChainArticleThreshold: 3}
+
+	if err := sender.SendChainResponse(1, chainItems(2), 10, nil); err != nil {
+		t.Fatalf("SendChainResponse() error = %v", err)
+	}
+	if articles.calls != 0 {
+		t.Fatalf("article calls = %d, want 0", articles.calls)
+	}
+	if len(bot.messages) != 2 {
+		t.Fatalf("messages sent = %d, want 2", len(bot.messages))
+	}
+}
+
+func TestSender_ChainArticleFailureFallsBackToMessages(t *testing.T) {
+	bot := &recordingBot{}
+	articles := &fakeChainArticles{err: errors.New("telegraph down")}
+	sender := Sender{Bot: bot, ChainArticles: articles, ChainArticleThreshold: 2}
+
+	if err := sender.SendChainResponse(1, chainItems(3), 10, nil); err != nil {
+		t.Fatalf("SendChainResponse() error = %v", err)
+	}
+	if len(bot.messages) != 3 {
+		t.Fatalf("messages sent = %d, want 3", len(bot.messages))
+	}
+}
+
+type fakeHistory struct {
+	sent []SentTweet
+}
+
+func (f *fakeHistory) RecordSent(_ context.Context, sent SentTweet) error {
+	f.sent = append(f.sent, sent)
+	return nil
+}
+
+func TestSender_RecordsSentTweet(t *testing.T) {
+	bot := &recordingBot{}
+	history := &fakeHistory{}
+	sender := Sender{Bot: bot, History: history}
+
+	tw := &twitterxapi.Tweet{ID: "42", Text: "Hello"}
+	if err := sender.SendTweet(context.Background(), 1, 10, tw, &SendResponseOpts{RequesterUsername: "@req"}); err != nil {
+		t.Fatalf("SendTweet() error = %v", err)
+	}
+
+	if len(history.sent) != 1 {
+		t.Fatalf("recorded = %d, want 1", len(history.sent))
+	}
+	got := history.sent[0]
+	if got.ChatID != 1 || got.MessageID != 1 || got.ReplyToMsgID != 10 || got.Tweet != tw || got.Requester != "@req" {
+		t.Fatalf("recorded = %+v", got)
+	}
+}
+
+func TestSender_RecordsChainOnce(t *testing.T) {
+	bot := &recordingBot{}
+	history := &fakeHistory{}
+	sender := Sender{Bot: bot, History: history}
+
+	if err := sender.SendChainResponse(1, chainItems(3), 10, nil); err != nil {
+		t.Fatalf("SendChainResponse() error = %v", err)
+	}
+
+	if len(history.sent) != 1 || history.sent[0].MessageID != 3 {
+		t.Fatalf("recorded = %+v, want the last chain message only", history.sent)
+	}
+}
+
+func settingsSender(bot BotAPI, settings chatsettings.Settings) Sender {
+	store := chatsettings.NewMemoryStore()
+	_ = store.Save(context.Background(), 1, settings)
+	return Sender{Bot: bot, Settings: store}
+}
+
+func photoTweet() *twitterxapi.Tweet {
+	return &twitterxapi.Tweet{
+		ID:     "1",
+		URL:    "https://x.com/user/status/1",
+		Text:   "Look",
+		Author: twitterxapi.Author{Name: "User", ScreenName: "user"},
+		Media:  &twitterxapi.Media{Photos: []twitterxapi.Photo{{URL: "https://img/1.jpg"}}},
+	}
+}
+
+func TestSender_MediaModes(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        chatsettings.MediaMode
+		wantPhotos  int
+		wantPreview *gotgbot.LinkPreviewOptions
+	}{
+		{name: "full", mode: chatsettings.MediaFull, wantPhotos: 1},
+		{name: "preview", mode: chatsettings.MediaPreview, wantPreview: &gotgbot.LinkPreviewOptions{Url: "https://img/1.jpg", PreferLargeMedia: true}},
+		{name: "text", mode: chatsettings.MediaText, wantPreview: &gotgbot.LinkPreviewOptions{IsDisabled: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := &recordingBot{}
+			sender := settingsSender(bot, chatsettings.Settings{MediaMode: tt.mode})
+
+			if err := sender.SendTweet(context.Background(), 1, 10, photoTweet(), nil); err != nil {
+				t.Fatalf("SendTweet() error = %v", err)
+			}
+			if len(bot.photoOpts) != tt.wantPhotos {
+				t.Fatalf("photos sent = %d, want %d", len(bot.photoOpts), tt.wantPhotos)
+			}
+			if tt.wantPreview == nil {
+				return
+			}
+			if len(bot.messages) != 1 {
+				t.Fatalf("messages sent = %d, want 1", len(bot.messages))
+			}
+			if got := bot.messages[0].opts.LinkPreviewOptions; *got != *tt.wantPreview {
+				t.Fatalf("link preview = %+v, want %+v", got, tt.wantPreview)
+			}
+		})
+	}
+}
+
+func TestSender_SensitiveMediaPolicy(t *testing.T) {
+	sensitive := photoTweet()
+	sensitive.PossiblySensitive = true
+
+	bot := &recordingBot{}
+	sender := settingsSender(bot, chatsettings.Settings{SensitiveMedia: chatsettings.SensitiveSpoiler})
+	if err := sender.SendTweet(context.Background(), 1, 10, sensitive, nil); err != nil {
+		t.Fatalf("SendTweet() error = %v", err)
+	}
+	if len(bot.photoOpts) != 1 || !bot.photoOpts[0].HasSpoiler {
+		t.Fatalf("photo opts = %+v, want photo behind spoiler", bot.photoOpts)
+	}
+
+	bot = &recordingBot{}
+	sender = settingsSender(bot, chatsettings.Settings{SensitiveMedia: chatsettings.SensitiveHide})
+	if err := sender.SendTweet(context.Background(), 1, 10, sensitive, nil); err != nil {
+		t.Fatalf("SendTweet() error = %v", err)
+	}
+	if len(bot.photoOpts) != 0 || len(bot.messages) != 1 || !bot.messages[0].opts.LinkPreviewOptions.IsDisabled {
+		t.Fatalf("want text-only message without preview, got %d photos, %d messages", len(bot.photoOpts), len(bot.messages))
+	}
+
+	// Tweets not marked as sensitive are unaffected
+	bot = &recordingBot{}
+	sender.Bot = bot
+	if err := sender.SendTweet(context.Background(), 1, 10, photoTweet(), nil); err != nil {
+		t.Fatalf("SendTweet() error = %v", err)
+	}
+	if len(bot.photoOpts) != 1 || bot.photoOpts[0].HasSpoiler {
+		t.Fatalf("photo opts = %+v, want plain photo", bot.photoOpts)
+	}
+}
+
+func TestSender_HideAttribution(t *testing.T) {
+	bot := &recordingBot{}
+	sender := settingsSender(bot, chatsettings.Settings{HideAttribution: true})
+
+	tw := &twitterxapi.Tweet{ID: "1", Text: "Hello", Author: twitterxapi.Author{Name: "User"}}
+	if err := sender.SendTweet(context.Background(), 1, 10, tw, &SendResponseOpts{RequesterUsername: "@req"}); err != nil {
+		t.Fatalf("SendTweet() error = %v", err)
+	}
+	if strings.Contains(bot.messages[0].text, "@req") {
+		t.Fatalf("message text = %q, want no requester", bot.messages[0].text)
+	}
+}
+
+type fakeArticles struct {
+	calls int
+}
+
+func (f *fakeArticles) CreateArticle(_ context.Context, _ *twitterxapi.Tweet) (string, error) {
+	f.calls++
+	return "https://telegra.ph/article", nil
+}
+
+func TestSender_DisableArticles(t *testing.T) {
+	bot := &recordingBot{}
+	articles := &fakeArticles{}
+	chainArticles := &fakeChainArticles{url: "https://telegra.ph/thread"}
+	sender := settingsSender(bot, chatsettings.Settings{DisableArticles: true})
+	sender.Telegraph = articles
+	sender.ChainArticles = chainArticles
+	sender.ChainArticleThreshold = 2
+
+	long := photoTweet()
+	long.Text = strings.Repeat("a", MaxCaptionLength+100)
+	if err := sender.SendTweet(context.Background(), 1, 10, long, nil); err != nil {
+		t.Fatalf("SendTweet() error = %v", err)
+	}
+	if articles.calls != 0 {
+		t.Fatalf("article calls = %d, want 0", articles.calls)
+	}
+	if caption := bot.photoOpts[0].Caption; !strings.Contains(caption, long.URL) {
+		t.Fatalf("caption = %q, want link to the original tweet", caption)
+	}
+
+	if err := sender.SendChainResponse(1, chainItems(3), 10, nil); err != nil {
+		t.Fatalf("SendChainResponse() error = %v", err)
+	}
+	if chainArticles.calls != 0 {
+		t.Fatalf("chain article calls = %d, want 0", chainArticles.calls)
+	}
+}
+
+type fakeQueue struct {
+	chats    []int64
+	messages []int
+	holds    []int64
+}
+
+func (q *fakeQueue) Hold(ctx context.Context, chatID int64) (context.Context, func(), error) {
+	q.holds = append(q.holds, chatID)
+	return ctx, func() {}, nil
+}
+
+func (q *fakeQueue) Do(_ context.Context, chatID int64, messages int, send func() error) error {
+	q.chats = append(q.chats, chatID)
+	q.messages = append(q.messages, messages)
+	return send()
+}
+
+func TestSender_SendsThroughQueue(t *testing.T) {
+	bot := &recordingBot{}
+	queue := &fakeQueue{}
+	sender := Sender{Bot: bot, Queue: queue}
+
+	if err := sender.SendChainResponse(-100, chainItems(2), 10, nil); err != nil {
+		t.Fatalf("SendChainResponse() error = %v", err)
+	}
+	tw := photoTweet()
+	tw.Media.Photos = append(tw.Media.Photos, tw.Media.Photos[0])
+	if err := sender.SendTweet(context.Background(), -100, 10, tw, nil); err != nil {
+		t.Fatalf("SendTweet() error = %v", err)
+	}
+
+	if len(bot.messages) != 2 {
+		t.Fatalf("messages sent = %d, want 2", len(bot.messages))
+	}
+	if len(queue.holds) != 2 {
+		t.Errorf("held turns = %v, want one for the chain and one for the tweet", queue.holds)
+	}
+	wantMessages := []int{1, 1, 2}
+	if len(queue.messages) != len(wantMessages) {
+		t.Fatalf("queued sends = %v, want %v", queue.messages, wantMessages)
+	}
+	for i, want := range wantMessages {
+		if queue.messages[i] != want || queue.chats[i] != -100 {
+			t.Errorf("queued send %d = chat %d, %d messages; want chat -100, %d", i, queue.chats[i], queue.messages[i], want)
+		}
+	}
+}
+
+func TestSender_SaveButton(t *testing.T) {
+	bot := &recordingBot{}
+	sender := Sender{Bot: bot, SaveButton: true}
+
+	tw := &twitterxapi.Tweet{ID: "42", Text: "Hello", Author: twitterxapi.Author{ScreenName: "user"}}
+	if err := sender.SendTweet(context.Background(), 1, 10, tw, &SendResponseOpts{ReplyMarkup: BuildKeyboard(10, nil)}); err != nil {
+		t.Fatalf("SendTweet() error = %v", err)
+	}
+	if err := sender.SendChainResponse(1, chainItems(2), 0, nil); err != nil {
+		t.Fatalf("SendChainResponse() error = %v", err)
+	}
+
+	if len(bot.messages) != 3 {
+		t.Fatalf("messages = %d, want 3", len(bot.messages))
+	}
+	markup, _ := bot.messages[0].opts.ReplyMarkup.(*gotgbot.InlineKeyboardMarkup)
+	if FindSaveButton(markup) != "save:user:42" || len(markup.InlineKeyboard) != 2 {
+		t.Errorf("tweet keyboard = %+v, want the delete button and a save button", markup)
+	}
+	// One save button per chain, on its last message
+	if markup := bot.messages[1].opts.ReplyMarkup; markup != nil {
+		t.Errorf("first chain message keyboard = %+v, want none", markup)
+	}
+	markup, _ = bot.messages[2].opts.ReplyMarkup.(*gotgbot.InlineKeyboardMarkup)
+	if FindSaveButton(markup) == "" {
+		t.Errorf("last chain message keyboard = %+v, want a save button", markup)
+	}
+}
+
+func TestSender_KeyboardFollowsMediaGroup(t *testing.T) {
+	bot := &recordingBot{}
+	sender := Sender{Bot: bot, SaveButton: true}
+
+	tw := &twitterxapi.Tweet{
+		ID:     "42",
+		Text:   "Album",
+		Author: twitterxapi.Author{ScreenName: "user"},
+		Media:  &twitterxapi.Media{Photos: []twitterxapi.Photo{{URL: "https://img/1.jpg"}, {URL: "https://img/2.jpg"}}},
+	}
+	if err := sender.SendTweet(context.Background(), 1, 10, tw, &SendResponseOpts{ReplyMarkup: BuildKeyboard(10, nil)}); err != nil {
+		t.Fatalf("SendTweet() error = %v", err)
+	}
+
+	if len(bot.messages) != 1 {
+		t.Fatalf("messages = %d, want one keyboard reply", len(bot.messages))
+	}
+	opts := bot.messages[0].opts
+	markup, _ := opts.ReplyMarkup.(*gotgbot.InlineKeyboardMarkup)
+	if FindSaveButton(markup) != "save:user:42" || len(markup.InlineKeyboard) != 2 {
+		t.Errorf("follow-up keyboard = %+v, want the delete button and the save button", markup)
+	}
+	if opts.ReplyParameters == nil || opts.ReplyParameters.MessageId != 1 {
+		t.Errorf("follow-up replies to %+v, want the first album message", opts.ReplyParameters)
+	}
+
+	bot.messages = nil
+	if err := (Sender{Bot: bot}).SendTweet(context.Background(), 1, 10, tw, nil); err != nil {
+		t.Fatalf("SendTweet() error = %v", err)
+	}
+	if len(bot.messages) != 0 {
+		t.Errorf("messages without a keyboard = %d, want none", len(bot.messages))
+	}
+}
+
+func TestSender_ExportButton(t *testing.T) {
+	bot := &recordingBot{}
+	sender := Sender{Bot: bot, ExportButton: true}
+
+	if err := sender.SendChainResponse(1, chainItems(2), 10, nil); err != nil {
+		t.Fatalf("SendChainResponse() error = %v", err)
+	}
+	if len(bot.messages) != 2 {
+		t.Fatalf("messages = %d, want 2", len(bot.messages))
+	}
+	if bot.messages[0].opts.ReplyMarkup != nil {
+		t.Errorf("first chain message keyboard = %+v, want none", bot.messages[0].opts.ReplyMarkup)
+	}
+	markup, _ := bot.messages[1].opts.ReplyMarkup.(*gotgbot.InlineKeyboardMarkup)
+	if markup == nil || len(markup.InlineKeyboard) != 2 || markup.InlineKeyboard[1][0].CallbackData != "export:user:1" {
+		t.Errorf("last chain message keyboard = %+v, want the delete button and an export button", markup)
+	}
+}
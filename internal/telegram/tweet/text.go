@@ -182,6 +182,17 @@ func (f Formatter) HTMLMessageTextWithRequester(tweet *twitterxapi.Tweet, reques
 	return TruncateHTML(f.HTMLContentWithRequester(tweet, requesterUsername), f.MaxMessageLength)
 }
 
+// HTMLMessageTextWithQuote returns HTML-formatted tweet for text messages followed by the tweet it quotes
+// in a block quote (max 4096 chars).
+func (f Formatter) HTMLMessageTextWithQuote(tweet *twitterxapi.Tweet) string {
+	f = f.withDefaults()
+	text := f.HTMLContent(tweet)
+	if tweet != nil && tweet.Quote != nil {
+		text += "\n\n<blockquote>" + f.HTMLContent(tweet.Quote) + "</blockquote>"
+	}
+	return TruncateHTML(text, f.MaxMessageLength)
+}
+
 func BuildTitle(tweet *twitterxapi.Tweet) string {
 	return Formatter{}.Title(tweet)
 }
@@ -0,0 +1,153 @@
+package tweet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+
+	"twitterx-bot/internal/translation"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+// LanguageDetector detects the language of tweet text offline. *translation.Detector implements it.
+type LanguageDetector interface {
+	DetectConfidence(text string) (translation.Language, translation.Confidence)
+}
+
+// HTMLLanguage returns a line naming the language of the tweet.
+// Format: 🌐 <i>In English</i>
+func (f Formatter) HTMLLanguage(lang translation.Language) string {
+	name := strings.TrimSpace(lang.Name)
+	if name == "" {
+		name = strings.ToLower(strings.TrimSpace(lang.ISO))
+	}
+	if name == "" {
+		return ""
+	}
+	return "🌐 <i>In " + html.EscapeString(name) + "</i>"
+}
+
+// HTMLTranslation returns an HTML block with the translated tweet text.
+// Format: 🌐 Translated from English\n<blockquote>text</blockquote>
+func (f Formatter) HTMLTranslation(tr *translation.Translation) string {
+	if tr == nil {
+		return ""
+	}
+	text := strings.TrimSpace(tr.Text)
+	if text == "" {
+		return ""
+	}
+
+	source := translation.LanguageFromISO(tr.From.ISO).Name
+	if source == "" {
+		source = strings.ToLower(strings.TrimSpace(tr.From.ISO))
+	}
+
+	header := "🌐 <b>Translation</b>"
+	if source != "" {
+		header = fmt.Sprintf("🌐 <b>Translated from %s</b>", html.EscapeString(source))
+	}
+	return header + "\n<blockquote>" + html.EscapeString(text) + "</blockquote>"
+}
+
+// HTMLLength returns the number of visible characters in HTML content, ignoring tags.
+func HTMLLength(input string) int {
+	count := 0
+	inTag := false
+	for _, r := range input {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>' && inTag:
+			inTag = false
+		case !inTag:
+			count++
+		}
+	}
+	return count
+}
+
+// appendBlock appends an HTML block, such as a translation, to text if the result fits into max characters.
+func appendBlock(text, block string, max int) (string, bool) {
+	if block == "" {
+		return text, false
+	}
+	combined := block
+	if text != "" {
+		combined = text + "\n\n" + block
+	}
+	if HTMLLength(combined) > max {
+		return text, false
+	}
+	return combined, true
+}
+
+// translate returns the translation of the tweet text into the chat's target language translateTo.
+// It returns nil when translation is disabled, fails, or the source already matches the target.
+func (s Sender) translate(ctx context.Context, chatID int64, tw *twitterxapi.Tweet, translateTo string) *translation.Translation {
+	if s.Translator == nil || tw == nil {
+		return nil
+	}
+	text := strings.TrimSpace(tw.Text)
+	target := strings.ToLower(strings.TrimSpace(translateTo))
+	if text == "" || target == "" {
+		return nil
+	}
+
+	log := s.log().With("component", "tweet_sender", "chat_id", chatID, "tweet_id", tw.ID)
+
+	tr, err := s.Translator.Translate(ctx, text, translation.LanguageFromISO(target))
+	if errors.Is(err, translation.ErrNothingToTranslate) {
+		log.Debug("translation skipped: no text to translate", "target", target)
+		return nil
+	}
+	if err != nil {
+		log.Warn("translate tweet failed", "target", target, "err", err)
+		return nil
+	}
+	if tr == nil || strings.EqualFold(strings.TrimSpace(tr.From.ISO), target) {
+		log.Debug("translation skipped: source matches target", "target", target)
+		return nil
+	}
+	if translated := strings.TrimSpace(tr.Text); translated == "" || translated == text {
+		return nil
+	}
+	return tr
+}
+
+// languageLabel returns the language line of the tweet, or "" when the language is not known for sure.
+func (s Sender) languageLabel(tw *twitterxapi.Tweet, f Formatter) string {
+	if s.Languages == nil || tw == nil || strings.TrimSpace(tw.Text) == "" {
+		return ""
+	}
+	lang, confidence := s.Languages.DetectConfidence(tw.Text)
+	if confidence != translation.HighConfidence {
+		return ""
+	}
+	return f.HTMLLanguage(lang)
+}
+
+// sendTranslationReply sends the translation block as a reply to the tweet message.
+// Used when the translation does not fit into the caption or message text.
+func (s Sender) sendTranslationReply(ctx context.Context, chatID, replyToMsgID int64, block string, f Formatter) {
+	log := s.log().With("component", "tweet_sender", "chat_id", chatID, "reply_to_msg_id", replyToMsgID)
+	_, err := s.bot(ctx).SendMessage(chatID, TruncateHTML(block, f.MaxMessageLength), &gotgbot.SendMessageOpts{
+		ParseMode: "HTML",
+		ReplyParameters: &gotgbot.ReplyParameters{
+			MessageId:                replyToMsgID,
+			AllowSendingWithoutReply: true,
+		},
+		LinkPreviewOptions: &gotgbot.LinkPreviewOptions{
+			IsDisabled: true,
+		},
+	})
+	if err != nil {
+		log.Warn("send translation reply failed", "err", err)
+		return
+	}
+	log.Debug("translation sent as reply")
+}
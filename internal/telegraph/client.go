@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 )
 
@@ -46,7 +47,7 @@ func (c *Client) CreateAccount(ctx context.Context, req CreateAccountRequest) (*
 	}
 
 	if !resp.OK {
-		return nil, fmt.Errorf("%w: %s", ErrAPIError, resp.Error)
+		return nil, apiError(resp.Error)
 	}
 
 	return &resp.Result, nil
@@ -78,7 +79,111 @@ func (c *Client) CreatePage(ctx context.Context, req CreatePageRequest) (*Page,
 	}
 
 	if !resp.OK {
-		return nil, fmt.Errorf("%w: %s", ErrAPIError, resp.Error)
+		return nil, apiError(resp.Error)
+	}
+
+	return &resp.Result, nil
+}
+
+// EditPage редагує існуючу сторінку
+func (c *Client) EditPage(ctx context.Context, req EditPageRequest) (*Page, error) {
+	if req.Path == "" {
+		return nil, ErrPathEmpty
+	}
+
+	contentJSON, err := json.Marshal(req.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal content: %w", err)
+	}
+
+	form := url.Values{}
+	form.Set("access_token", req.AccessToken)
+	form.Set("title", req.Title)
+	form.Set("content", string(contentJSON))
+	if req.AuthorName != "" {
+		form.Set("author_name", req.AuthorName)
+	}
+	if req.AuthorURL != "" {
+		form.Set("author_url", req.AuthorURL)
+	}
+	form.Set("return_content", fmt.Sprintf("%t", req.ReturnContent))
+
+	var resp Response[Page]
+	if err := c.post(ctx, "/editPage/"+url.PathEscape(req.Path), form, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.OK {
+		return nil, apiError(resp.Error)
+	}
+
+	return &resp.Result, nil
+}
+
+// GetPage отримує сторінку за шляхом
+func (c *Client) GetPage(ctx context.Context, path string, returnContent bool) (*Page, error) {
+	if path == "" {
+		return nil, ErrPathEmpty
+	}
+
+	form := url.Values{}
+	form.Set("return_content", fmt.Sprintf("%t", returnContent))
+
+	var resp Response[Page]
+	if err := c.post(ctx, "/getPage/"+url.PathEscape(path), form, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.OK {
+		return nil, apiError(resp.Error)
+	}
+
+	return &resp.Result, nil
+}
+
+// GetPageList отримує список сторінок акаунту (від нових до старих)
+func (c *Client) GetPageList(ctx context.Context, req GetPageListRequest) (*PageList, error) {
+	form := url.Values{}
+	form.Set("access_token", req.AccessToken)
+	if req.Offset > 0 {
+		form.Set("offset", strconv.Itoa(req.Offset))
+	}
+	if req.Limit > 0 {
+		form.Set("limit", strconv.Itoa(req.Limit))
+	}
+
+	var resp Response[PageList]
+	if err := c.post(ctx, "/getPageList", form, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.OK {
+		return nil, apiError(resp.Error)
+	}
+
+	return &resp.Result, nil
+}
+
+// GetViews отримує кількість переглядів сторінки
+func (c *Client) GetViews(ctx context.Context, req GetViewsRequest) (*PageViews, error) {
+	if req.Path == "" {
+		return nil, ErrPathEmpty
+	}
+
+	form := url.Values{}
+	for key, value := range map[string]int{"year": req.Year, "month": req.Month, "day": req.Day, "hour": req.Hour} {
+		if value > 0 {
+			form.Set(key, strconv.Itoa(value))
+		}
+	}
+
+	var resp Response[PageViews]
+	if err := c.post(ctx, "/getViews/"+url.PathEscape(req.Path), form, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.OK {
+		return nil, apiError(resp.Error)
 	}
 
 	return &resp.Result, nil
@@ -97,10 +202,19 @@ func (c *Client) post(ctx context.Context, path string, form url.Values, result
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
+		return fmt.Errorf("%w: %w", ErrRequestFailed, err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// Telegraph може повернути JSON з помилкою (наприклад, FLOOD_WAIT) і на не-2xx статусі
+		var apiResp Response[json.RawMessage]
+		if err := json.NewDecoder(resp.Body).Decode(&apiResp); err == nil && apiResp.Error != "" {
+			return apiError(apiResp.Error)
+		}
+		return &StatusError{StatusCode: resp.StatusCode}
+	}
+
 	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
 		return fmt.Errorf("failed to decode response: %w", err)
 	}
@@ -3,9 +3,11 @@ package telegraph
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestClient_CreateAccount_Success(t *testing.T) {
@@ -132,3 +134,193 @@ func TestClient_CreatePage_APIError(t *testing.T) {
 		t.Fatal("expected error, got nil")
 	}
 }
+
+func TestClient_EditPage_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/editPage/test-article-01-08" {
+			t.Errorf("expected /editPage/test-article-01-08, got %s", r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+		if r.Form.Get("access_token") != "test-token" || r.Form.Get("title") != "Updated" {
+			t.Errorf("unexpected form: %v", r.Form)
+		}
+
+		json.NewEncoder(w).Encode(Response[Page]{
+			OK:     true,
+			Result: Page{Path: "test-article-01-08", URL: "https://telegra.ph/test-article-01-08", Title: "Updated"},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	page, err := client.EditPage(context.Background(), EditPageRequest{
+		AccessToken: "test-token",
+		Path:        "test-article-01-08",
+		Title:       "Updated",
+		Content:     []any{"Updated content"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Title != "Updated" {
+		t.Errorf("unexpected title: %s", page.Title)
+	}
+}
+
+func TestClient_EditPage_EmptyPath(t *testing.T) {
+	client := NewClient(&http.Client{}, "http://127.0.0.1:0")
+
+	_, err := client.EditPage(context.Background(), EditPageRequest{AccessToken: "token"})
+	if err != ErrPathEmpty {
+		t.Fatalf("expected ErrPathEmpty, got %v", err)
+	}
+}
+
+func TestClient_GetPage_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/getPage/test-article-01-08" {
+			t.Errorf("expected /getPage/test-article-01-08, got %s", r.URL.Path)
+		}
+		r.ParseForm()
+		if r.Form.Get("return_content") != "true" {
+			t.Errorf("expected return_content=true, got %q", r.Form.Get("return_content"))
+		}
+
+		json.NewEncoder(w).Encode(Response[Page]{
+			OK: true,
+			Result: Page{
+				Path:    "test-article-01-08",
+				Title:   "Test Article",
+				Content: []any{map[string]any{"tag": "p", "children": []any{"text"}}},
+				Views:   42,
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	page, err := client.GetPage(context.Background(), "test-article-01-08", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Views != 42 || len(page.Content) != 1 {
+		t.Errorf("unexpected page: %+v", page)
+	}
+}
+
+func TestClient_GetPageList_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/getPageList" {
+			t.Errorf("expected /getPageList, got %s", r.URL.Path)
+		}
+		r.ParseForm()
+		if r.Form.Get("offset") != "10" || r.Form.Get("limit") != "5" {
+			t.Errorf("unexpected paging: %v", r.Form)
+		}
+
+		json.NewEncoder(w).Encode(Response[PageList]{
+			OK: true,
+			Result: PageList{
+				TotalCount: 12,
+				Pages:      []Page{{Path: "a"}, {Path: "b"}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	list, err := client.GetPageList(context.Background(), GetPageListRequest{AccessToken: "token", Offset: 10, Limit: 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if list.TotalCount != 12 || len(list.Pages) != 2 {
+		t.Errorf("unexpected page list: %+v", list)
+	}
+}
+
+func TestClient_GetViews_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/getViews/test-article-01-08" {
+			t.Errorf("expected /getViews/test-article-01-08, got %s", r.URL.Path)
+		}
+		r.ParseForm()
+		if r.Form.Get("year") != "2024" || r.Form.Has("hour") {
+			t.Errorf("unexpected filters: %v", r.Form)
+		}
+
+		json.NewEncoder(w).Encode(Response[PageViews]{OK: true, Result: PageViews{Views: 7}})
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	views, err := client.GetViews(context.Background(), GetViewsRequest{Path: "test-article-01-08", Year: 2024})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if views.Views != 7 {
+		t.Errorf("unexpected views: %d", views.Views)
+	}
+}
+
+func TestClient_FloodWait(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Response[Page]{OK: false, Error: "FLOOD_WAIT_7"})
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	_, err := client.CreatePage(context.Background(), CreatePageRequest{AccessToken: "token", Title: "T", Content: []any{"c"}})
+
+	var flood *FloodWaitError
+	if !errors.As(err, &flood) {
+		t.Fatalf("expected FloodWaitError, got %v", err)
+	}
+	if flood.Wait != 7*time.Second {
+		t.Errorf("unexpected wait: %v", flood.Wait)
+	}
+	if !errors.Is(err, ErrFloodWait) || !errors.Is(err, ErrAPIError) {
+		t.Errorf("expected error to match ErrFloodWait and ErrAPIError")
+	}
+}
+
+func TestClient_HTTPStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("<html>bad gateway</html>"))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	_, err := client.CreateAccount(context.Background(), CreateAccountRequest{ShortName: "bot"})
+
+	var status *StatusError
+	if !errors.As(err, &status) || status.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected StatusError 502, got %v", err)
+	}
+	if !status.Temporary() || !errors.Is(err, ErrHTTPStatus) {
+		t.Errorf("expected temporary ErrHTTPStatus")
+	}
+}
+
+func TestClient_HTTPStatusWithAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		json.NewEncoder(w).Encode(Response[Page]{OK: false, Error: "FLOOD_WAIT_3"})
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	_, err := client.GetPage(context.Background(), "page", false)
+	if !errors.Is(err, ErrFloodWait) {
+		t.Fatalf("expected ErrFloodWait, got %v", err)
+	}
+}
@@ -1,8 +1,13 @@
 package telegraph
 
 import (
+	"encoding/json"
+	"fmt"
 	"regexp"
 	"strings"
+
+	"twitterx-bot/internal/chain"
+	"twitterx-bot/internal/twitterxapi"
 )
 
 const (
@@ -46,29 +51,328 @@ func (c *Converter) TextToNodes(text string) ([]any, error) {
 		return nil, ErrContentTooLong
 	}
 
-	// Розбиваємо на параграфи (по подвійному переносу)
-	paragraphs := strings.Split(text, "\n\n")
+	nodes := c.paragraphNodes(text)
+	if len(nodes) == 0 {
+		return nil, ErrContentEmpty
+	}
+
+	return nodes, nil
+}
+
+// TweetTitle повертає заголовок статті для твіту
+func (c *Converter) TweetTitle(tweet *twitterxapi.Tweet) string {
+	if tweet == nil {
+		return "Tweet"
+	}
+	if screenName := strings.TrimPrefix(strings.TrimSpace(tweet.Author.ScreenName), "@"); screenName != "" {
+		return "Tweet by @" + screenName
+	}
+	if name := strings.TrimSpace(tweet.Author.Name); name != "" {
+		return "Tweet by " + name
+	}
+	return "Tweet"
+}
+
+// TweetToNodes конвертує твіт у Telegraph DOM:
+// aside з автором та аватаром, текст, figure з фото/відео, blockquote з цитатою та h4 з метаданими
+func (c *Converter) TweetToNodes(tweet *twitterxapi.Tweet) ([]any, error) {
+	if tweet == nil || !hasTweetContent(tweet) {
+		return nil, ErrContentEmpty
+	}
+
+	nodes := c.tweetNodes(tweet, true)
+	if err := validateContentSize(nodes); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// ChainTitle повертає заголовок статті для ланцюжка твітів
+func (c *Converter) ChainTitle(items []chain.ChainItem) string {
+	for i := len(items) - 1; i >= 0; i-- {
+		if items[i].Tweet != nil {
+			return strings.Replace(c.TweetTitle(items[i].Tweet), "Tweet", "Thread", 1)
+		}
+	}
+	return "Thread"
+}
+
+// ChainToNodes конвертує ланцюжок твітів у Telegraph DOM.
+// Кожен твіт — окрема секція з h3 маркером (відповідь, цитата, твіт), автором, текстом та медіа
+func (c *Converter) ChainToNodes(items []chain.ChainItem) ([]any, error) {
+	var nodes []any
+	var prev *twitterxapi.Tweet
+	index := 0
+	for _, item := range items {
+		if item.Tweet == nil || !hasTweetContent(item.Tweet) {
+			continue
+		}
+		index++
+
+		if len(nodes) > 0 {
+			nodes = append(nodes, map[string]any{"tag": "hr"})
+		}
+		nodes = append(nodes, map[string]any{
+			"tag":      "h3",
+			"children": []any{fmt.Sprintf("%d. %s", index, chainMarker(item.Type))},
+		})
+
+		// Цитата вже є окремою секцією перед твітом, який її цитує
+		includeQuote := item.Tweet.Quote == nil || item.Tweet.Quote != prev
+		nodes = append(nodes, c.tweetNodes(item.Tweet, includeQuote)...)
+		prev = item.Tweet
+	}
+
+	if len(nodes) == 0 {
+		return nil, ErrContentEmpty
+	}
+	if err := validateContentSize(nodes); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// DigestEntry - твіт у дайджесті чату з підписом секції (наприклад, скільки разів ним поділилися)
+type DigestEntry struct {
+	Tweet *twitterxapi.Tweet
+	Label string
+}
+
+// DigestToNodes конвертує твіти дайджесту у Telegraph DOM: кожен твіт — секція з h3 підписом.
+// Секції, що не вміщуються в ліміт Telegraph, відкидаються з кінця
+func (c *Converter) DigestToNodes(entries []DigestEntry) ([]any, error) {
+	var nodes []any
+	for _, entry := range entries {
+		if entry.Tweet == nil || !hasTweetContent(entry.Tweet) {
+			continue
+		}
+
+		section := make([]any, 0, 8)
+		if len(nodes) > 0 {
+			section = append(section, map[string]any{"tag": "hr"})
+		}
+		if label := strings.TrimSpace(entry.Label); label != "" {
+			section = append(section, map[string]any{
+				"tag":      "h3",
+				"children": []any{label},
+			})
+		}
+		section = append(section, c.tweetNodes(entry.Tweet, true)...)
+
+		candidate := append(append([]any(nil), nodes...), section...)
+		if validateContentSize(candidate) != nil {
+			break
+		}
+		nodes = candidate
+	}
+
+	if len(nodes) == 0 {
+		return nil, ErrContentEmpty
+	}
+	return nodes, nil
+}
+
+// chainMarker повертає підпис секції залежно від типу зв'язку в ланцюжку
+func chainMarker(t chain.ChainType) string {
+	switch t {
+	case chain.ChainTypeReply:
+		return "↩️ Reply"
+	case chain.ChainTypeQuote:
+		return "💬 Quoted tweet"
+	default:
+		return "🐦 Tweet"
+	}
+}
+
+// hasTweetContent перевіряє, що у твіті є текст, медіа або цитата
+func hasTweetContent(tweet *twitterxapi.Tweet) bool {
+	if strings.TrimSpace(tweet.Text) != "" || tweet.Quote != nil {
+		return true
+	}
+	return tweet.Media != nil && (len(tweet.Media.Photos) > 0 || len(tweet.Media.Videos) > 0)
+}
 
+// tweetNodes збирає вузли одного твіту: автор, текст, медіа, цитата (опційно) та метадані
+func (c *Converter) tweetNodes(tweet *twitterxapi.Tweet, includeQuote bool) []any {
 	var nodes []any
-	for _, para := range paragraphs {
+	if header := c.authorNode(tweet.Author); header != nil {
+		nodes = append(nodes, header)
+	}
+	nodes = append(nodes, c.paragraphNodes(tweet.Text)...)
+	nodes = append(nodes, c.mediaNodes(tweet.Media)...)
+	if includeQuote && tweet.Quote != nil {
+		nodes = append(nodes, c.quoteNodes(tweet.Quote)...)
+	}
+	if meta := c.metadataNode(tweet); meta != nil {
+		nodes = append(nodes, meta)
+	}
+	return nodes
+}
+
+// paragraphNodes розбиває текст на параграфи (по подвійному переносу)
+func (c *Converter) paragraphNodes(text string) []any {
+	var nodes []any
+	for _, para := range strings.Split(text, "\n\n") {
 		para = strings.TrimSpace(para)
 		if para == "" {
 			continue
 		}
 
-		children := c.processParagraph(para)
-		node := map[string]any{
+		nodes = append(nodes, map[string]any{
 			"tag":      "p",
+			"children": c.processParagraph(para),
+		})
+	}
+	return nodes
+}
+
+// authorNode створює шапку з аватаром та посиланням на профіль автора
+func (c *Converter) authorNode(author twitterxapi.Author) any {
+	name := strings.TrimSpace(author.Name)
+	screenName := strings.TrimPrefix(strings.TrimSpace(author.ScreenName), "@")
+	if name == "" && screenName == "" {
+		return nil
+	}
+	if name == "" {
+		name = "@" + screenName
+	}
+
+	var children []any
+	if avatar := strings.TrimSpace(author.AvatarURL); avatar != "" {
+		children = append(children, map[string]any{
+			"tag":   "img",
+			"attrs": map[string]string{"src": avatar},
+		})
+	}
+
+	nameNode := map[string]any{"tag": "b", "children": []any{name}}
+	if screenName != "" {
+		children = append(children, map[string]any{
+			"tag":      "a",
+			"attrs":    map[string]string{"href": "https://x.com/" + screenName},
+			"children": []any{nameNode},
+		})
+		children = append(children, " @"+screenName)
+	} else {
+		children = append(children, nameNode)
+	}
+
+	return map[string]any{
+		"tag":      "aside",
+		"children": children,
+	}
+}
+
+// mediaNodes створює figure з img для фото та video для відео
+func (c *Converter) mediaNodes(media *twitterxapi.Media) []any {
+	if media == nil {
+		return nil
+	}
+
+	var nodes []any
+	for _, photo := range media.Photos {
+		if url := strings.TrimSpace(photo.URL); url != "" {
+			nodes = append(nodes, figureNode("img", url))
+		}
+	}
+	for _, video := range media.Videos {
+		if url := strings.TrimSpace(video.URL); url != "" {
+			nodes = append(nodes, figureNode("video", url))
+		}
+	}
+	return nodes
+}
+
+// quoteNodes створює blockquote з автором, текстом та посиланням на цитований твіт, а також його медіа
+func (c *Converter) quoteNodes(quote *twitterxapi.Tweet) []any {
+	var children []any
+
+	name := strings.TrimSpace(quote.Author.Name)
+	screenName := strings.TrimPrefix(strings.TrimSpace(quote.Author.ScreenName), "@")
+	switch {
+	case name != "" && screenName != "":
+		children = append(children, map[string]any{"tag": "strong", "children": []any{fmt.Sprintf("%s (@%s)", name, screenName)}})
+	case name != "":
+		children = append(children, map[string]any{"tag": "strong", "children": []any{name}})
+	case screenName != "":
+		children = append(children, map[string]any{"tag": "strong", "children": []any{"@" + screenName}})
+	}
+
+	if text := strings.TrimSpace(quote.Text); text != "" {
+		if len(children) > 0 {
+			children = append(children, map[string]any{"tag": "br"})
+		}
+		children = append(children, c.processParagraph(text)...)
+	}
+
+	if url := strings.TrimSpace(quote.URL); url != "" {
+		if len(children) > 0 {
+			children = append(children, map[string]any{"tag": "br"})
+		}
+		children = append(children, map[string]any{
+			"tag":      "a",
+			"attrs":    map[string]string{"href": url},
+			"children": []any{"Quoted tweet"},
+		})
+	}
+
+	var nodes []any
+	if len(children) > 0 {
+		nodes = append(nodes, map[string]any{
+			"tag":      "blockquote",
 			"children": children,
+		})
+	}
+	return append(nodes, c.mediaNodes(quote.Media)...)
+}
+
+// metadataNode створює h4 з інформацією про відповідь та посиланням на оригінал
+func (c *Converter) metadataNode(tweet *twitterxapi.Tweet) any {
+	var children []any
+	if tweet.ReplyingTo != nil && strings.TrimSpace(*tweet.ReplyingTo) != "" {
+		children = append(children, "Replying to @"+strings.TrimPrefix(strings.TrimSpace(*tweet.ReplyingTo), "@"))
+	}
+	if url := strings.TrimSpace(tweet.URL); url != "" {
+		if len(children) > 0 {
+			children = append(children, " · ")
 		}
-		nodes = append(nodes, node)
+		children = append(children, map[string]any{
+			"tag":      "a",
+			"attrs":    map[string]string{"href": url},
+			"children": []any{"View on X"},
+		})
+	}
+	if len(children) == 0 {
+		return nil
+	}
+	return map[string]any{
+		"tag":      "h4",
+		"children": children,
 	}
+}
 
-	if len(nodes) == 0 {
-		return nil, ErrContentEmpty
+func figureNode(tag, src string) any {
+	return map[string]any{
+		"tag": "figure",
+		"children": []any{
+			map[string]any{
+				"tag":   tag,
+				"attrs": map[string]string{"src": src},
+			},
+		},
 	}
+}
 
-	return nodes, nil
+// validateContentSize перевіряє, що серіалізований контент не перевищує ліміт Telegraph
+func validateContentSize(nodes []any) error {
+	encoded, err := json.Marshal(nodes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal content: %w", err)
+	}
+	if len(encoded) > MaxContentLength {
+		return ErrContentTooLong
+	}
+	return nil
 }
 
 // processParagraph обробляє один параграф
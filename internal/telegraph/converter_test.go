@@ -3,6 +3,9 @@ package telegraph
 import (
 	"strings"
 	"testing"
+
+	"twitterx-bot/internal/chain"
+	"twitterx-bot/internal/twitterxapi"
 )
 
 func TestValidateTitle_Valid(t *testing.T) {
@@ -165,3 +168,226 @@ func TestTextToNodes_WithLineBreak(t *testing.T) {
 		t.Fatal("expected to find <br> in children")
 	}
 }
+
+func findNodes(nodes []any, tag string) []map[string]any {
+	var found []map[string]any
+	for _, n := range nodes {
+		m, ok := n.(map[string]any)
+		if !ok {
+			continue
+		}
+		if m["tag"] == tag {
+			found = append(found, m)
+		}
+		if children, ok := m["children"].([]any); ok {
+			found = append(found, findNodes(children, tag)...)
+		}
+	}
+	return found
+}
+
+func TestTweetTitle(t *testing.T) {
+	c := NewConverter()
+
+	tests := []struct {
+		tweet *twitterxapi.Tweet
+		want  string
+	}{
+		{&twitterxapi.Tweet{Author: twitterxapi.Author{Name: "Elon", ScreenName: "elonmusk"}}, "Tweet by @elonmusk"},
+		{&twitterxapi.Tweet{Author: twitterxapi.Author{Name: "Elon"}}, "Tweet by Elon"},
+		{&twitterxapi.Tweet{}, "Tweet"},
+		{nil, "Tweet"},
+	}
+
+	for _, tt := range tests {
+		if got := c.TweetTitle(tt.tweet); got != tt.want {
+			t.Errorf("TweetTitle() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestTweetToNodes_RichContent(t *testing.T) {
+	c := NewConverter()
+	replyTo := "someone"
+	tweet := &twitterxapi.Tweet{
+		ID:   "1",
+		URL:  "https://x.com/user/status/1",
+		Text: "First paragraph\n\nSecond paragraph",
+		Author: twitterxapi.Author{
+			Name:       "User",
+			ScreenName: "user",
+			AvatarURL:  "https://img/avatar.jpg",
+		},
+		Media: &twitterxapi.Media{
+			Photos: []twitterxapi.Photo{{URL: "https://img/1.jpg"}, {URL: "https://img/2.jpg"}},
+			Videos: []twitterxapi.Video{{URL: "https://video/1.mp4"}},
+		},
+		ReplyingTo: &replyTo,
+		Quote: &twitterxapi.Tweet{
+			URL:    "https://x.com/quoted/status/2",
+			Text:   "Quoted text",
+			Author: twitterxapi.Author{Name: "Quoted", ScreenName: "quoted"},
+			Media:  &twitterxapi.Media{Photos: []twitterxapi.Photo{{URL: "https://img/q.jpg"}}},
+		},
+	}
+
+	nodes, err := c.TweetToNodes(tweet)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	first, ok := nodes[0].(map[string]any)
+	if !ok || first["tag"] != "aside" {
+		t.Fatalf("expected aside author header first, got %v", nodes[0])
+	}
+	imgs := findNodes([]any{first}, "img")
+	if len(imgs) != 1 || imgs[0]["attrs"].(map[string]string)["src"] != "https://img/avatar.jpg" {
+		t.Fatalf("expected avatar image in header, got %v", imgs)
+	}
+
+	if got := len(findNodes(nodes, "p")); got != 2 {
+		t.Fatalf("expected 2 paragraphs, got %d", got)
+	}
+	if got := len(findNodes(nodes, "figure")); got != 4 {
+		t.Fatalf("expected 4 figures (3 own + 1 quoted), got %d", got)
+	}
+	if got := len(findNodes(nodes, "video")); got != 1 {
+		t.Fatalf("expected 1 video, got %d", got)
+	}
+
+	quotes := findNodes(nodes, "blockquote")
+	if len(quotes) != 1 {
+		t.Fatalf("expected 1 blockquote, got %d", len(quotes))
+	}
+	links := findNodes([]any{quotes[0]}, "a")
+	if len(links) != 1 || links[0]["attrs"].(map[string]string)["href"] != "https://x.com/quoted/status/2" {
+		t.Fatalf("expected link to quoted tweet, got %v", links)
+	}
+
+	last, ok := nodes[len(nodes)-1].(map[string]any)
+	if !ok || last["tag"] != "h4" {
+		t.Fatalf("expected h4 metadata last, got %v", nodes[len(nodes)-1])
+	}
+	children := last["children"].([]any)
+	if children[0] != "Replying to @someone" {
+		t.Fatalf("expected reply metadata, got %v", children[0])
+	}
+}
+
+func TestTweetToNodes_MediaOnly(t *testing.T) {
+	c := NewConverter()
+	tweet := &twitterxapi.Tweet{
+		Media: &twitterxapi.Media{Photos: []twitterxapi.Photo{{URL: "https://img/1.jpg"}}},
+	}
+
+	nodes, err := c.TweetToNodes(tweet)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(nodes) != 1 {
+		t.Fatalf("expected only a figure, got %d nodes", len(nodes))
+	}
+}
+
+func TestTweetToNodes_Empty(t *testing.T) {
+	c := NewConverter()
+
+	if _, err := c.TweetToNodes(&twitterxapi.Tweet{Author: twitterxapi.Author{ScreenName: "user"}}); err != ErrContentEmpty {
+		t.Fatalf("expected ErrContentEmpty, got %v", err)
+	}
+	if _, err := c.TweetToNodes(nil); err != ErrContentEmpty {
+		t.Fatalf("expected ErrContentEmpty for nil tweet, got %v", err)
+	}
+}
+
+func TestTweetToNodes_ContentTooLong(t *testing.T) {
+	c := NewConverter()
+
+	_, err := c.TweetToNodes(&twitterxapi.Tweet{Text: strings.Repeat("a", MaxContentLength+1)})
+	if err != ErrContentTooLong {
+		t.Fatalf("expected ErrContentTooLong, got %v", err)
+	}
+}
+
+func TestChainToNodes_Sections(t *testing.T) {
+	c := NewConverter()
+	quoted := &twitterxapi.Tweet{Text: "Quoted", Author: twitterxapi.Author{ScreenName: "quoted"}}
+	parent := &twitterxapi.Tweet{Text: "Parent", Quote: quoted, Author: twitterxapi.Author{ScreenName: "parent"}}
+	root := &twitterxapi.Tweet{
+		Text:   "Root",
+		Author: twitterxapi.Author{ScreenName: "root"},
+		Media:  &twitterxapi.Media{Photos: []twitterxapi.Photo{{URL: "https://img/1.jpg"}}},
+	}
+	items := []chain.ChainItem{
+		{Tweet: quoted, Type: chain.ChainTypeQuote},
+		{Tweet: parent, Type: chain.ChainTypeReply},
+		{Tweet: root, Type: chain.ChainTypeRoot},
+	}
+
+	nodes, err := c.ChainToNodes(items)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	headers := findNodes(nodes, "h3")
+	if len(headers) != 3 {
+		t.Fatalf("expected 3 sections, got %d", len(headers))
+	}
+	wantMarkers := []string{"1. 💬 Quoted tweet", "2. ↩️ Reply", "3. 🐦 Tweet"}
+	for i, h := range headers {
+		if got := h["children"].([]any)[0]; got != wantMarkers[i] {
+			t.Errorf("section %d marker = %v, want %q", i, got, wantMarkers[i])
+		}
+	}
+	if got := len(findNodes(nodes, "hr")); got != 2 {
+		t.Errorf("expected 2 separators, got %d", got)
+	}
+	// Цитата вже є окремою секцією, тому не дублюється в blockquote
+	if got := len(findNodes(nodes, "blockquote")); got != 0 {
+		t.Errorf("expected no duplicated blockquote, got %d", got)
+	}
+	if got := len(findNodes(nodes, "figure")); got != 1 {
+		t.Errorf("expected 1 figure, got %d", got)
+	}
+	if title := c.ChainTitle(items); title != "Thread by @root" {
+		t.Errorf("ChainTitle() = %q, want %q", title, "Thread by @root")
+	}
+}
+
+func TestChainToNodes_Empty(t *testing.T) {
+	c := NewConverter()
+
+	if _, err := c.ChainToNodes([]chain.ChainItem{{Tweet: nil}}); err != ErrContentEmpty {
+		t.Fatalf("expected ErrContentEmpty, got %v", err)
+	}
+}
+
+func TestDigestToNodes_SectionsWithinLimit(t *testing.T) {
+	c := NewConverter()
+	entries := []DigestEntry{
+		{Tweet: &twitterxapi.Tweet{Text: "First", Author: twitterxapi.Author{ScreenName: "a"}}, Label: "1. 🔁 Shared 3 times"},
+		{Tweet: nil, Label: "skipped"},
+		{Tweet: &twitterxapi.Tweet{Text: "Second", Author: twitterxapi.Author{ScreenName: "b"}}, Label: "2."},
+		{Tweet: &twitterxapi.Tweet{Text: strings.Repeat("a", MaxContentLength)}, Label: "3. too long"},
+	}
+
+	nodes, err := c.DigestToNodes(entries)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	headers := findNodes(nodes, "h3")
+	if len(headers) != 2 {
+		t.Fatalf("expected 2 sections, got %d", len(headers))
+	}
+	if got := headers[0]["children"].([]any)[0]; got != "1. 🔁 Shared 3 times" {
+		t.Errorf("first label = %v", got)
+	}
+	if got := len(findNodes(nodes, "hr")); got != 1 {
+		t.Errorf("expected 1 separator, got %d", got)
+	}
+
+	if _, err := c.DigestToNodes(nil); err != ErrContentEmpty {
+		t.Errorf("expected ErrContentEmpty, got %v", err)
+	}
+}
@@ -1,6 +1,12 @@
 package telegraph
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
 
 var (
 	ErrAPIError       = errors.New("telegraph api error")
@@ -9,4 +15,55 @@ var (
 	ErrTitleTooLong   = errors.New("title exceeds 256 characters")
 	ErrTitleEmpty     = errors.New("title is empty")
 	ErrNoAccessToken  = errors.New("account has no access token")
+	ErrPathEmpty      = errors.New("page path is empty")
+	ErrFloodWait      = errors.New("telegraph flood wait")
+	ErrRequestFailed  = errors.New("telegraph request failed")
+	ErrHTTPStatus     = errors.New("telegraph unexpected http status")
+	ErrQueueFull      = errors.New("telegraph queue is full")
 )
+
+// floodWaitPrefix - префікс помилки Telegraph при перевищенні ліміту запитів (FLOOD_WAIT_N, N - секунди)
+const floodWaitPrefix = "FLOOD_WAIT_"
+
+// FloodWaitError - Telegraph просить зачекати Wait перед наступним запитом.
+// Відповідає errors.Is(err, ErrFloodWait) та errors.Is(err, ErrAPIError)
+type FloodWaitError struct {
+	Wait time.Duration
+}
+
+func (e *FloodWaitError) Error() string {
+	return fmt.Sprintf("%s: %s%d", ErrAPIError, floodWaitPrefix, int(e.Wait/time.Second))
+}
+
+func (e *FloodWaitError) Is(target error) bool {
+	return target == ErrFloodWait || target == ErrAPIError
+}
+
+// StatusError - Telegraph відповів HTTP статусом, відмінним від 2xx.
+// Відповідає errors.Is(err, ErrHTTPStatus)
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: %d", ErrHTTPStatus, e.StatusCode)
+}
+
+func (e *StatusError) Is(target error) bool {
+	return target == ErrHTTPStatus
+}
+
+// Temporary повідомляє, чи варто повторити запит (5xx або 429)
+func (e *StatusError) Temporary() bool {
+	return e.StatusCode >= 500 || e.StatusCode == 429
+}
+
+// apiError перетворює текст помилки Telegraph у помилку, FLOOD_WAIT_N - у *FloodWaitError
+func apiError(message string) error {
+	if rest, ok := strings.CutPrefix(message, floodWaitPrefix); ok {
+		if seconds, err := strconv.Atoi(rest); err == nil && seconds >= 0 {
+			return &FloodWaitError{Wait: time.Duration(seconds) * time.Second}
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrAPIError, message)
+}
@@ -26,10 +26,22 @@ type Page struct {
 	AuthorName  string `json:"author_name,omitempty"`
 	AuthorURL   string `json:"author_url,omitempty"`
 	ImageURL    string `json:"image_url,omitempty"`
+	Content     []any  `json:"content,omitempty"`
 	Views       int    `json:"views,omitempty"`
 	CanEdit     bool   `json:"can_edit,omitempty"`
 }
 
+// PageList - список сторінок акаунту
+type PageList struct {
+	TotalCount int    `json:"total_count"`
+	Pages      []Page `json:"pages"`
+}
+
+// PageViews - кількість переглядів сторінки
+type PageViews struct {
+	Views int `json:"views"`
+}
+
 // CreateAccountRequest - запит на створення акаунту
 type CreateAccountRequest struct {
 	ShortName  string
@@ -46,3 +58,31 @@ type CreatePageRequest struct {
 	AuthorURL     string
 	ReturnContent bool
 }
+
+// EditPageRequest - запит на редагування існуючої сторінки
+type EditPageRequest struct {
+	AccessToken   string
+	Path          string
+	Title         string
+	Content       []any // Node або string
+	AuthorName    string
+	AuthorURL     string
+	ReturnContent bool
+}
+
+// GetPageListRequest - запит на отримання списку сторінок акаунту
+type GetPageListRequest struct {
+	AccessToken string
+	Offset      int
+	Limit       int // 0..200, 0 - значення за замовчуванням (50)
+}
+
+// GetViewsRequest - запит на отримання кількості переглядів.
+// Year, Month, Day, Hour опціональні: 0 - без фільтра
+type GetViewsRequest struct {
+	Path  string
+	Year  int
+	Month int
+	Day   int
+	Hour  int
+}
@@ -0,0 +1,241 @@
+package telegraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultMaxAttempts  = 3
+	defaultMaxFloodWait = 10 * time.Second
+	defaultRetryBackoff = 500 * time.Millisecond
+)
+
+var errUnknownAccount = errors.New("telegraph account is not in the pool")
+
+// poolAccount - акаунт пулу та час, до якого Telegraph просив його не використовувати
+type poolAccount struct {
+	Account
+	blockedUntil time.Time
+}
+
+// WithAccountPool встановлює кількість акаунтів, між якими по черзі розподіляються запити.
+// FLOOD_WAIT на одному акаунті не зупиняє інші
+func WithAccountPool(size int) Option {
+	return func(s *Service) {
+		if size > 0 {
+			s.poolSize = size
+		}
+	}
+}
+
+// WithRetry встановлює максимальну кількість спроб запиту та найдовше очікування FLOOD_WAIT.
+// Якщо Telegraph просить чекати довше за maxFloodWait, помилка повертається одразу
+func WithRetry(maxAttempts int, maxFloodWait time.Duration) Option {
+	return func(s *Service) {
+		if maxAttempts > 0 {
+			s.maxAttempts = maxAttempts
+		}
+		if maxFloodWait >= 0 {
+			s.maxFloodWait = maxFloodWait
+		}
+	}
+}
+
+// WithQueue обмежує кількість одночасних публікацій до workers, а черга очікування - до maxPending.
+// Якщо черга заповнена, публікація одразу завершується з ErrQueueFull
+func WithQueue(workers, maxPending int) Option {
+	return func(s *Service) {
+		if workers > 0 {
+			s.queue = make(chan struct{}, workers)
+			s.maxPending = max(maxPending, 0)
+		}
+	}
+}
+
+// acquire займає місце в черзі публікацій та повертає функцію для його звільнення
+func (s *Service) acquire(ctx context.Context) (func(), error) {
+	if s.queue == nil {
+		return func() {}, nil
+	}
+
+	if s.pending.Add(1) > int64(cap(s.queue)+s.maxPending) {
+		s.pending.Add(-1)
+		return nil, ErrQueueFull
+	}
+
+	select {
+	case s.queue <- struct{}{}:
+		return func() {
+			<-s.queue
+			s.pending.Add(-1)
+		}, nil
+	case <-ctx.Done():
+		s.pending.Add(-1)
+		return nil, ctx.Err()
+	}
+}
+
+// call виконує запит акаунтом з пулу. При FLOOD_WAIT акаунт блокується і наступна спроба
+// використовує інший акаунт (або чекає, якщо всі заблоковані), тимчасові помилки повторюються з затримкою.
+// shortName обирає конкретний акаунт (для редагування його сторінок); порожній - будь-який по черзі
+func (s *Service) call(ctx context.Context, shortName string, op func(account *Account) (*Page, error)) (*Page, string, error) {
+	var lastErr error
+	for attempt := 1; attempt <= s.maxAttempts; attempt++ {
+		account, wait, err := s.pickAccount(ctx, shortName)
+		if err != nil {
+			return nil, "", err
+		}
+		if wait > 0 {
+			if wait > s.maxFloodWait {
+				return nil, "", &FloodWaitError{Wait: wait}
+			}
+			if err := s.sleep(ctx, wait); err != nil {
+				return nil, "", err
+			}
+		}
+
+		page, err := op(&account.Account)
+		if err == nil {
+			return page, account.ShortName, nil
+		}
+		lastErr = err
+
+		var flood *FloodWaitError
+		switch {
+		case errors.As(err, &flood):
+			s.block(account, flood.Wait)
+		case isTemporary(err) && ctx.Err() == nil:
+			if attempt < s.maxAttempts {
+				if err := s.sleep(ctx, s.retryBackoff*time.Duration(attempt)); err != nil {
+					return nil, "", lastErr
+				}
+			}
+		default:
+			return nil, "", err
+		}
+	}
+	return nil, "", lastErr
+}
+
+// pickAccount повертає акаунт для запиту та час, який потрібно зачекати перед його використанням
+func (s *Service) pickAccount(ctx context.Context, shortName string) (*poolAccount, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.ensureAccounts(ctx); err != nil {
+		return nil, 0, err
+	}
+	now := s.now()
+
+	if shortName != "" {
+		for _, account := range s.accounts {
+			if account.ShortName == shortName {
+				return account, account.blockedUntil.Sub(now), nil
+			}
+		}
+		return nil, 0, fmt.Errorf("%w: %s", errUnknownAccount, shortName)
+	}
+
+	// Round-robin серед незаблокованих акаунтів
+	var soonest *poolAccount
+	for i := range s.accounts {
+		idx := (s.next + i) % len(s.accounts)
+		account := s.accounts[idx]
+		if !account.blockedUntil.After(now) {
+			s.next = idx + 1
+			return account, 0, nil
+		}
+		if soonest == nil || account.blockedUntil.Before(soonest.blockedUntil) {
+			soonest = account
+		}
+	}
+	return soonest, soonest.blockedUntil.Sub(now), nil
+}
+
+// block позначає акаунт як недоступний на час FLOOD_WAIT
+func (s *Service) block(account *poolAccount, wait time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if until := s.now().Add(wait); until.After(account.blockedUntil) {
+		account.blockedUntil = until
+	}
+}
+
+// ensureAccounts відновлює акаунти зі сховища та створює відсутні до розміру пулу.
+// Викликається під s.mu
+func (s *Service) ensureAccounts(ctx context.Context) error {
+	if !s.accountsLoaded {
+		// Відновлюємо збережені акаунти, щоб не втратити доступ до створених сторінок
+		stored, err := s.store.LoadAccounts(ctx)
+		if err != nil {
+			return fmt.Errorf("load telegraph accounts: %w", err)
+		}
+		for _, account := range stored {
+			if account.AccessToken != "" {
+				s.accounts = append(s.accounts, &poolAccount{Account: account})
+			}
+		}
+		s.accountsLoaded = true
+	}
+
+	created := false
+	for len(s.accounts) < s.poolSize {
+		shortName := s.shortName
+		if n := len(s.accounts); n > 0 {
+			shortName = fmt.Sprintf("%s-%d", s.shortName, n+1)
+		}
+
+		account, err := s.client.CreateAccount(ctx, CreateAccountRequest{
+			ShortName:  shortName,
+			AuthorName: s.authorName,
+			AuthorURL:  s.authorURL,
+		})
+		if err == nil && account.AccessToken == "" {
+			err = ErrNoAccessToken
+		}
+		if err != nil {
+			// Працюємо з тими акаунтами, що вже є
+			if len(s.accounts) > 0 {
+				break
+			}
+			return err
+		}
+
+		s.accounts = append(s.accounts, &poolAccount{Account: *account})
+		created = true
+	}
+
+	if created {
+		accounts := make([]Account, 0, len(s.accounts))
+		for _, account := range s.accounts {
+			accounts = append(accounts, account.Account)
+		}
+		if err := s.store.SaveAccounts(ctx, accounts); err != nil {
+			return fmt.Errorf("save telegraph accounts: %w", err)
+		}
+	}
+	return nil
+}
+
+// isTemporary повідомляє, чи можна повторити запит після помилки
+func isTemporary(err error) bool {
+	if errors.Is(err, ErrRequestFailed) {
+		return true
+	}
+	var status *StatusError
+	return errors.As(err, &status) && status.Temporary()
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
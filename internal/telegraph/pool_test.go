@@ -0,0 +1,181 @@
+package telegraph
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// scriptedClient повертає помилки CreatePage по черзі, а потім успіх
+type scriptedClient struct {
+	mu         sync.Mutex
+	pageErrs   []error
+	pageTokens []string
+	accounts   int
+}
+
+func (c *scriptedClient) CreateAccount(_ context.Context, req CreateAccountRequest) (*Account, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.accounts++
+	return &Account{ShortName: req.ShortName, AccessToken: "token-" + req.ShortName}, nil
+}
+
+func (c *scriptedClient) CreatePage(_ context.Context, req CreatePageRequest) (*Page, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pageTokens = append(c.pageTokens, req.AccessToken)
+	if len(c.pageErrs) > 0 {
+		err := c.pageErrs[0]
+		c.pageErrs = c.pageErrs[1:]
+		return nil, err
+	}
+	return &Page{URL: "https://telegra.ph/page", Path: "page"}, nil
+}
+
+func (c *scriptedClient) EditPage(_ context.Context, _ EditPageRequest) (*Page, error) {
+	return nil, ErrAPIError
+}
+
+// recordSleeps підміняє очікування в сервісі, щоб тести не чекали реальний час
+func recordSleeps(s *Service) *[]time.Duration {
+	var sleeps []time.Duration
+	s.sleep = func(_ context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return nil
+	}
+	return &sleeps
+}
+
+func TestService_AccountPool_RoundRobin(t *testing.T) {
+	client := &scriptedClient{}
+	service := NewService(client, WithShortName("bot"), WithAccountPool(2))
+
+	for i := 0; i < 3; i++ {
+		if _, err := service.CreateTextArticle(context.Background(), "Content", "Title"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if client.accounts != 2 {
+		t.Errorf("expected 2 accounts, got %d", client.accounts)
+	}
+	want := []string{"token-bot", "token-bot-2", "token-bot"}
+	for i, token := range want {
+		if client.pageTokens[i] != token {
+			t.Errorf("request %d used %q, want %q", i, client.pageTokens[i], token)
+		}
+	}
+}
+
+func TestService_FloodWait_SwitchesAccount(t *testing.T) {
+	client := &scriptedClient{pageErrs: []error{&FloodWaitError{Wait: time.Minute}}}
+	service := NewService(client, WithShortName("bot"), WithAccountPool(2))
+	sleeps := recordSleeps(service)
+
+	if _, err := service.CreateTextArticle(context.Background(), "Content", "Title"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(*sleeps) != 0 {
+		t.Errorf("expected no waiting with a free account, got %v", *sleeps)
+	}
+	if len(client.pageTokens) != 2 || client.pageTokens[1] != "token-bot-2" {
+		t.Errorf("expected retry on the second account, got %v", client.pageTokens)
+	}
+}
+
+func TestService_FloodWait_WaitsWhenShort(t *testing.T) {
+	client := &scriptedClient{pageErrs: []error{&FloodWaitError{Wait: 2 * time.Second}}}
+	service := NewService(client, WithRetry(3, 5*time.Second))
+	sleeps := recordSleeps(service)
+
+	if _, err := service.CreateTextArticle(context.Background(), "Content", "Title"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(*sleeps) != 1 || (*sleeps)[0] <= 0 || (*sleeps)[0] > 2*time.Second {
+		t.Errorf("expected one wait of up to 2s, got %v", *sleeps)
+	}
+}
+
+func TestService_FloodWait_TooLong(t *testing.T) {
+	client := &scriptedClient{pageErrs: []error{&FloodWaitError{Wait: time.Hour}}}
+	service := NewService(client, WithRetry(3, 5*time.Second))
+	sleeps := recordSleeps(service)
+
+	_, err := service.CreateTextArticle(context.Background(), "Content", "Title")
+
+	var flood *FloodWaitError
+	if !errors.As(err, &flood) {
+		t.Fatalf("expected FloodWaitError, got %v", err)
+	}
+	if flood.Wait <= 5*time.Second {
+		t.Errorf("unexpected wait: %v", flood.Wait)
+	}
+	if len(*sleeps) != 0 {
+		t.Errorf("expected no waiting, got %v", *sleeps)
+	}
+}
+
+func TestService_RetriesTemporaryErrors(t *testing.T) {
+	client := &scriptedClient{pageErrs: []error{&StatusError{StatusCode: 502}, ErrRequestFailed}}
+	service := NewService(client)
+	sleeps := recordSleeps(service)
+
+	if _, err := service.CreateTextArticle(context.Background(), "Content", "Title"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(client.pageTokens) != 3 {
+		t.Errorf("expected 3 attempts, got %d", len(client.pageTokens))
+	}
+	if len(*sleeps) != 2 {
+		t.Errorf("expected 2 backoff waits, got %v", *sleeps)
+	}
+}
+
+func TestService_DoesNotRetryAPIErrors(t *testing.T) {
+	client := &scriptedClient{pageErrs: []error{apiError("CONTENT_TOO_BIG")}}
+	service := NewService(client)
+	recordSleeps(service)
+
+	if _, err := service.CreateTextArticle(context.Background(), "Content", "Title"); !errors.Is(err, ErrAPIError) {
+		t.Fatalf("expected ErrAPIError, got %v", err)
+	}
+	if len(client.pageTokens) != 1 {
+		t.Errorf("expected 1 attempt, got %d", len(client.pageTokens))
+	}
+}
+
+func TestService_QueueFull(t *testing.T) {
+	service := NewService(&scriptedClient{}, WithQueue(1, 0))
+
+	release, err := service.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := service.CreateTextArticle(context.Background(), "Content", "Title"); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+
+	release()
+	if _, err := service.CreateTextArticle(context.Background(), "Content", "Title"); err != nil {
+		t.Fatalf("unexpected error after release: %v", err)
+	}
+}
+
+func TestService_QueueWaitRespectsContext(t *testing.T) {
+	service := NewService(&scriptedClient{}, WithQueue(1, 5))
+
+	release, _ := service.acquire(context.Background())
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := service.CreateTextArticle(ctx, "Content", "Title"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context deadline, got %v", err)
+	}
+}
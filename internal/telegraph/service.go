@@ -4,13 +4,18 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"twitterx-bot/internal/chain"
+	"twitterx-bot/internal/twitterxapi"
 )
 
 // TelegraphClient - інтерфейс для HTTP клієнта
 type TelegraphClient interface {
 	CreateAccount(ctx context.Context, req CreateAccountRequest) (*Account, error)
 	CreatePage(ctx context.Context, req CreatePageRequest) (*Page, error)
+	EditPage(ctx context.Context, req EditPageRequest) (*Page, error)
 }
 
 // Service - сервіс для роботи з Telegraph
@@ -20,9 +25,23 @@ type Service struct {
 	shortName  string
 	authorName string
 	authorURL  string
-
-	cachedAccount *Account
-	mu            sync.Mutex
+	store      Store
+
+	// Пул акаунтів та повтори (див. pool.go)
+	poolSize     int
+	maxAttempts  int
+	maxFloodWait time.Duration
+	retryBackoff time.Duration
+	queue        chan struct{}
+	maxPending   int
+	pending      atomic.Int64
+	now          func() time.Time
+	sleep        func(ctx context.Context, d time.Duration) error
+
+	accounts       []*poolAccount
+	accountsLoaded bool
+	next           int
+	mu             sync.Mutex
 }
 
 // Option - функція для налаштування сервісу
@@ -49,6 +68,14 @@ func WithAuthorURL(url string) Option {
 	}
 }
 
+// WithStore встановлює сховище акаунту та сторінок.
+// За замовчуванням використовується MemoryStore
+func WithStore(store Store) Option {
+	return func(s *Service) {
+		s.store = store
+	}
+}
+
 // NewService створює новий сервіс
 func NewService(client TelegraphClient, opts ...Option) *Service {
 	s := &Service{
@@ -56,77 +83,165 @@ func NewService(client TelegraphClient, opts ...Option) *Service {
 		converter:  NewConverter(),
 		shortName:  fmt.Sprintf("TwitterX-%d", time.Now().UnixNano()%100000),
 		authorName: "TwitterX Bot",
+
+		poolSize:     1,
+		maxAttempts:  defaultMaxAttempts,
+		maxFloodWait: defaultMaxFloodWait,
+		retryBackoff: defaultRetryBackoff,
+		now:          time.Now,
+		sleep:        sleepContext,
 	}
 
 	for _, opt := range opts {
 		opt(s)
 	}
+	if s.store == nil {
+		s.store = NewMemoryStore()
+	}
 
 	return s
 }
 
-// CreateArticle створює статтю в Telegraph та повертає URL
-func (s *Service) CreateArticle(ctx context.Context, text, title string) (string, error) {
+// CreateArticle створює статтю в Telegraph з твіту (текст, медіа, цитата, автор) та повертає URL.
+// Якщо для твіту вже є стаття, вона оновлюється замість створення дубліката
+func (s *Service) CreateArticle(ctx context.Context, tweet *twitterxapi.Tweet) (string, error) {
 	// Валідуємо заголовок
-	validTitle, err := s.converter.ValidateTitle(title)
+	validTitle, err := s.converter.ValidateTitle(s.converter.TweetTitle(tweet))
 	if err != nil {
 		return "", err
 	}
 
-	// Конвертуємо текст в DOM
-	content, err := s.converter.TextToNodes(text)
+	// Конвертуємо твіт в DOM
+	content, err := s.converter.TweetToNodes(tweet)
 	if err != nil {
 		return "", err
 	}
 
-	// Отримуємо або створюємо акаунт
-	account, err := s.getOrCreateAccount(ctx)
+	return s.publishPage(ctx, tweetPageKey(tweet), validTitle, content)
+}
+
+// CreateChainArticle створює одну статтю з усього ланцюжка твітів та повертає URL.
+// Повторний запит того ж ланцюжка оновлює існуючу статтю
+func (s *Service) CreateChainArticle(ctx context.Context, items []chain.ChainItem) (string, error) {
+	// Валідуємо заголовок
+	validTitle, err := s.converter.ValidateTitle(s.converter.ChainTitle(items))
 	if err != nil {
 		return "", err
 	}
 
-	// Створюємо сторінку
-	page, err := s.client.CreatePage(ctx, CreatePageRequest{
-		AccessToken:   account.AccessToken,
-		Title:         validTitle,
-		Content:       content,
-		AuthorName:    s.authorName,
-		AuthorURL:     s.authorURL,
-		ReturnContent: false,
-	})
+	// Конвертуємо ланцюжок в DOM
+	content, err := s.converter.ChainToNodes(items)
 	if err != nil {
 		return "", err
 	}
 
-	return page.URL, nil
+	return s.publishPage(ctx, chainPageKey(items), validTitle, content)
 }
 
-// getOrCreateAccount отримує акаунт з кешу або створює новий
-func (s *Service) getOrCreateAccount(ctx context.Context) (*Account, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// CreateDigestArticle створює статтю з твітів дайджесту чату та повертає URL.
+// Кожен дайджест — нова сторінка
+func (s *Service) CreateDigestArticle(ctx context.Context, title string, entries []DigestEntry) (string, error) {
+	// Валідуємо заголовок
+	validTitle, err := s.converter.ValidateTitle(title)
+	if err != nil {
+		return "", err
+	}
 
-	// Повертаємо з кешу якщо є
-	if s.cachedAccount != nil && s.cachedAccount.AccessToken != "" {
-		return s.cachedAccount, nil
+	// Конвертуємо твіти в DOM
+	content, err := s.converter.DigestToNodes(entries)
+	if err != nil {
+		return "", err
 	}
 
-	// Створюємо новий акаунт
-	account, err := s.client.CreateAccount(ctx, CreateAccountRequest{
-		ShortName:  s.shortName,
-		AuthorName: s.authorName,
-		AuthorURL:  s.authorURL,
+	return s.publishPage(ctx, "", validTitle, content)
+}
+
+// CreateTextArticle створює статтю в Telegraph з простого тексту та повертає URL
+func (s *Service) CreateTextArticle(ctx context.Context, text, title string) (string, error) {
+	// Валідуємо заголовок
+	validTitle, err := s.converter.ValidateTitle(title)
+	if err != nil {
+		return "", err
+	}
+
+	// Конвертуємо текст в DOM
+	content, err := s.converter.TextToNodes(text)
+	if err != nil {
+		return "", err
+	}
+
+	return s.publishPage(ctx, "", validTitle, content)
+}
+
+// publishPage оновлює сторінку, раніше створену для key, або створює нову та повертає URL.
+// Порожній key означає, що сторінка завжди створюється заново
+func (s *Service) publishPage(ctx context.Context, key, title string, content []any) (string, error) {
+	// Чекаємо своєї черги, щоб пікові навантаження не впиралися в FLOOD_WAIT
+	release, err := s.acquire(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	if key != "" {
+		if ref, ok, err := s.store.LoadPage(ctx, key); err == nil && ok {
+			page, _, err := s.call(ctx, ref.ShortName, func(account *Account) (*Page, error) {
+				return s.client.EditPage(ctx, EditPageRequest{
+					AccessToken: account.AccessToken,
+					Path:        ref.Path,
+					Title:       title,
+					Content:     content,
+					AuthorName:  s.authorName,
+					AuthorURL:   s.authorURL,
+				})
+			})
+			if err == nil {
+				return page.URL, nil
+			}
+			// Сторінка могла бути створена іншим акаунтом або видалена — створюємо нову
+		}
+	}
+
+	// Створюємо сторінку
+	page, shortName, err := s.call(ctx, "", func(account *Account) (*Page, error) {
+		return s.client.CreatePage(ctx, CreatePageRequest{
+			AccessToken:   account.AccessToken,
+			Title:         title,
+			Content:       content,
+			AuthorName:    s.authorName,
+			AuthorURL:     s.authorURL,
+			ReturnContent: false,
+		})
 	})
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	if account.AccessToken == "" {
-		return nil, ErrNoAccessToken
+	if key != "" && page.Path != "" {
+		// Помилка збереження не критична: у гіршому випадку наступного разу буде створено дублікат
+		_ = s.store.SavePage(ctx, key, PageRef{Path: page.Path, ShortName: shortName})
 	}
 
-	// Кешуємо
-	s.cachedAccount = account
+	return page.URL, nil
+}
+
+// tweetPageKey повертає ключ сторінки для твіту
+func tweetPageKey(tweet *twitterxapi.Tweet) string {
+	if tweet == nil || tweet.ID == "" {
+		return ""
+	}
+	return "tweet:" + tweet.ID
+}
 
-	return account, nil
+// chainPageKey повертає ключ сторінки для ланцюжка (за ID останнього твіту)
+func chainPageKey(items []chain.ChainItem) string {
+	for i := len(items) - 1; i >= 0; i-- {
+		if items[i].Tweet != nil {
+			if items[i].Tweet.ID == "" {
+				return ""
+			}
+			return "chain:" + items[i].Tweet.ID
+		}
+	}
+	return ""
 }
@@ -3,6 +3,8 @@ package telegraph
 import (
 	"context"
 	"testing"
+
+	"twitterx-bot/internal/twitterxapi"
 )
 
 // fakeClient для тестування
@@ -11,10 +13,15 @@ type fakeClient struct {
 	createAccountErr  error
 	createPageResp    *Page
 	createPageErr     error
+	editPageResp      *Page
+	editPageErr       error
 
 	// Для перевірки викликів
 	createAccountCalls int
 	createPageCalls    int
+	editPageCalls      int
+	lastPageReq        CreatePageRequest
+	lastEditReq        EditPageRequest
 }
 
 func (f *fakeClient) CreateAccount(ctx context.Context, req CreateAccountRequest) (*Account, error) {
@@ -24,10 +31,17 @@ func (f *fakeClient) CreateAccount(ctx context.Context, req CreateAccountRequest
 
 func (f *fakeClient) CreatePage(ctx context.Context, req CreatePageRequest) (*Page, error) {
 	f.createPageCalls++
+	f.lastPageReq = req
 	return f.createPageResp, f.createPageErr
 }
 
-func TestService_CreateArticle_Success(t *testing.T) {
+func (f *fakeClient) EditPage(ctx context.Context, req EditPageRequest) (*Page, error) {
+	f.editPageCalls++
+	f.lastEditReq = req
+	return f.editPageResp, f.editPageErr
+}
+
+func TestService_CreateTextArticle_Success(t *testing.T) {
 	client := &fakeClient{
 		createAccountResp: &Account{
 			ShortName:   "TestBot",
@@ -41,7 +55,7 @@ func TestService_CreateArticle_Success(t *testing.T) {
 
 	service := NewService(client)
 
-	url, err := service.CreateArticle(context.Background(), "Test content", "Test Title")
+	url, err := service.CreateTextArticle(context.Background(), "Test content", "Test Title")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -70,13 +84,13 @@ func TestService_CreateArticle_CachesAccount(t *testing.T) {
 	service := NewService(client)
 
 	// Перший виклик
-	_, err := service.CreateArticle(context.Background(), "Content 1", "Title 1")
+	_, err := service.CreateTextArticle(context.Background(), "Content 1", "Title 1")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
 	// Другий виклик - акаунт має бути з кешу
-	_, err = service.CreateArticle(context.Background(), "Content 2", "Title 2")
+	_, err = service.CreateTextArticle(context.Background(), "Content 2", "Title 2")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -92,21 +106,21 @@ func TestService_CreateArticle_CachesAccount(t *testing.T) {
 	}
 }
 
-func TestService_CreateArticle_InvalidTitle(t *testing.T) {
+func TestService_CreateTextArticle_InvalidTitle(t *testing.T) {
 	client := &fakeClient{}
 	service := NewService(client)
 
-	_, err := service.CreateArticle(context.Background(), "Content", "")
+	_, err := service.CreateTextArticle(context.Background(), "Content", "")
 	if err != ErrTitleEmpty {
 		t.Errorf("expected ErrTitleEmpty, got %v", err)
 	}
 }
 
-func TestService_CreateArticle_EmptyContent(t *testing.T) {
+func TestService_CreateTextArticle_EmptyContent(t *testing.T) {
 	client := &fakeClient{}
 	service := NewService(client)
 
-	_, err := service.CreateArticle(context.Background(), "", "Title")
+	_, err := service.CreateTextArticle(context.Background(), "", "Title")
 	if err != ErrContentEmpty {
 		t.Errorf("expected ErrContentEmpty, got %v", err)
 	}
@@ -122,7 +136,7 @@ func TestService_CreateArticle_NoAccessToken(t *testing.T) {
 
 	service := NewService(client)
 
-	_, err := service.CreateArticle(context.Background(), "Content", "Title")
+	_, err := service.CreateTextArticle(context.Background(), "Content", "Title")
 	if err != ErrNoAccessToken {
 		t.Errorf("expected ErrNoAccessToken, got %v", err)
 	}
@@ -155,3 +169,122 @@ func TestService_WithOptions(t *testing.T) {
 		t.Errorf("expected authorURL 'https://example.com', got '%s'", service.authorURL)
 	}
 }
+
+func TestService_CreateArticle_FromTweet(t *testing.T) {
+	client := &fakeClient{
+		createAccountResp: &Account{AccessToken: "test-token"},
+		createPageResp:    &Page{URL: "https://telegra.ph/tweet"},
+	}
+	service := NewService(client)
+
+	tweet := &twitterxapi.Tweet{
+		URL:    "https://x.com/user/status/1",
+		Text:   "Hello",
+		Author: twitterxapi.Author{Name: "User", ScreenName: "user"},
+		Media:  &twitterxapi.Media{Photos: []twitterxapi.Photo{{URL: "https://img/1.jpg"}}},
+	}
+
+	url, err := service.CreateArticle(context.Background(), tweet)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if url != "https://telegra.ph/tweet" {
+		t.Errorf("unexpected URL: %s", url)
+	}
+	if client.lastPageReq.Title != "Tweet by @user" {
+		t.Errorf("unexpected title: %s", client.lastPageReq.Title)
+	}
+	if got := len(findNodes(client.lastPageReq.Content, "figure")); got != 1 {
+		t.Errorf("expected 1 figure in content, got %d", got)
+	}
+}
+
+func TestService_CreateArticle_UsesStoredAccount(t *testing.T) {
+	store := NewMemoryStore()
+	store.SaveAccounts(context.Background(), []Account{{ShortName: "stored", AccessToken: "stored-token"}})
+
+	client := &fakeClient{createPageResp: &Page{URL: "https://telegra.ph/a", Path: "a"}}
+	service := NewService(client, WithStore(store))
+
+	if _, err := service.CreateTextArticle(context.Background(), "Content", "Title"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.createAccountCalls != 0 {
+		t.Errorf("expected no createAccount calls, got %d", client.createAccountCalls)
+	}
+	if client.lastPageReq.AccessToken != "stored-token" {
+		t.Errorf("expected stored token, got %q", client.lastPageReq.AccessToken)
+	}
+}
+
+func TestService_CreateArticle_SavesNewAccount(t *testing.T) {
+	store := NewMemoryStore()
+	client := &fakeClient{
+		createAccountResp: &Account{AccessToken: "new-token"},
+		createPageResp:    &Page{URL: "https://telegra.ph/a", Path: "a"},
+	}
+	service := NewService(client, WithStore(store))
+
+	if _, err := service.CreateTextArticle(context.Background(), "Content", "Title"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	accounts, _ := store.LoadAccounts(context.Background())
+	if len(accounts) != 1 || accounts[0].AccessToken != "new-token" {
+		t.Fatalf("expected account to be saved, got %+v", accounts)
+	}
+}
+
+func TestService_CreateArticle_EditsExistingPage(t *testing.T) {
+	client := &fakeClient{
+		createAccountResp: &Account{AccessToken: "token"},
+		createPageResp:    &Page{URL: "https://telegra.ph/tweet-1", Path: "tweet-1"},
+		editPageResp:      &Page{URL: "https://telegra.ph/tweet-1", Path: "tweet-1"},
+	}
+	service := NewService(client)
+	tweet := &twitterxapi.Tweet{ID: "1", Text: "Hello", Author: twitterxapi.Author{ScreenName: "user"}}
+
+	if _, err := service.CreateArticle(context.Background(), tweet); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tweet.Text = "Hello, edited"
+	url, err := service.CreateArticle(context.Background(), tweet)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if client.createPageCalls != 1 {
+		t.Errorf("expected 1 createPage call, got %d", client.createPageCalls)
+	}
+	if client.editPageCalls != 1 || client.lastEditReq.Path != "tweet-1" {
+		t.Errorf("expected edit of tweet-1, got %d calls with path %q", client.editPageCalls, client.lastEditReq.Path)
+	}
+	if url != "https://telegra.ph/tweet-1" {
+		t.Errorf("unexpected URL: %s", url)
+	}
+}
+
+func TestService_CreateArticle_EditFailureCreatesNewPage(t *testing.T) {
+	store := NewMemoryStore()
+	store.SavePage(context.Background(), "tweet:1", PageRef{Path: "old-page"})
+
+	client := &fakeClient{
+		createAccountResp: &Account{AccessToken: "token"},
+		createPageResp:    &Page{URL: "https://telegra.ph/new-page", Path: "new-page"},
+		editPageErr:       ErrAPIError,
+	}
+	service := NewService(client, WithStore(store))
+	tweet := &twitterxapi.Tweet{ID: "1", Text: "Hello"}
+
+	url, err := service.CreateArticle(context.Background(), tweet)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if url != "https://telegra.ph/new-page" {
+		t.Errorf("unexpected URL: %s", url)
+	}
+	if ref, _, _ := store.LoadPage(context.Background(), "tweet:1"); ref.Path != "new-page" {
+		t.Errorf("expected stored path to be replaced, got %q", ref.Path)
+	}
+}
@@ -0,0 +1,220 @@
+package telegraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Store - сховище акаунтів та створених сторінок, щоб не втрачати доступ до них після перезапуску
+// та оновлювати сторінку твіту замість створення дубліката
+type Store interface {
+	// LoadAccounts повертає збережені акаунти пулу (порожній список, якщо їх ще немає)
+	LoadAccounts(ctx context.Context) ([]Account, error)
+	SaveAccounts(ctx context.Context, accounts []Account) error
+
+	// LoadPage повертає сторінку, раніше створену для ключа (наприклад, ID твіту)
+	LoadPage(ctx context.Context, key string) (PageRef, bool, error)
+	SavePage(ctx context.Context, key string, ref PageRef) error
+}
+
+// PageRef - посилання на створену сторінку та акаунт, яким її можна редагувати
+type PageRef struct {
+	Path      string `json:"path"`
+	ShortName string `json:"short_name,omitempty"`
+}
+
+// maxPages - скільки сторінок пам'ятає сховище; найстаріші забуваються першими
+const maxPages = 1024
+
+// pageCache - обмежений набір сторінок за ключами. Забута сторінка лише означає,
+// що для ключа буде створено нову сторінку замість редагування старої
+type pageCache struct {
+	refs  map[string]PageRef
+	order []string
+}
+
+func (c *pageCache) get(key string) (PageRef, bool) {
+	ref, ok := c.refs[key]
+	return ref, ok
+}
+
+func (c *pageCache) put(key string, ref PageRef) {
+	if c.refs == nil {
+		c.refs = make(map[string]PageRef)
+	}
+	if _, ok := c.refs[key]; !ok {
+		c.order = append(c.order, key)
+		if len(c.order) > maxPages {
+			delete(c.refs, c.order[0])
+			c.order = c.order[1:]
+		}
+	}
+	c.refs[key] = ref
+}
+
+// MemoryStore - сховище в пам'яті (дані втрачаються після перезапуску)
+type MemoryStore struct {
+	mu       sync.RWMutex
+	accounts []Account
+	pages    pageCache
+}
+
+// NewMemoryStore створює сховище в пам'яті
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// LoadAccounts повертає копію збережених акаунтів
+func (s *MemoryStore) LoadAccounts(_ context.Context) ([]Account, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Account(nil), s.accounts...), nil
+}
+
+// SaveAccounts зберігає копію акаунтів
+func (s *MemoryStore) SaveAccounts(_ context.Context, accounts []Account) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accounts = append([]Account(nil), accounts...)
+	return nil
+}
+
+// LoadPage повертає сторінку для ключа
+func (s *MemoryStore) LoadPage(_ context.Context, key string) (PageRef, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ref, ok := s.pages.get(key)
+	return ref, ok, nil
+}
+
+// SavePage зберігає сторінку для ключа
+func (s *MemoryStore) SavePage(_ context.Context, key string, ref PageRef) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pages.put(key, ref)
+	return nil
+}
+
+// fileState - формат JSON файлу FileStore
+type fileState struct {
+	Accounts []Account `json:"accounts,omitempty"`
+}
+
+// FileStore - сховище акаунтів у JSON файлі. Файл містить access_token, тому створюється з правами 0600.
+// Сторінки зберігаються лише в пам'яті, як у MemoryStore: файл перезаписується тільки при зміні акаунтів,
+// а не на кожну нову статтю
+type FileStore struct {
+	path string
+
+	mu     sync.Mutex
+	state  fileState
+	pages  pageCache
+	loaded bool
+}
+
+// NewFileStore створює сховище у файлі path. Файл створюється при першому збереженні
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// LoadAccounts повертає акаунти з файлу або порожній список, якщо файлу ще немає
+func (s *FileStore) LoadAccounts(_ context.Context) ([]Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return append([]Account(nil), s.state.Accounts...), nil
+}
+
+// SaveAccounts зберігає акаунти у файл
+func (s *FileStore) SaveAccounts(_ context.Context, accounts []Account) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return err
+	}
+	s.state.Accounts = append([]Account(nil), accounts...)
+	return s.flush()
+}
+
+// LoadPage повертає сторінку для ключа
+func (s *FileStore) LoadPage(_ context.Context, key string) (PageRef, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ref, ok := s.pages.get(key)
+	return ref, ok, nil
+}
+
+// SavePage запам'ятовує сторінку для ключа, не змінюючи файл
+func (s *FileStore) SavePage(_ context.Context, key string, ref PageRef) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pages.put(key, ref)
+	return nil
+}
+
+// load читає файл один раз; відсутній файл означає порожній стан
+func (s *FileStore) load() error {
+	if s.loaded {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		s.loaded = true
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read telegraph store: %w", err)
+	}
+
+	var state fileState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("decode telegraph store: %w", err)
+	}
+	s.state = state
+	s.loaded = true
+	return nil
+}
+
+// flush атомарно перезаписує файл через тимчасовий файл
+func (s *FileStore) flush() error {
+	data, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode telegraph store: %w", err)
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create telegraph store dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".telegraph-*.tmp")
+	if err != nil {
+		return fmt.Errorf("create telegraph store temp file: %w", err)
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write telegraph store: %w", err)
+	}
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("chmod telegraph store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close telegraph store: %w", err)
+	}
+	if err := os.Rename(tmpName, s.path); err != nil {
+		return fmt.Errorf("replace telegraph store: %w", err)
+	}
+	return nil
+}
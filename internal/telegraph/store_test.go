@@ -0,0 +1,88 @@
+package telegraph
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileStore_PersistsAcrossInstances(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "telegraph", "account.json")
+	ctx := context.Background()
+
+	store := NewFileStore(path)
+	if accounts, err := store.LoadAccounts(ctx); err != nil || len(accounts) != 0 {
+		t.Fatalf("expected empty store, got %+v, %v", accounts, err)
+	}
+	accounts := []Account{
+		{ShortName: "TwitterX-1", AccessToken: "secret-1"},
+		{ShortName: "TwitterX-2", AccessToken: "secret-2"},
+	}
+	if err := store.SaveAccounts(ctx, accounts); err != nil {
+		t.Fatalf("SaveAccounts() error = %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat store file: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("store file mode = %o, want 600", perm)
+	}
+
+	reopened := NewFileStore(path)
+	loaded, err := reopened.LoadAccounts(ctx)
+	if err != nil {
+		t.Fatalf("LoadAccounts() error = %v", err)
+	}
+	if len(loaded) != 2 || loaded[1].AccessToken != "secret-2" {
+		t.Fatalf("expected persisted accounts, got %+v", loaded)
+	}
+}
+
+func TestFileStore_PagesDoNotRewriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "account.json")
+	ctx := context.Background()
+
+	store := NewFileStore(path)
+	if err := store.SavePage(ctx, "tweet:1", PageRef{Path: "tweet-by-user-01-01", ShortName: "TwitterX-2"}); err != nil {
+		t.Fatalf("SavePage() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("store file after SavePage(): %v, want none", err)
+	}
+	ref, ok, err := store.LoadPage(ctx, "tweet:1")
+	if err != nil || !ok || ref.Path != "tweet-by-user-01-01" || ref.ShortName != "TwitterX-2" {
+		t.Fatalf("LoadPage() = %+v, %v, %v", ref, ok, err)
+	}
+}
+
+func TestMemoryStore_ForgetsOldestPages(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	for i := 0; i <= maxPages; i++ {
+		if err := store.SavePage(ctx, fmt.Sprintf("tweet:%d", i), PageRef{Path: fmt.Sprintf("page-%d", i)}); err != nil {
+			t.Fatalf("SavePage() error = %v", err)
+		}
+	}
+
+	if _, ok, _ := store.LoadPage(ctx, "tweet:0"); ok {
+		t.Error("LoadPage() found the oldest page over the limit")
+	}
+	if ref, ok, _ := store.LoadPage(ctx, fmt.Sprintf("tweet:%d", maxPages)); !ok || ref.Path != fmt.Sprintf("page-%d", maxPages) {
+		t.Errorf("LoadPage() of the newest page = %+v, %v", ref, ok)
+	}
+}
+
+func TestFileStore_CorruptedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "account.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	if _, err := NewFileStore(path).LoadAccounts(context.Background()); err == nil {
+		t.Fatal("expected error for corrupted file, got nil")
+	}
+}
@@ -0,0 +1,120 @@
+package translation
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultCacheSize = 1000
+	DefaultCacheTTL  = 24 * time.Hour
+)
+
+// Cache wraps a Translator and memoizes results keyed by text hash and target language.
+// Entries are evicted least-recently-used once the cache is full, or after the TTL expires.
+type Cache struct {
+	next Translator
+	size int
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type cacheEntry struct {
+	key       string
+	value     Translation
+	expiresAt time.Time
+}
+
+// NewCache creates a translation cache in front of next.
+// Non-positive size or ttl fall back to DefaultCacheSize and DefaultCacheTTL.
+func NewCache(next Translator, size int, ttl time.Duration) *Cache {
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &Cache{
+		next:    next,
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Translate returns a cached translation or delegates to the wrapped translator.
+func (c *Cache) Translate(ctx context.Context, text string, to Language) (*Translation, error) {
+	key := cacheKey(text, to)
+
+	if tr, ok := c.get(key); ok {
+		return tr, nil
+	}
+
+	tr, err := c.next.Translate(ctx, text, to)
+	if err != nil {
+		return nil, err
+	}
+	if tr != nil {
+		c.put(key, *tr)
+	}
+	return tr, nil
+}
+
+func (c *Cache) get(key string) (*Translation, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*cacheEntry)
+	if c.now().After(entry.expiresAt) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	tr := entry.value
+	return &tr, true
+}
+
+func (c *Cache) put(key string, tr Translation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		entry := el.Value.(*cacheEntry)
+		entry.value = tr
+		entry.expiresAt = c.now().Add(c.ttl)
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{
+		key:       key,
+		value:     tr,
+		expiresAt: c.now().Add(c.ttl),
+	})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// cacheKey builds a cache key from the SHA-256 of text and the target language.
+func cacheKey(text string, to Language) string {
+	sum := sha256.Sum256([]byte(text))
+	return to.ISO + ":" + hex.EncodeToString(sum[:])
+}
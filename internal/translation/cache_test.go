@@ -0,0 +1,81 @@
+package translation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCache_ReusesTranslation(t *testing.T) {
+	next := &stubTranslator{result: &Translation{Text: "Привіт"}}
+	cache := NewCache(next, 10, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		translation, err := cache.Translate(context.Background(), "Hello", LangUkrainian)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if translation.Text != "Привіт" {
+			t.Errorf("expected 'Привіт', got '%s'", translation.Text)
+		}
+	}
+	if next.calls != 1 {
+		t.Errorf("expected 1 upstream call, got %d", next.calls)
+	}
+
+	if _, err := cache.Translate(context.Background(), "Hello", LangRussian); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next.calls != 2 {
+		t.Errorf("expected a new upstream call for another target, got %d calls", next.calls)
+	}
+}
+
+func TestCache_DoesNotCacheErrors(t *testing.T) {
+	next := &stubTranslator{err: errors.New("boom")}
+	cache := NewCache(next, 10, time.Hour)
+
+	cache.Translate(context.Background(), "Hello", LangUkrainian)
+	cache.Translate(context.Background(), "Hello", LangUkrainian)
+
+	if next.calls != 2 {
+		t.Errorf("expected 2 upstream calls, got %d", next.calls)
+	}
+}
+
+func TestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	next := &stubTranslator{result: &Translation{Text: "x"}}
+	cache := NewCache(next, 2, time.Hour)
+	ctx := context.Background()
+
+	cache.Translate(ctx, "a", LangUkrainian)
+	cache.Translate(ctx, "b", LangUkrainian)
+	cache.Translate(ctx, "a", LangUkrainian) // touch "a"
+	cache.Translate(ctx, "c", LangUkrainian) // evicts "b"
+	calls := next.calls
+
+	cache.Translate(ctx, "a", LangUkrainian)
+	if next.calls != calls {
+		t.Errorf("expected 'a' to stay cached")
+	}
+	cache.Translate(ctx, "b", LangUkrainian)
+	if next.calls != calls+1 {
+		t.Errorf("expected 'b' to be evicted")
+	}
+}
+
+func TestCache_ExpiresEntries(t *testing.T) {
+	next := &stubTranslator{result: &Translation{Text: "x"}}
+	cache := NewCache(next, 10, time.Minute)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	cache.Translate(context.Background(), "Hello", LangUkrainian)
+	now = now.Add(2 * time.Minute)
+	cache.Translate(context.Background(), "Hello", LangUkrainian)
+
+	if next.calls != 2 {
+		t.Errorf("expected expired entry to be refetched, got %d calls", next.calls)
+	}
+}
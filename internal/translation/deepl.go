@@ -0,0 +1,95 @@
+package translation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultDeepLBaseURL is the DeepL API Free endpoint. Pro keys use https://api.deepl.com/v2.
+const DefaultDeepLBaseURL = "https://api-free.deepl.com/v2"
+
+// DeepL provides translations via a DeepL-compatible HTTP API.
+// See https://developers.deepl.com/docs/api-reference/translate for the API reference.
+type DeepL struct {
+	httpClient *http.Client
+	baseURL    string
+	authKey    string
+	log        *slog.Logger
+}
+
+// NewDeepL creates a DeepL provider. If baseURL is empty, DefaultDeepLBaseURL is used.
+func NewDeepL(httpClient *http.Client, baseURL, authKey string) *DeepL {
+	if baseURL == "" {
+		baseURL = DefaultDeepLBaseURL
+	}
+	return &DeepL{
+		httpClient: httpClient,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		authKey:    authKey,
+		log:        slog.Default().With("component", "translation", "provider", "deepl"),
+	}
+}
+
+type deepLResponse struct {
+	Translations []struct {
+		DetectedSourceLanguage string `json:"detected_source_language"`
+		Text                   string `json:"text"`
+	} `json:"translations"`
+	Message string `json:"message,omitempty"`
+}
+
+// Translate translates text into the specified language.
+func (d *DeepL) Translate(ctx context.Context, text string, to Language) (*Translation, error) {
+	d.log.Debug("starting translation request", "text_length", len(text), "target_language", to.ISO)
+
+	form := url.Values{}
+	form.Set("text", text)
+	form.Set("target_lang", strings.ToUpper(to.ISO))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.baseURL+"/translate", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "DeepL-Auth-Key "+d.authKey)
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		d.log.Error("translation request failed", "err", err)
+		return nil, fmt.Errorf("translation request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var deepLResp deepLResponse
+	decodeErr := json.NewDecoder(resp.Body).Decode(&deepLResp)
+
+	if resp.StatusCode != http.StatusOK {
+		d.log.Error("translation API returned error", "status", resp.StatusCode, "message", deepLResp.Message)
+		if deepLResp.Message != "" {
+			return nil, fmt.Errorf("translation API returned status %d: %s", resp.StatusCode, deepLResp.Message)
+		}
+		return nil, fmt.Errorf("translation API returned status %d", resp.StatusCode)
+	}
+	if decodeErr != nil {
+		d.log.Error("failed to decode translation response", "err", decodeErr)
+		return nil, fmt.Errorf("failed to decode response: %w", decodeErr)
+	}
+	if len(deepLResp.Translations) == 0 {
+		return nil, fmt.Errorf("translation API returned no translations")
+	}
+
+	first := deepLResp.Translations[0]
+	from := LanguageFromISO(first.DetectedSourceLanguage)
+
+	d.log.Info("translation completed successfully", "from", from.ISO, "to", to.ISO)
+	return &Translation{
+		From: from,
+		To:   to,
+		Text: first.Text,
+	}, nil
+}
@@ -0,0 +1,81 @@
+package translation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDeepL_Translate_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/translate" {
+			t.Errorf("expected /translate, got %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "DeepL-Auth-Key secret" {
+			t.Errorf("unexpected authorization header '%s'", r.Header.Get("Authorization"))
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("failed to parse form: %v", err)
+		}
+		if r.Form.Get("target_lang") != "UK" {
+			t.Errorf("expected target_lang 'UK', got '%s'", r.Form.Get("target_lang"))
+		}
+		if r.Form.Get("text") != "Hello world" {
+			t.Errorf("expected text 'Hello world', got '%s'", r.Form.Get("text"))
+		}
+
+		w.Write([]byte(`{"translations":[{"detected_source_language":"EN","text":"Привіт світ"}]}`))
+	}))
+	defer server.Close()
+
+	provider := NewDeepL(&http.Client{}, server.URL, "secret")
+
+	translation, err := provider.Translate(context.Background(), "Hello world", LangUkrainian)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if translation.Text != "Привіт світ" {
+		t.Errorf("expected 'Привіт світ', got '%s'", translation.Text)
+	}
+	if translation.From.ISO != "en" {
+		t.Errorf("expected source language 'en', got '%s'", translation.From.ISO)
+	}
+}
+
+func TestDeepL_Translate_Forbidden(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"Wrong auth key"}`))
+	}))
+	defer server.Close()
+
+	provider := NewDeepL(&http.Client{}, server.URL, "bad")
+
+	_, err := provider.Translate(context.Background(), "Hello", LangUkrainian)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestDeepL_Translate_EmptyTranslations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"translations":[]}`))
+	}))
+	defer server.Close()
+
+	provider := NewDeepL(&http.Client{}, server.URL, "secret")
+
+	_, err := provider.Translate(context.Background(), "Hello", LangUkrainian)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestNewDeepL_DefaultBaseURL(t *testing.T) {
+	provider := NewDeepL(&http.Client{}, "", "key")
+
+	if provider.baseURL != DefaultDeepLBaseURL {
+		t.Errorf("expected default base URL '%s', got '%s'", DefaultDeepLBaseURL, provider.baseURL)
+	}
+}
@@ -0,0 +1,300 @@
+package translation
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const (
+	// minDetectLetters is the minimum number of letters required to attempt detection.
+	minDetectLetters = 3
+
+	// minConfidentScore and minConfidentMargin make a profile match confident: enough signal,
+	// and a clear lead over the runner-up, e.g. a related language sharing most short words.
+	minConfidentScore  = 4
+	minConfidentMargin = 1.5
+)
+
+// Confidence tells how sure the detector is about the language of a text.
+type Confidence int
+
+const (
+	// NoLanguage means the text has no words, e.g. only links, mentions or emoji.
+	NoLanguage Confidence = iota
+	// UnknownLanguage means the text has words, but in a language the detector does not know.
+	UnknownLanguage
+	// LowConfidence is a guess, e.g. from a single word shared by related languages.
+	LowConfidence
+	// HighConfidence is a language told by its own script or by a clear lead over the others.
+	HighConfidence
+)
+
+// ErrNothingToTranslate is returned when text has no detectable language, e.g. only links or emoji.
+var ErrNothingToTranslate = errors.New("nothing to translate")
+
+var (
+	detectURLRegex     = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+	detectMentionRegex = regexp.MustCompile(`[@#$][\p{L}\p{N}_]+`)
+)
+
+// scriptLanguages maps scripts used by a single language to that language.
+var scriptLanguages = []struct {
+	table *unicode.RangeTable
+	iso   string
+}{
+	{unicode.Hangul, "ko"},
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Thai, "th"},
+	{unicode.Greek, "el"},
+	{unicode.Hebrew, "he"},
+	{unicode.Arabic, "ar"},
+	{unicode.Devanagari, "hi"},
+	{unicode.Georgian, "ka"},
+	{unicode.Armenian, "hy"},
+}
+
+// languageProfile describes a language written in a shared script (Latin or Cyrillic)
+// by its most frequent short words, its distinctive letters and characteristic letter sequences.
+// In ngrams, "_" marks a word boundary.
+type languageProfile struct {
+	iso       string
+	stopwords map[string]bool
+	markers   string
+	ngrams    []string
+}
+
+func newProfile(iso, words, markers, ngrams string) languageProfile {
+	stopwords := make(map[string]bool)
+	for _, w := range strings.Fields(words) {
+		stopwords[w] = true
+	}
+	var grams []string
+	for _, g := range strings.Fields(ngrams) {
+		grams = append(grams, strings.ReplaceAll(g, "_", " "))
+	}
+	return languageProfile{iso: iso, stopwords: stopwords, markers: markers, ngrams: grams}
+}
+
+var cyrillicProfiles = []languageProfile{
+	newProfile("uk",
+		"і й та що це не на як в у з до від для за але ми ви вони він вона воно його її їх був була було бути є буде дуже тут там чи вже ще теж також який яка яке які щоб коли бо лише тільки так ні цей ця ці свій своє мене мені тебе тобі нас вас їм зараз після через про під над",
+		"іїєґ",
+		"ння ськ ться ою_ ого_ ої_ ці_ ці ає_ ує_ ємо ите_ ати_ ити_ ли_ ва_ ій_ ів_"),
+	newProfile("ru",
+		"и в не на что с по это как а но он она они мы вы его её их был была было быть есть будет очень тут там ли уже ещё тоже также который которая которое которые чтобы когда потому только так нет этот эта эти свой меня мне тебя тебе нас вас им сейчас после через про под над или же всем всё",
+		"ыэъё",
+		"ого_ ая_ ое_ ые_ ие_ ия_ ию_ ую_ ть_ ться ешь ет_ ит_ ей_ ом_ ами_ ях_ ии_ ный_"),
+	newProfile("be",
+		"і ў не на што з па гэта як а але ён яна яны мы вы яго яе іх быў была было быць ёсць будзе вельмі тут там ці ужо яшчэ таксама які якая якое якія каб калі бо толькі так не гэты гэтая гэтыя",
+		"ўі",
+		"дз ць_ цца"),
+	newProfile("bg",
+		"и в не на че с по това как а но той тя те ние вие го я ги беше са е ще много тук там ли вече още също който която което които за да когато само така не този тази тези от при след",
+		"ъщ",
+		"ът_ та_ то_ ите_"),
+}
+
+var latinProfiles = []languageProfile{
+	newProfile("en",
+		"the be to of and a in that have i it for not on with he as you do at this but his by from they we say her she or an will my one all would there their what so up out if about who get which go me when make can like time no just him know take people into year your good some could them see other than then now look only come its over think also back after use two how our work first well way even new want because any these give day most us is are was were has had been being does did should very",
+		"",
+		"th wh ing_ ght ly_ ed_ ea ou_ ck"),
+	newProfile("de",
+		"der die das und ist nicht ein eine einen einem einer zu den von mit sich des auf für im dem auch es an als nach wie aus bei oder nur noch so wird werden sind war wir ihr sie er ich du aber wenn dass schon mehr über kann vor bis durch heute hat haben sein kein keine",
+		"äöüß",
+		"sch ich cht ung ei ie_ en_ tz ck"),
+	newProfile("fr",
+		"le la les de des du un une et est pas que qui en dans pour sur avec au aux ce cette ces il elle ils elles nous vous je tu ne se sont été être avoir a plus par mais ou comme tout fait très bien aussi leur son sa ses mon ma mes on y",
+		"àâçèéêëîïôûùœ",
+		"eau eux oi ai qu' _l' _d' _c' _n' _j' _s' ent_ ait_ ons_ ez_"),
+	newProfile("es",
+		"el la los las de del un una y es no que en por para con su sus se lo al como más pero muy ya también este esta estos estas ese esa hay son fue ser estar está están tiene yo tú él ella nosotros ellos le les me mi hoy cuando donde porque sin sobre alguien",
+		"ñáéíóú¿¡",
+		"ción ll os_ as_ ado_ ida_ ue ie ía_"),
+	newProfile("it",
+		"il lo la i gli le di del della dei un una e è non che in per con su al alla come più ma anche questo questa questi sono era essere ha hanno ho io tu lui lei noi voi loro mi ti si ci molto oggi quando dove perché senza sul nel nella se",
+		"àèéìòù",
+		"zion gli zz cch ll' ell tt pp_ ano_ are_ ere_ ire_ one_ ato_ ata_ ete_ ch"),
+	newProfile("pt",
+		"o a os as de do da dos das um uma e é não que em por para com seu sua se no na nos nas ao como mais mas muito já também este esta isso esse essa são foi ser estar está tem eu você ele ela nós eles me hoje quando onde porque sem sobre",
+		"ãõçáâêéíóôú",
+		"ção ões ão nh lh em_ mente"),
+	newProfile("pl",
+		"i w nie na że z się to jest do jak ale co po tak od za o już jego jej ich był była było być są będzie bardzo tu tam czy jeszcze też który która które żeby kiedy bo tylko ten ta te mnie mi ciebie nas was dla przez przy pod nad",
+		"ąćęłńśźż",
+		"sz cz rz dz ie ych owa ego_ ki wi prz cie"),
+	newProfile("nl",
+		"de het een en van is niet dat in op te met voor zijn er aan ook als maar om dan wat bij nog uit zo naar kan door over ze hij zij wij jullie ik je was waren worden wordt heeft hebben deze dit die geen meer",
+		"ĳ",
+		"ij oe aa ee uu sch cht lijk gen_ ijk"),
+	newProfile("tr",
+		"ve bir bu da de için ile çok ne var mı mi ama gibi daha en ben sen o biz siz onlar olarak olan kadar sonra şey her değil yok bugün ancak diye",
+		"ğışçöü",
+		"lar ler yor ız iz_ ın_"),
+}
+
+// Detector identifies the language of short texts offline using scripts, distinctive letters,
+// stopwords and characteristic letter sequences.
+type Detector struct{}
+
+// NewDetector creates an offline language detector.
+func NewDetector() *Detector {
+	return &Detector{}
+}
+
+// Detect returns the most likely language of text.
+// ok is false when the text has too little signal, e.g. only links, mentions or emoji,
+// or is in a language the detector does not know.
+func (d *Detector) Detect(text string) (Language, bool) {
+	lang, confidence := d.DetectConfidence(text)
+	return lang, confidence >= LowConfidence
+}
+
+// DetectConfidence returns the most likely language of text and how sure the guess is.
+func (d *Detector) DetectConfidence(text string) (Language, Confidence) {
+	cleaned := detectURLRegex.ReplaceAllString(text, " ")
+	cleaned = detectMentionRegex.ReplaceAllString(cleaned, " ")
+	cleaned = strings.ToLower(cleaned)
+
+	var letters, latin, cyrillic, han, kana int
+	scripts := make(map[string]int)
+	for _, r := range cleaned {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		switch {
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Han, r):
+			han++
+		default:
+			for _, s := range scriptLanguages {
+				if unicode.Is(s.table, r) {
+					scripts[s.iso]++
+					if s.iso == "ja" {
+						kana++
+					}
+					break
+				}
+			}
+		}
+	}
+	if letters < minDetectLetters && han == 0 && len(scripts) == 0 {
+		return Language{}, NoLanguage
+	}
+
+	// Japanese mixes kana with Han characters; Han alone is Chinese.
+	if han > 0 && kana == 0 && han*2 >= letters {
+		return LanguageFromISO("zh"), HighConfidence
+	}
+
+	bestISO, bestCount := "", 0
+	for iso, count := range scripts {
+		if count > bestCount {
+			bestISO, bestCount = iso, count
+		}
+	}
+	if bestISO == "ja" {
+		bestCount += han
+	}
+
+	switch {
+	case bestCount > latin && bestCount > cyrillic:
+		return LanguageFromISO(bestISO), HighConfidence
+	case cyrillic >= latin && cyrillic > 0:
+		return scoreProfiles(cleaned, cyrillicProfiles)
+	case latin > 0:
+		return scoreProfiles(cleaned, latinProfiles)
+	default:
+		return Language{}, UnknownLanguage
+	}
+}
+
+// scoreProfiles picks the profile with the most stopword hits, distinctive letters and letter sequences.
+// The guess is confident when the best score is high enough and clearly ahead of the runner-up.
+func scoreProfiles(text string, profiles []languageProfile) (Language, Confidence) {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	padded := " " + strings.Join(words, " ") + " "
+
+	bestISO, bestScore, runnerUp := "", 0.0, 0.0
+	for _, p := range profiles {
+		score := 0.0
+		for _, w := range words {
+			if p.stopwords[w] {
+				score += 2
+			}
+		}
+		if p.markers != "" {
+			for _, r := range text {
+				if strings.ContainsRune(p.markers, r) {
+					score += 1.5
+				}
+			}
+		}
+		for _, g := range p.ngrams {
+			score += float64(strings.Count(padded, g))
+		}
+		switch {
+		case score > bestScore:
+			bestISO, bestScore, runnerUp = p.iso, score, bestScore
+		case score > runnerUp:
+			runnerUp = score
+		}
+	}
+
+	switch {
+	case bestISO == "":
+		return Language{}, UnknownLanguage
+	case bestScore >= minConfidentScore && bestScore >= runnerUp*minConfidentMargin:
+		return LanguageFromISO(bestISO), HighConfidence
+	default:
+		return LanguageFromISO(bestISO), LowConfidence
+	}
+}
+
+// SkipDetected wraps a Translator and avoids network calls when the detector finds
+// the text is already in the target language or has nothing to translate.
+type SkipDetected struct {
+	next     Translator
+	detector *Detector
+}
+
+// NewSkipDetected creates a translator that short-circuits using offline detection.
+func NewSkipDetected(next Translator, detector *Detector) *SkipDetected {
+	if detector == nil {
+		detector = NewDetector()
+	}
+	return &SkipDetected{next: next, detector: detector}
+}
+
+// Translate returns the text unchanged when it is confidently in the target language,
+// ErrNothingToTranslate when it has no words, and otherwise delegates to the wrapped translator,
+// including text in unknown languages and uncertain guesses.
+// Translations without a reported source language are labeled with a confidently detected one.
+func (s *SkipDetected) Translate(ctx context.Context, text string, to Language) (*Translation, error) {
+	detected, confidence := s.detector.DetectConfidence(text)
+	switch {
+	case confidence == NoLanguage:
+		return nil, ErrNothingToTranslate
+	case confidence == HighConfidence && detected.ISO == to.ISO:
+		return &Translation{From: detected, To: to, Text: text}, nil
+	}
+
+	tr, err := s.next.Translate(ctx, text, to)
+	if err != nil {
+		return nil, err
+	}
+	if tr != nil && tr.From.ISO == "" && confidence == HighConfidence {
+		tr.From = detected
+	}
+	return tr, nil
+}
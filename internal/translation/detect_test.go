@@ -0,0 +1,197 @@
+package translation
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// minDetectAccuracy is the required share of correctly detected corpus lines per language.
+const minDetectAccuracy = 0.9
+
+func TestDetector_CorpusAccuracy(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "detect", "*.txt"))
+	if err != nil {
+		t.Fatalf("glob corpus: %v", err)
+	}
+	if len(files) == 0 {
+		t.Fatal("detect corpus is empty")
+	}
+
+	detector := NewDetector()
+	total, correct := 0, 0
+
+	for _, file := range files {
+		want := strings.TrimSuffix(filepath.Base(file), ".txt")
+		lines := readCorpus(t, file)
+
+		t.Run(want, func(t *testing.T) {
+			hits := 0
+			for _, line := range lines {
+				got, ok := detector.Detect(line)
+				if ok && got.ISO == want {
+					hits++
+					continue
+				}
+				t.Logf("misdetected %q as %q (ok=%v)", line, got.ISO, ok)
+			}
+
+			accuracy := float64(hits) / float64(len(lines))
+			if accuracy < minDetectAccuracy {
+				t.Errorf("accuracy = %.2f, want >= %.2f", accuracy, minDetectAccuracy)
+			}
+			total += len(lines)
+			correct += hits
+		})
+	}
+
+	t.Logf("overall accuracy: %d/%d", correct, total)
+}
+
+func TestDetector_NoSignal(t *testing.T) {
+	tests := []string{
+		"",
+		"https://x.com/user/status/123",
+		"🔥🔥🔥",
+		"@elonmusk #AI",
+		"12:30 — 42!",
+	}
+
+	detector := NewDetector()
+	for _, text := range tests {
+		if got, ok := detector.Detect(text); ok {
+			t.Errorf("Detect(%q) = %q, want no detection", text, got.ISO)
+		}
+	}
+}
+
+func TestDetector_IgnoresLinksAndMentions(t *testing.T) {
+	detector := NewDetector()
+
+	got, ok := detector.Detect("@user дякую, це дуже круто! https://example.com/the-best-news-of-the-week")
+	if !ok || got.ISO != "uk" {
+		t.Fatalf("Detect() = %q (ok=%v), want uk", got.ISO, ok)
+	}
+	if got.Name != "Ukrainian" {
+		t.Fatalf("Name = %q, want Ukrainian", got.Name)
+	}
+}
+
+func readCorpus(t *testing.T, path string) []string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open corpus: %v", err)
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("read corpus: %v", err)
+	}
+	return lines
+}
+
+func TestSkipDetected_SameLanguageSkipsNetwork(t *testing.T) {
+	next := &stubTranslator{result: &Translation{Text: "unused"}}
+	translator := NewSkipDetected(next, nil)
+
+	tr, err := translator.Translate(context.Background(), "Сьогодні дуже гарна погода, чи не так?", LangUkrainian)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next.calls != 0 {
+		t.Fatalf("expected no upstream calls, got %d", next.calls)
+	}
+	if tr.From.ISO != "uk" {
+		t.Fatalf("expected source language 'uk', got '%s'", tr.From.ISO)
+	}
+}
+
+func TestSkipDetected_NothingToTranslate(t *testing.T) {
+	next := &stubTranslator{result: &Translation{Text: "unused"}}
+	translator := NewSkipDetected(next, nil)
+
+	_, err := translator.Translate(context.Background(), "https://x.com/user/status/1 🔥", LangUkrainian)
+	if !errors.Is(err, ErrNothingToTranslate) {
+		t.Fatalf("expected ErrNothingToTranslate, got %v", err)
+	}
+	if next.calls != 0 {
+		t.Fatalf("expected no upstream calls, got %d", next.calls)
+	}
+}
+
+func TestSkipDetected_LabelsSourceLanguage(t *testing.T) {
+	next := &stubTranslator{result: &Translation{Text: "Привіт світ"}}
+	translator := NewSkipDetected(next, nil)
+
+	tr, err := translator.Translate(context.Background(), "Hello world, this is the best news of the week", LangUkrainian)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next.calls != 1 {
+		t.Fatalf("expected 1 upstream call, got %d", next.calls)
+	}
+	if tr.From.ISO != "en" || tr.From.Name != "English" {
+		t.Fatalf("expected source English, got %+v", tr.From)
+	}
+}
+
+func TestDetector_Confidence(t *testing.T) {
+	tests := []struct {
+		text string
+		want Confidence
+	}{
+		{"https://x.com/user/status/123 🔥", NoLanguage},
+		{"ሰላም ለዓለም", UnknownLanguage},
+		{"xqzv kjhg", UnknownLanguage},
+		{"Дякую", LowConfidence},
+		{"Сьогодні дуже гарна погода, чи не так?", HighConfidence},
+		{"こんにちは世界", HighConfidence},
+	}
+
+	detector := NewDetector()
+	for _, tt := range tests {
+		if _, got := detector.DetectConfidence(tt.text); got != tt.want {
+			t.Errorf("DetectConfidence(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSkipDetected_UncertainTextReachesNetwork(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		to   Language
+	}{
+		{"unknown language", "ሰላም ለዓለም", LangEnglish},
+		{"low confidence match of the target", "Дякую", LangRussian},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &stubTranslator{result: &Translation{Text: "Thanks"}}
+			translator := NewSkipDetected(next, nil)
+
+			tr, err := translator.Translate(context.Background(), tt.text, tt.to)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if next.calls != 1 {
+				t.Fatalf("expected 1 upstream call, got %d", next.calls)
+			}
+			if tr.From.ISO != "" {
+				t.Fatalf("expected no source label for an uncertain guess, got %+v", tr.From)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package translation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// ErrNoProviders is returned when a fallback chain has no translators configured.
+var ErrNoProviders = errors.New("no translation providers configured")
+
+// Fallback tries translators in order and returns the first successful translation.
+type Fallback struct {
+	translators []Translator
+	log         *slog.Logger
+}
+
+// NewFallback creates a fallback chain over the given translators. Nil translators are skipped.
+func NewFallback(translators ...Translator) *Fallback {
+	chain := make([]Translator, 0, len(translators))
+	for _, t := range translators {
+		if t != nil {
+			chain = append(chain, t)
+		}
+	}
+	return &Fallback{
+		translators: chain,
+		log:         slog.Default().With("component", "translation", "provider", "fallback"),
+	}
+}
+
+// Translate translates text with the first translator that succeeds.
+// If all translators fail, the joined errors are returned.
+func (f *Fallback) Translate(ctx context.Context, text string, to Language) (*Translation, error) {
+	if len(f.translators) == 0 {
+		return nil, ErrNoProviders
+	}
+
+	var errs []error
+	for i, t := range f.translators {
+		tr, err := t.Translate(ctx, text, to)
+		if err == nil {
+			return tr, nil
+		}
+		errs = append(errs, fmt.Errorf("provider %d: %w", i, err))
+		if ctx.Err() != nil {
+			break
+		}
+		f.log.Warn("translation provider failed, trying next", "index", i, "err", err)
+	}
+	return nil, errors.Join(errs...)
+}
@@ -0,0 +1,56 @@
+package translation
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type stubTranslator struct {
+	result *Translation
+	err    error
+	calls  int
+}
+
+func (s *stubTranslator) Translate(_ context.Context, _ string, _ Language) (*Translation, error) {
+	s.calls++
+	return s.result, s.err
+}
+
+func TestFallback_UsesFirstSuccessfulProvider(t *testing.T) {
+	failing := &stubTranslator{err: errors.New("throttled")}
+	working := &stubTranslator{result: &Translation{Text: "Привіт"}}
+	unused := &stubTranslator{result: &Translation{Text: "unused"}}
+
+	fallback := NewFallback(failing, nil, working, unused)
+
+	translation, err := fallback.Translate(context.Background(), "Hello", LangUkrainian)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if translation.Text != "Привіт" {
+		t.Errorf("expected 'Привіт', got '%s'", translation.Text)
+	}
+	if failing.calls != 1 || working.calls != 1 || unused.calls != 0 {
+		t.Errorf("unexpected calls: failing=%d working=%d unused=%d", failing.calls, working.calls, unused.calls)
+	}
+}
+
+func TestFallback_AllProvidersFail(t *testing.T) {
+	first := errors.New("first")
+	second := errors.New("second")
+
+	fallback := NewFallback(&stubTranslator{err: first}, &stubTranslator{err: second})
+
+	_, err := fallback.Translate(context.Background(), "Hello", LangUkrainian)
+	if !errors.Is(err, first) || !errors.Is(err, second) {
+		t.Fatalf("expected joined errors, got %v", err)
+	}
+}
+
+func TestFallback_NoProviders(t *testing.T) {
+	_, err := NewFallback().Translate(context.Background(), "Hello", LangUkrainian)
+	if !errors.Is(err, ErrNoProviders) {
+		t.Fatalf("expected ErrNoProviders, got %v", err)
+	}
+}
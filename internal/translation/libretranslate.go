@@ -0,0 +1,104 @@
+package translation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// LibreTranslate provides translations via a LibreTranslate-compatible HTTP API.
+// See https://libretranslate.com/docs for the API reference.
+type LibreTranslate struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	log        *slog.Logger
+}
+
+// NewLibreTranslate creates a LibreTranslate provider.
+// baseURL is the server root (e.g. http://localhost:5000); apiKey is optional for self-hosted servers.
+func NewLibreTranslate(httpClient *http.Client, baseURL, apiKey string) *LibreTranslate {
+	return &LibreTranslate{
+		httpClient: httpClient,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		log:        slog.Default().With("component", "translation", "provider", "libretranslate"),
+	}
+}
+
+type libreTranslateRequest struct {
+	Query  string `json:"q"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Format string `json:"format"`
+	APIKey string `json:"api_key,omitempty"`
+}
+
+type libreTranslateResponse struct {
+	TranslatedText   string `json:"translatedText"`
+	DetectedLanguage *struct {
+		Language   string  `json:"language"`
+		Confidence float64 `json:"confidence"`
+	} `json:"detectedLanguage,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Translate translates text into the specified language.
+func (l *LibreTranslate) Translate(ctx context.Context, text string, to Language) (*Translation, error) {
+	l.log.Debug("starting translation request", "text_length", len(text), "target_language", to.ISO)
+
+	body, err := json.Marshal(libreTranslateRequest{
+		Query:  text,
+		Source: "auto",
+		Target: to.ISO,
+		Format: "text",
+		APIKey: l.apiKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.baseURL+"/translate", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		l.log.Error("translation request failed", "err", err)
+		return nil, fmt.Errorf("translation request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var libreResp libreTranslateResponse
+	decodeErr := json.NewDecoder(resp.Body).Decode(&libreResp)
+
+	if resp.StatusCode != http.StatusOK {
+		l.log.Error("translation API returned error", "status", resp.StatusCode, "error", libreResp.Error)
+		if libreResp.Error != "" {
+			return nil, fmt.Errorf("translation API returned status %d: %s", resp.StatusCode, libreResp.Error)
+		}
+		return nil, fmt.Errorf("translation API returned status %d", resp.StatusCode)
+	}
+	if decodeErr != nil {
+		l.log.Error("failed to decode translation response", "err", decodeErr)
+		return nil, fmt.Errorf("failed to decode response: %w", decodeErr)
+	}
+
+	from := Language{}
+	if libreResp.DetectedLanguage != nil {
+		from = LanguageFromISO(libreResp.DetectedLanguage.Language)
+	}
+
+	l.log.Info("translation completed successfully", "from", from.ISO, "to", to.ISO)
+	return &Translation{
+		From: from,
+		To:   to,
+		Text: libreResp.TranslatedText,
+	}, nil
+}
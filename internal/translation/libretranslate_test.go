@@ -0,0 +1,65 @@
+package translation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLibreTranslate_Translate_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/translate" {
+			t.Errorf("expected /translate, got %s", r.URL.Path)
+		}
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("expected json content-type")
+		}
+
+		var req libreTranslateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.Query != "Hello world" || req.Target != "uk" || req.Source != "auto" {
+			t.Errorf("unexpected request: %+v", req)
+		}
+		if req.APIKey != "secret" {
+			t.Errorf("expected api key 'secret', got '%s'", req.APIKey)
+		}
+
+		w.Write([]byte(`{"translatedText":"Привіт світ","detectedLanguage":{"language":"en","confidence":92}}`))
+	}))
+	defer server.Close()
+
+	provider := NewLibreTranslate(&http.Client{}, server.URL+"/", "secret")
+
+	translation, err := provider.Translate(context.Background(), "Hello world", LangUkrainian)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if translation.Text != "Привіт світ" {
+		t.Errorf("expected 'Привіт світ', got '%s'", translation.Text)
+	}
+	if translation.From.ISO != "en" {
+		t.Errorf("expected source language 'en', got '%s'", translation.From.ISO)
+	}
+	if translation.To.ISO != "uk" {
+		t.Errorf("expected target language 'uk', got '%s'", translation.To.ISO)
+	}
+}
+
+func TestLibreTranslate_Translate_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"uk is not supported"}`))
+	}))
+	defer server.Close()
+
+	provider := NewLibreTranslate(&http.Client{}, server.URL, "")
+
+	_, err := provider.Translate(context.Background(), "Hello", LangUkrainian)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
@@ -13,6 +13,33 @@ var (
 	LangRussian   = Language{ISO: "ru", Name: "Russian"}
 )
 
+// languageNames holds display names for languages known to the detector.
+var languageNames = map[string]string{
+	"uk": LangUkrainian.Name,
+	"en": LangEnglish.Name,
+	"ru": LangRussian.Name,
+	"be": "Belarusian",
+	"bg": "Bulgarian",
+	"de": "German",
+	"fr": "French",
+	"es": "Spanish",
+	"it": "Italian",
+	"pt": "Portuguese",
+	"pl": "Polish",
+	"nl": "Dutch",
+	"tr": "Turkish",
+	"ja": "Japanese",
+	"ko": "Korean",
+	"zh": "Chinese",
+	"ar": "Arabic",
+	"he": "Hebrew",
+	"el": "Greek",
+	"hi": "Hindi",
+	"th": "Thai",
+	"ka": "Georgian",
+	"hy": "Armenian",
+}
+
 // Translation represents a translation result.
 type Translation struct {
 	From Language
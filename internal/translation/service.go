@@ -116,15 +116,8 @@ func (s *Service) Translate(ctx context.Context, text string, to Language) (*Tra
 }
 
 // LanguageFromISO returns a Language for the given ISO code.
+// Unknown codes are returned lower-cased without a name.
 func LanguageFromISO(iso string) Language {
-	switch strings.ToLower(iso) {
-	case "uk":
-		return LangUkrainian
-	case "en":
-		return LangEnglish
-	case "ru":
-		return LangRussian
-	default:
-		return Language{ISO: iso}
-	}
+	iso = strings.ToLower(strings.TrimSpace(iso))
+	return Language{ISO: iso, Name: languageNames[iso]}
 }
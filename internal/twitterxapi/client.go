@@ -9,6 +9,7 @@ import (
 	"log/slog"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -47,6 +48,13 @@ type Tweet struct {
 	Author Author `json:"author"`
 	Media  *Media `json:"media,omitempty"`
 
+	PossiblySensitive bool `json:"possibly_sensitive,omitempty"`
+
+	// Engagement counters at the time the tweet was fetched
+	Likes    int `json:"likes,omitempty"`
+	Retweets int `json:"retweets,omitempty"`
+	Replies  int `json:"replies,omitempty"`
+
 	// Chain fields for replies and quotes
 	ReplyingTo       *string `json:"replying_to,omitempty"`
 	ReplyingToStatus *string `json:"replying_to_status,omitempty"`
@@ -88,6 +96,21 @@ type Mosaic struct {
 	Formats map[string]string `json:"formats,omitempty"`
 }
 
+// TimelineResponse is the response of the user timeline endpoint.
+type TimelineResponse struct {
+	Code    int      `json:"code"`
+	Message string   `json:"message"`
+	Tweets  []*Tweet `json:"tweets"`
+}
+
+// TimelineOpts narrows GetTimeline; zero fields use the API defaults.
+type TimelineOpts struct {
+	// SinceID returns only tweets newer than this tweet ID.
+	SinceID string
+	// Limit caps the number of returned tweets.
+	Limit int
+}
+
 func (c *Client) GetTweet(ctx context.Context, username, tweetID string) (*Tweet, error) {
 	if username == "" {
 		return nil, errors.New("username is required")
@@ -99,45 +122,104 @@ func (c *Client) GetTweet(ctx context.Context, username, tweetID string) (*Tweet
 	log := slog.Default().With("component", "twitterxapi", "tweet_username", username, "tweet_id", tweetID)
 
 	reqURL := fmt.Sprintf("%s/api/users/%s/tweets/%s", c.baseURL, url.PathEscape(username), url.PathEscape(tweetID))
+	var tweetResp TweetResponse
+	if err := c.getJSON(ctx, log, reqURL, "tweet", &tweetResp); err != nil {
+		return nil, err
+	}
+	if tweetResp.Code != http.StatusOK {
+		log.Warn("api error response", "code", tweetResp.Code, "message", tweetResp.Message)
+		return nil, &APIError{Code: tweetResp.Code, Message: tweetResp.Message}
+	}
+	if tweetResp.Tweet == nil {
+		log.Error("api response missing tweet")
+		return nil, errors.New("api response missing tweet")
+	}
+
+	log.Info("tweet fetched", "tweet_url", tweetResp.Tweet.URL)
+	return tweetResp.Tweet, nil
+}
+
+// GetTimeline returns the latest tweets of the user, newest first.
+func (c *Client) GetTimeline(ctx context.Context, username string, opts TimelineOpts) ([]*Tweet, error) {
+	if username == "" {
+		return nil, errors.New("username is required")
+	}
+
+	log := slog.Default().With("component", "twitterxapi", "tweet_username", username, "since_id", opts.SinceID)
+
+	query := url.Values{}
+	if opts.SinceID != "" {
+		query.Set("since_id", opts.SinceID)
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	reqURL := fmt.Sprintf("%s/api/users/%s/tweets", c.baseURL, url.PathEscape(username))
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+
+	var timelineResp TimelineResponse
+	if err := c.getJSON(ctx, log, reqURL, "timeline", &timelineResp); err != nil {
+		return nil, err
+	}
+	if timelineResp.Code != http.StatusOK {
+		log.Warn("api error response", "code", timelineResp.Code, "message", timelineResp.Message)
+		return nil, &APIError{Code: timelineResp.Code, Message: timelineResp.Message}
+	}
+
+	tweets := make([]*Tweet, 0, len(timelineResp.Tweets))
+	for _, tw := range timelineResp.Tweets {
+		if tw != nil {
+			tweets = append(tweets, tw)
+		}
+	}
+	log.Info("timeline fetched", "tweets", len(tweets))
+	return tweets, nil
+}
+
+// getJSON fetches reqURL and decodes a 200 OK JSON body into out; what names the resource in logs and errors.
+func (c *Client) getJSON(ctx context.Context, log *slog.Logger, reqURL, what string, out any) error {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
 	if err != nil {
 		log.Error("build request failed", "err", err)
-		return nil, fmt.Errorf("build request: %w", err)
+		return fmt.Errorf("build request: %w", err)
 	}
 
-	log.Debug("fetch tweet", "url", reqURL)
+	log.Debug("fetch "+what, "url", reqURL)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		log.Error("fetch tweet failed", "err", err)
-		return nil, fmt.Errorf("fetch tweet: %w", err)
+		log.Error("fetch "+what+" failed", "err", err)
+		return fmt.Errorf("fetch %s: %w", what, err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		log.Error("read response failed", "err", err)
-		return nil, fmt.Errorf("read response: %w", err)
+		return fmt.Errorf("read response: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		log.Warn("unexpected status code", "status", resp.StatusCode, "body_len", len(body))
-		return nil, fmt.Errorf("api status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		return statusError(resp.StatusCode, body)
 	}
 
-	var tweetResp TweetResponse
-	if err := json.Unmarshal(body, &tweetResp); err != nil {
+	if err := json.Unmarshal(body, out); err != nil {
 		log.Error("decode response failed", "err", err)
-		return nil, fmt.Errorf("decode response: %w", err)
+		return fmt.Errorf("decode response: %w", err)
 	}
-	if tweetResp.Code != http.StatusOK {
-		log.Warn("api error response", "code", tweetResp.Code, "message", tweetResp.Message)
-		return nil, fmt.Errorf("api error %d: %s", tweetResp.Code, tweetResp.Message)
+	return nil
+}
+
+// statusError returns the error of a non-200 response, with the message of its JSON body when present.
+func statusError(status int, body []byte) error {
+	var payload struct {
+		Message string `json:"message"`
 	}
-	if tweetResp.Tweet == nil {
-		log.Error("api response missing tweet")
-		return nil, errors.New("api response missing tweet")
+	message := strings.TrimSpace(string(body))
+	if err := json.Unmarshal(body, &payload); err == nil && payload.Message != "" {
+		message = payload.Message
 	}
-
-	log.Info("tweet fetched", "tweet_url", tweetResp.Tweet.URL)
-	return tweetResp.Tweet, nil
+	return &APIError{Code: status, Message: message}
 }
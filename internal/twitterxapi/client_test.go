@@ -0,0 +1,74 @@
+package twitterxapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_GetTimeline(t *testing.T) {
+	var gotPath, gotSince, gotLimit string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotSince = r.URL.Query().Get("since_id")
+		gotLimit = r.URL.Query().Get("limit")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code":200,"message":"OK","tweets":[{"id":"12","text":"newer"},null,{"id":"11","text":"older"}]}`))
+	}))
+	defer server.Close()
+
+	tweets, err := NewClient(server.URL).GetTimeline(context.Background(), "nasa", TimelineOpts{SinceID: "10", Limit: 5})
+	if err != nil {
+		t.Fatalf("GetTimeline() error = %v", err)
+	}
+	if gotPath != "/api/users/nasa/tweets" || gotSince != "10" || gotLimit != "5" {
+		t.Errorf("request = %s?since_id=%s&limit=%s", gotPath, gotSince, gotLimit)
+	}
+	if len(tweets) != 2 || tweets[0].ID != "12" || tweets[1].ID != "11" {
+		t.Errorf("GetTimeline() = %+v, want tweets 12 and 11", tweets)
+	}
+}
+
+func TestClient_GetTimeline_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":404,"message":"User not found"}`))
+	}))
+	defer server.Close()
+
+	if _, err := NewClient(server.URL).GetTimeline(context.Background(), "nobody", TimelineOpts{}); err == nil {
+		t.Fatal("GetTimeline() error = nil, want api error")
+	}
+}
+
+func TestClient_GetTweet_ClassifiesErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+	}{
+		{"not found in body", http.StatusOK, `{"code":404,"message":"NOT_FOUND"}`, ErrNotFound},
+		{"not found status", http.StatusNotFound, `{"code":404,"message":"NOT_FOUND"}`, ErrNotFound},
+		{"protected", http.StatusUnauthorized, `{"code":401,"message":"PRIVATE_TWEET"}`, ErrProtected},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			_, err := NewClient(server.URL).GetTweet(context.Background(), "nasa", "1")
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("GetTweet() error = %v, want %v", err, tt.want)
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.Message == "" {
+				t.Errorf("GetTweet() error = %#v, want an APIError with the message", err)
+			}
+		})
+	}
+}
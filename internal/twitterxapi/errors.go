@@ -0,0 +1,36 @@
+package twitterxapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// ErrNotFound matches API errors for deleted or nonexistent tweets and users.
+	ErrNotFound = errors.New("not found")
+	// ErrProtected matches API errors for tweets of protected accounts.
+	ErrProtected = errors.New("protected")
+)
+
+// APIError is an error response of the API, from the HTTP status or the code of the response body.
+type APIError struct {
+	Code    int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api error %d: %s", e.Code, e.Message)
+}
+
+// Is reports whether the error code means ErrNotFound or ErrProtected.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.Code == http.StatusNotFound
+	case ErrProtected:
+		return e.Code == http.StatusUnauthorized || e.Code == http.StatusForbidden
+	default:
+		return false
+	}
+}
@@ -4,13 +4,28 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/PaulSonOfLars/gotgbot/v2"
 
+	"twitterx-bot/internal/chain"
 	"twitterx-bot/internal/telegram/tweet"
 	"twitterx-bot/internal/twitterxapi"
 )
 
+// ResultsPageSize is the number of choices answered per inline query; the rest follow via the next offset.
+// The thread article comes last, so it is only published when the user scrolls to it.
+const ResultsPageSize = 5
+
+const (
+	// threadArticleTTL is how long a published thread article is reused for further queries of the reply.
+	threadArticleTTL = time.Hour
+	// maxThreadArticles caps the published thread articles remembered at once.
+	maxThreadArticles = 256
+)
+
 var (
 	ErrFetchTweet  = errors.New("fetch tweet")
 	ErrBuildInline = errors.New("build inline result")
@@ -24,6 +39,21 @@ type TweetFetcher interface {
 // UseCase handles building inline query results from tweets.
 type UseCase struct {
 	Fetcher TweetFetcher
+	// SaveButton adds a "⭐ Save" button to the sent tweets.
+	SaveButton bool
+	// ChainArticles offers replies as their full thread published as one article. Optional.
+	ChainArticles tweet.ChainArticleCreator
+
+	mu sync.Mutex
+	// threads are the published thread articles by reply tweet ID, see threadArticle.
+	threads map[string]publishedThread
+}
+
+// publishedThread is a thread article published for an inline query.
+type publishedThread struct {
+	items     []chain.ChainItem
+	url       string
+	expiresAt time.Time
 }
 
 // New creates a new inline UseCase.
@@ -42,13 +72,111 @@ func (uc *UseCase) BuildInlineResult(ctx context.Context, username, tweetID stri
 
 	tw, err := uc.Fetcher.GetTweet(ctx, username, tweetID)
 	if err != nil {
-		return nil, false, fmt.Errorf("%w: %v", ErrFetchTweet, err)
+		return nil, false, fmt.Errorf("%w: %w", ErrFetchTweet, err)
 	}
 
-	result, ok := tweet.BuildInlineResult(tw, tweetID)
+	result, ok := tweet.InlineBuilder{SaveButton: uc.SaveButton}.Build(tw, tweetID)
 	if !ok {
 		return nil, false, nil
 	}
 
 	return result, true, nil
 }
+
+// BuildInlineResults fetches a tweet and builds the page of its inline choices starting at offset.
+// nextOffset is empty on the last page.
+func (uc *UseCase) BuildInlineResults(ctx context.Context, username, tweetID string, offset int) (results []gotgbot.InlineQueryResult, nextOffset string, err error) {
+	if uc == nil {
+		return nil, "", fmt.Errorf("inline usecase: %w", ErrBuildInline)
+	}
+	if uc.Fetcher == nil {
+		return nil, "", fmt.Errorf("inline usecase: %w", ErrFetchTweet)
+	}
+
+	tw, err := uc.Fetcher.GetTweet(ctx, username, tweetID)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %w", ErrFetchTweet, err)
+	}
+
+	builder := tweet.InlineBuilder{SaveButton: uc.SaveButton}
+	choices := builder.BuildChoices(tw, tweetID)
+	if len(choices) == 0 {
+		return nil, "", nil
+	}
+
+	total := len(choices)
+	withThread := uc.ChainArticles != nil && tw.ReplyingToStatus != nil
+	if withThread {
+		total++
+	}
+	if offset < 0 || offset >= total {
+		return nil, "", nil
+	}
+	end := min(offset+ResultsPageSize, total)
+
+	results = choices[offset:min(end, len(choices))]
+	if withThread && end == total {
+		if result, ok := uc.threadArticle(ctx, builder, tw); ok {
+			results = append(results, result)
+		}
+	}
+	if end < total {
+		nextOffset = strconv.Itoa(end)
+	}
+	return results, nextOffset, nil
+}
+
+// threadArticle publishes the thread of the reply and builds the choice linking to it.
+// The article is reused for an hour, so queries typed again or scrolled again do not
+// publish a new page each time. Threads that cannot be published are left out of the choices.
+func (uc *UseCase) threadArticle(ctx context.Context, builder tweet.InlineBuilder, tw *twitterxapi.Tweet) (gotgbot.InlineQueryResult, bool) {
+	if thread, ok := uc.publishedThread(tw.ID); ok {
+		return builder.BuildChainArticle(thread.items, thread.url)
+	}
+
+	items, err := chain.BuildChain(ctx, uc.Fetcher, tw)
+	if err != nil || len(items) < 2 {
+		return nil, false
+	}
+	articleURL, err := uc.ChainArticles.CreateChainArticle(ctx, items)
+	if err != nil {
+		return nil, false
+	}
+	uc.rememberThread(tw.ID, publishedThread{items: items, url: articleURL, expiresAt: time.Now().Add(threadArticleTTL)})
+	return builder.BuildChainArticle(items, articleURL)
+}
+
+func (uc *UseCase) publishedThread(tweetID string) (publishedThread, bool) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	thread, ok := uc.threads[tweetID]
+	if !ok || time.Now().After(thread.expiresAt) {
+		return publishedThread{}, false
+	}
+	return thread, true
+}
+
+// rememberThread keeps the thread for reuse, dropping expired threads once maxThreadArticles are kept.
+func (uc *UseCase) rememberThread(tweetID string, thread publishedThread) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	if uc.threads == nil {
+		uc.threads = make(map[string]publishedThread)
+	}
+	if len(uc.threads) >= maxThreadArticles {
+		now := time.Now()
+		for id, kept := range uc.threads {
+			if now.After(kept.expiresAt) {
+				delete(uc.threads, id)
+			}
+		}
+	}
+	if len(uc.threads) >= maxThreadArticles {
+		// All still fresh: forget any one of them, it is only republished when queried again
+		for id := range uc.threads {
+			delete(uc.threads, id)
+			break
+		}
+	}
+	uc.threads[tweetID] = thread
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/PaulSonOfLars/gotgbot/v2"
 
+	"twitterx-bot/internal/chain"
 	"twitterx-bot/internal/twitterxapi"
 )
 
@@ -140,3 +141,80 @@ func TestUseCaseBuildInlineResultFetchError(t *testing.T) {
 		t.Fatalf("ok = true, want false")
 	}
 }
+
+type threadFetcher map[string]*twitterxapi.Tweet
+
+func (f threadFetcher) GetTweet(_ context.Context, username, tweetID string) (*twitterxapi.Tweet, error) {
+	if tw, ok := f[username+"/"+tweetID]; ok {
+		return tw, nil
+	}
+	return nil, errors.New("not found")
+}
+
+type fakeChainArticles struct {
+	calls int
+}
+
+func (f *fakeChainArticles) CreateChainArticle(_ context.Context, items []chain.ChainItem) (string, error) {
+	f.calls++
+	return "https://telegra.ph/thread", nil
+}
+
+func TestUseCaseBuildInlineResultsPaginates(t *testing.T) {
+	parent := &twitterxapi.Tweet{ID: "1", Text: "parent", URL: "https://x.com/alice/status/1", Author: twitterxapi.Author{ScreenName: "alice"}}
+	replyingTo, replyingToStatus := "alice", "1"
+	reply := &twitterxapi.Tweet{
+		ID:               "2",
+		Text:             "four photos",
+		URL:              "https://x.com/bob/status/2",
+		Author:           twitterxapi.Author{ScreenName: "bob"},
+		ReplyingTo:       &replyingTo,
+		ReplyingToStatus: &replyingToStatus,
+		Quote:            &twitterxapi.Tweet{ID: "3", Text: "quoted", Author: twitterxapi.Author{ScreenName: "carol"}},
+		Media: &twitterxapi.Media{Photos: []twitterxapi.Photo{
+			{URL: "https://img/1.jpg"}, {URL: "https://img/2.jpg"}, {URL: "https://img/3.jpg"}, {URL: "https://img/4.jpg"},
+		}},
+	}
+	articles := &fakeChainArticles{}
+	uc := New(threadFetcher{"alice/1": parent, "bob/2": reply})
+	uc.ChainArticles = articles
+
+	results, next, err := uc.BuildInlineResults(context.Background(), "bob", "2", 0)
+	if err != nil {
+		t.Fatalf("BuildInlineResults() error = %v", err)
+	}
+	if len(results) != ResultsPageSize || next != "5" {
+		t.Fatalf("first page = %d results, next %q; want %d, \"5\"", len(results), next, ResultsPageSize)
+	}
+	if articles.calls != 0 {
+		t.Errorf("thread article published for the first page")
+	}
+
+	results, next, err = uc.BuildInlineResults(context.Background(), "bob", "2", 5)
+	if err != nil {
+		t.Fatalf("BuildInlineResults() error = %v", err)
+	}
+	if len(results) != 3 || next != "" {
+		t.Fatalf("second page = %d results, next %q; want 3 and no next page", len(results), next)
+	}
+	thread, ok := results[2].(gotgbot.InlineQueryResultArticle)
+	if !ok || thread.Id != "2:thread" || thread.Url != "https://telegra.ph/thread" || articles.calls != 1 {
+		t.Errorf("last result = %#v, want the thread article", results[2])
+	}
+
+	if results, next, _ := uc.BuildInlineResults(context.Background(), "bob", "2", 8); len(results) != 0 || next != "" {
+		t.Errorf("page past the end = %d results, next %q; want none", len(results), next)
+	}
+
+	// Scrolling to the last page again reuses the published article
+	results, _, err = uc.BuildInlineResults(context.Background(), "bob", "2", 5)
+	if err != nil {
+		t.Fatalf("BuildInlineResults() error = %v", err)
+	}
+	if thread, ok := results[2].(gotgbot.InlineQueryResultArticle); !ok || thread.Url != "https://telegra.ph/thread" {
+		t.Errorf("last result = %#v, want the thread article", results[2])
+	}
+	if articles.calls != 1 {
+		t.Errorf("thread articles published = %d, want 1", articles.calls)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"twitterx-bot/internal/chain"
+	"twitterx-bot/internal/chatsettings"
 	"twitterx-bot/internal/telegram/tweet"
 	"twitterx-bot/internal/twitterxapi"
 )
@@ -23,13 +24,15 @@ type TweetFetcher interface {
 
 // ChainSender sends chain responses to Telegram.
 type ChainSender interface {
-	SendChainResponse(chatID int64, items []chain.ChainItem, replyToMsgID int64, opts *tweet.SendChainResponseOpts) error
+	SendChain(ctx context.Context, chatID int64, items []chain.ChainItem, replyToMsgID int64, opts *tweet.SendChainResponseOpts) error
 }
 
 // UseCase handles sending tweet chains to Telegram.
 type UseCase struct {
 	Fetcher TweetFetcher
 	Sender  ChainSender
+	// Settings is optional; without it every chat uses the default settings.
+	Settings chatsettings.Provider
 }
 
 // New creates a new sendchain UseCase.
@@ -59,10 +62,15 @@ func (uc *UseCase) SendChain(ctx context.Context, chatID, replyToMsgID int64, us
 		return fmt.Errorf("%w: %v", ErrBuildChain, err)
 	}
 
+	// The original message is about to be deleted: do not reply to it
+	if chatsettings.Load(ctx, uc.Settings, chatID, nil).DeleteOriginal {
+		replyToMsgID = 0
+	}
+
 	opts := &tweet.SendChainResponseOpts{
 		RequesterUsername: requester,
 	}
-	if err := uc.Sender.SendChainResponse(chatID, items, replyToMsgID, opts); err != nil {
+	if err := uc.Sender.SendChain(ctx, chatID, items, replyToMsgID, opts); err != nil {
 		return fmt.Errorf("%w: %v", ErrSendChain, err)
 	}
 
@@ -52,7 +52,7 @@ type fakeSender struct {
 	}
 }
 
-func (s *fakeSender) SendChainResponse(chatID int64, items []chain.ChainItem, replyToMsgID int64, opts *tweet.SendChainResponseOpts) error {
+func (s *fakeSender) SendChain(_ context.Context, chatID int64, items []chain.ChainItem, replyToMsgID int64, opts *tweet.SendChainResponseOpts) error {
 	s.calls++
 	s.got.chatID = chatID
 	s.got.replyToMsgID = replyToMsgID
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"twitterx-bot/internal/chatsettings"
 	"twitterx-bot/internal/telegram/tweet"
 	"twitterx-bot/internal/twitterxapi"
 )
@@ -28,6 +29,8 @@ type TweetSender interface {
 type UseCase struct {
 	Fetcher TweetFetcher
 	Sender  TweetSender
+	// Settings is optional; without it every chat uses the default settings.
+	Settings chatsettings.Provider
 }
 
 // New creates a new sendtweet UseCase.
@@ -52,8 +55,10 @@ func (uc *UseCase) SendTweet(ctx context.Context, chatID, replyToMsgID int64, us
 		return fmt.Errorf("%w: %v", ErrFetchTweet, err)
 	}
 
+	settings := chatsettings.Load(ctx, uc.Settings, chatID, nil)
+
 	var keyboardOpts *tweet.KeyboardOpts
-	if tw != nil && tw.ReplyingToStatus != nil {
+	if tw != nil && tw.ReplyingToStatus != nil && settings.ChainMode != chatsettings.ChainOff {
 		keyboardOpts = &tweet.KeyboardOpts{
 			ShowChainButton: true,
 			ChainUsername:   username,
@@ -66,6 +71,15 @@ func (uc *UseCase) SendTweet(ctx context.Context, chatID, replyToMsgID int64, us
 		RequesterUsername: requester,
 	}
 
+	// The original message is about to be deleted: do not reply to it or offer to delete it
+	if settings.DeleteOriginal {
+		replyToMsgID = 0
+		opts.ReplyMarkup = nil
+		if keyboardOpts != nil {
+			opts.ReplyMarkup = tweet.BuildChainOnlyKeyboard(tweet.EncodeChainCallback(username, tweetID, 0))
+		}
+	}
+
 	if err := uc.Sender.SendTweet(ctx, chatID, replyToMsgID, tw, opts); err != nil {
 		return fmt.Errorf("%w: %v", ErrSendTweet, err)
 	}
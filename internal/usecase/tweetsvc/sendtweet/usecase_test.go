@@ -8,6 +8,7 @@ import (
 
 	"github.com/PaulSonOfLars/gotgbot/v2"
 
+	"twitterx-bot/internal/chatsettings"
 	"twitterx-bot/internal/telegram/tweet"
 	"twitterx-bot/internal/twitterxapi"
 )
@@ -194,8 +195,9 @@ func TestUseCaseSendTweetSelectsMediaGroup(t *testing.T) {
 	if err != nil {
 		t.Fatalf("SendTweet() error = %v", err)
 	}
-	if bot.mediaGroupCalls != 1 || bot.videoCalls != 0 || bot.photoCalls != 0 || bot.messageCalls != 0 {
-		t.Fatalf("calls: video=%d photo=%d media=%d msg=%d, want media only", bot.videoCalls, bot.photoCalls, bot.mediaGroupCalls, bot.messageCalls)
+	// The delete keyboard follows the media group, which cannot carry it
+	if bot.mediaGroupCalls != 1 || bot.videoCalls != 0 || bot.photoCalls != 0 || bot.messageCalls != 1 {
+		t.Fatalf("calls: video=%d photo=%d media=%d msg=%d, want media and its keyboard", bot.videoCalls, bot.photoCalls, bot.mediaGroupCalls, bot.messageCalls)
 	}
 	if len(bot.lastMedia) != tweet.MaxMediaGroupSize {
 		t.Fatalf("media group size = %d, want %d", len(bot.lastMedia), tweet.MaxMediaGroupSize)
@@ -271,3 +273,64 @@ func TestUseCaseSendTweetMissingDeps(t *testing.T) {
 		t.Fatalf("expected error for missing deps")
 	}
 }
+
+func replyTweetFetcher() *fakeFetcher {
+	return &fakeFetcher{
+		tweet: &twitterxapi.Tweet{
+			ID:               "555",
+			Text:             "a reply",
+			URL:              "https://x.com/user/status/555",
+			ReplyingToStatus: strPtr("1"),
+		},
+	}
+}
+
+func settingsFor(chatID int64, settings chatsettings.Settings) *chatsettings.MemoryStore {
+	store := chatsettings.NewMemoryStore()
+	_ = store.Save(context.Background(), chatID, settings)
+	return store
+}
+
+func TestUseCaseSendTweetChainOffHidesChainButton(t *testing.T) {
+	bot := &fakeBot{}
+	uc := New(replyTweetFetcher(), tweet.Sender{Bot: bot})
+	uc.Settings = settingsFor(10, chatsettings.Settings{ChainMode: chatsettings.ChainOff})
+
+	if err := uc.SendTweet(context.Background(), 10, 7, "user", "555", "@req"); err != nil {
+		t.Fatalf("SendTweet() error = %v", err)
+	}
+	markup, ok := bot.lastMessageOpts.ReplyMarkup.(*gotgbot.InlineKeyboardMarkup)
+	if !ok {
+		t.Fatalf("reply markup type = %T, want InlineKeyboardMarkup", bot.lastMessageOpts.ReplyMarkup)
+	}
+	if tweet.FindChainButton(markup) != "" {
+		t.Fatalf("chain button present with chain mode off")
+	}
+}
+
+func TestUseCaseSendTweetDeleteOriginalSkipsReply(t *testing.T) {
+	bot := &fakeBot{}
+	uc := New(replyTweetFetcher(), tweet.Sender{Bot: bot})
+	uc.Settings = settingsFor(10, chatsettings.Settings{DeleteOriginal: true})
+
+	if err := uc.SendTweet(context.Background(), 10, 7, "user", "555", "@req"); err != nil {
+		t.Fatalf("SendTweet() error = %v", err)
+	}
+	if bot.lastMessageOpts.ReplyParameters != nil {
+		t.Fatalf("reply parameters = %+v, want none", bot.lastMessageOpts.ReplyParameters)
+	}
+	markup, ok := bot.lastMessageOpts.ReplyMarkup.(*gotgbot.InlineKeyboardMarkup)
+	if !ok {
+		t.Fatalf("reply markup type = %T, want InlineKeyboardMarkup", bot.lastMessageOpts.ReplyMarkup)
+	}
+	for _, row := range markup.InlineKeyboard {
+		for _, btn := range row {
+			if strings.HasPrefix(btn.CallbackData, tweet.DeleteCallbackPrefix) {
+				t.Fatalf("delete button present although the original is deleted")
+			}
+		}
+	}
+	if tweet.FindChainButton(markup) == "" {
+		t.Fatalf("chain button missing")
+	}
+}
@@ -16,7 +16,7 @@ type TweetFetcher interface {
 // TweetSender sends tweet responses to Telegram.
 type TweetSender interface {
 	SendTweet(ctx context.Context, chatID, replyToMsgID int64, tweet *twitterxapi.Tweet, opts *tweet.SendResponseOpts) error
-	SendChainResponse(chatID int64, items []chain.ChainItem, replyToMsgID int64, opts *tweet.SendChainResponseOpts) error
+	SendChain(ctx context.Context, chatID int64, items []chain.ChainItem, replyToMsgID int64, opts *tweet.SendChainResponseOpts) error
 }
 
 // Service holds tweet-related use cases.
@@ -33,7 +33,7 @@ func (s *Service) SendChain(ctx context.Context, chatID, replyToMsgID int64, use
 	opts := &tweet.SendChainResponseOpts{
 		RequesterUsername: requester,
 	}
-	if err := s.Sender.SendChainResponse(chatID, items, replyToMsgID, opts); err != nil {
+	if err := s.Sender.SendChain(ctx, chatID, items, replyToMsgID, opts); err != nil {
 		return fmt.Errorf("%w: %v", ErrSendChain, err)
 	}
 
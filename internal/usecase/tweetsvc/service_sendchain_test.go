@@ -56,7 +56,7 @@ func (s *chainSender) SendTweet(_ context.Context, _ int64, _ int64, _ *twitterx
 	return nil
 }
 
-func (s *chainSender) SendChainResponse(chatID int64, items []chain.ChainItem, replyToMsgID int64, opts *tweet.SendChainResponseOpts) error {
+func (s *chainSender) SendChain(_ context.Context, chatID int64, items []chain.ChainItem, replyToMsgID int64, opts *tweet.SendChainResponseOpts) error {
 	s.calls++
 	s.got.chatID = chatID
 	s.got.replyToMsgID = replyToMsgID
@@ -190,8 +190,9 @@ func TestServiceSendTweetSelectsMediaGroup(t *testing.T) {
 	if err != nil {
 		t.Fatalf("SendTweet() error = %v", err)
 	}
-	if bot.mediaGroupCalls != 1 || bot.videoCalls != 0 || bot.photoCalls != 0 || bot.messageCalls != 0 {
-		t.Fatalf("calls: video=%d photo=%d media=%d msg=%d, want media only", bot.videoCalls, bot.photoCalls, bot.mediaGroupCalls, bot.messageCalls)
+	// The delete keyboard follows the media group, which cannot carry it
+	if bot.mediaGroupCalls != 1 || bot.videoCalls != 0 || bot.photoCalls != 0 || bot.messageCalls != 1 {
+		t.Fatalf("calls: video=%d photo=%d media=%d msg=%d, want media and its keyboard", bot.videoCalls, bot.photoCalls, bot.mediaGroupCalls, bot.messageCalls)
 	}
 	if len(bot.lastMedia) != tweet.MaxMediaGroupSize {
 		t.Fatalf("media group size = %d, want %d", len(bot.lastMedia), tweet.MaxMediaGroupSize)
@@ -0,0 +1,56 @@
+package watch
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MuteWindow is a daily period in UTC during which a rule sends no alerts.
+// A window whose end is before its start spans midnight.
+type MuteWindow struct {
+	// Start and End are minutes since midnight.
+	Start, End int
+}
+
+// ParseMuteWindow parses "HH:MM-HH:MM".
+func ParseMuteWindow(input string) (MuteWindow, error) {
+	from, to, ok := strings.Cut(strings.TrimSpace(input), "-")
+	if !ok {
+		return MuteWindow{}, fmt.Errorf("mute window %q: want HH:MM-HH:MM", input)
+	}
+	start, err := parseClock(from)
+	if err != nil {
+		return MuteWindow{}, fmt.Errorf("mute window %q: %w", input, err)
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return MuteWindow{}, fmt.Errorf("mute window %q: %w", input, err)
+	}
+	if start == end {
+		return MuteWindow{}, fmt.Errorf("mute window %q is empty", input)
+	}
+	return MuteWindow{Start: start, End: end}, nil
+}
+
+// Contains reports whether t falls within the window.
+func (w MuteWindow) Contains(t time.Time) bool {
+	t = t.UTC()
+	minute := t.Hour()*60 + t.Minute()
+	if w.Start < w.End {
+		return minute >= w.Start && minute < w.End
+	}
+	return minute >= w.Start || minute < w.End
+}
+
+func (w MuteWindow) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", w.Start/60, w.Start%60, w.End/60, w.End%60)
+}
+
+func parseClock(input string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(input))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", input)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
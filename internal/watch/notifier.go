@@ -0,0 +1,261 @@
+package watch
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/storage"
+	"twitterx-bot/internal/telegram/tweet"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+const (
+	defaultQueueSize = 256
+	checkTimeout     = 30 * time.Second
+	// alertWindow keeps a rule from alerting twice about one tweet seen in several chats.
+	alertWindow = 24 * time.Hour
+	// memberTTL is how long a membership check is reused.
+	memberTTL = 10 * time.Minute
+)
+
+// Sighting is a tweet the bot sent to a chat.
+type Sighting struct {
+	ChatID    int64
+	MessageID int64
+	Tweet     *twitterxapi.Tweet
+}
+
+// Alert tells the owner of Rule about a matching tweet.
+type Alert struct {
+	Rule     storage.WatchRule
+	Sighting Sighting
+	// Chat is the chat the tweet was seen in; zero when it is not known.
+	Chat storage.Chat
+}
+
+// Alerter delivers alerts to users.
+type Alerter interface {
+	// IsMember reports whether the user can see the messages of the chat.
+	IsMember(ctx context.Context, chatID, userID int64) (bool, error)
+	Alert(ctx context.Context, alert Alert) error
+}
+
+// Notifier matches the tweets the bot sends against all watch rules. Tweets are queued by
+// RecordSent and matched by Run, so sending never waits for the rules. Users are only alerted
+// about chats they are members of.
+// The zero value is not usable; create it with NewNotifier.
+type Notifier struct {
+	log   *logger.Logger
+	rules storage.WatchRepository
+	chats storage.ChatRepository
+	queue chan Sighting
+	now   func() time.Time
+
+	mu      sync.Mutex
+	queries map[int64]parsedQuery
+	alerted map[alertKey]time.Time
+	members map[memberKey]membership
+}
+
+type parsedQuery struct {
+	raw   string
+	query Query
+	err   error
+}
+
+type alertKey struct {
+	ruleID  int64
+	tweetID string
+}
+
+type memberKey struct {
+	chatID, userID int64
+}
+
+type membership struct {
+	member    bool
+	checkedAt time.Time
+}
+
+// NewNotifier creates a notifier for the given rules; chats names the chat in alerts.
+func NewNotifier(log *logger.Logger, rules storage.WatchRepository, chats storage.ChatRepository) *Notifier {
+	return &Notifier{
+		log:     log,
+		rules:   rules,
+		chats:   chats,
+		queue:   make(chan Sighting, defaultQueueSize),
+		now:     time.Now,
+		queries: make(map[int64]parsedQuery),
+		alerted: make(map[alertKey]time.Time),
+		members: make(map[memberKey]membership),
+	}
+}
+
+// RecordSent queues a sent tweet for matching. It implements tweet.HistoryRecorder.
+func (n *Notifier) RecordSent(_ context.Context, sent tweet.SentTweet) error {
+	n.Observe(Sighting{ChatID: sent.ChatID, MessageID: sent.MessageID, Tweet: sent.Tweet})
+	return nil
+}
+
+// Observe queues a tweet for matching; it is dropped when the queue is full.
+func (n *Notifier) Observe(s Sighting) {
+	if s.Tweet == nil {
+		return
+	}
+	select {
+	case n.queue <- s:
+	default:
+		n.log.With("component", "watch").Warn("watch queue full, tweet dropped", "chat_id", s.ChatID, "tweet_id", s.Tweet.ID)
+	}
+}
+
+// Run matches queued tweets and sends alerts until ctx is cancelled.
+func (n *Notifier) Run(ctx context.Context, alerter Alerter) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case s := <-n.queue:
+			checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+			n.Check(checkCtx, alerter, s)
+			cancel()
+		}
+	}
+}
+
+// Check alerts the owner of every rule matching the sighting, unless the rule is muted,
+// already alerted about the tweet, or the owner is not a member of the chat.
+func (n *Notifier) Check(ctx context.Context, alerter Alerter, s Sighting) {
+	log := n.log.With("component", "watch", "chat_id", s.ChatID, "tweet_id", s.Tweet.ID)
+
+	rules, err := n.rules.List(ctx)
+	if err != nil {
+		log.Error("list watch rules failed", "err", err)
+		return
+	}
+
+	now := n.now()
+	n.forget(now, rules)
+
+	var chat *storage.Chat
+	for _, rule := range rules {
+		// Tweets a user gets in their own private chat need no alert
+		if rule.UserID == s.ChatID || !n.matches(rule, s.Tweet) {
+			continue
+		}
+		if muted(rule, now) {
+			log.Debug("watch rule muted", "rule_id", rule.ID)
+			continue
+		}
+		key := alertKey{ruleID: rule.ID, tweetID: s.Tweet.ID}
+		if n.wasAlerted(key) {
+			continue
+		}
+
+		member, err := n.isMember(ctx, alerter, s.ChatID, rule.UserID, now)
+		if err != nil {
+			log.Debug("check watcher membership failed", "rule_id", rule.ID, "user_id", rule.UserID, "err", err)
+		}
+		if !member {
+			continue
+		}
+
+		if chat == nil {
+			chat = &storage.Chat{ID: s.ChatID}
+			if known, ok, err := n.chats.Get(ctx, s.ChatID); err != nil {
+				log.Warn("load chat failed", "err", err)
+			} else if ok {
+				chat = &known
+			}
+		}
+
+		if err := alerter.Alert(ctx, Alert{Rule: rule, Sighting: s, Chat: *chat}); err != nil {
+			log.Warn("send watch alert failed", "rule_id", rule.ID, "user_id", rule.UserID, "err", err)
+			continue
+		}
+		n.markAlerted(key, now)
+		log.Info("watch alert sent", "rule_id", rule.ID, "user_id", rule.UserID)
+	}
+}
+
+// matches parses the rule (reusing the last parse of an unchanged query) and matches the tweet.
+func (n *Notifier) matches(rule storage.WatchRule, tw *twitterxapi.Tweet) bool {
+	n.mu.Lock()
+	parsed, ok := n.queries[rule.ID]
+	if !ok || parsed.raw != rule.Query {
+		parsed = parsedQuery{raw: rule.Query}
+		parsed.query, parsed.err = ParseQuery(rule.Query)
+		n.queries[rule.ID] = parsed
+	}
+	n.mu.Unlock()
+	return parsed.err == nil && parsed.query.Match(tw)
+}
+
+func muted(rule storage.WatchRule, now time.Time) bool {
+	if rule.MuteWindow == "" {
+		return false
+	}
+	window, err := ParseMuteWindow(rule.MuteWindow)
+	return err == nil && window.Contains(now)
+}
+
+func (n *Notifier) isMember(ctx context.Context, alerter Alerter, chatID, userID int64, now time.Time) (bool, error) {
+	key := memberKey{chatID: chatID, userID: userID}
+	n.mu.Lock()
+	cached, ok := n.members[key]
+	n.mu.Unlock()
+	if ok && now.Sub(cached.checkedAt) < memberTTL {
+		return cached.member, nil
+	}
+
+	member, err := alerter.IsMember(ctx, chatID, userID)
+	if err != nil {
+		member = false
+	}
+	n.mu.Lock()
+	n.members[key] = membership{member: member, checkedAt: now}
+	n.mu.Unlock()
+	return member, err
+}
+
+func (n *Notifier) wasAlerted(key alertKey) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	_, ok := n.alerted[key]
+	return ok
+}
+
+func (n *Notifier) markAlerted(key alertKey, now time.Time) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.alerted[key] = now
+}
+
+// forget drops expired alert and membership entries and the parses of deleted rules.
+func (n *Notifier) forget(now time.Time, rules []storage.WatchRule) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if len(n.queries) > len(rules) {
+		current := make(map[int64]struct{}, len(rules))
+		for _, rule := range rules {
+			current[rule.ID] = struct{}{}
+		}
+		for id := range n.queries {
+			if _, ok := current[id]; !ok {
+				delete(n.queries, id)
+			}
+		}
+	}
+	for key, at := range n.alerted {
+		if now.Sub(at) >= alertWindow {
+			delete(n.alerted, key)
+		}
+	}
+	for key, m := range n.members {
+		if now.Sub(m.checkedAt) >= memberTTL {
+			delete(n.members, key)
+		}
+	}
+}
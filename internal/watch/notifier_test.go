@@ -0,0 +1,134 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"twitterx-bot/internal/logger"
+	"twitterx-bot/internal/storage"
+	"twitterx-bot/internal/telegram/tweet"
+	"twitterx-bot/internal/twitterxapi"
+)
+
+type fakeAlerter struct {
+	members     map[int64]bool
+	memberCalls int
+	alerts      []Alert
+}
+
+func (f *fakeAlerter) IsMember(_ context.Context, _ int64, userID int64) (bool, error) {
+	f.memberCalls++
+	member, ok := f.members[userID]
+	if !ok {
+		return false, errors.New("user not found")
+	}
+	return member, nil
+}
+
+func (f *fakeAlerter) Alert(_ context.Context, alert Alert) error {
+	f.alerts = append(f.alerts, alert)
+	return nil
+}
+
+func addRule(t *testing.T, store storage.Storage, rule storage.WatchRule) storage.WatchRule {
+	t.Helper()
+	rule, err := store.Watches().Add(context.Background(), rule)
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	return rule
+}
+
+func TestNotifier_Check_AlertsMatchingMembersOnce(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemory()
+	_ = store.Chats().Upsert(ctx, storage.Chat{ID: -100, Type: "supergroup", Title: "Gophers"})
+	match := addRule(t, store, storage.WatchRule{UserID: 1, Query: "golang"})
+	addRule(t, store, storage.WatchRule{UserID: 2, Query: "golang"}) // not a member
+	addRule(t, store, storage.WatchRule{UserID: 3, Query: "rust"})   // no match
+	addRule(t, store, storage.WatchRule{UserID: 4, Query: "golang"}) // lookup fails
+
+	alerter := &fakeAlerter{members: map[int64]bool{1: true, 2: false, 3: true}}
+	n := NewNotifier(logger.New(true), store.Watches(), store.Chats())
+	tw := &twitterxapi.Tweet{ID: "42", Text: "Golang news"}
+
+	n.Check(ctx, alerter, Sighting{ChatID: -100, MessageID: 7, Tweet: tw})
+
+	if len(alerter.alerts) != 1 {
+		t.Fatalf("alerts = %+v, want 1", alerter.alerts)
+	}
+	alert := alerter.alerts[0]
+	if alert.Rule.ID != match.ID || alert.Chat.Title != "Gophers" || alert.Sighting.MessageID != 7 {
+		t.Errorf("alert = %+v", alert)
+	}
+
+	// The same tweet seen in another chat does not alert again; memberships are cached
+	calls := alerter.memberCalls
+	n.Check(ctx, alerter, Sighting{ChatID: -100, MessageID: 8, Tweet: tw})
+	if len(alerter.alerts) != 1 {
+		t.Errorf("alerts = %d after repeat, want 1", len(alerter.alerts))
+	}
+	if alerter.memberCalls != calls {
+		t.Errorf("membership checked %d more times, want cached", alerter.memberCalls-calls)
+	}
+}
+
+func TestNotifier_Check_SkipsMutedRulesAndOwnChat(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemory()
+	addRule(t, store, storage.WatchRule{UserID: 1, Query: "golang", MuteWindow: "22:00-07:00"})
+	addRule(t, store, storage.WatchRule{UserID: 2, Query: "golang"})
+
+	alerter := &fakeAlerter{members: map[int64]bool{1: true, 2: true}}
+	n := NewNotifier(logger.New(true), store.Watches(), store.Chats())
+	n.now = func() time.Time { return time.Date(2026, 1, 1, 23, 0, 0, 0, time.UTC) }
+
+	// User 2 shared the tweet in their own private chat
+	n.Check(ctx, alerter, Sighting{ChatID: 2, Tweet: &twitterxapi.Tweet{ID: "1", Text: "golang"}})
+	if len(alerter.alerts) != 0 {
+		t.Errorf("alerts = %+v, want none", alerter.alerts)
+	}
+
+	n.now = func() time.Time { return time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC) }
+	n.Check(ctx, alerter, Sighting{ChatID: -100, Tweet: &twitterxapi.Tweet{ID: "2", Text: "golang"}})
+	if len(alerter.alerts) != 2 {
+		t.Errorf("alerts = %d after the mute window, want 2", len(alerter.alerts))
+	}
+}
+
+func TestNotifier_RunProcessesRecordedTweets(t *testing.T) {
+	store := storage.NewMemory()
+	addRule(t, store, storage.WatchRule{UserID: 1, Query: "@nasa"})
+	alerter := &fakeAlerter{members: map[int64]bool{1: true}}
+	n := NewNotifier(logger.New(true), store.Watches(), store.Chats())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- n.Run(ctx, alerter) }()
+
+	tw := &twitterxapi.Tweet{ID: "5", Author: twitterxapi.Author{ScreenName: "NASA"}}
+	if err := n.RecordSent(ctx, tweet.SentTweet{ChatID: -100, MessageID: 3, Tweet: tw}); err != nil {
+		t.Fatalf("RecordSent() error = %v", err)
+	}
+
+	// An unmatched tweet queued after the match drains the queue past the first one
+	n.Observe(Sighting{ChatID: -100, Tweet: &twitterxapi.Tweet{ID: "6"}})
+	deadline := time.After(2 * time.Second)
+	for len(n.queue) > 0 {
+		select {
+		case <-deadline:
+			t.Fatal("queue not drained")
+		default:
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(alerter.alerts) != 1 || alerter.alerts[0].Sighting.Tweet.ID != "5" {
+		t.Errorf("alerts = %+v, want tweet 5", alerter.alerts)
+	}
+}
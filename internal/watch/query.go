@@ -0,0 +1,183 @@
+// Package watch matches tweets seen by the bot against users' watch rules and alerts
+// the watching users in private.
+package watch
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"twitterx-bot/internal/twitterxapi"
+)
+
+const maxPatternLength = 200
+
+// Query is a parsed watch rule. A tweet matches when it contains every keyword, is written
+// by one of the authors (if any), matches the pattern (if any) and contains no excluded term
+// or author. Matching is case-insensitive.
+type Query struct {
+	Keywords       []string
+	Authors        []string
+	Exclude        []string
+	ExcludeAuthors []string
+	Pattern        *regexp.Regexp
+}
+
+// ParseQuery parses a rule such as `golang "generic types" -rust @golang /go ?1\.2\d/`:
+// words and quoted phrases are keywords, @name is an author, a leading "-" excludes a term
+// or author and /.../ is a regular expression.
+func ParseQuery(input string) (Query, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return Query{}, err
+	}
+
+	var q Query
+	for _, tok := range tokens {
+		switch {
+		case tok.regex:
+			if q.Pattern != nil {
+				return Query{}, errors.New("only one /regex/ per rule")
+			}
+			if len(tok.text) > maxPatternLength {
+				return Query{}, fmt.Errorf("regex is longer than %d characters", maxPatternLength)
+			}
+			pattern, err := regexp.Compile("(?i)" + tok.text)
+			if err != nil {
+				return Query{}, fmt.Errorf("invalid regex: %w", err)
+			}
+			q.Pattern = pattern
+		case !tok.quoted && strings.HasPrefix(tok.text, "@"):
+			author := strings.ToLower(strings.TrimPrefix(tok.text, "@"))
+			if author == "" {
+				return Query{}, errors.New("empty @author")
+			}
+			if tok.exclude {
+				q.ExcludeAuthors = append(q.ExcludeAuthors, author)
+			} else {
+				q.Authors = append(q.Authors, author)
+			}
+		default:
+			term := strings.ToLower(tok.text)
+			if tok.exclude {
+				q.Exclude = append(q.Exclude, term)
+			} else {
+				q.Keywords = append(q.Keywords, term)
+			}
+		}
+	}
+
+	if len(q.Keywords) == 0 && len(q.Authors) == 0 && q.Pattern == nil {
+		return Query{}, errors.New("a rule needs a keyword, an @author or a /regex/")
+	}
+	return q, nil
+}
+
+// Match reports whether the tweet text (including a quoted tweet) and author match the query.
+func (q Query) Match(tw *twitterxapi.Tweet) bool {
+	if tw == nil {
+		return false
+	}
+	text := tw.Text
+	if tw.Quote != nil {
+		text += "\n" + tw.Quote.Text
+	}
+	lower := strings.ToLower(text)
+	author := strings.ToLower(tw.Author.ScreenName)
+
+	if len(q.Authors) > 0 && !contains(q.Authors, author) {
+		return false
+	}
+	if contains(q.ExcludeAuthors, author) {
+		return false
+	}
+	for _, kw := range q.Keywords {
+		if !strings.Contains(lower, kw) {
+			return false
+		}
+	}
+	for _, term := range q.Exclude {
+		if strings.Contains(lower, term) {
+			return false
+		}
+	}
+	return q.Pattern == nil || q.Pattern.MatchString(text)
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+type token struct {
+	text    string
+	exclude bool
+	quoted  bool
+	regex   bool
+}
+
+// tokenize splits the rule on spaces, keeping "quoted phrases" and /regexes/ whole.
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	rest := strings.TrimSpace(input)
+	for rest != "" {
+		var tok token
+		if strings.HasPrefix(rest, "-") && len(rest) > 1 {
+			tok.exclude = true
+			rest = rest[1:]
+		}
+
+		switch rest[0] {
+		case '"':
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				return nil, errors.New("unterminated quote")
+			}
+			tok.text, tok.quoted = rest[1:end+1], true
+			rest = rest[end+2:]
+		case '/':
+			end := regexEnd(rest)
+			if end < 0 {
+				return nil, errors.New("unterminated /regex/")
+			}
+			if tok.exclude {
+				return nil, errors.New("a /regex/ cannot be excluded")
+			}
+			tok.text, tok.regex = rest[1:end], true
+			rest = rest[end+1:]
+		default:
+			end := strings.IndexAny(rest, " \t\n")
+			if end < 0 {
+				end = len(rest)
+			}
+			tok.text = rest[:end]
+			rest = rest[end:]
+		}
+
+		if strings.TrimSpace(tok.text) == "" {
+			return nil, errors.New("empty term")
+		}
+		tokens = append(tokens, tok)
+		rest = strings.TrimSpace(rest)
+	}
+	return tokens, nil
+}
+
+// regexEnd returns the index of the "/" closing the regex that starts at s[0]: the first
+// unescaped "/" followed by a space or the end of the input.
+func regexEnd(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case s[i] == '/' && i > 1 && (i == len(s)-1 || strings.ContainsRune(" \t\n", rune(s[i+1]))):
+			return i
+		}
+	}
+	return -1
+}
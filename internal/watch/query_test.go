@@ -0,0 +1,104 @@
+package watch
+
+import (
+	"testing"
+	"time"
+
+	"twitterx-bot/internal/twitterxapi"
+)
+
+func TestParseQuery(t *testing.T) {
+	q, err := ParseQuery(`Golang "Generic Types" -rust -@spam @GoLang /go ?1\.2\d/`)
+	if err != nil {
+		t.Fatalf("ParseQuery() error = %v", err)
+	}
+	if len(q.Keywords) != 2 || q.Keywords[0] != "golang" || q.Keywords[1] != "generic types" {
+		t.Errorf("Keywords = %q", q.Keywords)
+	}
+	if len(q.Exclude) != 1 || q.Exclude[0] != "rust" {
+		t.Errorf("Exclude = %q", q.Exclude)
+	}
+	if len(q.Authors) != 1 || q.Authors[0] != "golang" || len(q.ExcludeAuthors) != 1 || q.ExcludeAuthors[0] != "spam" {
+		t.Errorf("Authors = %q, ExcludeAuthors = %q", q.Authors, q.ExcludeAuthors)
+	}
+	if q.Pattern == nil || !q.Pattern.MatchString("GO 1.23") {
+		t.Errorf("Pattern = %v, want case-insensitive go 1.2x", q.Pattern)
+	}
+}
+
+func TestParseQuery_Errors(t *testing.T) {
+	for _, input := range []string{"", "-rust", `"open`, "/open", "/a/ /b/", "/[/", "-/x/", "@"} {
+		if _, err := ParseQuery(input); err == nil {
+			t.Errorf("ParseQuery(%q) error = nil", input)
+		}
+	}
+}
+
+func TestQuery_Match(t *testing.T) {
+	tweet := func(author, text string) *twitterxapi.Tweet {
+		return &twitterxapi.Tweet{ID: "1", Text: text, Author: twitterxapi.Author{ScreenName: author}}
+	}
+	quoted := tweet("alice", "Look at this")
+	quoted.Quote = tweet("golang", "Go 1.23 is released")
+
+	tests := []struct {
+		query string
+		tweet *twitterxapi.Tweet
+		want  bool
+	}{
+		{"golang release", tweet("x", "The Golang team made a RELEASE"), true},
+		{"golang release", tweet("x", "Golang only"), false},
+		{"golang -rust", tweet("x", "golang and rust"), false},
+		{"@golang", tweet("GoLang", "anything"), true},
+		{"@golang", tweet("alice", "golang"), false},
+		{"go -@alice", tweet("alice", "go go"), false},
+		{`"go 1.23"`, quoted, true},
+		{`/\bv?\d+\.\d+\b/`, tweet("x", "version 2.0 is out"), true},
+		{`/\bv?\d+\.\d+\b/`, tweet("x", "no version"), false},
+	}
+	for _, tt := range tests {
+		q, err := ParseQuery(tt.query)
+		if err != nil {
+			t.Fatalf("ParseQuery(%q) error = %v", tt.query, err)
+		}
+		if got := q.Match(tt.tweet); got != tt.want {
+			t.Errorf("%q.Match(%q by %s) = %v, want %v", tt.query, tt.tweet.Text, tt.tweet.Author.ScreenName, got, tt.want)
+		}
+	}
+}
+
+func TestMuteWindow(t *testing.T) {
+	night, err := ParseMuteWindow("22:00-07:30")
+	if err != nil {
+		t.Fatalf("ParseMuteWindow() error = %v", err)
+	}
+	if night.String() != "22:00-07:30" {
+		t.Errorf("String() = %q", night.String())
+	}
+	lunch, _ := ParseMuteWindow("12:00-13:00")
+
+	tests := []struct {
+		window MuteWindow
+		clock  string
+		want   bool
+	}{
+		{night, "23:15", true},
+		{night, "03:00", true},
+		{night, "07:30", false},
+		{night, "12:00", false},
+		{lunch, "12:30", true},
+		{lunch, "13:00", false},
+	}
+	for _, tt := range tests {
+		at, _ := time.Parse("15:04", tt.clock)
+		if got := tt.window.Contains(at); got != tt.want {
+			t.Errorf("%s.Contains(%s) = %v, want %v", tt.window, tt.clock, got, tt.want)
+		}
+	}
+
+	for _, input := range []string{"22:00", "25:00-01:00", "10:00-10:00", "a-b"} {
+		if _, err := ParseMuteWindow(input); err == nil {
+			t.Errorf("ParseMuteWindow(%q) error = nil", input)
+		}
+	}
+}
@@ -300,7 +300,7 @@ func (m *MockServer) handle(w http.ResponseWriter, r *http.Request) {
 			},
 		})
 		return
-	case "sendMessage", "sendPhoto", "sendVideo":
+	case "sendMessage", "sendPhoto", "sendVideo", "sendDocument":
 		writeJSON(w, http.StatusOK, map[string]any{
 			"ok":     true,
 			"result": defaultMessage(chatID, msgID),
@@ -378,6 +378,14 @@ func extractMediaGroupSize(call Call) int {
 	if !ok {
 		return 0
 	}
+	// gotgbot encodes nested values as JSON strings
+	if s, ok := v.(string); ok {
+		var decoded []any
+		if err := json.Unmarshal([]byte(s), &decoded); err != nil {
+			return 0
+		}
+		v = decoded
+	}
 	arr, ok := v.([]any)
 	if !ok {
 		return 0